@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// applyFlagDefaults sets the flags of cmd that were not passed on the
+// command line to the values configured for it in defaults. Flag names may
+// use underscores in place of dashes. Unknown flags and values the flag
+// rejects are reported together after the rest are applied.
+func applyFlagDefaults(cmd *cobra.Command, defaults map[string]map[string]any) error {
+	var errs []error
+
+	key := flagDefaultsKey(cmd)
+	for name, value := range defaults[key] {
+		flagName := strings.ReplaceAll(name, "_", "-")
+
+		flag := cmd.Flags().Lookup(flagName)
+		if flag == nil {
+			errs = append(errs, fmt.Errorf("defaults[%q].%s: unknown flag --%s", key, name, flagName))
+			continue
+		}
+		if flag.Changed {
+			continue
+		}
+
+		if err := setFlagDefault(cmd.Flags(), flag, value); err != nil {
+			errs = append(errs, fmt.Errorf("defaults[%q].%s: %w", key, name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// flagDefaultsKey returns the key of cmd in the defaults config: its command
+// path without the root command, e.g. "list" or "template edit". The name
+// alone is not enough since subcommands of different commands share names.
+func flagDefaultsKey(cmd *cobra.Command) string {
+	return strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" ")
+}
+
+// setFlagDefault sets flag from a decoded JSON value without marking it as
+// changed, so commands still see it as not passed on the command line.
+func setFlagDefault(flags *pflag.FlagSet, flag *pflag.Flag, value any) error {
+	var values []string
+	switch v := value.(type) {
+	case string:
+		values = []string{v}
+	case bool:
+		values = []string{strconv.FormatBool(v)}
+	case float64:
+		values = []string{strconv.FormatFloat(v, 'f', -1, 64)}
+	case []any:
+		for _, item := range v {
+			values = append(values, fmt.Sprint(item))
+		}
+	default:
+		return fmt.Errorf("unsupported value %v", value)
+	}
+
+	if len(values) == 0 {
+		return nil
+	}
+	if err := flags.Set(flag.Name, strings.Join(values, ",")); err != nil {
+		return err
+	}
+	flag.Changed = false
+
+	return nil
+}
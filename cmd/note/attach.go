@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/AnishShah1803/jotr/internal/config"
+	"github.com/AnishShah1803/jotr/internal/notes"
+)
+
+var AttachCmd = &cobra.Command{
+	Use:   "attach [note] [file]",
+	Short: "Attach a file to a note",
+	Long: `Copy a file into the attachments/ directory and embed it in a note.
+
+The copy is named after a hash of its content, so attaching the same file
+twice reuses one copy. An ![[name]] embed is appended to the note.
+
+Examples:
+  jotr attach MyNote ~/Downloads/diagram.png
+  jotr attach Work/Projects.md report.pdf`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.LoadWithContext(cmd.Context(), "")
+		if err != nil {
+			return err
+		}
+
+		return attachFile(cmd.Context(), cfg, args[0], args[1])
+	},
+}
+
+// resolveNoteArg finds the note named by arg: a path relative to the base
+// directory, an absolute path, or a unique file name match.
+func resolveNoteArg(ctx context.Context, cfg *config.LoadedConfig, arg string) (string, error) {
+	candidates := []string{arg}
+	if !filepath.IsAbs(arg) {
+		candidates = []string{filepath.Join(cfg.Paths.BaseDir, arg)}
+	}
+
+	for _, candidate := range candidates {
+		if info, err := os.Stat(candidate); err == nil && info.Mode().IsRegular() {
+			return candidate, nil
+		}
+	}
+
+	allNotes, err := notes.FindNotes(ctx, cfg.Paths.BaseDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to find notes: %w", err)
+	}
+
+	matches := matchNoteFiles(allNotes, arg)
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("no notes found matching: %s", arg)
+	case 1:
+		return matches[0], nil
+	default:
+		return "", fmt.Errorf("%d notes match %q; use a more specific name or path", len(matches), arg)
+	}
+}
+
+func attachFile(ctx context.Context, cfg *config.LoadedConfig, noteArg, file string) error {
+	notePath, err := resolveNoteArg(ctx, cfg, noteArg)
+	if err != nil {
+		return err
+	}
+
+	name, err := notes.StoreAttachment(cfg.Paths.BaseDir, file)
+	if err != nil {
+		return err
+	}
+
+	content, err := os.ReadFile(notePath)
+	if err != nil {
+		return fmt.Errorf("failed to read note: %w", err)
+	}
+
+	updated := string(content)
+	if updated != "" && !strings.HasSuffix(updated, "\n") {
+		updated += "\n"
+	}
+	updated += notes.AttachmentEmbed(name) + "\n"
+
+	if err := notes.WriteNote(ctx, notePath, updated); err != nil {
+		return fmt.Errorf("failed to write note: %w", err)
+	}
+
+	relPath, _ := filepath.Rel(cfg.Paths.BaseDir, notePath)
+	fmt.Printf("✓ Attached %s to %s as %s\n", filepath.Base(file), relPath, filepath.Join(notes.AttachmentsDir, name))
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/AnishShah1803/jotr/internal/notes"
+)
+
+// TestAttachFile tests that attaching copies the file under a content-hash
+// name and appends an embed to the note.
+func TestAttachFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := createTestConfigForCapture(t, tmpDir)
+
+	notePath := filepath.Join(tmpDir, "Projects.md")
+	if err := notes.WriteNote(context.Background(), notePath, "# Projects\n\nSee diagram:"); err != nil {
+		t.Fatalf("Failed to create note: %v", err)
+	}
+
+	src := filepath.Join(t.TempDir(), "Diagram.PNG")
+	if err := os.WriteFile(src, []byte("fake image bytes"), 0644); err != nil {
+		t.Fatalf("Failed to create attachment: %v", err)
+	}
+
+	if err := attachFile(context.Background(), cfg, "Projects", src); err != nil {
+		t.Fatalf("attachFile failed: %v", err)
+	}
+
+	name := notes.AttachmentName([]byte("fake image bytes"), ".PNG")
+	if !strings.HasSuffix(name, ".png") {
+		t.Errorf("Expected lowercased extension, got %s", name)
+	}
+
+	copied, err := os.ReadFile(filepath.Join(tmpDir, notes.AttachmentsDir, name))
+	if err != nil {
+		t.Fatalf("Expected attachment copy to exist: %v", err)
+	}
+	if string(copied) != "fake image bytes" {
+		t.Errorf("Attachment copy content = %q", copied)
+	}
+
+	content, err := os.ReadFile(notePath)
+	if err != nil {
+		t.Fatalf("Failed to read note: %v", err)
+	}
+	want := "# Projects\n\nSee diagram:\n![[" + name + "]]\n"
+	if string(content) != want {
+		t.Errorf("Note content = %q, want %q", content, want)
+	}
+
+	embeds := notes.FindAttachmentEmbeds(string(content))
+	if len(embeds) != 1 || !notes.AttachmentExists(tmpDir, notePath, embeds[0].Name) {
+		t.Errorf("Expected the embed to resolve to the stored attachment, got %+v", embeds)
+	}
+}
@@ -17,14 +17,23 @@ import (
 
 var captureTask bool
 
+func init() {
+	CaptureCmd.Flags().BoolVar(&captureTask, "task", false, "Capture as a task checkbox in the task section")
+}
+
 var CaptureCmd = &cobra.Command{
 	Use:   "capture [text]",
 	Short: "Quick capture to daily note",
 	Long: `Quickly capture text to today's daily note.
-	
+
+Notes go to the capture section (format.capture_section). Tasks, captured
+with --task or as text starting with "- [ ]", go to the task section
+(format.task_section) so sync picks them up.
+
 Examples:
   jotr capture "Meeting with team"
   jotr capture --task "Review PR #123"
+  jotr capture "- [ ] Review PR #123"
   jotr cap "Quick thought"`,
 	Aliases: []string{"cap"},
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -43,59 +52,92 @@ Examples:
 }
 
 func captureText(ctx context.Context, cfg *config.LoadedConfig, text string) error {
-	today := time.Now()
-	notePath := notes.BuildDailyNotePath(cfg.DiaryPath, today)
-
-	if !utils.FileExists(notePath) {
-		if err := notes.CreateDailyNote(ctx, notePath, cfg.Format.DailyNoteSections, today); err != nil {
-			return fmt.Errorf("failed to create daily note: %w", err)
+	// Tasks go to the task section so sync tracks them; notes go to the
+	// capture section
+	captureSection := cfg.Format.CaptureSection
+	if captureSection == "" {
+		captureSection = "Captured"
+	}
+	capturedLine := "- " + text
+	if taskText, ok := strings.CutPrefix(text, "- [ ] "); captureTask || ok {
+		captureSection = cfg.Format.TaskSection
+		if captureSection == "" {
+			captureSection = "Tasks"
 		}
+		capturedLine = "- [ ] " + taskText
 	}
 
-	content, err := os.ReadFile(notePath)
-	if err != nil {
-		return fmt.Errorf("failed to read note: %w", err)
+	if layout := cfg.Format.CaptureTimestampLayout(); layout != "" {
+		capturedLine += fmt.Sprintf(" (%s)", time.Now().Format(layout))
 	}
 
-	// Find the capture section
-	captureSection := cfg.Format.CaptureSection
-	if captureSection == "" {
-		captureSection = "Captured"
+	notePath, err := appendToDailySection(ctx, cfg, time.Now(), captureSection, capturedLine)
+	if err != nil {
+		return err
 	}
 
-	lines := strings.Split(string(content), "\n")
+	fmt.Printf("✓ Captured to: %s\n", notePath)
+	fmt.Printf("  %s\n", capturedLine)
 
-	insertIndex := utils.FindSectionIndex(lines, captureSection)
+	return nil
+}
+
+// appendToDailySection appends line after the last entry of section in the
+// daily note for date, creating the note, and the section if
+// auto_create_sections allows, as needed. It returns the note's path.
+func appendToDailySection(ctx context.Context, cfg *config.LoadedConfig, date time.Time, section, line string) (string, error) {
+	notePath := notes.BuildDailyNotePath(cfg.DiaryPath, date)
 
-	// If section not found, add it at the end
-	if insertIndex == -1 {
-		lines = append(lines, "", fmt.Sprintf("## %s", captureSection), "")
-		insertIndex = len(lines)
+	if !utils.FileExists(notePath) {
+		if err := notes.CreateDailyNote(ctx, notePath, cfg.Format.DailyNoteSections, date); err != nil {
+			return "", fmt.Errorf("failed to create daily note: %w", err)
+		}
 	}
 
-	// Format the captured text
-	timestamp := time.Now().Format("15:04")
+	content, err := os.ReadFile(notePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read note: %w", err)
+	}
 
-	var capturedLine string
+	lines := strings.Split(string(content), "\n")
 
-	if captureTask {
-		capturedLine = fmt.Sprintf("- [ ] %s (%s)", text, timestamp)
-	} else {
-		capturedLine = fmt.Sprintf("- %s (%s)", text, timestamp)
-	}
+	var newLines []string
 
-	newLines := make([]string, 0, len(lines)+1)
-	newLines = append(newLines, lines[:insertIndex]...)
-	newLines = append(newLines, capturedLine)
-	newLines = append(newLines, lines[insertIndex:]...)
+	insertIndex := utils.FindSectionEnd(lines, section)
+	switch {
+	case insertIndex == -1 && !cfg.Format.AutoCreateSectionsEnabled():
+		return "", fmt.Errorf("section %q not found in %s (auto_create_sections is disabled)", section, notePath)
+	case insertIndex == -1:
+		// Section not found: add it at the end of the note
+		for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+			lines = lines[:len(lines)-1]
+		}
+		newLines = append(lines, "", fmt.Sprintf("## %s", section), "", line, "")
+	default:
+		// Append after the last entry in the section, keeping blank lines
+		// around the section content
+		insert := []string{line}
+		if strings.HasPrefix(strings.TrimSpace(lines[insertIndex-1]), "## ") {
+			if insertIndex < len(lines) && strings.TrimSpace(lines[insertIndex]) == "" {
+				insertIndex++
+			} else {
+				insert = append([]string{""}, insert...)
+			}
+		}
+		if insertIndex < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[insertIndex]), "#") {
+			insert = append(insert, "")
+		}
+
+		newLines = make([]string, 0, len(lines)+len(insert))
+		newLines = append(newLines, lines[:insertIndex]...)
+		newLines = append(newLines, insert...)
+		newLines = append(newLines, lines[insertIndex:]...)
+	}
 
 	newContent := strings.Join(newLines, "\n")
 	if err := utils.AtomicWriteFile(notePath, []byte(newContent), constants.FilePerm0644); err != nil {
-		return fmt.Errorf("failed to write note: %w", err)
+		return "", fmt.Errorf("failed to write note: %w", err)
 	}
 
-	fmt.Printf("✓ Captured to: %s\n", notePath)
-	fmt.Printf("  %s\n", capturedLine)
-
-	return nil
+	return notePath, nil
 }
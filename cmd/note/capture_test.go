@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -12,6 +14,7 @@ import (
 	"github.com/AnishShah1803/jotr/internal/config"
 	"github.com/AnishShah1803/jotr/internal/constants"
 	"github.com/AnishShah1803/jotr/internal/notes"
+	"github.com/AnishShah1803/jotr/internal/tasks"
 	"github.com/AnishShah1803/jotr/internal/utils"
 )
 
@@ -123,6 +126,51 @@ func TestCaptureText_WithTaskFlag(t *testing.T) {
 	}
 }
 
+// TestCaptureText_RoutesTasksToTaskSection tests that captured tasks land in
+// the task section and captured notes in the capture section.
+func TestCaptureText_RoutesTasksToTaskSection(t *testing.T) {
+	cfg := createTestConfigForCapture(t, t.TempDir())
+	cfg.Format.TaskSection = "Tasks"
+	saveAndRestoreCaptureTask(t)
+
+	for _, text := range []string{"A passing thought", "- [ ] Review PR #123"} {
+		if err := captureText(context.Background(), cfg, text); err != nil {
+			t.Fatalf("captureText(%q) returned error: %v", text, err)
+		}
+	}
+	captureTask = true
+	if err := captureText(context.Background(), cfg, "Call the plumber"); err != nil {
+		t.Fatalf("captureText() with --task returned error: %v", err)
+	}
+
+	content, err := os.ReadFile(getDailyNotePath(cfg))
+	if err != nil {
+		t.Fatalf("Failed to read note: %v", err)
+	}
+
+	parsed := tasks.ParseTasks(string(content))
+	if len(parsed) != 2 {
+		t.Fatalf("expected 2 tasks in the note, got %d:\n%s", len(parsed), content)
+	}
+	for _, task := range parsed {
+		if task.Section != "Tasks" {
+			t.Errorf("task %q is in section %q, want Tasks:\n%s", task.Text, task.Section, content)
+		}
+	}
+
+	lines := strings.Split(string(content), "\n")
+	end := utils.FindSectionEnd(lines, "Captured")
+	if end == -1 {
+		t.Fatalf("note has no Captured section:\n%s", content)
+	}
+	start := slices.Index(lines, "## Captured")
+	if !slices.ContainsFunc(lines[start:end], func(line string) bool {
+		return strings.HasPrefix(line, "- A passing thought")
+	}) {
+		t.Errorf("note should be in the Captured section, got:\n%s", content)
+	}
+}
+
 // TestCaptureText_AppendsToExistingSection tests that capture appends to existing section.
 func TestCaptureText_AppendsToExistingSection(t *testing.T) {
 	tmpDir, err := os.MkdirTemp("", "jotr-capture-test-")
@@ -358,3 +406,260 @@ func TestCaptureText_EmptyCaptureSection(t *testing.T) {
 		t.Errorf("Note content should contain '## Captured' section (default), got:\n%s", contentStr)
 	}
 }
+
+// TestCaptureText_AppendsUnderHeadingWithTimestamp tests that a capture lands at
+// the end of the capture section, before the next heading, with a timestamp.
+func TestCaptureText_AppendsUnderHeadingWithTimestamp(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "jotr-capture-test-")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	cfg := createTestConfigForCapture(t, tmpDir)
+	saveAndRestoreCaptureTask(t)
+
+	notePath := getDailyNotePath(cfg)
+	initialContent := "# Today\n\n## Captured Ideas\n\n- not here\n\n## Captured\n\n- First entry (09:00)\n\n## Tasks\n\n- [ ] Task\n"
+	os.MkdirAll(filepath.Dir(notePath), 0755)
+	if err := os.WriteFile(notePath, []byte(initialContent), constants.FilePerm0644); err != nil {
+		t.Fatalf("Failed to create initial note: %v", err)
+	}
+
+	if err := captureText(context.Background(), cfg, "random thought"); err != nil {
+		t.Fatalf("captureText() returned error: %v", err)
+	}
+
+	content, err := os.ReadFile(notePath)
+	if err != nil {
+		t.Fatalf("Failed to read note: %v", err)
+	}
+
+	lines := strings.Split(string(content), "\n")
+	capturedRe := regexp.MustCompile(`^- random thought \(\d{2}:\d{2}\)$`)
+
+	want := []string{"## Captured", "", "- First entry (09:00)", "", "## Tasks"}
+	for i, line := range lines {
+		if line != "## Captured" {
+			continue
+		}
+		if len(lines) < i+6 {
+			t.Fatalf("Note truncated after capture section:\n%s", content)
+		}
+		if !capturedRe.MatchString(lines[i+3]) {
+			t.Errorf("Expected timestamped capture after first entry, got %q\n%s", lines[i+3], content)
+		}
+		got := []string{lines[i], lines[i+1], lines[i+2], lines[i+4], lines[i+5]}
+		if strings.Join(got, "\n") != strings.Join(want, "\n") {
+			t.Errorf("Capture section layout wrong:\n%s", content)
+		}
+		return
+	}
+
+	t.Fatalf("## Captured heading missing:\n%s", content)
+}
+
+// TestCaptureText_CustomTimestampLayout tests that the configured timestamp layout is used.
+func TestCaptureText_CustomTimestampLayout(t *testing.T) {
+	tests := []struct {
+		name    string
+		layout  string
+		pattern string
+	}{
+		{"12-hour clock", "3:04PM", `^- Custom stamp \(\d{1,2}:\d{2}(AM|PM)\)$`},
+		{"date and time", "2006-01-02 15:04", `^- Custom stamp \(\d{4}-\d{2}-\d{2} \d{2}:\d{2}\)$`},
+		{"disabled", "", `^- Custom stamp$`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createTestConfigForCapture(t, t.TempDir())
+			layout := tt.layout
+			cfg.Format.CaptureTimestamp = &layout
+			saveAndRestoreCaptureTask(t)
+
+			if err := captureText(context.Background(), cfg, "Custom stamp"); err != nil {
+				t.Fatalf("captureText() returned error: %v", err)
+			}
+
+			content, err := os.ReadFile(getDailyNotePath(cfg))
+			if err != nil {
+				t.Fatalf("Failed to read note: %v", err)
+			}
+
+			re := regexp.MustCompile(tt.pattern)
+			for _, line := range strings.Split(string(content), "\n") {
+				if strings.Contains(line, "Custom stamp") {
+					if !re.MatchString(line) {
+						t.Errorf("Captured line %q does not match %s", line, tt.pattern)
+					}
+					return
+				}
+			}
+			t.Errorf("Captured line not found:\n%s", content)
+		})
+	}
+}
+
+// TestLogEntry_AppendsInOrder tests that log entries land under "## Log" in
+// the order they were written.
+func TestLogEntry_AppendsInOrder(t *testing.T) {
+	cfg := createTestConfigForCapture(t, t.TempDir())
+
+	now := time.Now()
+	first := time.Date(now.Year(), now.Month(), now.Day(), 9, 5, 0, 0, now.Location())
+	second := first.Add(90 * time.Minute)
+
+	if err := logEntry(context.Background(), cfg, first, "started deploy"); err != nil {
+		t.Fatalf("logEntry() returned error: %v", err)
+	}
+	if err := logEntry(context.Background(), cfg, second, "finished deploy"); err != nil {
+		t.Fatalf("logEntry() returned error: %v", err)
+	}
+
+	content, err := os.ReadFile(notes.BuildDailyNotePath(cfg.DiaryPath, first))
+	if err != nil {
+		t.Fatalf("Failed to read note: %v", err)
+	}
+
+	lines := strings.Split(string(content), "\n")
+	heading := slices.Index(lines, "## Log")
+	if heading == -1 {
+		t.Fatalf("Note should contain a ## Log section, got:\n%s", content)
+	}
+
+	var entries []string
+	for _, line := range lines[heading+1:] {
+		if strings.HasPrefix(line, "#") {
+			break
+		}
+		if strings.TrimSpace(line) != "" {
+			entries = append(entries, line)
+		}
+	}
+
+	want := []string{"- 09:05 started deploy", "- 10:35 finished deploy"}
+	if !slices.Equal(entries, want) {
+		t.Errorf("Log entries = %q, want %q", entries, want)
+	}
+}
+
+func TestAppendToInbox(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := createTestConfigForCapture(t, tmpDir)
+	cfg.InboxPath = filepath.Join(tmpDir, "inbox.md")
+	ctx := context.Background()
+
+	for _, text := range []string{"Look into standing desks", "Call the dentist"} {
+		if err := appendToInbox(ctx, cfg, text); err != nil {
+			t.Fatalf("appendToInbox() error = %v", err)
+		}
+	}
+
+	content, err := os.ReadFile(cfg.InboxPath)
+	if err != nil {
+		t.Fatalf("Failed to read inbox: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimRight(string(content), "\n"), "\n")
+	if len(lines) != 4 || lines[0] != "# Inbox" {
+		t.Fatalf("inbox = %q; want a heading and two items", content)
+	}
+	itemRegex := regexp.MustCompile(`^- (.+) \(\d{4}-\d{2}-\d{2} \d{2}:\d{2}\)$`)
+	for i, want := range []string{"Look into standing desks", "Call the dentist"} {
+		match := itemRegex.FindStringSubmatch(lines[i+2])
+		if match == nil || match[1] != want {
+			t.Errorf("line %d = %q; want timestamped item %q", i+3, lines[i+2], want)
+		}
+	}
+}
+
+func TestProcessInbox_ConvertsItemToTask(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := createTestConfigForCapture(t, tmpDir)
+	cfg.Format.TaskSection = "Tasks"
+	cfg.InboxPath = filepath.Join(tmpDir, "inbox.md")
+	cfg.TodoPath = filepath.Join(tmpDir, "todo.md")
+	cfg.StatePath = filepath.Join(tmpDir, ".todo_state.json")
+	ctx := context.Background()
+
+	inbox := "# Inbox\n\n- Renew passport (2025-01-02 09:30)\n- Someday idea (2025-01-03 18:00)\n"
+	if err := os.WriteFile(cfg.InboxPath, []byte(inbox), 0644); err != nil {
+		t.Fatalf("Failed to write inbox: %v", err)
+	}
+
+	if err := processInbox(ctx, cfg, newMockReader("t\n", "s\n")); err != nil {
+		t.Fatalf("processInbox() error = %v", err)
+	}
+
+	todo, err := os.ReadFile(cfg.TodoPath)
+	if err != nil {
+		t.Fatalf("Failed to read todo file: %v", err)
+	}
+	if !strings.Contains(string(todo), "- [ ] Renew passport") {
+		t.Errorf("todo file does not contain the converted task:\n%s", todo)
+	}
+	if strings.Contains(string(todo), "2025-01-02 09:30") {
+		t.Errorf("converted task kept the inbox timestamp:\n%s", todo)
+	}
+
+	remaining, err := os.ReadFile(cfg.InboxPath)
+	if err != nil {
+		t.Fatalf("Failed to read inbox: %v", err)
+	}
+	if want := "# Inbox\n\n- Someday idea (2025-01-03 18:00)\n"; string(remaining) != want {
+		t.Errorf("inbox = %q; want %q", remaining, want)
+	}
+}
+
+// appendingReader captures an item to the inbox before its first answer, as
+// "jotr in" running while the inbox is processed would.
+type appendingReader struct {
+	*mockReader
+	append func()
+}
+
+func (r *appendingReader) ReadString(delim byte) (string, error) {
+	if r.append != nil {
+		r.append()
+		r.append = nil
+	}
+	return r.mockReader.ReadString(delim)
+}
+
+func TestProcessInbox_KeepsItemsCapturedDuringProcessing(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := createTestConfigForCapture(t, tmpDir)
+	cfg.InboxPath = filepath.Join(tmpDir, "inbox.md")
+	ctx := context.Background()
+
+	inbox := "# Inbox\n\n- Old idea (2025-01-02 09:30)\n- Someday idea (2025-01-03 18:00)\n"
+	if err := os.WriteFile(cfg.InboxPath, []byte(inbox), 0644); err != nil {
+		t.Fatalf("Failed to write inbox: %v", err)
+	}
+
+	reader := &appendingReader{
+		mockReader: newMockReader("d\n", "s\n"),
+		append: func() {
+			if err := appendToInbox(ctx, cfg, "Captured meanwhile"); err != nil {
+				t.Fatalf("appendToInbox() error = %v", err)
+			}
+		},
+	}
+	if err := processInbox(ctx, cfg, reader); err != nil {
+		t.Fatalf("processInbox() error = %v", err)
+	}
+
+	remaining, err := os.ReadFile(cfg.InboxPath)
+	if err != nil {
+		t.Fatalf("Failed to read inbox: %v", err)
+	}
+	if strings.Contains(string(remaining), "Old idea") {
+		t.Errorf("inbox still contains the deleted item:\n%s", remaining)
+	}
+	for _, want := range []string{"Someday idea", "Captured meanwhile"} {
+		if !strings.Contains(string(remaining), want) {
+			t.Errorf("inbox lost %q:\n%s", want, remaining)
+		}
+	}
+}
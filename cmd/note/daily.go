@@ -4,28 +4,49 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"os/exec"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/AnishShah1803/jotr/internal/config"
+	"github.com/AnishShah1803/jotr/internal/hooks"
 	"github.com/AnishShah1803/jotr/internal/notes"
 	"github.com/AnishShah1803/jotr/internal/options"
+	"github.com/AnishShah1803/jotr/internal/services"
 	"github.com/AnishShah1803/jotr/internal/utils"
 )
 
 var dateOption = options.NewDateOption()
 var outputOption = options.NewOutputOption()
 
+var dailyNoCreate bool
+
 func init() {
 	dateOption.AddFlags(DailyCmd)
 	outputOption.AddFlags(DailyCmd)
+	DailyCmd.Flags().BoolVar(&dailyNoCreate, "no-create", false, "Fail instead of creating the note if it doesn't exist")
 }
 
 var DailyCmd = &cobra.Command{
-	Use:     "daily",
-	Short:   "Create or open daily note",
-	Long:    `Create or open today's daily note.`,
+	Use:   "daily [sort]",
+	Short: "Create or open daily note",
+	Long: `Create or open today's daily note.
+
+If today's note already exists, format.on_existing_daily decides what
+happens: "open" (default) opens it, "append-sections" first adds any
+configured sections it is missing, and "error" fails instead.
+
+With --no-create, a missing note is not created and the command fails.
+
+Actions:
+  sort              Reorder the task section by priority, then due date
+
+Examples:
+  jotr daily                   # Open today's note
+  jotr daily sort              # Sort today's tasks
+  jotr daily sort --yesterday  # Sort yesterday's tasks
+  jotr daily --no-create       # Open today's note only if it exists`,
 	Aliases: []string{"d"},
 	RunE: func(cmd *cobra.Command, args []string) error {
 		cfg, err := config.LoadWithContext(cmd.Context(), "")
@@ -36,37 +57,79 @@ var DailyCmd = &cobra.Command{
 		dateOption.SetTargetDate()
 		notePath := notes.BuildDailyNotePath(cfg.DiaryPath, dateOption.Date)
 
+		if len(args) > 0 {
+			if args[0] != "sort" {
+				return fmt.Errorf("unknown action: %s", args[0])
+			}
+			return sortDailyTasks(cfg, notePath)
+		}
+
 		if outputOption.PathOnly {
 			fmt.Println(notePath)
 			return nil
 		}
 
-		if _, err := os.Stat(notePath); os.IsNotExist(err) {
-			sections := notes.BuildDailyNoteSections(cfg)
-			if err := notes.CreateDailyNote(cmd.Context(), notePath, sections, dateOption.Date); err != nil {
-				return fmt.Errorf("failed to create daily note: %w", err)
-			}
-			fmt.Printf("✓ Created: %s\n", notePath)
+		if err := prepareDailyNote(cmd.Context(), cfg, notePath, dateOption.Date); err != nil {
+			return err
 		}
 
 		return openInEditor(cmd.Context(), notePath)
 	},
 }
 
-func openInEditor(ctx context.Context, path string) error {
-	editor := config.GetEditorWithContext(ctx)
-	if editor == "" {
-		return fmt.Errorf("no editor configured - set EDITOR environment variable or configure editor.default")
+// prepareDailyNote creates the daily note if it is missing, or applies the
+// format.on_existing_daily behavior if it already exists. With --no-create a
+// missing note is an error.
+func prepareDailyNote(ctx context.Context, cfg *config.LoadedConfig, notePath string, date time.Time) error {
+	sections := notes.BuildDailyNoteSections(cfg)
+
+	if _, err := os.Stat(notePath); os.IsNotExist(err) {
+		if dailyNoCreate {
+			return fmt.Errorf("daily note doesn't exist: %s", notePath)
+		}
+		if err := notes.CreateDailyNote(ctx, notePath, sections, date); err != nil {
+			return fmt.Errorf("failed to create daily note: %w", err)
+		}
+		fmt.Printf("✓ Created: %s\n", notePath)
+		hooks.RunOrWarn(ctx, os.Stderr, cfg.Hooks.PostCreateNote, cfg.Paths.BaseDir, notePath)
+		return nil
 	}
 
-	if err := utils.ValidateEditor(editor); err != nil {
-		return fmt.Errorf("invalid editor: %w", err)
+	switch cfg.Format.OnExistingDailyMode() {
+	case config.OnExistingDailyError:
+		return fmt.Errorf("daily note already exists: %s", notePath)
+	case config.OnExistingDailyAppendSections:
+		added, err := notes.AppendMissingSections(ctx, notePath, sections)
+		if err != nil {
+			return fmt.Errorf("failed to add sections to daily note: %w", err)
+		}
+		if len(added) > 0 {
+			fmt.Printf("✓ Added section(s) %s to: %s\n", strings.Join(added, ", "), notePath)
+		}
 	}
 
-	execCmd := exec.Command(editor, path)
-	execCmd.Stdin = os.Stdin
-	execCmd.Stdout = os.Stdout
-	execCmd.Stderr = os.Stderr
+	return nil
+}
 
-	return execCmd.Run()
+func sortDailyTasks(cfg *config.LoadedConfig, notePath string) error {
+	if !utils.FileExists(notePath) {
+		return fmt.Errorf("daily note doesn't exist: %s", notePath)
+	}
+
+	changed, err := services.NewTaskService().SortDailyTasks(notePath, cfg.Format.TaskSection, services.TaskOptions(cfg.Format))
+	if err != nil {
+		return err
+	}
+
+	if !changed {
+		fmt.Println("✓ Tasks already sorted")
+		return nil
+	}
+
+	fmt.Printf("✓ Sorted tasks in: %s\n", notePath)
+	return nil
+}
+
+func openInEditor(ctx context.Context, path string) error {
+	return notes.OpenInEditorWithContext(ctx, path)
 }
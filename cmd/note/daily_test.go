@@ -457,3 +457,73 @@ func TestOpenInEditor_ErrorMessageClarity(t *testing.T) {
 		t.Errorf("Error message should provide a solution, got: %s", errorMsg)
 	}
 }
+
+// TestPrepareDailyNote_OnExistingDaily tests each on_existing_daily mode
+// against an existing note missing a configured section.
+func TestPrepareDailyNote_OnExistingDaily(t *testing.T) {
+	const existing = "# 2025-01-15-Wed\n\n## Tasks\n\n- [ ] Keep me\n"
+
+	testCases := []struct {
+		mode        string
+		wantErr     bool
+		wantContent string
+	}{
+		{mode: "", wantContent: existing},
+		{mode: config.OnExistingDailyOpen, wantContent: existing},
+		{mode: config.OnExistingDailyAppendSections, wantContent: existing + "\n## Notes\n\n"},
+		{mode: config.OnExistingDailyError, wantErr: true, wantContent: existing},
+	}
+
+	for _, tc := range testCases {
+		t.Run("mode="+tc.mode, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			cfg := createTestConfigForDaily(t, tmpDir)
+			cfg.Format.TaskSection = "Tasks"
+			cfg.Format.DailyNoteSections = []string{"Notes", "Tasks"}
+			cfg.Format.OnExistingDaily = tc.mode
+
+			date := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+			notePath := notes.BuildDailyNotePath(cfg.DiaryPath, date)
+			if err := notes.WriteNote(context.Background(), notePath, existing); err != nil {
+				t.Fatalf("Failed to write note: %v", err)
+			}
+
+			err := prepareDailyNote(context.Background(), cfg, notePath, date)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("prepareDailyNote() error = %v, wantErr %v", err, tc.wantErr)
+			}
+
+			content, err := os.ReadFile(notePath)
+			if err != nil {
+				t.Fatalf("Failed to read note: %v", err)
+			}
+			if string(content) != tc.wantContent {
+				t.Errorf("note content = %q, want %q", content, tc.wantContent)
+			}
+		})
+	}
+}
+
+// TestPrepareDailyNote_NoCreate tests that --no-create fails on a missing
+// note without creating it.
+func TestPrepareDailyNote_NoCreate(t *testing.T) {
+	defer func() { dailyNoCreate = false }()
+	dailyNoCreate = true
+
+	tmpDir := t.TempDir()
+	cfg := createTestConfigForDaily(t, tmpDir)
+
+	date := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+	notePath := notes.BuildDailyNotePath(cfg.DiaryPath, date)
+
+	err := prepareDailyNote(context.Background(), cfg, notePath, date)
+	if err == nil {
+		t.Fatal("prepareDailyNote() error = nil, want an error for a missing note")
+	}
+	if !strings.Contains(err.Error(), "doesn't exist") {
+		t.Errorf("error = %q, want it to say the note doesn't exist", err)
+	}
+	if _, statErr := os.Stat(notePath); !os.IsNotExist(statErr) {
+		t.Errorf("daily note was created with --no-create: %v", statErr)
+	}
+}
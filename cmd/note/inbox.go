@@ -0,0 +1,286 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/AnishShah1803/jotr/internal/config"
+	"github.com/AnishShah1803/jotr/internal/constants"
+	"github.com/AnishShah1803/jotr/internal/notes"
+	"github.com/AnishShah1803/jotr/internal/services"
+	"github.com/AnishShah1803/jotr/internal/utils"
+)
+
+// inboxTimestampLayout is the timestamp appended to inbox items. Unlike daily
+// note captures it includes the date, since the inbox spans many days.
+const inboxTimestampLayout = "2006-01-02 15:04"
+
+// inboxTimestampRegex matches the timestamp suffix written by appendToInbox.
+var inboxTimestampRegex = regexp.MustCompile(`\s+\(\d{4}-\d{2}-\d{2} \d{2}:\d{2}\)$`)
+
+var InCmd = &cobra.Command{
+	Use:   "in [text]",
+	Short: "Capture a thought to the inbox",
+	Long: `Append a timestamped item to the inbox file, separate from daily notes.
+Process the inbox later with "jotr inbox process".
+
+The inbox file is inbox.md in the notes directory unless paths.inbox_file
+is set.
+
+Examples:
+  jotr in "Look into standing desks"
+  jotr in Call the dentist`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return fmt.Errorf("text to capture is required")
+		}
+
+		cfg, err := config.LoadWithContext(cmd.Context(), "")
+		if err != nil {
+			return err
+		}
+
+		return appendToInbox(cmd.Context(), cfg, strings.Join(args, " "))
+	},
+}
+
+var InboxCmd = &cobra.Command{
+	Use:   "inbox process",
+	Short: "Process items captured to the inbox",
+	Long: `Work through the inbox one item at a time, turning each into a task on
+the todo list or a new note, skipping it, or deleting it. Converted and
+deleted items are removed from the inbox; skipped items stay.
+
+Examples:
+  jotr inbox process`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if args[0] != "process" {
+			return fmt.Errorf("unknown inbox action: %s (expected process)", args[0])
+		}
+
+		cfg, err := config.LoadWithContext(cmd.Context(), "")
+		if err != nil {
+			return err
+		}
+
+		return processInbox(cmd.Context(), cfg, defaultReader)
+	},
+}
+
+// appendToInbox adds a timestamped bullet to the end of the inbox file,
+// creating the file if needed.
+func appendToInbox(ctx context.Context, cfg *config.LoadedConfig, text string) error {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return fmt.Errorf("text to capture is required")
+	}
+
+	content := "# Inbox\n\n"
+	if utils.FileExists(cfg.InboxPath) {
+		data, err := os.ReadFile(cfg.InboxPath)
+		if err != nil {
+			return fmt.Errorf("failed to read inbox: %w", err)
+		}
+		content = string(data)
+		if content != "" && !strings.HasSuffix(content, "\n") {
+			content += "\n"
+		}
+	}
+
+	item := fmt.Sprintf("- %s (%s)", text, time.Now().Format(inboxTimestampLayout))
+	content += item + "\n"
+
+	if err := notes.WriteNote(ctx, cfg.InboxPath, content); err != nil {
+		return fmt.Errorf("failed to write inbox: %w", err)
+	}
+
+	fmt.Printf("✓ Added to inbox: %s\n", cfg.InboxPath)
+	fmt.Printf("  %s\n", item)
+
+	return nil
+}
+
+// inboxItem is a top-level bullet in the inbox file.
+type inboxItem struct {
+	line int
+	text string
+}
+
+// parseInboxItems returns the top-level bullets in the inbox, with their
+// timestamps removed. Task checkboxes are not inbox items.
+func parseInboxItems(lines []string) []inboxItem {
+	var items []inboxItem
+	for i, line := range lines {
+		if !strings.HasPrefix(line, "- ") || strings.HasPrefix(line, "- [") {
+			continue
+		}
+		text := inboxTimestampRegex.ReplaceAllString(strings.TrimPrefix(line, "- "), "")
+		if text = strings.TrimSpace(text); text != "" {
+			items = append(items, inboxItem{line: i, text: text})
+		}
+	}
+	return items
+}
+
+// processInbox prompts for what to do with each inbox item and rewrites the
+// inbox without the items that were converted or deleted. The inbox is read
+// again before it is rewritten, so items captured while processing are kept.
+func processInbox(ctx context.Context, cfg *config.LoadedConfig, reader Reader) error {
+	if !utils.FileExists(cfg.InboxPath) {
+		fmt.Println("✓ Inbox is empty")
+		return nil
+	}
+
+	data, err := os.ReadFile(cfg.InboxPath)
+	if err != nil {
+		return fmt.Errorf("failed to read inbox: %w", err)
+	}
+
+	lines := strings.Split(string(data), "\n")
+	items := parseInboxItems(lines)
+	if len(items) == 0 {
+		fmt.Println("✓ Inbox is empty")
+		return nil
+	}
+
+	remove := make(map[int]bool)
+	var processErr error
+
+items:
+	for i, item := range items {
+		fmt.Printf("\n[%d/%d] %s\n", i+1, len(items), item.text)
+		fmt.Print("(t)ask, (n)ote, (s)kip, (d)elete, (q)uit: ")
+
+		input, err := reader.ReadString('\n')
+		if err != nil {
+			break
+		}
+
+		switch strings.ToLower(strings.TrimSpace(input)) {
+		case "t", "task":
+			if err := inboxItemToTask(ctx, cfg, item.text); err != nil {
+				processErr = err
+				break items
+			}
+			remove[item.line] = true
+		case "n", "note":
+			created, err := inboxItemToNote(ctx, cfg, item.text, reader)
+			if err != nil {
+				processErr = err
+				break items
+			}
+			if created {
+				remove[item.line] = true
+			}
+		case "d", "delete":
+			remove[item.line] = true
+		case "q", "quit":
+			break items
+		default:
+			// Anything else, including "s", leaves the item in the inbox.
+		}
+	}
+
+	left := len(items) - len(remove)
+	if len(remove) > 0 {
+		processed := make(map[string]int, len(remove))
+		for line := range remove {
+			processed[lines[line]]++
+		}
+
+		current, err := os.ReadFile(cfg.InboxPath)
+		if err != nil {
+			return fmt.Errorf("failed to read inbox: %w", err)
+		}
+
+		kept := removeInboxLines(strings.Split(string(current), "\n"), processed)
+		if err := utils.AtomicWriteFile(cfg.InboxPath, []byte(strings.Join(kept, "\n")), constants.FilePerm0644); err != nil {
+			return fmt.Errorf("failed to write inbox: %w", err)
+		}
+		left = len(parseInboxItems(kept))
+	}
+
+	fmt.Printf("\n✓ Processed %d item(s), %d left in inbox\n", len(remove), left)
+
+	return processErr
+}
+
+// removeInboxLines returns lines without the processed ones, matched by
+// content. Each entry in processed removes that many matching lines, so a
+// duplicate item that was skipped is kept.
+func removeInboxLines(lines []string, processed map[string]int) []string {
+	kept := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if processed[line] > 0 {
+			processed[line]--
+			continue
+		}
+		kept = append(kept, line)
+	}
+	return kept
+}
+
+// inboxItemToTask adds an inbox item to the todo list's task section.
+func inboxItemToTask(ctx context.Context, cfg *config.LoadedConfig, text string) error {
+	task, err := services.NewTaskService().AddTask(ctx, services.AddTaskOptions{
+		TodoPath:               cfg.TodoPath,
+		StatePath:              cfg.StatePath,
+		Text:                   text,
+		Section:                cfg.Format.TaskSection,
+		TodoFormat:             services.NewTodoFormat(cfg.Format),
+		RequireExistingSection: !cfg.Format.AutoCreateSectionsEnabled(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add task: %w", err)
+	}
+
+	fmt.Printf("✓ Added task to %s: %s (id: %s)\n", task.Section, task.Text, task.ID)
+
+	return nil
+}
+
+// inboxItemToNote prompts for a note name and creates a note holding the
+// item. It reports false without an error if the name is empty or taken, so
+// the item stays in the inbox.
+func inboxItemToNote(ctx context.Context, cfg *config.LoadedConfig, text string, reader Reader) (bool, error) {
+	fmt.Print("Note name: ")
+
+	input, err := reader.ReadString('\n')
+	if err != nil {
+		return false, fmt.Errorf("failed to read note name: %w", err)
+	}
+
+	name := strings.TrimSpace(input)
+	if name == "" {
+		fmt.Println("No name given, keeping item in inbox")
+		return false, nil
+	}
+
+	fileName, err := utils.SanitizeFilename(name, cfg.Format.FilenameReplacementString())
+	if err != nil {
+		fmt.Printf("%v, keeping item in inbox\n", err)
+		return false, nil
+	}
+
+	notePath := filepath.Join(cfg.Paths.BaseDir, fileName+".md")
+	if utils.FileExists(notePath) {
+		fmt.Printf("Note already exists: %s, keeping item in inbox\n", notePath)
+		return false, nil
+	}
+
+	if err := notes.WriteNote(ctx, notePath, fmt.Sprintf("# %s\n\n%s\n", name, text)); err != nil {
+		return false, fmt.Errorf("failed to create note: %w", err)
+	}
+
+	fmt.Printf("✓ Created: %s\n", notePath)
+
+	return true, nil
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/AnishShah1803/jotr/internal/config"
+	"github.com/AnishShah1803/jotr/internal/notes"
+)
+
+var lastPrint bool
+
+var LastCmd = &cobra.Command{
+	Use:   "last",
+	Short: "Open the most recently modified note",
+	Long: `Open the note you were most recently working on.
+
+Finds the markdown note with the newest modification time and opens it
+in your editor.
+
+Examples:
+  jotr last            # Open the last edited note
+  jotr last --print    # Print its path instead`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.LoadWithContext(cmd.Context(), "")
+		if err != nil {
+			return err
+		}
+
+		return openLastNote(cmd.Context(), cfg)
+	},
+}
+
+func init() {
+	LastCmd.Flags().BoolVar(&lastPrint, "print", false, "Print the note path instead of opening it")
+}
+
+// findLastModifiedNote returns the note under dir with the newest mtime.
+// Only markdown files are considered, so the state, alias and shortcut JSON
+// files are never selected.
+func findLastModifiedNote(ctx context.Context, dir string) (string, error) {
+	allNotes, err := notes.FindNotes(ctx, dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to find notes: %w", err)
+	}
+
+	var newest string
+	var newestInfo os.FileInfo
+
+	for _, notePath := range allNotes {
+		info, err := os.Stat(notePath)
+		if err != nil {
+			continue
+		}
+
+		if newestInfo == nil || info.ModTime().After(newestInfo.ModTime()) {
+			newest = notePath
+			newestInfo = info
+		}
+	}
+
+	if newest == "" {
+		return "", fmt.Errorf("no notes found in %s", dir)
+	}
+
+	return newest, nil
+}
+
+func openLastNote(ctx context.Context, cfg *config.LoadedConfig) error {
+	notePath, err := findLastModifiedNote(ctx, cfg.Paths.BaseDir)
+	if err != nil {
+		return err
+	}
+
+	if lastPrint {
+		fmt.Println(notePath)
+		return nil
+	}
+
+	return notes.OpenInEditorWithContext(ctx, notePath)
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/AnishShah1803/jotr/internal/config"
+)
+
+var LogCmd = &cobra.Command{
+	Use:   "log [text]",
+	Short: "Append a timestamped entry to today's log",
+	Long: `Append a timestamped line to the log section of today's daily note.
+
+Entries go to the section named by format.log_section ("Log" by default),
+which is created if it doesn't exist. Each entry starts with the current
+time formatted with format.log_timestamp ("15:04" by default).
+
+Examples:
+  jotr log "started deploy"
+  jotr log finished deploy`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return fmt.Errorf("text to log is required")
+		}
+
+		cfg, err := config.LoadWithContext(cmd.Context(), "")
+		if err != nil {
+			return err
+		}
+
+		return logEntry(cmd.Context(), cfg, time.Now(), strings.Join(args, " "))
+	},
+}
+
+func logEntry(ctx context.Context, cfg *config.LoadedConfig, now time.Time, text string) error {
+	line := fmt.Sprintf("- %s %s", now.Format(cfg.Format.LogTimestampLayout()), text)
+
+	notePath, err := appendToDailySection(ctx, cfg, now, cfg.Format.LogSectionName(), line)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ Logged to: %s\n", notePath)
+	fmt.Printf("  %s\n", line)
+
+	return nil
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/AnishShah1803/jotr/internal/config"
+	"github.com/AnishShah1803/jotr/internal/hooks"
 	"github.com/AnishShah1803/jotr/internal/notes"
 	"github.com/AnishShah1803/jotr/internal/utils"
 )
@@ -102,11 +103,16 @@ func createNoteWithReader(ctx context.Context, cfg *config.LoadedConfig, noteTyp
 		return fmt.Errorf("note name is required")
 	}
 
+	fileName, err := utils.SanitizeFilename(name, cfg.Format.FilenameReplacementString())
+	if err != nil {
+		return fmt.Errorf("invalid note name: %w", err)
+	}
+
 	var notePath string
 	if noteType != "" {
-		notePath = filepath.Join(cfg.Paths.BaseDir, noteType, name+".md")
+		notePath = filepath.Join(cfg.Paths.BaseDir, noteType, fileName+".md")
 	} else {
-		notePath = filepath.Join(cfg.Paths.BaseDir, name+".md")
+		notePath = filepath.Join(cfg.Paths.BaseDir, fileName+".md")
 	}
 
 	if utils.FileExists(notePath) {
@@ -120,6 +126,7 @@ func createNoteWithReader(ctx context.Context, cfg *config.LoadedConfig, noteTyp
 	}
 
 	fmt.Printf("✓ Created: %s\n", notePath)
+	hooks.RunOrWarn(ctx, os.Stderr, cfg.Hooks.PostCreateNote, cfg.Paths.BaseDir, notePath)
 
 	return notes.OpenInEditor(notePath)
 }
@@ -138,22 +145,7 @@ func openNoteWithReader(ctx context.Context, cfg *config.LoadedConfig, query str
 		return fmt.Errorf("no notes found")
 	}
 
-	// If query provided, filter notes
-	var matches []string
-
-	if query != "" {
-		query = strings.ToLower(query)
-
-		for _, notePath := range allNotes {
-			name := strings.ToLower(filepath.Base(notePath))
-			if strings.Contains(name, query) {
-				matches = append(matches, notePath)
-			}
-		}
-	} else {
-		matches = allNotes
-	}
-
+	matches := matchNoteFiles(allNotes, query)
 	if len(matches) == 0 {
 		return fmt.Errorf("no notes found matching: %s", query)
 	}
@@ -191,6 +183,30 @@ func openNoteWithReader(ctx context.Context, cfg *config.LoadedConfig, query str
 	return notes.OpenInEditor(matches[selection-1])
 }
 
+// matchNoteFiles returns the candidates whose file name contains query,
+// case-insensitively. Only regular .md files are kept, so a directory (or a
+// symlink to one) that happens to match is never offered for opening.
+func matchNoteFiles(candidates []string, query string) []string {
+	query = strings.ToLower(query)
+
+	var matches []string
+	for _, notePath := range candidates {
+		if !strings.EqualFold(filepath.Ext(notePath), ".md") {
+			continue
+		}
+		if !strings.Contains(strings.ToLower(filepath.Base(notePath)), query) {
+			continue
+		}
+		info, err := os.Stat(notePath)
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		matches = append(matches, notePath)
+	}
+
+	return matches
+}
+
 func listNotes(ctx context.Context, cfg *config.LoadedConfig) error {
 	allNotes, err := notes.FindNotes(ctx, cfg.Paths.BaseDir)
 	if err != nil {
@@ -7,10 +7,12 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/AnishShah1803/jotr/internal/config"
 	"github.com/AnishShah1803/jotr/internal/constants"
 	"github.com/AnishShah1803/jotr/internal/notes"
+	"github.com/AnishShah1803/jotr/internal/testhelpers"
 	"github.com/AnishShah1803/jotr/internal/utils"
 )
 
@@ -578,6 +580,62 @@ func TestCreateNoteWithReader_EmptyName(t *testing.T) {
 	}
 }
 
+// TestCreateNoteWithReader_SanitizesName tests that unsafe characters in a
+// note name are replaced in the file name but kept in the title.
+func TestCreateNoteWithReader_SanitizesName(t *testing.T) {
+	origEditor := os.Getenv("EDITOR")
+	os.Setenv("EDITOR", "true")
+	defer os.Setenv("EDITOR", origEditor)
+
+	underscore := "_"
+
+	testCases := []struct {
+		name        string
+		input       string
+		replacement *string
+		wantFile    string
+		wantTitle   string
+		wantErr     bool
+	}{
+		{name: "slashes", input: "Q3/Q4 plans: draft\n", wantFile: "Q3-Q4 plans- draft.md", wantTitle: "# Q3/Q4 plans: draft"},
+		{name: "configured replacement", input: "a/b\n", replacement: &underscore, wantFile: "a_b.md", wantTitle: "# a/b"},
+		{name: "trailing spaces", input: "Meeting notes   \n", wantFile: "Meeting notes.md", wantTitle: "# Meeting notes"},
+		{name: "emoji at the end", input: "Party 🎉\n", wantFile: "Party.md", wantTitle: "# Party 🎉"},
+		{name: "sanitizes to empty", input: "///\n", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			cfg := createTestConfig(t, tmpDir)
+			cfg.Format.FilenameReplacement = tc.replacement
+
+			err := createNoteWithReader(context.Background(), cfg, "", newMockReader(tc.input))
+			if tc.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "invalid note name") {
+					t.Errorf("createNoteWithReader() error = %v, want an invalid note name error", err)
+				}
+				entries, _ := os.ReadDir(tmpDir)
+				if len(entries) != 0 {
+					t.Errorf("expected no files to be created, found %d", len(entries))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("createNoteWithReader() error = %v", err)
+			}
+
+			content, err := os.ReadFile(filepath.Join(tmpDir, tc.wantFile))
+			if err != nil {
+				t.Fatalf("expected note %s: %v", tc.wantFile, err)
+			}
+			if !strings.HasPrefix(string(content), tc.wantTitle+"\n") {
+				t.Errorf("note content = %q, want title %q", content, tc.wantTitle)
+			}
+		})
+	}
+}
+
 // TestCreateNoteWithReader_AlreadyExists tests that creating duplicate note returns error.
 func TestCreateNoteWithReader_AlreadyExists(t *testing.T) {
 	tmpDir, err := os.MkdirTemp("", "jotr-note-test-")
@@ -718,6 +776,53 @@ func TestOpenNoteWithReader_NoMatch(t *testing.T) {
 	}
 }
 
+// TestOpenNoteWithReader_SkipsMatchingDirectory tests that a directory sharing
+// the query's name is never treated as a note.
+func TestOpenNoteWithReader_SkipsMatchingDirectory(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := createTestConfig(t, tmpDir)
+
+	ctx := context.Background()
+
+	notePath := filepath.Join(tmpDir, "meeting-notes.md")
+	if err := notes.WriteNote(ctx, notePath, "# Meeting\n"); err != nil {
+		t.Fatalf("Failed to create note: %v", err)
+	}
+
+	// A directory and a symlink to it, both matching the query by name.
+	dirPath := filepath.Join(tmpDir, "meeting")
+	if err := os.MkdirAll(dirPath, 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := os.Symlink(dirPath, filepath.Join(tmpDir, "meeting-archive.md")); err != nil {
+		t.Fatalf("Failed to create symlink: %v", err)
+	}
+
+	allNotes, err := notes.FindNotes(ctx, tmpDir)
+	if err != nil {
+		t.Fatalf("FindNotes() error = %v", err)
+	}
+
+	matches := matchNoteFiles(allNotes, "meeting")
+	if len(matches) != 1 || matches[0] != notePath {
+		t.Errorf("matchNoteFiles() = %v; want [%s]", matches, notePath)
+	}
+
+	origEditor := os.Getenv("EDITOR")
+	os.Setenv("EDITOR", "true")
+	defer os.Setenv("EDITOR", origEditor)
+
+	// No reader input: a single match must open without prompting.
+	if err := openNoteWithReader(ctx, cfg, "meeting", newMockReader()); err != nil {
+		t.Errorf("openNoteWithReader() error = %v; want the single note opened", err)
+	}
+
+	err = openNoteWithReader(ctx, cfg, "archive", newMockReader())
+	if err == nil || !strings.Contains(err.Error(), "no notes found matching") {
+		t.Errorf("openNoteWithReader() error = %v, want message containing 'no notes found matching'", err)
+	}
+}
+
 // TestOpenNoteWithReader_InvalidSelection tests handling of invalid selection input.
 func TestOpenNoteWithReader_InvalidSelection(t *testing.T) {
 	tmpDir, err := os.MkdirTemp("", "jotr-note-test-")
@@ -806,3 +911,88 @@ func TestOpenNoteWithReader_EmptyNotes(t *testing.T) {
 		t.Errorf("openNoteWithReader() error = %v, want message containing 'no notes found'", err)
 	}
 }
+
+func TestOpenLastNote_PrintsNewest(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := createTestConfig(t, tmpDir)
+
+	base := time.Now().Add(-time.Hour)
+	files := []struct {
+		name string
+		age  time.Duration
+	}{
+		{"old.md", 30 * time.Minute},
+		{"Diary/2025/01-Jan/2025-01-15-Wed.md", 10 * time.Minute},
+		{"middle.md", 20 * time.Minute},
+		{".shortcuts.json", 0},
+	}
+
+	for _, f := range files {
+		path := filepath.Join(tmpDir, f.name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte("content"), constants.FilePerm0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", f.name, err)
+		}
+		mtime := base.Add(time.Hour - f.age)
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatalf("Failed to set mtime: %v", err)
+		}
+	}
+
+	lastPrint = true
+	defer func() { lastPrint = false }()
+
+	var runErr error
+	output := testhelpers.CaptureStdout(func() {
+		runErr = openLastNote(context.Background(), cfg)
+	})
+	if runErr != nil {
+		t.Fatalf("openLastNote() error = %v", runErr)
+	}
+
+	want := filepath.Join(tmpDir, "Diary/2025/01-Jan/2025-01-15-Wed.md")
+	if strings.TrimSpace(output) != want {
+		t.Errorf("openLastNote() printed %q, want %q", strings.TrimSpace(output), want)
+	}
+}
+
+func TestCreateNoteWithReader_PostCreateHook(t *testing.T) {
+	origEditor := os.Getenv("EDITOR")
+	os.Setenv("EDITOR", "true")
+	defer os.Setenv("EDITOR", origEditor)
+
+	tmpDir := t.TempDir()
+	hookDir := t.TempDir()
+	logPath := filepath.Join(hookDir, "hook.log")
+	hookPath := filepath.Join(hookDir, "hook.sh")
+	script := "#!/bin/sh\necho \"$1|$JOTR_PATH\" >> " + logPath + "\n"
+	if err := os.WriteFile(hookPath, []byte(script), 0755); err != nil {
+		t.Fatalf("Failed to write hook: %v", err)
+	}
+
+	cfg := createTestConfig(t, tmpDir)
+	cfg.Hooks.PostCreateNote = hookPath
+
+	if err := createNoteWithReader(context.Background(), cfg, "", newMockReader("Hooked\n")); err != nil {
+		t.Fatalf("createNoteWithReader() error = %v", err)
+	}
+
+	notePath := filepath.Join(tmpDir, "Hooked.md")
+	logged, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("hook was not run: %v", err)
+	}
+	if want := notePath + "|" + notePath + "\n"; string(logged) != want {
+		t.Errorf("hook log = %q, want %q", logged, want)
+	}
+
+	cfg.Hooks.PostCreateNote = "false"
+	if err := createNoteWithReader(context.Background(), cfg, "", newMockReader("Failing hook\n")); err != nil {
+		t.Errorf("createNoteWithReader() with a failing hook error = %v, want nil", err)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "Failing hook.md")); err != nil {
+		t.Errorf("note was not created when the hook failed: %v", err)
+	}
+}
@@ -3,6 +3,7 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"os"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -36,6 +37,7 @@ import (
 
 var updateFlag bool
 var configPath string
+var baseDir string
 var timeout time.Duration
 
 var rootCmd = &cobra.Command{
@@ -47,8 +49,10 @@ It supports daily notes, task management, templates, search, and much more.
 When run without arguments, jotr launches the interactive dashboard.`,
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
 		verbose, _ := cmd.Flags().GetBool("verbose")
+		quiet, _ := cmd.Flags().GetBool("quiet")
 		timeout, _ := cmd.Flags().GetDuration("timeout")
 		configPath, _ := cmd.Flags().GetString("config")
+		baseDir, _ := cmd.Flags().GetString("base-dir")
 
 		var ctx context.Context
 		if timeout > 0 {
@@ -60,9 +64,13 @@ When run without arguments, jotr launches the interactive dashboard.`,
 			ctx = context.Background()
 		}
 
-		if verbose {
+		switch {
+		case verbose:
+			utils.SetLogLevel(utils.LevelDebug)
 			ctx = utils.WithVerboseContext(ctx, true)
 			utils.VerboseLogWithContext(ctx, "Starting jotr with verbose mode enabled")
+		case quiet:
+			utils.SetLogLevel(utils.LevelError)
 		}
 
 		ctx = config.WithConfig(ctx, configPath)
@@ -70,6 +78,21 @@ When run without arguments, jotr launches the interactive dashboard.`,
 			utils.VerboseLog("Config path set to: %s", configPath)
 		}
 
+		if baseDir != "" {
+			ctx = config.WithBaseDir(ctx, baseDir)
+			utils.VerboseLog("Base directory overridden to: %s", baseDir)
+		}
+
+		// Configured flag defaults; commands that run without a config
+		// (such as configure) simply get none. The config is kept in the
+		// context so the command does not load it again.
+		if cfg, err := config.LoadWithContext(ctx, ""); err == nil {
+			ctx = config.WithLoadedConfig(ctx, cfg)
+			if err := applyFlagDefaults(cmd, cfg.Defaults); err != nil {
+				fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+			}
+		}
+
 		cmd.SetContext(ctx)
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -110,7 +133,10 @@ func Execute() error {
 func init() {
 	// Global flags
 	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "verbose output")
+	rootCmd.PersistentFlags().BoolP("quiet", "q", false, "only log errors")
+	rootCmd.MarkFlagsMutuallyExclusive("verbose", "quiet")
 	rootCmd.PersistentFlags().StringVar(&configPath, "config", "", "config file path")
+	rootCmd.PersistentFlags().StringVar(&baseDir, "base-dir", "", "notes directory to use instead of paths.base_dir from config")
 	rootCmd.PersistentFlags().DurationVar(&timeout, "timeout", 0, "command timeout (e.g., 30s, 5m)")
 	rootCmd.Flags().BoolVar(&updateFlag, "update", false, "check for and install updates")
 
@@ -118,13 +144,24 @@ func init() {
 	rootCmd.AddCommand(notecmd.DailyCmd)
 	rootCmd.AddCommand(notecmd.NoteCmd)
 	rootCmd.AddCommand(notecmd.CaptureCmd)
+	rootCmd.AddCommand(notecmd.LogCmd)
+	rootCmd.AddCommand(notecmd.InCmd)
+	rootCmd.AddCommand(notecmd.InboxCmd)
+	rootCmd.AddCommand(notecmd.LastCmd)
 	rootCmd.AddCommand(notecmd.TemplateCmd)
+	rootCmd.AddCommand(notecmd.AttachCmd)
 
 	// Task Management
 	rootCmd.AddCommand(taskcmd.SyncCmd)
 	rootCmd.AddCommand(taskcmd.SummaryCmd)
 	rootCmd.AddCommand(taskcmd.StatsCmd)
 	rootCmd.AddCommand(taskcmd.ArchiveCmd)
+	rootCmd.AddCommand(taskcmd.TasksCmd)
+	rootCmd.AddCommand(taskcmd.AddCmd)
+	rootCmd.AddCommand(taskcmd.DoneCmd)
+	rootCmd.AddCommand(taskcmd.FocusCmd)
+	rootCmd.AddCommand(taskcmd.StatusCmd)
+	rootCmd.AddCommand(taskcmd.StateCmd)
 
 	// Search and Navigation
 	rootCmd.AddCommand(searchcmd.SearchCmd)
@@ -137,20 +174,26 @@ func init() {
 	rootCmd.AddCommand(visualcmd.StreakCmd)
 	rootCmd.AddCommand(visualcmd.GraphCmd)
 	rootCmd.AddCommand(visualcmd.DashboardCmd)
+	rootCmd.AddCommand(visualcmd.ReportCmd)
+	rootCmd.AddCommand(visualcmd.BoardCmd)
 
 	// Productivity Features
 	rootCmd.AddCommand(systemcmd.AliasCmd)
 	rootCmd.AddCommand(systemcmd.ShortcutCmd)
 	rootCmd.AddCommand(systemcmd.ScheduleCmd)
+	rootCmd.AddCommand(systemcmd.SettingsCmd)
 	rootCmd.AddCommand(systemcmd.MonthlyCmd)
 	rootCmd.AddCommand(systemcmd.FrontmatterCmd)
 
 	// Utilities
 	rootCmd.AddCommand(utilcmd.BulkCmd)
+	rootCmd.AddCommand(utilcmd.ReplaceCmd)
+	rootCmd.AddCommand(utilcmd.ImportCmd)
 	rootCmd.AddCommand(utilcmd.GitCmd)
 	rootCmd.AddCommand(utilcmd.QuickCmd)
 	rootCmd.AddCommand(utilcmd.CheckCmd)
 	rootCmd.AddCommand(utilcmd.ValidateCmd)
+	rootCmd.AddCommand(utilcmd.LintCmd)
 
 	// Templates
 	rootCmd.AddCommand(templatecmd.TemplateCmd)
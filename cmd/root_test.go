@@ -1,9 +1,13 @@
 package cmd
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/spf13/cobra"
+
+	"github.com/AnishShah1803/jotr/internal/testhelpers"
+	"github.com/AnishShah1803/jotr/internal/utils"
 )
 
 func TestRootCommand(t *testing.T) {
@@ -33,3 +37,124 @@ func TestCommandExecution(t *testing.T) {
 		t.Errorf("Command execution failed: %v", err)
 	}
 }
+
+func TestVerboseAndQuietFlags(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		wantVerbose bool
+	}{
+		{"default", []string{"version"}, false},
+		{"verbose", []string{"--verbose", "version"}, true},
+		{"quiet", []string{"-q", "version"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			utils.ResetLogLevel()
+			defer utils.ResetLogLevel()
+
+			rootCmd.SetArgs(tt.args)
+			defer rootCmd.SetArgs(nil)
+			defer func() {
+				for _, name := range []string{"verbose", "quiet"} {
+					flag := rootCmd.PersistentFlags().Lookup(name)
+					flag.Value.Set("false")
+					flag.Changed = false
+				}
+			}()
+
+			var err error
+			stderr := testhelpers.CaptureStderr(func() {
+				testhelpers.CaptureStdout(func() {
+					err = rootCmd.Execute()
+				})
+				utils.VerboseLog("probe message")
+			})
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+
+			if got := strings.Contains(stderr, "probe message"); got != tt.wantVerbose {
+				t.Errorf("verbose output shown = %v; want %v (stderr: %q)", got, tt.wantVerbose, stderr)
+			}
+		})
+	}
+}
+
+func TestApplyFlagDefaults(t *testing.T) {
+	defaults := map[string]map[string]any{
+		"list": {"with_tasks": true, "depth": float64(2)},
+	}
+
+	tests := []struct {
+		name          string
+		args          []string
+		wantWithTasks bool
+		wantDepth     int
+	}{
+		{"configured defaults", nil, true, 2},
+		{"explicit flag wins", []string{"--with-tasks=false"}, false, 2},
+		{"explicit value wins", []string{"--depth", "5"}, true, 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var withTasks bool
+			var depth int
+			cmd := &cobra.Command{Use: "list"}
+			cmd.Flags().BoolVar(&withTasks, "with-tasks", false, "")
+			cmd.Flags().IntVar(&depth, "depth", 0, "")
+
+			if err := cmd.ParseFlags(tt.args); err != nil {
+				t.Fatalf("ParseFlags() error = %v", err)
+			}
+			if err := applyFlagDefaults(cmd, defaults); err != nil {
+				t.Fatalf("applyFlagDefaults() error = %v", err)
+			}
+
+			if withTasks != tt.wantWithTasks || depth != tt.wantDepth {
+				t.Errorf("with-tasks = %v, depth = %d; want %v, %d", withTasks, depth, tt.wantWithTasks, tt.wantDepth)
+			}
+			if len(tt.args) == 0 && cmd.Flags().Changed("with-tasks") {
+				t.Error("a configured default should not mark the flag as changed")
+			}
+		})
+	}
+
+	cmd := &cobra.Command{Use: "list"}
+	err := applyFlagDefaults(cmd, map[string]map[string]any{"list": {"no_such_flag": true}})
+	if err == nil || !strings.Contains(err.Error(), "unknown flag --no-such-flag") {
+		t.Errorf("applyFlagDefaults() error = %v; want unknown flag", err)
+	}
+}
+
+func TestApplyFlagDefaults_KeyedByCommandPath(t *testing.T) {
+	root := &cobra.Command{Use: "jotr"}
+	var templateName, noteName string
+	for _, parent := range []struct {
+		use  string
+		name *string
+	}{{"template", &templateName}, {"note", &noteName}} {
+		edit := &cobra.Command{Use: "edit"}
+		edit.Flags().StringVar(parent.name, "name", "", "")
+		parentCmd := &cobra.Command{Use: parent.use}
+		parentCmd.AddCommand(edit)
+		root.AddCommand(parentCmd)
+	}
+
+	defaults := map[string]map[string]any{"template edit": {"name": "weekly"}}
+	for _, path := range [][]string{{"template", "edit"}, {"note", "edit"}} {
+		cmd, _, err := root.Find(path)
+		if err != nil {
+			t.Fatalf("Find(%v) error = %v", path, err)
+		}
+		if err := applyFlagDefaults(cmd, defaults); err != nil {
+			t.Fatalf("applyFlagDefaults() error = %v", err)
+		}
+	}
+
+	if templateName != "weekly" || noteName != "" {
+		t.Errorf("template edit --name = %q, note edit --name = %q; want \"weekly\" and \"\"", templateName, noteName)
+	}
+}
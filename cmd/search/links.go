@@ -14,16 +14,26 @@ import (
 	"github.com/AnishShah1803/jotr/internal/notes"
 )
 
+var linksCheck bool
+
+func init() {
+	LinksCmd.Flags().BoolVar(&linksCheck, "check", false, "Report attachment embeds that point to missing files")
+}
+
 var LinksCmd = &cobra.Command{
 	Use:   "links [note-name]",
 	Short: "Show links and backlinks",
 	Long: `Show links in a note and backlinks to a note.
+
+With --check, verify that every attachment embed (![[file.png]]) points to
+an existing file instead.
 	
 Examples:
   jotr links MyNote          # Show links in MyNote
-  jotr links --backlinks MyNote  # Show backlinks to MyNote`,
+  jotr links --backlinks MyNote  # Show backlinks to MyNote
+  jotr links --check         # Report missing attachments`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if len(args) == 0 {
+		if len(args) == 0 && !linksCheck {
 			return fmt.Errorf("note name required")
 		}
 
@@ -32,6 +42,10 @@ Examples:
 			return err
 		}
 
+		if linksCheck {
+			return checkAttachments(cmd.Context(), cfg)
+		}
+
 		noteName := args[0]
 		backlinks, _ := cmd.Flags().GetBool("backlinks")
 
@@ -138,3 +152,91 @@ func showBacklinks(ctx context.Context, cfg *config.LoadedConfig, noteName strin
 
 	return nil
 }
+
+// wikiLinkRegex matches a [[target]] link.
+var wikiLinkRegex = regexp.MustCompile(`\[\[([^\]]+)\]\]`)
+
+// linkTargetName returns the lowercased note name a [[target]] link points
+// to, without any |alias, #heading or .md extension.
+func linkTargetName(target string) string {
+	if i := strings.IndexAny(target, "|#"); i >= 0 {
+		target = target[:i]
+	}
+	target = strings.TrimSuffix(strings.TrimSpace(target), ".md")
+	return strings.ToLower(filepath.Base(target))
+}
+
+// noteLinkName returns the name [[links]] use for a note: its lowercased file
+// name without the .md extension.
+func noteLinkName(notePath string) string {
+	return strings.ToLower(strings.TrimSuffix(filepath.Base(notePath), ".md"))
+}
+
+// countBacklinks scans every note once and returns, for each linked note
+// name, how many other notes link to it. A note linking to a target several
+// times counts once, and links from a note to itself are ignored.
+func countBacklinks(ctx context.Context, baseDir string) (map[string]int, error) {
+	allNotes, err := notes.FindNotes(ctx, baseDir)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int)
+
+	for _, note := range allNotes {
+		content, err := os.ReadFile(note)
+		if err != nil {
+			continue
+		}
+
+		self := noteLinkName(note)
+		seen := make(map[string]bool)
+		for _, match := range wikiLinkRegex.FindAllStringSubmatch(string(content), -1) {
+			target := linkTargetName(match[1])
+			if target == "" || target == self || seen[target] {
+				continue
+			}
+			seen[target] = true
+			counts[target]++
+		}
+	}
+
+	return counts, nil
+}
+
+// checkAttachments reports attachment embeds whose file cannot be found and
+// returns an error if there are any.
+func checkAttachments(ctx context.Context, cfg *config.LoadedConfig) error {
+	allNotes, err := notes.FindNotes(ctx, cfg.Paths.BaseDir)
+	if err != nil {
+		return err
+	}
+
+	checked, missing := 0, 0
+
+	for _, note := range allNotes {
+		content, err := os.ReadFile(note)
+		if err != nil {
+			continue
+		}
+
+		for _, embed := range notes.FindAttachmentEmbeds(string(content)) {
+			checked++
+			if notes.AttachmentExists(cfg.Paths.BaseDir, note, embed.Name) {
+				continue
+			}
+
+			missing++
+			relPath, _ := filepath.Rel(cfg.Paths.BaseDir, note)
+			fmt.Printf("  %s:%d  missing attachment %s\n", relPath, embed.Line, embed.Name)
+		}
+	}
+
+	if missing > 0 {
+		return fmt.Errorf("%d of %d attachment embed(s) missing", missing, checked)
+	}
+
+	fmt.Printf("✓ All %d attachment embed(s) found\n", checked)
+
+	return nil
+}
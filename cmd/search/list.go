@@ -3,6 +3,7 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"os"
 	"path/filepath"
 	"time"
 
@@ -11,15 +12,23 @@ import (
 	"github.com/AnishShah1803/jotr/internal/config"
 	"github.com/AnishShah1803/jotr/internal/notes"
 	"github.com/AnishShah1803/jotr/internal/options"
+	"github.com/AnishShah1803/jotr/internal/services"
+	"github.com/AnishShah1803/jotr/internal/tasks"
 	"github.com/AnishShah1803/jotr/internal/utils"
 )
 
 var outputOption = options.NewOutputOption()
 var recentNotesLimit = 5
+var listWithTasks bool
+var listBacklinks bool
+var listDepth int
 
 func init() {
 	outputOption.AddFlags(ListCmd)
 	ListCmd.Flags().IntVar(&recentNotesLimit, "limit", 5, "Number of recent notes to show")
+	ListCmd.Flags().BoolVar(&listWithTasks, "with-tasks", false, "Show a completed/total task badge for each note")
+	ListCmd.Flags().BoolVar(&listBacklinks, "backlinks", false, "Show how many other notes link to each note")
+	ListCmd.Flags().IntVar(&listDepth, "depth", 0, "Only include notes this many directory levels deep (0 = unlimited)")
 }
 
 var ListCmd = &cobra.Command{
@@ -32,6 +41,8 @@ Shows daily notes with dates and status indicators.
 Examples:
   jotr list                   # List last 5 daily notes
   jotr list --files           # List all notes
+  jotr list --with-tasks      # Show [completed/total] task counts
+  jotr list --files --backlinks  # Show incoming link counts
   jotr ls                     # Using alias`,
 	Aliases: []string{"ls"},
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -40,11 +51,22 @@ Examples:
 			return err
 		}
 
-		return listRecentNotes(cmd.Context(), cfg)
+		return listRecentNotes(notes.WithMaxDepth(cmd.Context(), listDepth), cfg)
 	},
 }
 
 func listRecentNotes(ctx context.Context, cfg *config.LoadedConfig) error {
+	taskOpts := services.TaskOptions(cfg.Format)
+
+	var backlinks map[string]int
+	if listBacklinks {
+		counts, err := countBacklinks(ctx, cfg.Paths.BaseDir)
+		if err != nil {
+			return fmt.Errorf("failed to count backlinks: %w", err)
+		}
+		backlinks = counts
+	}
+
 	if outputOption.FilesOnly {
 		allNotes, err := notes.FindNotes(ctx, cfg.Paths.BaseDir)
 		if err != nil {
@@ -55,7 +77,7 @@ func listRecentNotes(ctx context.Context, cfg *config.LoadedConfig) error {
 
 		for _, notePath := range allNotes {
 			relPath, _ := filepath.Rel(cfg.Paths.BaseDir, notePath)
-			fmt.Printf("  %s\n", relPath)
+			fmt.Printf("  %s%s%s\n", relPath, listTaskBadge(notePath, taskOpts), backlinkBadge(backlinks, notePath))
 		}
 
 		return nil
@@ -80,7 +102,7 @@ func listRecentNotes(ctx context.Context, cfg *config.LoadedConfig) error {
 				dateStr += " (yesterday)"
 			}
 
-			fmt.Printf("  %s %s\n", status, dateStr)
+			fmt.Printf("  %s %s%s%s\n", status, dateStr, listTaskBadge(notePath, taskOpts), backlinkBadge(backlinks, notePath))
 
 			foundCount++
 		}
@@ -92,3 +114,37 @@ func listRecentNotes(ctx context.Context, cfg *config.LoadedConfig) error {
 
 	return nil
 }
+
+// listTaskBadge returns a " [completed/total]" badge for the tasks in a note
+// when --with-tasks is set. Notes without tasks or that cannot be read get no badge.
+func listTaskBadge(notePath string, taskOpts tasks.Options) string {
+	if !listWithTasks {
+		return ""
+	}
+
+	content, err := os.ReadFile(notePath)
+	if err != nil {
+		return ""
+	}
+
+	return taskBadge(taskOpts.ParseTasks(string(content)))
+}
+
+func taskBadge(noteTasks []tasks.Task) string {
+	total, completed, _ := tasks.CountTasks(noteTasks)
+	if total == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf(" [%d/%d]", completed, total)
+}
+
+// backlinkBadge returns a " (N backlinks)" badge for a note when --backlinks
+// is set, using counts from countBacklinks.
+func backlinkBadge(counts map[string]int, notePath string) string {
+	if counts == nil {
+		return ""
+	}
+
+	return fmt.Sprintf(" (%d backlinks)", counts[noteLinkName(notePath)])
+}
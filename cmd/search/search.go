@@ -12,17 +12,38 @@ import (
 	"github.com/AnishShah1803/jotr/internal/config"
 	"github.com/AnishShah1803/jotr/internal/notes"
 	"github.com/AnishShah1803/jotr/internal/options"
+	"github.com/AnishShah1803/jotr/internal/utils"
 )
 
 var searchOutputOption = options.NewOutputOption()
 
 var searchCmdFlags = struct {
-	count bool
-	files bool
+	count        bool
+	files        bool
+	withoutMatch bool
+	edit         bool
+	line         bool
+	title        bool
+	depth        int
+	path         string
+	patterns     string
 }{}
 
 func init() {
-	searchOutputOption.AddFlags(SearchCmd)
+	// Not searchOutputOption.AddFlags: --path is the search scope glob here
+	// rather than the path-only output switch.
+	SearchCmd.Flags().BoolVar(&searchOutputOption.CountOnly, "count", false, "Show only the count of matches")
+	SearchCmd.Flags().BoolVar(&searchOutputOption.FilesOnly, "files", false, "Show only file names without content")
+	SearchCmd.Flags().BoolVar(&searchOutputOption.FilesOnly, "files-with-matches", false, "Same as --files")
+	SearchCmd.Flags().BoolVar(&searchCmdFlags.withoutMatch, "files-without-match", false, "Show only the notes that do not contain the query")
+	SearchCmd.Flags().BoolVar(&searchOutputOption.Quiet, "quiet", false, "Suppress normal output")
+	SearchCmd.Flags().BoolVar(&searchOutputOption.JSON, "json", false, "Output in JSON format")
+	SearchCmd.Flags().BoolVar(&searchCmdFlags.edit, "edit", false, "Open every matching file in the editor")
+	SearchCmd.Flags().BoolVar(&searchCmdFlags.line, "line", false, "With --edit, open each file at its first matching line")
+	SearchCmd.Flags().BoolVar(&searchCmdFlags.title, "title", false, "Match only note titles (first # heading) and file names")
+	SearchCmd.Flags().IntVar(&searchCmdFlags.depth, "depth", 0, "Only search notes this many directory levels deep (0 = unlimited)")
+	SearchCmd.Flags().StringVar(&searchCmdFlags.path, "path", "", "Only search notes whose path matches this glob (** matches any directories)")
+	SearchCmd.Flags().StringVar(&searchCmdFlags.patterns, "patterns", "", "Search for each line of this file and group the results by pattern")
 }
 
 func SetSearchCountForTest(count bool) {
@@ -49,10 +70,26 @@ var SearchCmd = &cobra.Command{
 Examples:
   jotr search "meeting notes"    # Search for text
   jotr search --count "TODO"     # Count matches
-  jotr search --files "project"  # Show only filenames`,
+  jotr search --files "project"  # Show only filenames
+  jotr search --files-without-match "#reviewed"  # Notes missing a tag
+  jotr search --edit "project"   # Open all matching files in the editor
+  jotr search --edit --line "project"  # ...each at its first match
+  jotr search --title "roadmap"  # Match note titles and file names only
+  jotr search --depth 1 "idea"   # Skip notes in subdirectories
+  jotr search --path "work/**" "launch"  # Only search notes under work/
+  jotr search --patterns audit.txt       # One query per line, grouped
+
+A --patterns file holds one query per line. Blank lines and comment lines
+starting with "# " are skipped, so "#tag" lines are still searched.
+
+Exit codes:
+  0  one or more matches found (or, with --files-without-match, one or
+     more notes without a match)
+  1  an error occurred
+  2  no matches found`,
 	Aliases: []string{"find", "grep"},
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if len(args) == 0 {
+		if len(args) == 0 && searchCmdFlags.patterns == "" {
 			return fmt.Errorf("search query required")
 		}
 
@@ -61,43 +98,136 @@ Examples:
 			return err
 		}
 
+		if err := notes.ValidatePathGlob(searchCmdFlags.path); err != nil {
+			return err
+		}
+
+		if searchCmdFlags.line && !searchCmdFlags.edit {
+			return fmt.Errorf("--line requires --edit")
+		}
+
 		query := strings.Join(args, " ")
-		return SearchNotes(cmd.Context(), cfg, query)
+		ctx := notes.WithMaxDepth(cmd.Context(), searchCmdFlags.depth)
+		ctx = notes.WithPathGlob(ctx, searchCmdFlags.path)
+
+		var count int
+		if searchCmdFlags.patterns != "" {
+			count, err = searchPatternFile(ctx, cfg, searchCmdFlags.patterns)
+		} else {
+			count, err = searchNotes(ctx, cfg, query)
+		}
+		if err != nil {
+			return err
+		}
+
+		if count == 0 {
+			cmd.SilenceUsage = true
+			cmd.SilenceErrors = true
+			return utils.NewExitError(utils.ExitCodeNoMatches, utils.ErrNoMatches)
+		}
+
+		return nil
 	},
 }
 
 // SearchNotes performs a full-text search across all notes in the configured base directory.
 // It displays matching files with highlighted context lines unless --count or --files flags are used.
+//
+// Results are reported per file: the match count is the number of matching
+// files, --files prints each path at most once, and the default output lists
+// each file once followed by every matching line in it.
 func SearchNotes(ctx context.Context, cfg *config.LoadedConfig, query string) error {
+	_, err := searchNotes(ctx, cfg, query)
+	return err
+}
+
+// uniquePaths returns paths without duplicates, keeping the first occurrence.
+func uniquePaths(paths []string) []string {
+	seen := make(map[string]bool, len(paths))
+	var unique []string
+	for _, path := range paths {
+		if seen[path] {
+			continue
+		}
+		seen[path] = true
+		unique = append(unique, path)
+	}
+	return unique
+}
+
+// searchNotes runs the search and returns the number of matching files, or
+// with --files-without-match the number of notes without a match.
+func searchNotes(ctx context.Context, cfg *config.LoadedConfig, query string) (int, error) {
 	// Skip empty queries
 	if query == "" {
-		return nil
+		return 0, nil
 	}
 
-	matches, err := notes.SearchNotes(ctx, cfg.Paths.BaseDir, query)
+	var matches []string
+	var err error
+	if searchCmdFlags.title {
+		matches, err = notes.SearchNoteTitles(ctx, cfg.Paths.BaseDir, query)
+	} else {
+		matches, err = notes.SearchNotes(ctx, cfg.Paths.BaseDir, query)
+	}
 	if err != nil {
-		return fmt.Errorf("search failed: %w", err)
+		return 0, fmt.Errorf("search failed: %w", err)
+	}
+
+	if searchCmdFlags.withoutMatch {
+		return listFilesWithoutMatch(ctx, cfg, matches)
 	}
 
 	if len(matches) == 0 {
 		fmt.Println("No matches found")
-		return nil
+		return 0, nil
 	}
 
 	// Count only
 	if GetSearchCountForTest() || searchOutputOption.CountOnly {
 		fmt.Printf("%d matches found\n", len(matches))
-		return nil
+		return len(matches), nil
 	}
 
-	// Files only
+	// Files only, each path printed at most once
 	if GetSearchFilesForTest() || searchOutputOption.FilesOnly {
-		for _, match := range matches {
+		for _, match := range uniquePaths(matches) {
 			relPath, _ := filepath.Rel(cfg.Paths.BaseDir, match)
 			fmt.Println(relPath)
 		}
 
-		return nil
+		return len(matches), nil
+	}
+
+	// Open every matching file in the editor
+	if searchCmdFlags.edit {
+		paths := uniquePaths(matches)
+		fmt.Printf("Opening %d files in editor\n", len(paths))
+		if searchCmdFlags.line {
+			err = openAtFirstMatch(ctx, cfg, query, paths)
+		} else {
+			err = notes.OpenFilesInEditor(ctx, paths)
+		}
+		if err != nil {
+			return 0, err
+		}
+
+		return len(matches), nil
+	}
+
+	// Title matches show the title instead of matching body lines
+	if searchCmdFlags.title {
+		fmt.Printf("Found %d matches:\n\n", len(matches))
+		for _, match := range matches {
+			relPath, _ := filepath.Rel(cfg.Paths.BaseDir, match)
+			if title, _ := notes.NoteTitle(match); title != "" {
+				fmt.Printf("📄 %s — %s\n", relPath, title)
+			} else {
+				fmt.Printf("📄 %s\n", relPath)
+			}
+		}
+
+		return len(matches), nil
 	}
 
 	// Full output with context
@@ -128,5 +258,151 @@ func SearchNotes(ctx context.Context, cfg *config.LoadedConfig, query string) er
 		fmt.Println()
 	}
 
+	return len(matches), nil
+}
+
+// openAtFirstMatch opens each path in the editor in turn, at the line of its
+// first match. Title matches have no line and open at the top.
+func openAtFirstMatch(ctx context.Context, cfg *config.LoadedConfig, query string, paths []string) error {
+	firstLine := make(map[string]int)
+	if !searchCmdFlags.title {
+		results, err := notes.SearchNotesDetailed(ctx, cfg.Paths.BaseDir, query)
+		if err != nil {
+			return fmt.Errorf("search failed: %w", err)
+		}
+		for _, result := range results {
+			if _, ok := firstLine[result.Path]; !ok {
+				firstLine[result.Path] = result.LineNumber
+			}
+		}
+	}
+
+	for _, path := range paths {
+		if err := notes.OpenInEditorAtLine(ctx, path, firstLine[path]); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
+
+// readPatterns returns the queries in a --patterns file, one per line,
+// skipping blank lines and "# " comments.
+func readPatterns(path string) ([]string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read patterns: %w", err)
+	}
+
+	var patterns []string
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || line == "#" || strings.HasPrefix(line, "# ") {
+			continue
+		}
+		patterns = append(patterns, line)
+	}
+
+	return patterns, nil
+}
+
+// patternMatches holds the files matching one query from a --patterns file.
+type patternMatches struct {
+	Pattern string
+	Paths   []string
+}
+
+// searchPatterns searches for each pattern in turn, returning the matching
+// files for every pattern, including those with no matches.
+func searchPatterns(ctx context.Context, cfg *config.LoadedConfig, patterns []string) ([]patternMatches, error) {
+	groups := make([]patternMatches, 0, len(patterns))
+	for _, pattern := range patterns {
+		var matches []string
+		var err error
+		if searchCmdFlags.title {
+			matches, err = notes.SearchNoteTitles(ctx, cfg.Paths.BaseDir, pattern)
+		} else {
+			matches, err = notes.SearchNotes(ctx, cfg.Paths.BaseDir, pattern)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("search for %q failed: %w", pattern, err)
+		}
+		groups = append(groups, patternMatches{Pattern: pattern, Paths: uniquePaths(matches)})
+	}
+
+	return groups, nil
+}
+
+// searchPatternFile runs every query in a --patterns file and prints the
+// matching files grouped by pattern, or with --count the number per pattern.
+// It returns the number of pattern/file matches.
+func searchPatternFile(ctx context.Context, cfg *config.LoadedConfig, path string) (int, error) {
+	patterns, err := readPatterns(path)
+	if err != nil {
+		return 0, err
+	}
+	if len(patterns) == 0 {
+		return 0, fmt.Errorf("no patterns in %s", path)
+	}
+
+	groups, err := searchPatterns(ctx, cfg, patterns)
+	if err != nil {
+		return 0, err
+	}
+
+	total := 0
+	for i, group := range groups {
+		total += len(group.Paths)
+
+		if searchOutputOption.CountOnly {
+			fmt.Printf("%s: %d\n", group.Pattern, len(group.Paths))
+			continue
+		}
+
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("🔎 %s (%d)\n", group.Pattern, len(group.Paths))
+		for _, match := range group.Paths {
+			relPath, _ := filepath.Rel(cfg.Paths.BaseDir, match)
+			fmt.Printf("  📄 %s\n", relPath)
+		}
+	}
+
+	return total, nil
+}
+
+// listFilesWithoutMatch prints the notes that are not in matches, relative to
+// the base directory, and returns how many there are. The notes considered
+// are the same ones the search looked at, so --depth and --path still apply.
+func listFilesWithoutMatch(ctx context.Context, cfg *config.LoadedConfig, matches []string) (int, error) {
+	allNotes, err := notes.FindNotes(ctx, cfg.Paths.BaseDir)
+	if err != nil {
+		return 0, fmt.Errorf("search failed: %w", err)
+	}
+
+	matched := make(map[string]bool, len(matches))
+	for _, match := range matches {
+		matched[match] = true
+	}
+
+	count := 0
+	for _, note := range allNotes {
+		if matched[note] {
+			continue
+		}
+		count++
+		if !searchOutputOption.CountOnly && !searchOutputOption.Quiet {
+			relPath, _ := filepath.Rel(cfg.Paths.BaseDir, note)
+			fmt.Println(relPath)
+		}
+	}
+
+	if count == 0 {
+		fmt.Println("Every note contains the query")
+	} else if searchOutputOption.CountOnly {
+		fmt.Printf("%d notes without a match\n", count)
+	}
+
+	return count, nil
+}
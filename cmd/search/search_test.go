@@ -2,15 +2,22 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"sort"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/AnishShah1803/jotr/internal/config"
 	"github.com/AnishShah1803/jotr/internal/notes"
+	"github.com/AnishShah1803/jotr/internal/output"
+	"github.com/AnishShah1803/jotr/internal/tasks"
+	"github.com/AnishShah1803/jotr/internal/testhelpers"
+	"github.com/AnishShah1803/jotr/internal/utils"
 )
 
 // createTestSearchConfig creates a test configuration with a temporary directory.
@@ -425,6 +432,318 @@ func TestSearchNotes_WithFilesFlag(t *testing.T) {
 	}
 }
 
+// TestSearchNotes_FilesFlagDedupesPaths tests that --files lists a note once
+// even when it matches on several lines.
+func TestSearchNotes_FilesFlagDedupesPaths(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := createTestSearchConfig(t, tmpDir)
+
+	createTestNote(t, tmpDir, "Repeated", "# Repeated\n\nTODO one\nTODO two\nTODO three\n")
+
+	SetSearchFilesForTest(true)
+	defer func() { SetSearchFilesForTest(false) }()
+
+	var searchErr error
+	output := testhelpers.CaptureStdout(func() {
+		searchErr = SearchNotes(context.Background(), cfg, "TODO")
+	})
+	if searchErr != nil {
+		t.Fatalf("SearchNotes failed: %v", searchErr)
+	}
+
+	if got := strings.Count(output, "Repeated.md"); got != 1 {
+		t.Errorf("expected Repeated.md exactly once, got %d times in output:\n%s", got, output)
+	}
+}
+
+// TestSearchNotes_FilesWithoutMatch tests that --files-without-match lists
+// exactly the notes that do not contain the query.
+func TestSearchNotes_FilesWithoutMatch(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := createTestSearchConfig(t, tmpDir)
+
+	createTestNote(t, tmpDir, "Reviewed", "# Reviewed\n\nLooks good #reviewed\n")
+	createTestNote(t, tmpDir, "Draft", "# Draft\n\nStill rough.\n")
+	createTestNote(t, tmpDir, "Ideas", "# Ideas\n\nNothing yet.\n")
+
+	searchCmdFlags.withoutMatch = true
+	defer func() { searchCmdFlags.withoutMatch = false }()
+
+	var count int
+	var searchErr error
+	output := testhelpers.CaptureStdout(func() {
+		count, searchErr = searchNotes(context.Background(), cfg, "#reviewed")
+	})
+	if searchErr != nil {
+		t.Fatalf("searchNotes failed: %v", searchErr)
+	}
+
+	got := strings.Fields(output)
+	sort.Strings(got)
+	want := []string{"Draft.md", "Ideas.md"}
+	if !reflect.DeepEqual(got, want) || count != len(want) {
+		t.Errorf("files without match = %v (count %d), want %v", got, count, want)
+	}
+}
+
+// TestSearchPatternFile tests that --patterns searches for each line of the
+// file and groups the matching notes by pattern.
+func TestSearchPatternFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := createTestSearchConfig(t, tmpDir)
+
+	createTestNote(t, tmpDir, "Alpha", "# Alpha\n\nTODO: tidy up\n")
+	createTestNote(t, tmpDir, "Beta", "# Beta\n\nFIXME: broken link\n")
+	createTestNote(t, tmpDir, "Gamma", "# Gamma\n\nTODO and FIXME\n")
+	createTestNote(t, tmpDir, "Delta", "# Delta\n\nAll clean\n")
+
+	patternsPath := filepath.Join(t.TempDir(), "patterns.txt")
+	if err := os.WriteFile(patternsPath, []byte("# audit terms\nTODO\n\nFIXME\n"), 0644); err != nil {
+		t.Fatalf("Failed to write patterns file: %v", err)
+	}
+
+	var count int
+	var searchErr error
+	output := testhelpers.CaptureStdout(func() {
+		count, searchErr = searchPatternFile(context.Background(), cfg, patternsPath)
+	})
+	if searchErr != nil {
+		t.Fatalf("searchPatternFile failed: %v", searchErr)
+	}
+	if count != 4 {
+		t.Errorf("count = %d, want 4", count)
+	}
+
+	groups := strings.Split(output, "🔎 ")[1:]
+	if len(groups) != 2 {
+		t.Fatalf("expected 2 pattern groups, got %d:\n%s", len(groups), output)
+	}
+	for i, want := range []struct {
+		pattern string
+		files   []string
+	}{
+		{"TODO", []string{"Alpha.md", "Gamma.md"}},
+		{"FIXME", []string{"Beta.md", "Gamma.md"}},
+	} {
+		if !strings.HasPrefix(groups[i], want.pattern+" (2)") {
+			t.Errorf("group %d = %q, want pattern %s with 2 files", i, groups[i], want.pattern)
+		}
+		for _, file := range want.files {
+			if !strings.Contains(groups[i], file) {
+				t.Errorf("group %s missing %s:\n%s", want.pattern, file, groups[i])
+			}
+		}
+		if strings.Contains(groups[i], "Delta.md") {
+			t.Errorf("group %s should not contain Delta.md:\n%s", want.pattern, groups[i])
+		}
+	}
+}
+
+// TestSearchNotes_EditOpensAllMatches tests that --edit passes every matching
+// file to a single editor invocation.
+func TestSearchNotes_EditOpensAllMatches(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := createTestSearchConfig(t, tmpDir)
+
+	createTestNote(t, tmpDir, "First", "# First\n\nTODO one\nTODO two\n")
+	createTestNote(t, tmpDir, "Second", "# Second\n\nTODO three\n")
+	createTestNote(t, tmpDir, "Other", "# Other\n\nnothing here\n")
+
+	// A stand-in for vim that records its arguments, one invocation per line.
+	binDir := t.TempDir()
+	logPath := filepath.Join(binDir, "args.log")
+	editor := filepath.Join(binDir, "vim")
+	script := fmt.Sprintf("#!/bin/sh\necho \"$@\" >> %s\n", logPath)
+	if err := os.WriteFile(editor, []byte(script), 0755); err != nil {
+		t.Fatalf("Failed to write editor script: %v", err)
+	}
+	t.Setenv("EDITOR", editor)
+
+	searchCmdFlags.edit = true
+	defer func() { searchCmdFlags.edit = false }()
+
+	var searchErr error
+	testhelpers.CaptureStdout(func() {
+		searchErr = SearchNotes(context.Background(), cfg, "TODO")
+	})
+	if searchErr != nil {
+		t.Fatalf("SearchNotes failed: %v", searchErr)
+	}
+
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("editor was not run: %v", err)
+	}
+	invocations := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(invocations) != 1 {
+		t.Fatalf("expected one editor invocation, got %d: %q", len(invocations), invocations)
+	}
+
+	args := strings.Fields(invocations[0])
+	if len(args) != 2 {
+		t.Fatalf("expected 2 paths, got %q", args)
+	}
+	for _, name := range []string{"First.md", "Second.md"} {
+		if !strings.Contains(invocations[0], filepath.Join(tmpDir, name)) {
+			t.Errorf("editor arguments %q missing %s", invocations[0], name)
+		}
+	}
+}
+
+// TestSearchNotes_EditAtLine tests that --edit --line opens each matching
+// file at its first matching line, using each editor's own line syntax.
+func TestSearchNotes_EditAtLine(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := createTestSearchConfig(t, tmpDir)
+
+	createTestNote(t, tmpDir, "First", "# First\n\nintro\nTODO one\nTODO two\n")
+
+	searchCmdFlags.edit = true
+	searchCmdFlags.line = true
+	defer func() { searchCmdFlags.edit, searchCmdFlags.line = false, false }()
+
+	notePath := filepath.Join(tmpDir, "First.md")
+	for editorName, want := range map[string]string{
+		"vim":  "+4 " + notePath,
+		"code": "-g " + notePath + ":4",
+	} {
+		t.Run(editorName, func(t *testing.T) {
+			// A stand-in editor that records its arguments.
+			binDir := t.TempDir()
+			logPath := filepath.Join(binDir, "args.log")
+			editor := filepath.Join(binDir, editorName)
+			script := fmt.Sprintf("#!/bin/sh\necho \"$@\" >> %s\n", logPath)
+			if err := os.WriteFile(editor, []byte(script), 0755); err != nil {
+				t.Fatalf("Failed to write editor script: %v", err)
+			}
+			t.Setenv("EDITOR", editor)
+
+			var searchErr error
+			testhelpers.CaptureStdout(func() {
+				searchErr = SearchNotes(context.Background(), cfg, "TODO")
+			})
+			if searchErr != nil {
+				t.Fatalf("SearchNotes failed: %v", searchErr)
+			}
+
+			data, err := os.ReadFile(logPath)
+			if err != nil {
+				t.Fatalf("editor was not run: %v", err)
+			}
+			if got := strings.TrimSpace(string(data)); got != want {
+				t.Errorf("editor arguments = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+// TestSearchNotes_TitleMode tests that --title matches note titles and file
+// names but not body text.
+func TestSearchNotes_TitleMode(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := createTestSearchConfig(t, tmpDir)
+
+	createTestNote(t, tmpDir, "Roadmap", "# Product Roadmap\n\nPlans for the year.\n")
+	createTestNote(t, tmpDir, "standup", "# Standup\n\nDiscussed the roadmap again.\n")
+	createTestNote(t, tmpDir, "ideas", "# Ideas\n\nNothing relevant.\n")
+
+	defaultOutput := testhelpers.CaptureStdout(func() {
+		if err := SearchNotes(context.Background(), cfg, "roadmap"); err != nil {
+			t.Fatalf("SearchNotes failed: %v", err)
+		}
+	})
+	if !strings.Contains(defaultOutput, "standup.md") {
+		t.Errorf("default search should match body text, got:\n%s", defaultOutput)
+	}
+
+	searchCmdFlags.title = true
+	defer func() { searchCmdFlags.title = false }()
+
+	titleOutput := testhelpers.CaptureStdout(func() {
+		if err := SearchNotes(context.Background(), cfg, "roadmap"); err != nil {
+			t.Fatalf("SearchNotes failed: %v", err)
+		}
+	})
+	if !strings.Contains(titleOutput, "Roadmap.md — Product Roadmap") {
+		t.Errorf("title search should match the Roadmap note, got:\n%s", titleOutput)
+	}
+	if strings.Contains(titleOutput, "standup.md") {
+		t.Errorf("title search should not match body text, got:\n%s", titleOutput)
+	}
+
+	titleOnly := testhelpers.CaptureStdout(func() {
+		if err := SearchNotes(context.Background(), cfg, "product"); err != nil {
+			t.Fatalf("SearchNotes failed: %v", err)
+		}
+	})
+	if !strings.Contains(titleOnly, "Roadmap.md") {
+		t.Errorf("title search should match heading text not in the file name, got:\n%s", titleOnly)
+	}
+}
+
+// TestListTags_TagColors tests that configured tags are colorized only when
+// stdout is a terminal.
+func TestListTags_TagColors(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := createTestSearchConfig(t, tmpDir)
+	cfg.Format.TagColors = map[string]string{"urgent": "red"}
+
+	createTestNote(t, tmpDir, "Tagged", "# Tagged\n\n#urgent #later\n")
+	t.Setenv("NO_COLOR", "")
+	defer output.ResetStdoutTerminalForTest()
+
+	output.SetStdoutTerminalForTest(true)
+	colored := testhelpers.CaptureStdout(func() {
+		if err := listTags(context.Background(), cfg); err != nil {
+			t.Fatalf("listTags failed: %v", err)
+		}
+	})
+	if !strings.Contains(colored, "\x1b[31m#urgent\x1b[0m") {
+		t.Errorf("expected #urgent in red on a terminal, got %q", colored)
+	}
+	if strings.Contains(colored, "\x1b[31m#later") || !strings.Contains(colored, "  #later\n") {
+		t.Errorf("expected #later uncolored, got %q", colored)
+	}
+
+	output.SetStdoutTerminalForTest(false)
+	plain := testhelpers.CaptureStdout(func() {
+		if err := listTags(context.Background(), cfg); err != nil {
+			t.Fatalf("listTags failed: %v", err)
+		}
+	})
+	if strings.Contains(plain, "\x1b[") {
+		t.Errorf("expected no color codes when piped, got %q", plain)
+	}
+}
+
+// TestCheckAttachments tests that links --check fails only on missing embeds.
+func TestCheckAttachments(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := createTestSearchConfig(t, tmpDir)
+
+	if err := notes.WriteNote(context.Background(), filepath.Join(tmpDir, notes.AttachmentsDir, "abc.png"), "img"); err != nil {
+		t.Fatalf("Failed to create attachment: %v", err)
+	}
+	createTestNote(t, tmpDir, "Good", "![[abc.png]] and [[Other note]] and ![[Embedded note]]\n")
+
+	if err := checkAttachments(context.Background(), cfg); err != nil {
+		t.Errorf("checkAttachments should pass when all attachments exist: %v", err)
+	}
+
+	createTestNote(t, tmpDir, "Bad", "# Bad\n![[missing.pdf]]\n")
+
+	var checkErr error
+	output := testhelpers.CaptureStdout(func() {
+		checkErr = checkAttachments(context.Background(), cfg)
+	})
+	if checkErr == nil {
+		t.Error("checkAttachments should fail when an attachment is missing")
+	}
+	if !strings.Contains(output, "Bad.md:2  missing attachment missing.pdf") {
+		t.Errorf("expected missing attachment to be reported, got %q", output)
+	}
+}
+
 // TestSearchNotes_NoMatchesOutput tests searching when no matches exist (SearchNotes wrapper).
 func TestSearchNotes_NoMatchesOutput(t *testing.T) {
 	tmpDir, err := os.MkdirTemp("", "jotr-search-no-matches-test-")
@@ -593,6 +912,29 @@ func TestTagStats_WithTags(t *testing.T) {
 	}
 }
 
+// TestFindSingletonTags tests that only tags used in a single note are reported.
+func TestFindSingletonTags(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	createTestNote(t, tmpDir, "Note1", "# Note 1\n\n#important\n")
+	createTestNote(t, tmpDir, "Note2", "# Note 2\n\n#important\n")
+	typoPath := createTestNote(t, tmpDir, "Note3", "# Note 3\n\nSome text\n#important #improtant\n")
+
+	singletons, err := findSingletonTags(context.Background(), tmpDir)
+	if err != nil {
+		t.Fatalf("findSingletonTags failed: %v", err)
+	}
+
+	if len(singletons) != 1 {
+		t.Fatalf("Expected 1 singleton tag, got %d: %+v", len(singletons), singletons)
+	}
+
+	want := tagLocation{Tag: "improtant", Path: typoPath, Line: 4}
+	if singletons[0] != want {
+		t.Errorf("Expected %+v, got %+v", want, singletons[0])
+	}
+}
+
 // TestShowLinks_NoLinks tests showLinks when note has no links.
 func TestShowLinks_NoLinks(t *testing.T) {
 	tmpDir, err := os.MkdirTemp("", "jotr-links-test-")
@@ -742,6 +1084,147 @@ func TestListCmd_WithListAll(t *testing.T) {
 	}
 }
 
+// TestListTaskBadge_MixedTasks tests that --with-tasks reports actual task counts.
+func TestListTaskBadge_MixedTasks(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "jotr-list-test-")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	notePath := createTestNote(t, tmpDir, "Mixed", `# Mixed
+
+## Tasks
+
+- [x] Done one
+- [ ] Open one
+- [X] Done two
+* [ ] Open two
+- [ ] Open three
+`)
+	emptyPath := createTestNote(t, tmpDir, "Empty", "# No tasks here\n")
+
+	if badge := listTaskBadge(notePath, tasks.Options{}); badge != "" {
+		t.Errorf("Expected no badge without --with-tasks, got %q", badge)
+	}
+
+	listWithTasks = true
+	defer func() { listWithTasks = false }()
+
+	if badge := listTaskBadge(notePath, tasks.Options{}); badge != " [2/5]" {
+		t.Errorf("listTaskBadge() = %q, want %q", badge, " [2/5]")
+	}
+
+	if badge := listTaskBadge(emptyPath, tasks.Options{}); badge != "" {
+		t.Errorf("Expected no badge for note without tasks, got %q", badge)
+	}
+}
+
+// TestListRecentNotes_Backlinks tests that --backlinks shows incoming link counts.
+func TestListRecentNotes_Backlinks(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := createTestSearchConfig(t, tmpDir)
+
+	createTestNote(t, tmpDir, "Hub", "# Hub\n\nSee [[Leaf]] and [[Hub]].\n")
+	createTestNote(t, tmpDir, "Leaf", "# Leaf\n\nBack to [[hub|the hub]].\n")
+	createTestNote(t, tmpDir, "Project", "# Project\n\n[[Hub]] twice: [[Hub#Links]]. Also [[Leaf]].\n")
+	createTestNote(t, tmpDir, "Orphan", "# Orphan\n\nLinks to [[Missing]].\n")
+
+	outputOption.FilesOnly = true
+	listBacklinks = true
+	defer func() {
+		outputOption.FilesOnly = false
+		listBacklinks = false
+	}()
+
+	var err error
+	out := testhelpers.CaptureStdout(func() {
+		err = listRecentNotes(context.Background(), cfg)
+	})
+	if err != nil {
+		t.Fatalf("listRecentNotes() error = %v", err)
+	}
+
+	for _, want := range []string{
+		"Hub.md (2 backlinks)",
+		"Leaf.md (2 backlinks)",
+		"Project.md (0 backlinks)",
+		"Orphan.md (0 backlinks)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+// TestTagMap_JSON tests that tags map --json lists each note's tags.
+func TestTagMap_JSON(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := createTestSearchConfig(t, tmpDir)
+
+	createTestNote(t, tmpDir, "Meeting", "# Meeting #work\n\nDiscuss #roadmap and #work again.\n")
+	createTestNote(t, tmpDir, "Groceries", "# Groceries #home\n")
+	createTestNote(t, tmpDir, "Plain", "# No tags here\n")
+
+	tagsJSON = true
+	defer func() {
+		tagsJSON = false
+		tagsOmitEmpty = false
+	}()
+
+	for _, omitEmpty := range []bool{false, true} {
+		tagsOmitEmpty = omitEmpty
+
+		var err error
+		out := testhelpers.CaptureStdout(func() {
+			err = tagMap(context.Background(), cfg)
+		})
+		if err != nil {
+			t.Fatalf("tagMap() error = %v", err)
+		}
+
+		var got map[string][]string
+		if err := json.Unmarshal([]byte(out), &got); err != nil {
+			t.Fatalf("tagMap() output is not JSON: %v\n%s", err, out)
+		}
+
+		want := map[string][]string{
+			"Meeting.md":   {"roadmap", "work"},
+			"Groceries.md": {"home"},
+		}
+		if !omitEmpty {
+			want["Plain.md"] = []string{}
+		}
+		if fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Errorf("tagMap(omitEmpty=%v) = %v, want %v", omitEmpty, got, want)
+		}
+		if plain, ok := got["Plain.md"]; !omitEmpty && (!ok || plain == nil) {
+			t.Errorf("untagged note should map to an empty list, got %v (present %v)", plain, ok)
+		}
+	}
+}
+
+// TestSearchCmd_ExitCodes tests that search exits 0 on matches and 2 on no matches.
+func TestSearchCmd_ExitCodes(t *testing.T) {
+	fs := testhelpers.NewTestFS(t)
+	defer fs.Cleanup()
+
+	testhelpers.NewConfigHelper(fs).CreateBasicConfig(t)
+	t.Setenv("JOTR_CONFIG", filepath.Join(fs.BaseDir, ".config", "jotr", "config.json"))
+
+	fs.WriteFile(t, "Note.md", "# Note\nsomething findable\n")
+
+	result := testhelpers.ExecuteCommand(SearchCmd, "findable")
+	if result.ExitCode != utils.ExitCodeSuccess {
+		t.Errorf("Expected exit code %d for matches, got %d (err: %v)", utils.ExitCodeSuccess, result.ExitCode, result.Error)
+	}
+
+	result = testhelpers.ExecuteCommand(SearchCmd, "nonexistentterm12345")
+	if result.ExitCode != utils.ExitCodeNoMatches {
+		t.Errorf("Expected exit code %d for no matches, got %d (err: %v)", utils.ExitCodeNoMatches, result.ExitCode, result.Error)
+	}
+}
+
 // BenchmarkSearchNotes_Small is a small-scale benchmark for SearchNotes.
 func BenchmarkSearchNotes_Small(b *testing.B) {
 	tmpDir, err := os.MkdirTemp("", "jotr-search-bench-small-")
@@ -984,3 +1467,36 @@ func createTestNoteForBenchmark(b *testing.B, tmpDir, filename, content string)
 
 	return notePath
 }
+
+func TestSearchNotes_PathGlob(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := createTestSearchConfig(t, tmpDir)
+
+	createTestNote(t, tmpDir, filepath.Join("work", "launch"), "# Launch\n\nbudget review\n")
+	createTestNote(t, tmpDir, filepath.Join("work", "q3", "plan"), "# Plan\n\nbudget for Q3\n")
+	createTestNote(t, tmpDir, filepath.Join("personal", "home"), "# Home\n\nbudget for groceries\n")
+
+	SetSearchFilesForTest(true)
+	defer SetSearchFilesForTest(false)
+
+	ctx := notes.WithPathGlob(context.Background(), "work/**")
+
+	var count int
+	var err error
+	out := testhelpers.CaptureStdout(func() {
+		count, err = searchNotes(ctx, cfg, "budget")
+	})
+	if err != nil {
+		t.Fatalf("searchNotes() error = %v", err)
+	}
+
+	if count != 2 {
+		t.Errorf("searchNotes() = %d matches, want 2:\n%s", count, out)
+	}
+	if !strings.Contains(out, filepath.Join("work", "launch.md")) || !strings.Contains(out, filepath.Join("work", "q3", "plan.md")) {
+		t.Errorf("expected both work notes in output:\n%s", out)
+	}
+	if strings.Contains(out, "personal") {
+		t.Errorf("work/** scope should exclude the personal note:\n%s", out)
+	}
+}
@@ -2,10 +2,12 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"sort"
 	"strings"
 
@@ -13,22 +15,39 @@ import (
 
 	"github.com/AnishShah1803/jotr/internal/config"
 	"github.com/AnishShah1803/jotr/internal/notes"
+	"github.com/AnishShah1803/jotr/internal/output"
 )
 
+var (
+	tagsJSON      bool
+	tagsOmitEmpty bool
+	tagsDepth     int
+)
+
+func init() {
+	TagsCmd.Flags().BoolVar(&tagsJSON, "json", false, "Output in JSON format (map)")
+	TagsCmd.Flags().BoolVar(&tagsOmitEmpty, "omit-empty", false, "Leave out notes without tags (map)")
+	TagsCmd.Flags().IntVar(&tagsDepth, "depth", 0, "Only read notes this many directory levels deep (0 = unlimited)")
+}
+
 var TagsCmd = &cobra.Command{
 	Use:   "tags [action]",
-	Short: "Manage tags (list, find, stats)",
+	Short: "Manage tags (list, find, stats, singletons, map)",
 	Long: `Manage tags across all notes.
 	
 Actions:
   list              List all tags
   find [tag]        Find notes with tag
   stats             Show tag statistics
+  singletons        List tags used in only one note (often typos)
+  map               Show the tags of every note (--json for indexing)
   
 Examples:
   jotr tags list
   jotr tags find meeting
-  jotr tags stats`,
+  jotr tags stats
+  jotr tags singletons
+  jotr tags map --json --omit-empty`,
 	Aliases: []string{"tag"},
 	RunE: func(cmd *cobra.Command, args []string) error {
 		cfg, err := config.LoadWithContext(cmd.Context(), "")
@@ -41,16 +60,22 @@ Examples:
 			action = args[0]
 		}
 
+		ctx := notes.WithMaxDepth(cmd.Context(), tagsDepth)
+
 		switch action {
 		case "list":
-			return listTags(cmd.Context(), cfg)
+			return listTags(ctx, cfg)
 		case "find":
 			if len(args) < 2 {
 				return fmt.Errorf("tag name required")
 			}
-			return findByTag(cmd.Context(), cfg, args[1])
+			return findByTag(ctx, cfg, args[1])
 		case "stats":
-			return tagStats(cmd.Context(), cfg)
+			return tagStats(ctx, cfg)
+		case "singletons":
+			return tagSingletons(ctx, cfg)
+		case "map":
+			return tagMap(ctx, cfg)
 		default:
 			return fmt.Errorf("unknown action: %s", action)
 		}
@@ -112,8 +137,9 @@ func listTags(ctx context.Context, cfg *config.LoadedConfig) error {
 
 	fmt.Printf("Found %d tags:\n\n", len(tags))
 
+	colorOn := output.StdoutColorEnabled()
 	for _, tag := range tags {
-		fmt.Printf("  #%s\n", tag)
+		fmt.Printf("  %s\n", output.ColorizeTags("#"+tag, cfg.Format.TagColors, colorOn))
 	}
 
 	return nil
@@ -159,13 +185,14 @@ func findByTag(ctx context.Context, cfg *config.LoadedConfig, tag string) error
 	return nil
 }
 
-func tagStats(ctx context.Context, cfg *config.LoadedConfig) error {
-	allNotes, err := notes.FindNotes(ctx, cfg.Paths.BaseDir)
+// tagNotes maps each tag to the notes under baseDir that use it.
+func tagNotes(ctx context.Context, baseDir string) (map[string][]string, error) {
+	allNotes, err := notes.FindNotes(ctx, baseDir)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	tagCounts := make(map[string]int)
+	byTag := make(map[string][]string)
 
 	for _, notePath := range allNotes {
 		content, err := os.ReadFile(notePath)
@@ -175,10 +202,24 @@ func tagStats(ctx context.Context, cfg *config.LoadedConfig) error {
 
 		tags := extractTags(string(content))
 		for _, tag := range tags {
-			tagCounts[tag]++
+			byTag[tag] = append(byTag[tag], notePath)
 		}
 	}
 
+	return byTag, nil
+}
+
+func tagStats(ctx context.Context, cfg *config.LoadedConfig) error {
+	byTag, err := tagNotes(ctx, cfg.Paths.BaseDir)
+	if err != nil {
+		return err
+	}
+
+	tagCounts := make(map[string]int, len(byTag))
+	for tag, paths := range byTag {
+		tagCounts[tag] = len(paths)
+	}
+
 	if len(tagCounts) == 0 {
 		fmt.Println("No tags found")
 		return nil
@@ -208,3 +249,141 @@ func tagStats(ctx context.Context, cfg *config.LoadedConfig) error {
 
 	return nil
 }
+
+// tagLocation is where a tag first appears: a note and 1-based line number.
+type tagLocation struct {
+	Tag  string
+	Path string
+	Line int
+}
+
+// findSingletonTags returns tags that occur in exactly one note, sorted by tag.
+func findSingletonTags(ctx context.Context, baseDir string) ([]tagLocation, error) {
+	byTag, err := tagNotes(ctx, baseDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var singletons []tagLocation
+
+	for tag, paths := range byTag {
+		if len(paths) != 1 {
+			continue
+		}
+
+		singletons = append(singletons, tagLocation{
+			Tag:  tag,
+			Path: paths[0],
+			Line: findTagLine(paths[0], tag),
+		})
+	}
+
+	sort.Slice(singletons, func(i, j int) bool {
+		return singletons[i].Tag < singletons[j].Tag
+	})
+
+	return singletons, nil
+}
+
+// findTagLine returns the first line of the note that uses tag, or 0.
+func findTagLine(notePath, tag string) int {
+	content, err := os.ReadFile(notePath)
+	if err != nil {
+		return 0
+	}
+
+	for i, line := range strings.Split(string(content), "\n") {
+		if slices.Contains(extractTags(line), tag) {
+			return i + 1
+		}
+	}
+
+	return 0
+}
+
+func tagSingletons(ctx context.Context, cfg *config.LoadedConfig) error {
+	singletons, err := findSingletonTags(ctx, cfg.Paths.BaseDir)
+	if err != nil {
+		return err
+	}
+
+	if len(singletons) == 0 {
+		fmt.Println("No single-use tags found")
+		return nil
+	}
+
+	fmt.Printf("Found %d tags used in only one note:\n\n", len(singletons))
+
+	for _, loc := range singletons {
+		relPath, _ := filepath.Rel(cfg.Paths.BaseDir, loc.Path)
+		fmt.Printf("  #%-20s %s:%d\n", loc.Tag, relPath, loc.Line)
+	}
+
+	return nil
+}
+
+// noteTags maps each note under baseDir, by path relative to baseDir, to its
+// sorted tags. Notes without tags map to an empty list unless omitEmpty is set.
+func noteTags(ctx context.Context, baseDir string, omitEmpty bool) (map[string][]string, error) {
+	allNotes, err := notes.FindNotes(ctx, baseDir)
+	if err != nil {
+		return nil, err
+	}
+
+	byNote := make(map[string][]string, len(allNotes))
+
+	for _, notePath := range allNotes {
+		content, err := os.ReadFile(notePath)
+		if err != nil {
+			continue
+		}
+
+		tags := extractTags(string(content))
+		if len(tags) == 0 && omitEmpty {
+			continue
+		}
+		sort.Strings(tags)
+
+		relPath, _ := filepath.Rel(baseDir, notePath)
+		byNote[relPath] = tags
+	}
+
+	return byNote, nil
+}
+
+func tagMap(ctx context.Context, cfg *config.LoadedConfig) error {
+	byNote, err := noteTags(ctx, cfg.Paths.BaseDir, tagsOmitEmpty)
+	if err != nil {
+		return err
+	}
+
+	if tagsJSON {
+		data, err := json.MarshalIndent(byNote, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(byNote) == 0 {
+		fmt.Println("No notes found")
+		return nil
+	}
+
+	paths := make([]string, 0, len(byNote))
+	for path := range byNote {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		tags := make([]string, len(byNote[path]))
+		for i, tag := range byNote[path] {
+			tags[i] = "#" + tag
+		}
+		fmt.Printf("  %s: %s\n", path, strings.Join(tags, " "))
+	}
+
+	return nil
+}
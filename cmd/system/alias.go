@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -31,6 +32,7 @@ Examples:
   jotr alias add work "Work/Projects.md"
   jotr alias add today "daily:0"
   jotr alias add yesterday "daily:-1"
+  jotr alias add previous "daily~:-1"   # nearest existing note on or before yesterday
   jotr alias list
   jotr alias resolve work`,
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -192,6 +194,28 @@ func resolveAlias(cfg *config.LoadedConfig, name string) error {
 }
 
 func resolveAliasValue(cfg *config.LoadedConfig, value string) (string, error) {
+	// Handle nearest-prior daily aliases: daily~:0, daily~:-1, etc. These
+	// resolve to the latest existing note on or before the offset date.
+	if strings.HasPrefix(value, "daily~:") {
+		offsetStr := strings.TrimPrefix(value, "daily~:")
+
+		var offset int
+
+		fmt.Sscanf(offsetStr, "%d", &offset)
+
+		date := time.Now().AddDate(0, 0, offset)
+
+		notePath, err := notes.FindDailyNoteOnOrBefore(context.Background(), cfg.DiaryPath, date)
+		if err != nil {
+			return "", fmt.Errorf("scanning diary: %w", err)
+		}
+		if notePath == "" {
+			return "", fmt.Errorf("no daily note on or before %s", date.Format("2006-01-02"))
+		}
+
+		return notePath, nil
+	}
+
 	// Handle dynamic daily aliases: daily:0, daily:-1, etc.
 	if strings.HasPrefix(value, "daily:") {
 		offsetStr := strings.TrimPrefix(value, "daily:")
@@ -3,8 +3,10 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -12,16 +14,21 @@ import (
 	"github.com/AnishShah1803/jotr/internal/config"
 	"github.com/AnishShah1803/jotr/internal/constants"
 	"github.com/AnishShah1803/jotr/internal/notes"
+	"github.com/AnishShah1803/jotr/internal/utils"
 )
 
 var FrontmatterCmd = &cobra.Command{
 	Use:   "frontmatter [note-name]",
 	Short: "Manage note frontmatter",
 	Long: `View or edit frontmatter in notes.
+
+"jotr frontmatter check" scans every note and reports frontmatter that is
+unterminated or not valid YAML. Notes without frontmatter are fine.
 	
 Examples:
   jotr frontmatter MyNote        # Show frontmatter
-  jotr frontmatter MyNote --set status=done`,
+  jotr frontmatter MyNote --set status=done
+  jotr frontmatter check         # Find notes with broken frontmatter`,
 	Aliases: []string{"fm"},
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) == 0 {
@@ -33,6 +40,18 @@ Examples:
 			return err
 		}
 
+		if len(args) == 1 && args[0] == "check" {
+			count, err := checkFrontmatter(cmd.Context(), cfg, os.Stdout)
+			if err != nil {
+				return err
+			}
+			if count > 0 {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("found %d note(s) with broken frontmatter", count)
+			}
+			return nil
+		}
+
 		noteName := args[0]
 		setValue, _ := cmd.Flags().GetString("set")
 
@@ -43,6 +62,39 @@ Examples:
 	},
 }
 
+// checkFrontmatter reports each note whose frontmatter fails to parse and
+// returns how many there are.
+func checkFrontmatter(ctx context.Context, cfg *config.LoadedConfig, out io.Writer) (int, error) {
+	allNotes, err := notes.FindNotes(ctx, cfg.Paths.BaseDir)
+	if err != nil {
+		return 0, err
+	}
+	sort.Strings(allNotes)
+
+	broken := 0
+	for _, note := range allNotes {
+		content, err := os.ReadFile(note)
+		if err != nil {
+			return broken, err
+		}
+
+		if err := utils.CheckFrontmatter(string(content)); err != nil {
+			rel, relErr := filepath.Rel(cfg.Paths.BaseDir, note)
+			if relErr != nil {
+				rel = note
+			}
+			fmt.Fprintf(out, "%s: %v\n", rel, err)
+			broken++
+		}
+	}
+
+	if broken == 0 {
+		fmt.Fprintf(out, "✓ Frontmatter is valid in all %d notes\n", len(allNotes))
+	}
+
+	return broken, nil
+}
+
 func showFrontmatter(ctx context.Context, cfg *config.LoadedConfig, noteName string) error {
 	allNotes, err := notes.FindNotes(ctx, cfg.Paths.BaseDir)
 	if err != nil {
@@ -67,31 +119,21 @@ func showFrontmatter(ctx context.Context, cfg *config.LoadedConfig, noteName str
 		return err
 	}
 
-	lines := strings.Split(string(content), "\n")
-
-	if len(lines) < 3 || lines[0] != "---" {
+	frontmatter, found, closed := utils.FrontmatterLines(strings.Split(string(content), "\n"))
+	if !found {
 		fmt.Printf("No frontmatter in %s\n", filepath.Base(targetNote))
 		return nil
 	}
 
-	endIdx := -1
-
-	for i := 1; i < len(lines); i++ {
-		if lines[i] == "---" {
-			endIdx = i
-			break
-		}
-	}
-
-	if endIdx == -1 {
+	if !closed {
 		fmt.Printf("Invalid frontmatter in %s\n", filepath.Base(targetNote))
 		return nil
 	}
 
 	fmt.Printf("Frontmatter in %s:\n\n", filepath.Base(targetNote))
 
-	for i := 1; i < endIdx; i++ {
-		fmt.Printf("  %s\n", lines[i])
+	for _, line := range frontmatter {
+		fmt.Printf("  %s\n", line)
 	}
 
 	return nil
@@ -10,9 +10,11 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/AnishShah1803/jotr/internal/ai"
 	"github.com/AnishShah1803/jotr/internal/config"
 	"github.com/AnishShah1803/jotr/internal/constants"
 	"github.com/AnishShah1803/jotr/internal/notes"
+	"github.com/AnishShah1803/jotr/internal/services"
 	"github.com/AnishShah1803/jotr/internal/tasks"
 	"github.com/AnishShah1803/jotr/internal/utils"
 )
@@ -20,6 +22,7 @@ import (
 var (
 	monthlyYear  int
 	monthlyMonth int
+	monthlyAI    bool
 )
 
 var MonthlyCmd = &cobra.Command{
@@ -28,9 +31,11 @@ var MonthlyCmd = &cobra.Command{
 	Long: `Generate a summary of the month including notes and tasks.
 
 Creates a summary markdown file in the summaries directory.
+With --ai, the month's notes are also summarized by the configured AI command.
 
 Examples:
   jotr monthly --year 2025 --month 1  # Generate January 2025 summary
+  jotr monthly --ai                   # Include an AI-written summary
   jotr month                          # Using alias`,
 	Aliases: []string{"month"},
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -43,6 +48,34 @@ Examples:
 	},
 }
 
+func init() {
+	now := time.Now()
+	MonthlyCmd.Flags().IntVar(&monthlyYear, "year", now.Year(), "Year of the summary")
+	MonthlyCmd.Flags().IntVar(&monthlyMonth, "month", int(now.Month()), "Month of the summary (1-12)")
+	MonthlyCmd.Flags().BoolVar(&monthlyAI, "ai", false, "Add an AI-generated summary of the month's notes")
+}
+
+// buildAISummarySection asks the summarizer for a summary of the given notes
+// and returns it as a markdown section.
+func buildAISummarySection(ctx context.Context, summarizer ai.Summarizer, notePaths []string) (string, error) {
+	var text strings.Builder
+
+	for _, notePath := range notePaths {
+		data, err := os.ReadFile(notePath)
+		if err != nil {
+			continue
+		}
+		fmt.Fprintf(&text, "### %s\n\n%s\n\n", filepath.Base(notePath), data)
+	}
+
+	summary, err := summarizer.Summarize(ctx, text.String())
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("## AI Summary\n\n%s\n\n", summary), nil
+}
+
 func generateMonthlySummary(ctx context.Context, cfg *config.LoadedConfig) error {
 	// Validate month
 	if monthlyMonth < 1 || monthlyMonth > 12 {
@@ -101,7 +134,7 @@ func generateMonthlySummary(ctx context.Context, cfg *config.LoadedConfig) error
 
 	// Count tasks from todo file
 	if utils.FileExists(cfg.TodoPath) {
-		allTasks, err := tasks.ReadTasks(ctx, cfg.TodoPath)
+		allTasks, err := services.NewTodoFormat(cfg.Format).ReadTasks(ctx, cfg.TodoPath)
 		if err == nil {
 			total, completed, pending := tasks.CountTasks(allTasks)
 			content += fmt.Sprintf("## Tasks\n\n")
@@ -111,6 +144,19 @@ func generateMonthlySummary(ctx context.Context, cfg *config.LoadedConfig) error
 		}
 	}
 
+	if monthlyAI {
+		summarizer, err := ai.New(cfg.AI)
+		if err != nil {
+			return err
+		}
+
+		section, err := buildAISummarySection(ctx, summarizer, validNotes)
+		if err != nil {
+			return fmt.Errorf("failed to generate AI summary: %w", err)
+		}
+		content += section
+	}
+
 	// List daily notes
 	content += fmt.Sprintf("## Daily Notes\n\n")
 
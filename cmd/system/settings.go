@@ -0,0 +1,194 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+
+	"github.com/spf13/cobra"
+
+	"github.com/AnishShah1803/jotr/internal/config"
+	"github.com/AnishShah1803/jotr/internal/constants"
+	"github.com/AnishShah1803/jotr/internal/utils"
+)
+
+var settingsReplace bool
+
+func init() {
+	SettingsCmd.Flags().BoolVar(&settingsReplace, "replace", false, "On import, replace the existing settings instead of merging into them")
+}
+
+// SettingsBundle holds the aliases, shortcuts and scheduled notes of a vault
+// for backup or moving to another vault.
+type SettingsBundle struct {
+	Aliases   map[string]string `json:"aliases"`
+	Shortcuts map[string]string `json:"shortcuts"`
+	Schedules []ScheduledNote   `json:"schedules"`
+}
+
+// SettingsCmd exports and imports aliases, shortcuts and schedules together.
+var SettingsCmd = &cobra.Command{
+	Use:   "settings [action]",
+	Short: "Export or import aliases, shortcuts and schedules",
+	Long: `Export or import aliases, shortcuts and scheduled notes as one JSON bundle.
+
+Actions:
+  export           Write the bundle to stdout
+  import [file]    Restore a bundle written by export
+
+By default import merges the bundle into the existing settings: entries
+with the same name (or schedule ID) are overwritten and the rest are kept.
+With --replace the existing settings are replaced by the bundle.
+
+Examples:
+  jotr settings export > bundle.json
+  jotr settings import bundle.json
+  jotr settings import --replace bundle.json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return fmt.Errorf("action required: export or import")
+		}
+
+		cfg, err := config.LoadWithContext(cmd.Context(), "")
+		if err != nil {
+			return err
+		}
+
+		switch args[0] {
+		case "export":
+			return exportSettings(cfg, os.Stdout)
+		case "import":
+			if len(args) < 2 {
+				return fmt.Errorf("usage: settings import [file]")
+			}
+			return importSettings(cfg, args[1], settingsReplace)
+		default:
+			return fmt.Errorf("unknown action: %s", args[0])
+		}
+	},
+}
+
+func exportSettings(cfg *config.LoadedConfig, out io.Writer) error {
+	aliases, err := loadAliases(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to load aliases: %w", err)
+	}
+
+	shortcuts, err := loadShortcuts(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to load shortcuts: %w", err)
+	}
+
+	scheduled, err := loadScheduledNotes(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to load scheduled notes: %w", err)
+	}
+
+	data, err := json.MarshalIndent(SettingsBundle{
+		Aliases:   aliases,
+		Shortcuts: shortcuts,
+		Schedules: scheduled,
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal settings: %w", err)
+	}
+	fmt.Fprintln(out, string(data))
+
+	return nil
+}
+
+func importSettings(cfg *config.LoadedConfig, path string, replace bool) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read bundle: %w", err)
+	}
+
+	var bundle SettingsBundle
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		return fmt.Errorf("invalid settings bundle: %w", err)
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(bundle.Shortcuts)) {
+		if isReserved(name) {
+			return fmt.Errorf("invalid settings bundle: cannot use reserved command name for shortcut: %s", name)
+		}
+	}
+
+	aliases := make(map[string]string)
+	shortcuts := make(map[string]string)
+	var scheduled []ScheduledNote
+	if !replace {
+		if aliases, err = loadAliases(cfg); err != nil {
+			return fmt.Errorf("failed to load aliases: %w", err)
+		}
+		if shortcuts, err = loadShortcuts(cfg); err != nil {
+			return fmt.Errorf("failed to load shortcuts: %w", err)
+		}
+		if scheduled, err = loadScheduledNotes(cfg); err != nil {
+			return fmt.Errorf("failed to load scheduled notes: %w", err)
+		}
+	}
+
+	for name, target := range bundle.Aliases {
+		aliases[name] = target
+	}
+	for name, command := range bundle.Shortcuts {
+		shortcuts[name] = command
+	}
+	scheduled = mergeScheduledNotes(scheduled, bundle.Schedules)
+
+	// The three files are written together so a failure part way through
+	// does not leave a partial import behind.
+	tx := utils.NewWriteTransaction()
+	defer tx.Abort()
+
+	if err := stageJSON(tx, getAliasFile(cfg), aliases, constants.FilePerm0644); err != nil {
+		return fmt.Errorf("failed to save aliases: %w", err)
+	}
+	if err := stageJSON(tx, getShortcutFile(cfg), shortcuts, constants.FilePerm0644); err != nil {
+		return fmt.Errorf("failed to save shortcuts: %w", err)
+	}
+	if err := stageJSON(tx, getScheduleFile(cfg), scheduled, constants.FilePerm0600); err != nil {
+		return fmt.Errorf("failed to save scheduled notes: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to save settings, nothing was imported: %w", err)
+	}
+
+	fmt.Printf("✓ Imported %d aliases, %d shortcuts and %d scheduled notes\n",
+		len(bundle.Aliases), len(bundle.Shortcuts), len(bundle.Schedules))
+
+	return nil
+}
+
+// stageJSON stages v, encoded as the alias, shortcut and schedule files are,
+// to be written to path when tx commits.
+func stageJSON(tx *utils.WriteTransaction, path string, v any, perm os.FileMode) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return tx.Stage(path, data, perm)
+}
+
+// mergeScheduledNotes adds incoming to existing, with an incoming note
+// replacing an existing one that has the same ID.
+func mergeScheduledNotes(existing, incoming []ScheduledNote) []ScheduledNote {
+	merged := make([]ScheduledNote, 0, len(existing)+len(incoming))
+	index := make(map[string]int)
+
+	for _, note := range append(existing, incoming...) {
+		if i, ok := index[note.ID]; ok {
+			merged[i] = note
+			continue
+		}
+		index[note.ID] = len(merged)
+		merged = append(merged, note)
+	}
+
+	return merged
+}
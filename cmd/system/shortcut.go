@@ -99,7 +99,9 @@ var reservedCommands = []string{
 	"template", "streak", "calendar", "dashboard", "bulk", "graph",
 	"alias", "check", "configure", "validate", "shortcut", "schedule",
 	"help", "version", "list", "quick", "stats", "archive", "git",
-	"links", "frontmatter", "monthly",
+	"links", "frontmatter", "monthly", "replace", "tasks", "report",
+	"lint", "last", "add", "import", "settings", "done", "focus", "in",
+	"inbox", "log", "attach", "status", "state", "board",
 }
 
 func isReserved(name string) bool {
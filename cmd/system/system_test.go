@@ -1,14 +1,19 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/AnishShah1803/jotr/internal/ai"
 	"github.com/AnishShah1803/jotr/internal/config"
 	"github.com/AnishShah1803/jotr/internal/constants"
+	"github.com/AnishShah1803/jotr/internal/notes"
 	"github.com/AnishShah1803/jotr/internal/utils"
 )
 
@@ -142,6 +147,50 @@ func TestResolveAliasValue_DailyAlias(t *testing.T) {
 	}
 }
 
+// TestResolveAliasValue_NearestDailyAlias tests that daily~: skips days
+// without a note and resolves to the nearest earlier one.
+func TestResolveAliasValue_NearestDailyAlias(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := createTestConfig(t, tmpDir)
+
+	threeDaysAgo := notes.BuildDailyNotePath(cfg.DiaryPath, time.Now().AddDate(0, 0, -3))
+	fiveDaysAgo := notes.BuildDailyNotePath(cfg.DiaryPath, time.Now().AddDate(0, 0, -5))
+	for _, notePath := range []string{threeDaysAgo, fiveDaysAgo} {
+		if err := notes.WriteNote(context.Background(), notePath, "# Daily\n"); err != nil {
+			t.Fatalf("Failed to create daily note: %v", err)
+		}
+	}
+
+	resolved, err := resolveAliasValue(cfg, "daily~:-1")
+	if err != nil {
+		t.Fatalf("Failed to resolve daily~:-1: %v", err)
+	}
+	if resolved != threeDaysAgo {
+		t.Errorf("Expected daily~:-1 to resolve to %s, got %s", threeDaysAgo, resolved)
+	}
+
+	resolved, err = resolveAliasValue(cfg, "daily~:-4")
+	if err != nil {
+		t.Fatalf("Failed to resolve daily~:-4: %v", err)
+	}
+	if resolved != fiveDaysAgo {
+		t.Errorf("Expected daily~:-4 to resolve to %s, got %s", fiveDaysAgo, resolved)
+	}
+
+	if _, err := resolveAliasValue(cfg, "daily~:-6"); err == nil {
+		t.Error("Expected an error when no daily note exists on or before the date")
+	}
+
+	// Exact daily: aliases keep resolving to the requested day.
+	exact, err := resolveAliasValue(cfg, "daily:-1")
+	if err != nil {
+		t.Fatalf("Failed to resolve daily:-1: %v", err)
+	}
+	if exact != notes.BuildDailyNotePath(cfg.DiaryPath, time.Now().AddDate(0, 0, -1)) {
+		t.Errorf("Expected daily:-1 to keep exact semantics, got %s", exact)
+	}
+}
+
 // TestShortcutOperations tests the shortcut command functions.
 func TestShortcutOperations(t *testing.T) {
 	tmpDir, err := os.MkdirTemp("", "jotr-shortcut-test-")
@@ -354,6 +403,40 @@ Content here.
 	}
 }
 
+// TestCheckFrontmatter tests that unterminated and invalid YAML frontmatter
+// are reported while valid frontmatter and plain notes pass.
+func TestCheckFrontmatter(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := createTestConfig(t, tmpDir)
+
+	for name, content := range map[string]string{
+		"Valid.md":        "---\nstatus: done\ntags: [a, b]\n---\n\n# Valid\n",
+		"Plain.md":        "# No frontmatter\n",
+		"Unterminated.md": "---\nstatus: done\n\n# Unterminated\n",
+		"BadYAML.md":      "---\nstatus: done\n  priority: P1\ntags: [a, b\n---\n\n# Bad\n",
+	} {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), constants.FilePerm0600); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	var out bytes.Buffer
+	count, err := checkFrontmatter(context.Background(), cfg, &out)
+	if err != nil {
+		t.Fatalf("checkFrontmatter() error = %v", err)
+	}
+
+	if count != 2 {
+		t.Errorf("checkFrontmatter() = %d broken notes, want 2\n%s", count, out.String())
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "BadYAML.md: invalid frontmatter: yaml:") ||
+		lines[1] != "Unterminated.md: "+utils.ErrUnterminatedFrontmatter.Error() {
+		t.Errorf("unexpected report:\n%s", out.String())
+	}
+}
+
 // TestUpdateCheck tests the update check functionality.
 func TestUpdateCheck(t *testing.T) {
 	// Test CheckForUpdates function (exported)
@@ -449,6 +532,73 @@ func TestInvalidMonthValidation(t *testing.T) {
 	}
 }
 
+// fakeSummarizer is an ai.Summarizer that records its input.
+type fakeSummarizer struct {
+	got string
+}
+
+func (f *fakeSummarizer) Summarize(ctx context.Context, text string) (string, error) {
+	f.got = text
+	return "A productive month.", nil
+}
+
+// TestBuildAISummarySection tests that the monthly summary goes through the summarizer interface.
+func TestBuildAISummarySection(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	notePath := filepath.Join(tmpDir, "2025-01-02-Thu.md")
+	if err := os.WriteFile(notePath, []byte("Shipped the release"), constants.FilePerm0644); err != nil {
+		t.Fatalf("Failed to write test note: %v", err)
+	}
+
+	fake := &fakeSummarizer{}
+
+	section, err := buildAISummarySection(context.Background(), fake, []string{notePath})
+	if err != nil {
+		t.Fatalf("buildAISummarySection() error = %v", err)
+	}
+
+	if !contains(fake.got, "2025-01-02-Thu.md") || !contains(fake.got, "Shipped the release") {
+		t.Errorf("Summarizer received %q, want note name and content", fake.got)
+	}
+
+	if section != "## AI Summary\n\nA productive month.\n\n" {
+		t.Errorf("buildAISummarySection() = %q", section)
+	}
+}
+
+// TestMonthlySummary_AIDisabled tests that --ai fails cleanly when AI is disabled.
+func TestMonthlySummary_AIDisabled(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := createTestConfig(t, tmpDir)
+	cfg.AI.Enabled = false
+	cfg.AI.Command = "echo"
+
+	now := time.Now()
+	monthlyYear = now.Year()
+	monthlyMonth = int(now.Month())
+	monthlyAI = true
+	defer func() { monthlyAI = false }()
+
+	diaryPath := filepath.Join(tmpDir, "Diary", now.Format("2006"), now.Format("01-Jan"))
+	if err := os.MkdirAll(diaryPath, 0750); err != nil {
+		t.Fatalf("Failed to create diary directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(diaryPath, now.Format("2006-01-02-Mon.md")), []byte("Content"), constants.FilePerm0644); err != nil {
+		t.Fatalf("Failed to write test note: %v", err)
+	}
+
+	err := generateMonthlySummary(context.Background(), cfg)
+	if !errors.Is(err, ai.ErrDisabled) {
+		t.Errorf("generateMonthlySummary() error = %v, want ai.ErrDisabled", err)
+	}
+
+	summaryPath := filepath.Join(tmpDir, "Diary", now.Format("2006"), "summaries", now.Format("01-Jan")+"-Summary.md")
+	if utils.FileExists(summaryPath) {
+		t.Error("Summary should not be written when AI is disabled")
+	}
+}
+
 // Helper function to check if string contains substring.
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr || len(s) > 0 && containsHelper(s, substr))
@@ -660,3 +810,115 @@ func TestScheduleFilePath(t *testing.T) {
 		t.Errorf("Expected schedule file path %s, got %s", expected, scheduleFile)
 	}
 }
+
+// TestSettingsExportImport tests that a settings bundle exported from one
+// vault restores its aliases, shortcuts and schedules into another.
+func TestSettingsExportImport(t *testing.T) {
+	src := createTestConfig(t, t.TempDir())
+
+	futureDate := time.Now().AddDate(0, 0, 7).Format("2006-01-02")
+	if err := addAlias(src, "work", "Work/Projects.md"); err != nil {
+		t.Fatalf("addAlias() error = %v", err)
+	}
+	if err := addShortcut(src, "ws", "search work"); err != nil {
+		t.Fatalf("addShortcut() error = %v", err)
+	}
+	if err := addScheduledNote(src, futureDate, "Q1 Review"); err != nil {
+		t.Fatalf("addScheduledNote() error = %v", err)
+	}
+
+	var bundle bytes.Buffer
+	if err := exportSettings(src, &bundle); err != nil {
+		t.Fatalf("exportSettings() error = %v", err)
+	}
+	bundlePath := filepath.Join(t.TempDir(), "bundle.json")
+	if err := os.WriteFile(bundlePath, bundle.Bytes(), 0600); err != nil {
+		t.Fatalf("Failed to write bundle: %v", err)
+	}
+
+	dst := createTestConfig(t, t.TempDir())
+	if err := addAlias(dst, "home", "Home.md"); err != nil {
+		t.Fatalf("addAlias() error = %v", err)
+	}
+	if err := importSettings(dst, bundlePath, false); err != nil {
+		t.Fatalf("importSettings() error = %v", err)
+	}
+
+	aliases, err := loadAliases(dst)
+	if err != nil {
+		t.Fatalf("loadAliases() error = %v", err)
+	}
+	if aliases["work"] != "Work/Projects.md" || aliases["home"] != "Home.md" {
+		t.Errorf("merged aliases = %v, want work and home", aliases)
+	}
+
+	shortcuts, err := loadShortcuts(dst)
+	if err != nil {
+		t.Fatalf("loadShortcuts() error = %v", err)
+	}
+	if shortcuts["ws"] != "search work" {
+		t.Errorf("shortcuts = %v, want ws → search work", shortcuts)
+	}
+
+	scheduled, err := loadScheduledNotes(dst)
+	if err != nil {
+		t.Fatalf("loadScheduledNotes() error = %v", err)
+	}
+	if len(scheduled) != 1 || scheduled[0].Text != "Q1 Review" || scheduled[0].Date.Format("2006-01-02") != futureDate {
+		t.Errorf("scheduled notes = %+v, want the Q1 Review note", scheduled)
+	}
+
+	// Importing twice merges by schedule ID instead of duplicating
+	if err := importSettings(dst, bundlePath, false); err != nil {
+		t.Fatalf("importSettings() error = %v", err)
+	}
+	if scheduled, _ = loadScheduledNotes(dst); len(scheduled) != 1 {
+		t.Errorf("re-import should not duplicate schedules, got %+v", scheduled)
+	}
+
+	if err := importSettings(dst, bundlePath, true); err != nil {
+		t.Fatalf("importSettings() with replace error = %v", err)
+	}
+	if aliases, _ = loadAliases(dst); len(aliases) != 1 || aliases["work"] == "" {
+		t.Errorf("replaced aliases = %v, want only work", aliases)
+	}
+}
+
+// TestSettingsImport_AllOrNothing tests that an invalid bundle or a failed
+// save leaves every settings file as it was.
+func TestSettingsImport_AllOrNothing(t *testing.T) {
+	cfg := createTestConfig(t, t.TempDir())
+	if err := addAlias(cfg, "home", "Home.md"); err != nil {
+		t.Fatalf("addAlias() error = %v", err)
+	}
+
+	writeBundle := func(bundle string) string {
+		path := filepath.Join(t.TempDir(), "bundle.json")
+		if err := os.WriteFile(path, []byte(bundle), 0600); err != nil {
+			t.Fatalf("Failed to write bundle: %v", err)
+		}
+		return path
+	}
+
+	reserved := writeBundle(`{"aliases": {"work": "Work.md"}, "shortcuts": {"ws": "search work", "sync": "tasks"}}`)
+	if err := importSettings(cfg, reserved, false); err == nil || !strings.Contains(err.Error(), "sync") {
+		t.Errorf("importSettings() error = %v, want the reserved shortcut name rejected", err)
+	}
+
+	// A directory where the schedule file belongs makes its save fail after
+	// the aliases and shortcuts were prepared.
+	if err := os.Mkdir(getScheduleFile(cfg), 0750); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	valid := writeBundle(`{"aliases": {"work": "Work.md"}, "shortcuts": {"ws": "search work"}}`)
+	if err := importSettings(cfg, valid, false); err == nil {
+		t.Error("importSettings() error = nil, want the failed schedule save reported")
+	}
+
+	if aliases, _ := loadAliases(cfg); len(aliases) != 1 || aliases["home"] != "Home.md" {
+		t.Errorf("aliases = %v, want only home after failed imports", aliases)
+	}
+	if shortcuts, _ := loadShortcuts(cfg); len(shortcuts) != 0 {
+		t.Errorf("shortcuts = %v, want none after failed imports", shortcuts)
+	}
+}
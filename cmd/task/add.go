@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/AnishShah1803/jotr/internal/config"
+	"github.com/AnishShah1803/jotr/internal/services"
+)
+
+var (
+	addSection  string
+	addPriority string
+)
+
+var priorityFlagRegex = regexp.MustCompile(`^P[0-3]$`)
+
+var AddCmd = &cobra.Command{
+	Use:   "add [text...]",
+	Short: "Add a task to the todo list",
+	Long: `Add a task straight to the todo list without opening an editor.
+
+Words starting with # become tags, and a due date can be included in the
+text as due:YYYY-MM-DD. Quote #tags so your shell doesn't treat them as
+comments.
+
+Examples:
+  jotr add "Buy milk" --section Errands --priority P2 "#shopping"
+  jotr add Renew passport due:2025-06-01
+  jotr add "Call plumber" -s Home -p P1`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.LoadWithContext(cmd.Context(), "")
+		if err != nil {
+			return err
+		}
+
+		return addTask(cmd.Context(), cfg, strings.Join(args, " "))
+	},
+}
+
+func init() {
+	AddCmd.Flags().StringVarP(&addSection, "section", "s", "", "Section to add the task to (default: task section from config)")
+	AddCmd.Flags().StringVarP(&addPriority, "priority", "p", "", "Task priority (P0-P3)")
+}
+
+func addTask(ctx context.Context, cfg *config.LoadedConfig, text string) error {
+	priority := strings.ToUpper(addPriority)
+	if priority != "" && !priorityFlagRegex.MatchString(priority) {
+		return fmt.Errorf("invalid priority %q: must be P0, P1, P2, or P3", addPriority)
+	}
+
+	section := addSection
+	if section == "" {
+		section = cfg.Format.TaskSection
+	}
+
+	task, err := services.NewTaskService().AddTask(ctx, services.AddTaskOptions{
+		TodoPath:               cfg.TodoPath,
+		StatePath:              cfg.StatePath,
+		Text:                   text,
+		Section:                section,
+		Priority:               priority,
+		TodoFormat:             services.NewTodoFormat(cfg.Format),
+		RequireExistingSection: !cfg.Format.AutoCreateSectionsEnabled(),
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ Added task to %s: %s (id: %s)\n", task.Section, task.Text, task.ID)
+
+	return nil
+}
@@ -3,13 +3,21 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"path/filepath"
 
 	"github.com/spf13/cobra"
 
 	"github.com/AnishShah1803/jotr/internal/config"
 	"github.com/AnishShah1803/jotr/internal/services"
+	"github.com/AnishShah1803/jotr/internal/utils"
 )
 
+var archiveFrom string
+
+func init() {
+	ArchiveCmd.Flags().StringVar(&archiveFrom, "from", "", "Archive the completed tasks in this note instead of the todo list")
+}
+
 var ArchiveCmd = &cobra.Command{
 	Use:   "archive",
 	Short: "Archive completed tasks",
@@ -17,8 +25,13 @@ var ArchiveCmd = &cobra.Command{
 
 Archived tasks are removed from the active todo list.
 
+With --from, the completed tasks in a note (such as a daily note) are
+archived instead: they are removed from that note and marked archived in
+the sync state, and the todo list is left as it is.
+
 Examples:
   jotr archive                 # Archive completed tasks
+  jotr archive --from Diary/2025/03/2025-03-10.md
   jotr arc                     # Using alias`,
 	Aliases: []string{"arc"},
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -34,9 +47,20 @@ Examples:
 func archiveTasks(ctx context.Context, cfg *config.LoadedConfig) error {
 	taskService := services.NewTaskService()
 
+	notePath := archiveFrom
+	if notePath != "" && !filepath.IsAbs(notePath) && !utils.FileExists(notePath) {
+		notePath = filepath.Join(cfg.Paths.BaseDir, notePath)
+	}
+	if notePath != "" && !utils.FileExists(notePath) {
+		return fmt.Errorf("note not found: %s", archiveFrom)
+	}
+
 	result, err := taskService.ArchiveTasks(ctx, services.ArchiveOptions{
-		TodoPath: cfg.TodoPath,
-		BaseDir:  cfg.Paths.BaseDir,
+		TodoPath:   cfg.TodoPath,
+		StatePath:  cfg.StatePath,
+		BaseDir:    cfg.Paths.BaseDir,
+		NotePath:   notePath,
+		TodoFormat: services.NewTodoFormat(cfg.Format),
 	})
 	if err != nil {
 		return err
@@ -48,6 +72,10 @@ func archiveTasks(ctx context.Context, cfg *config.LoadedConfig) error {
 	}
 
 	fmt.Printf("✓ Archived %d completed tasks to: %s\n", result.ArchivedCount, result.ArchivePath)
+	if notePath != "" {
+		fmt.Printf("✓ %d active tasks remaining in: %s\n", result.RemainingCount, notePath)
+		return nil
+	}
 	fmt.Printf("✓ %d active tasks remaining\n", result.RemainingCount)
 
 	return nil
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/AnishShah1803/jotr/internal/config"
+	"github.com/AnishShah1803/jotr/internal/services"
+	"github.com/AnishShah1803/jotr/internal/state"
+	"github.com/AnishShah1803/jotr/internal/tasks"
+)
+
+var DoneCmd = &cobra.Command{
+	Use:   "done [text or id...]",
+	Short: "Mark a task complete by text or ID",
+	Long: `Mark a pending task complete without opening an editor.
+
+The task is found by ID or by text: a task whose text matches exactly wins,
+otherwise every pending task containing all of the given words is a match.
+If more than one task matches you are asked to pick one.
+
+The task is checked off in the todo file and in the daily note it came from.
+
+Examples:
+  jotr done "review proposal"
+  jotr done call plumber
+  jotr done 1a2b3c4d`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.LoadWithContext(cmd.Context(), "")
+		if err != nil {
+			return err
+		}
+
+		return completeTaskByText(cmd.Context(), cfg, strings.Join(args, " "), os.Stdin)
+	},
+}
+
+// completeTaskByText completes the pending task matching query, prompting on
+// in for a choice when several match.
+func completeTaskByText(ctx context.Context, cfg *config.LoadedConfig, query string, in io.Reader) error {
+	taskService := services.NewTaskService()
+	format := services.NewTodoFormat(cfg.Format)
+
+	matches, err := taskService.MatchPendingTasks(cfg.StatePath, query)
+	if err != nil {
+		return err
+	}
+
+	var selected state.TaskState
+	switch len(matches) {
+	case 0:
+		return fmt.Errorf("no pending task matches: %s", query)
+	case 1:
+		selected = matches[0]
+	default:
+		selected, err = selectTask(matches, in, format.Tasks)
+		if err != nil {
+			return err
+		}
+	}
+
+	task, err := taskService.CompleteTask(ctx, services.CompleteTaskOptions{
+		ID:            selected.ID,
+		TodoPath:      cfg.TodoPath,
+		StatePath:     cfg.StatePath,
+		TodoFormat:    format,
+		HideCompleted: !cfg.Format.ShowCompletedInTodoEnabled(),
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ Completed: %s\n", format.Tasks.StripTaskID(task.Text))
+
+	return nil
+}
+
+// selectTask lists matches and reads a 1-based choice from in.
+func selectTask(matches []state.TaskState, in io.Reader, taskOpts tasks.Options) (state.TaskState, error) {
+	fmt.Println("Multiple tasks found:")
+
+	for i, task := range matches {
+		fmt.Printf("%d. %s (%s)\n", i+1, taskOpts.StripTaskID(task.Text), task.Section)
+	}
+
+	fmt.Print("\nSelect task (1-", len(matches), "): ")
+
+	input, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && input == "" {
+		return state.TaskState{}, fmt.Errorf("failed to read selection: %w", err)
+	}
+
+	var selection int
+	if _, err := fmt.Sscanf(strings.TrimSpace(input), "%d", &selection); err != nil {
+		return state.TaskState{}, fmt.Errorf("failed to parse selection: %w", err)
+	}
+
+	if selection < 1 || selection > len(matches) {
+		return state.TaskState{}, fmt.Errorf("invalid selection")
+	}
+
+	return matches[selection-1], nil
+}
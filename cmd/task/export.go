@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/AnishShah1803/jotr/internal/config"
+	"github.com/AnishShah1803/jotr/internal/services"
+	"github.com/AnishShah1803/jotr/internal/state"
+	"github.com/AnishShah1803/jotr/internal/tasks"
+)
+
+// csvHeader lists the columns written by writeTasksCSV.
+var csvHeader = []string{"id", "text", "section", "priority", "completed", "created_date", "completed_date", "tags", "due"}
+
+func exportTasks(cfg *config.LoadedConfig, out io.Writer) error {
+	if tasksFormat != "csv" {
+		return fmt.Errorf("unsupported export format: %s (supported: csv)", tasksFormat)
+	}
+
+	todoState, err := state.Read(cfg.StatePath)
+	if err != nil {
+		return err
+	}
+
+	return writeTasksCSV(out, todoState, services.TaskOptions(cfg.Format))
+}
+
+// writeTasksCSV writes every task in state as CSV, ordered by creation date
+// and then ID. Tags are joined with semicolons.
+func writeTasksCSV(out io.Writer, todoState *state.TodoState, taskOpts tasks.Options) error {
+	all := make([]state.TaskState, 0, len(todoState.Tasks))
+	for _, task := range todoState.Tasks {
+		all = append(all, task)
+	}
+
+	sort.Slice(all, func(i, j int) bool {
+		if all[i].CreatedDate != all[j].CreatedDate {
+			return all[i].CreatedDate < all[j].CreatedDate
+		}
+		return all[i].ID < all[j].ID
+	})
+
+	w := csv.NewWriter(out)
+	if err := w.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for _, task := range all {
+		due := ""
+		if dueDate, ok := tasks.DueDate(tasks.Task{Text: task.Text}); ok {
+			due = dueDate.Format("2006-01-02")
+		}
+
+		record := []string{
+			task.ID,
+			tasks.StripCompletedTag(taskOpts.StripTaskID(task.Text)),
+			task.Section,
+			task.Priority,
+			strconv.FormatBool(task.Completed),
+			task.CreatedDate,
+			task.CompletedDate,
+			strings.Join(task.Tags, ";"),
+			due,
+		}
+		if err := w.Write(record); err != nil {
+			return err
+		}
+	}
+
+	w.Flush()
+
+	return w.Error()
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/AnishShah1803/jotr/internal/config"
+	"github.com/AnishShah1803/jotr/internal/output"
+	"github.com/AnishShah1803/jotr/internal/services"
+	"github.com/AnishShah1803/jotr/internal/tasks"
+)
+
+var focusCount int
+
+func init() {
+	FocusCmd.Flags().IntVarP(&focusCount, "count", "n", 0, "Number of tasks to show (default format.focus_count, or 3)")
+}
+
+var FocusCmd = &cobra.Command{
+	Use:   "focus",
+	Short: "Show today's top priority tasks",
+	Long: `Show only the few tasks that matter most right now.
+
+Active tasks are ranked by priority (P0 first), then by due date (earliest
+first), and only the top ones are shown. The number comes from --count or
+format.focus_count in the config, and defaults to 3.
+
+Examples:
+  jotr focus                   # Top 3 tasks
+  jotr focus -n 5              # Top 5 tasks`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.LoadWithContext(cmd.Context(), "")
+		if err != nil {
+			return err
+		}
+
+		return showFocus(cfg)
+	},
+}
+
+func showFocus(cfg *config.LoadedConfig) error {
+	count := focusCount
+	if count == 0 {
+		count = cfg.Format.FocusCountOrDefault()
+	}
+	if count < 1 {
+		return fmt.Errorf("--count must be at least 1")
+	}
+
+	focus, err := services.NewTaskService().FocusTasks(cfg.StatePath, count)
+	if err != nil {
+		return err
+	}
+
+	if len(focus) == 0 {
+		fmt.Println("✨ No pending tasks!")
+		return nil
+	}
+
+	fmt.Println("🎯 Focus")
+	fmt.Println()
+
+	colorOn := output.StdoutColorEnabled()
+	taskOpts := services.TaskOptions(cfg.Format)
+	for i, task := range focus {
+		task.Text = output.ColorizeTags(taskOpts.StripTaskID(task.Text), cfg.Format.TagColors, colorOn)
+		fmt.Printf("  %d. %s\n", i+1, tasks.FormatTask(task))
+	}
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/AnishShah1803/jotr/internal/config"
+	"github.com/AnishShah1803/jotr/internal/state"
+)
+
+var StateCmd = &cobra.Command{
+	Use:   "state repair",
+	Short: "Maintain the task sync state file",
+	Long: `Maintain the task sync state file.
+
+"state repair" recovers a corrupt state file. It restores the .bak backup
+next to the state file if there is a readable one, and otherwise keeps every
+task entry that can still be read, listing what was dropped. The corrupt file
+is kept alongside with a .corrupt suffix.
+
+Examples:
+  jotr state repair            # Recover a corrupt state file`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if args[0] != "repair" {
+			return fmt.Errorf("unknown state action: %s (expected repair)", args[0])
+		}
+
+		cfg, err := config.LoadWithContext(cmd.Context(), "")
+		if err != nil {
+			return err
+		}
+
+		return repairState(cfg)
+	},
+}
+
+func repairState(cfg *config.LoadedConfig) error {
+	result, err := state.Repair(cfg.StatePath)
+	if err != nil {
+		return fmt.Errorf("failed to repair state file: %w", err)
+	}
+
+	switch result.Source {
+	case state.RepairSourceNone:
+		fmt.Println("✓ State file is valid, nothing to repair")
+		return nil
+	case state.RepairSourceBackup:
+		fmt.Printf("✓ Restored %d tasks from backup\n", result.Recovered)
+	default:
+		fmt.Printf("✓ Recovered %d tasks from the corrupt state file\n", result.Recovered)
+	}
+
+	for _, dropped := range result.Dropped {
+		fmt.Printf("  dropped: %s\n", dropped)
+	}
+	fmt.Printf("Corrupt file kept at %s\n", result.CorruptCopy)
+
+	return nil
+}
@@ -9,12 +9,17 @@ import (
 	"github.com/AnishShah1803/jotr/internal/config"
 	"github.com/AnishShah1803/jotr/internal/options"
 	"github.com/AnishShah1803/jotr/internal/services"
+	"github.com/AnishShah1803/jotr/internal/tasks"
 )
 
-var statsTimeRange = options.NewTimeRangeOption()
+var (
+	statsTimeRange = options.NewTimeRangeOption()
+	statsSection   string
+)
 
 func init() {
 	statsTimeRange.AddFlags(StatsCmd)
+	StatsCmd.Flags().StringVar(&statsSection, "section", "", "Only count tasks in this section")
 }
 
 var StatsCmd = &cobra.Command{
@@ -26,6 +31,7 @@ Examples:
   jotr stats                   # Show all-time stats
   jotr stats --week           # Show stats for last 7 days
   jotr stats --month          # Show stats for last 30 days
+  jotr stats --section Urgent # Show stats for one section
   jotr st                     # Using alias`,
 	Aliases: []string{"st"},
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -41,10 +47,13 @@ Examples:
 func showStats(ctx context.Context, cfg *config.LoadedConfig) error {
 	taskService := services.NewTaskService()
 
-	stats, err := taskService.GetTaskStats(ctx, cfg.TodoPath)
+	stats, err := taskService.GetSectionTaskStats(ctx, cfg.TodoPath, statsSection, services.NewTodoFormat(cfg.Format))
 	if err != nil {
 		return fmt.Errorf("failed to read tasks: %w", err)
 	}
+	if statsSection != "" && stats.Total == 0 {
+		return fmt.Errorf("no tasks in section: %s", statsSection)
+	}
 
 	fmt.Println("📊 Task Statistics")
 	fmt.Println("==================")
@@ -62,6 +71,9 @@ func showStats(ctx context.Context, cfg *config.LoadedConfig) error {
 	} else {
 		fmt.Println("📅 All Time")
 	}
+	if statsSection != "" {
+		fmt.Printf("📂 Section: %s\n", statsSection)
+	}
 	fmt.Println()
 
 	fmt.Printf("Total Tasks:      %d\n", stats.Total)
@@ -71,26 +83,25 @@ func showStats(ctx context.Context, cfg *config.LoadedConfig) error {
 
 	fmt.Println("By Priority:")
 
-	priorities := []string{"P0", "P1", "P2", "P3", "None"}
-	for _, priority := range priorities {
-		if taskList, ok := stats.ByPriority[priority]; ok {
-			completedCount := 0
+	for _, priority := range tasks.SortedPriorities(stats.ByPriority) {
+		taskList := stats.ByPriority[priority]
+		completedCount := 0
 
-			for _, task := range taskList {
-				if task.Completed {
-					completedCount++
-				}
+		for _, task := range taskList {
+			if task.Completed {
+				completedCount++
 			}
-
-			fmt.Printf("  %-10s %d tasks (%d completed)\n", priority+":", len(taskList), completedCount)
 		}
+
+		fmt.Printf("  %-10s %d tasks (%d completed)\n", priority+":", len(taskList), completedCount)
 	}
 
 	fmt.Println()
 
 	fmt.Println("By Section:")
 
-	for section, taskList := range stats.BySection {
+	for _, section := range tasks.SortedSections(stats.BySection) {
+		taskList := stats.BySection[section]
 		completedCount := 0
 
 		for _, task := range taskList {
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/AnishShah1803/jotr/internal/config"
+	"github.com/AnishShah1803/jotr/internal/services"
+	"github.com/AnishShah1803/jotr/internal/state"
+)
+
+var statusJSON bool
+
+var StatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Compare sync state with the todo file and today's note",
+	Long: `Compare the sync state with the todo file and today's daily note without
+changing anything.
+
+Reports tasks that are in a file but not in state, tasks in state that are
+missing from a file, and tasks whose text, priority, tags or completion
+differ. Use it to see what the next sync would act on.
+
+Examples:
+  jotr status                  # Show differences
+  jotr status --json           # Output in JSON format`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.LoadWithContext(cmd.Context(), "")
+		if err != nil {
+			return err
+		}
+
+		return showSyncStatus(cmd.Context(), cfg)
+	},
+}
+
+func init() {
+	StatusCmd.Flags().BoolVar(&statusJSON, "json", false, "Output in JSON format")
+}
+
+func showSyncStatus(ctx context.Context, cfg *config.LoadedConfig) error {
+	taskService := services.NewTaskService()
+
+	result, err := taskService.SyncStatus(ctx, services.SyncOptions{
+		DiaryPath:     cfg.DiaryPath,
+		TodoPath:      cfg.TodoPath,
+		StatePath:     cfg.StatePath,
+		TaskSection:   cfg.Format.TaskSection,
+		TodoFormat:    services.NewTodoFormat(cfg.Format),
+		HideCompleted: !cfg.Format.ShowCompletedInTodoEnabled(),
+	})
+	if err != nil {
+		return err
+	}
+
+	if statusJSON {
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal result to JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	fmt.Printf("State: %d tasks (%s)\n", result.StateTasks, result.StatePath)
+	printSourceStatus("Todo file", result.Todo)
+	printSourceStatus("Today's note", result.Daily)
+
+	if result.InSync() {
+		fmt.Println()
+		fmt.Println("✓ State matches the todo file and today's note")
+	}
+
+	return nil
+}
+
+func printSourceStatus(label string, status services.SourceStatus) {
+	fmt.Println()
+	if !status.Exists {
+		fmt.Printf("%s: not found (%s)\n", label, status.Path)
+		return
+	}
+
+	fmt.Printf("%s: %s\n", label, status.Path)
+	if status.InSync() {
+		fmt.Println("  in sync")
+		return
+	}
+
+	printStatusDetails("Not in state", status.NotInState)
+	printStatusDetails("Missing from file", status.MissingFromFile)
+	printStatusDetails("Mismatched", status.Mismatches)
+}
+
+func printStatusDetails(heading string, details []state.TaskChangeDetail) {
+	if len(details) == 0 {
+		return
+	}
+
+	fmt.Printf("  %s (%d):\n", heading, len(details))
+	for _, detail := range details {
+		if detail.To != "" || detail.From != "" {
+			fmt.Printf("    ~ [%s] %s (%s)\n", detail.ID, detail.From, detail.Details)
+			if detail.From != detail.To {
+				fmt.Printf("        state: %s\n", detail.From)
+				fmt.Printf("        file:  %s\n", detail.To)
+			}
+			continue
+		}
+		fmt.Printf("    - [%s] %s\n", detail.ID, detail.Text)
+	}
+}
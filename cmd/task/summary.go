@@ -7,6 +7,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/AnishShah1803/jotr/internal/config"
+	"github.com/AnishShah1803/jotr/internal/output"
 	"github.com/AnishShah1803/jotr/internal/services"
 	"github.com/AnishShah1803/jotr/internal/tasks"
 )
@@ -37,13 +38,14 @@ Examples:
 func ShowSummary(ctx context.Context, cfg *config.LoadedConfig) error {
 
 	taskService := services.NewTaskService()
+	format := services.NewTodoFormat(cfg.Format)
 
-	stats, err := taskService.GetTaskStats(ctx, cfg.TodoPath)
+	stats, err := taskService.GetTaskStats(ctx, cfg.TodoPath, format)
 	if err != nil {
 		return fmt.Errorf("failed to read tasks: %w", err)
 	}
 
-	allTasks, err := taskService.GetAllTasks(ctx, cfg.TodoPath)
+	allTasks, err := taskService.GetAllTasks(ctx, cfg.TodoPath, format)
 	if err != nil {
 		return fmt.Errorf("failed to read tasks: %w", err)
 	}
@@ -57,6 +59,7 @@ func ShowSummary(ctx context.Context, cfg *config.LoadedConfig) error {
 	}
 
 	byPriority := tasks.GroupByPriority(pendingTasks)
+	colorOn := output.StdoutColorEnabled()
 
 	fmt.Println("📋 Task Summary")
 	fmt.Println("===============")
@@ -74,7 +77,7 @@ func ShowSummary(ctx context.Context, cfg *config.LoadedConfig) error {
 		fmt.Printf("⚠️  Overdue (%d):\n", len(overdue))
 
 		for _, task := range overdue {
-			fmt.Printf("  %s\n", tasks.FormatTask(task))
+			fmt.Printf("  %s\n", output.ColorizeTags(tasks.FormatTask(task), cfg.Format.TagColors, colorOn))
 		}
 
 		fmt.Println()
@@ -84,7 +87,7 @@ func ShowSummary(ctx context.Context, cfg *config.LoadedConfig) error {
 		fmt.Printf("🔴 P0 - Critical (%d):\n", len(p0Tasks))
 
 		for _, task := range p0Tasks {
-			fmt.Printf("  %s\n", tasks.FormatTask(task))
+			fmt.Printf("  %s\n", output.ColorizeTags(tasks.FormatTask(task), cfg.Format.TagColors, colorOn))
 		}
 
 		fmt.Println()
@@ -94,7 +97,7 @@ func ShowSummary(ctx context.Context, cfg *config.LoadedConfig) error {
 		fmt.Printf("🟠 P1 - High (%d):\n", len(p1Tasks))
 
 		for _, task := range p1Tasks {
-			fmt.Printf("  %s\n", tasks.FormatTask(task))
+			fmt.Printf("  %s\n", output.ColorizeTags(tasks.FormatTask(task), cfg.Format.TagColors, colorOn))
 		}
 
 		fmt.Println()
@@ -104,7 +107,7 @@ func ShowSummary(ctx context.Context, cfg *config.LoadedConfig) error {
 		fmt.Printf("🟡 P2 - Medium (%d):\n", len(p2Tasks))
 
 		for _, task := range p2Tasks {
-			fmt.Printf("  %s\n", tasks.FormatTask(task))
+			fmt.Printf("  %s\n", output.ColorizeTags(tasks.FormatTask(task), cfg.Format.TagColors, colorOn))
 		}
 
 		fmt.Println()
@@ -114,7 +117,7 @@ func ShowSummary(ctx context.Context, cfg *config.LoadedConfig) error {
 		fmt.Printf("🟢 P3 - Low (%d):\n", len(p3Tasks))
 
 		for _, task := range p3Tasks {
-			fmt.Printf("  %s\n", tasks.FormatTask(task))
+			fmt.Printf("  %s\n", output.ColorizeTags(tasks.FormatTask(task), cfg.Format.TagColors, colorOn))
 		}
 
 		fmt.Println()
@@ -124,7 +127,7 @@ func ShowSummary(ctx context.Context, cfg *config.LoadedConfig) error {
 		fmt.Printf("⚪ No Priority (%d):\n", len(noPriority))
 
 		for _, task := range noPriority {
-			fmt.Printf("  %s\n", tasks.FormatTask(task))
+			fmt.Printf("  %s\n", output.ColorizeTags(tasks.FormatTask(task), cfg.Format.TagColors, colorOn))
 		}
 
 		fmt.Println()
@@ -1,17 +1,26 @@
 package cmd
 
 import (
+	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"strings"
+	"time"
 
+	"github.com/mattn/go-isatty"
 	"github.com/spf13/cobra"
 
 	"github.com/AnishShah1803/jotr/internal/config"
+	"github.com/AnishShah1803/jotr/internal/hooks"
 	"github.com/AnishShah1803/jotr/internal/output"
 	"github.com/AnishShah1803/jotr/internal/services"
 	"github.com/AnishShah1803/jotr/internal/state"
+	"github.com/AnishShah1803/jotr/internal/tasks"
+	"github.com/AnishShah1803/jotr/internal/utils"
 )
 
 var (
@@ -20,6 +29,13 @@ var (
 	syncJSON    bool
 	syncVerbose bool
 	syncNoColor bool
+	syncStats   bool
+	syncAlerts  bool
+
+	syncConfirmDeletions bool
+	syncForce            bool
+	syncResolve          bool
+	syncDate             string
 )
 
 func isColorEnabled() bool {
@@ -63,14 +79,29 @@ var SyncCmd = &cobra.Command{
 
 Changes in daily notes are propagated to the todo list.
 Changes in the todo list are propagated to daily notes.
-Conflicts are detected and reported.
+Conflicts are detected and reported; with --resolve you are asked which
+version of each conflicting task to keep.
+Pending tasks missing from both the daily note and the todo list are deleted;
+with --confirm-deletions they are listed and kept until you confirm (or pass
+--force). Completed tasks are never deleted.
 
 Examples:
   jotr sync                    # Sync tasks bidirectionally
   jotr s                       # Using alias
   jotr sync --dry-run          # Preview changes without applying
   jotr sync --json             # Output in JSON format
-  jotr sync --quiet            # Show only summary counts`,
+  jotr sync --quiet            # Show only summary counts
+  jotr sync --stats            # Show a breakdown of added/updated/deleted tasks
+  jotr sync --stats --json     # Include the breakdown in JSON output
+  jotr sync --confirm-deletions  # Ask before removing tasks missing from both files
+  jotr sync --date 2025-06-01  # Sync that day's note as if it were today
+  jotr sync --resolve          # Pick the daily or todo version of each conflict
+  jotr sync --alerts           # List overdue and due-today tasks afterwards
+
+Exit codes:
+  0  sync completed (or nothing to sync)
+  1  an error occurred
+  3  conflicts were detected and nothing was applied`,
 	Aliases: []string{"s"},
 	RunE: func(cmd *cobra.Command, args []string) error {
 		cfg, err := config.LoadWithContext(cmd.Context(), "")
@@ -78,7 +109,13 @@ Examples:
 			return err
 		}
 
-		return syncTasks(cmd.Context(), cfg)
+		err = syncTasks(cmd.Context(), cfg)
+		if errors.Is(err, utils.ErrSyncConflicts) {
+			cmd.SilenceUsage = true
+			cmd.SilenceErrors = true
+		}
+
+		return err
 	},
 }
 
@@ -88,17 +125,38 @@ func init() {
 	SyncCmd.Flags().BoolVar(&syncJSON, "json", false, "Output in JSON format")
 	SyncCmd.Flags().BoolVar(&syncVerbose, "verbose", false, "Enable verbose output with detailed task information")
 	SyncCmd.Flags().BoolVar(&syncNoColor, "no-color", false, "Disable colored output")
+	SyncCmd.Flags().BoolVar(&syncStats, "stats", false, "Show a breakdown of added, updated and deleted tasks and conflicts")
+	SyncCmd.Flags().BoolVar(&syncConfirmDeletions, "confirm-deletions", false, "Ask before deleting tasks missing from both the daily note and todo list")
+	SyncCmd.Flags().BoolVar(&syncResolve, "resolve", false, "Choose the daily or todo version of each conflicting task interactively")
+	SyncCmd.Flags().BoolVar(&syncForce, "force", false, "Apply deletions without confirmation when --confirm-deletions is set")
+	SyncCmd.Flags().BoolVar(&syncAlerts, "alerts", false, "After syncing, list overdue and due-today tasks")
+	SyncCmd.Flags().StringVar(&syncDate, "date", "", "Treat this date (YYYY-MM-DD) as today, for backfilling past daily notes")
 }
 
 func syncTasks(ctx context.Context, cfg *config.LoadedConfig) error {
 	taskService := services.NewTaskService()
+	if syncDate != "" {
+		date, err := time.ParseInLocation("2006-01-02", syncDate, time.Local)
+		if err != nil {
+			return fmt.Errorf("invalid --date (use YYYY-MM-DD): %w", err)
+		}
+		taskService = services.NewTaskServiceWithClock(func() time.Time { return date })
+	}
 
 	opts := services.SyncOptions{
-		DiaryPath:   cfg.DiaryPath,
-		TodoPath:    cfg.TodoPath,
-		StatePath:   cfg.StatePath,
-		TaskSection: cfg.Format.TaskSection,
-		DryRun:      syncDryRun,
+		DiaryPath:        cfg.DiaryPath,
+		TodoPath:         cfg.TodoPath,
+		StatePath:        cfg.StatePath,
+		TaskSection:      cfg.Format.TaskSection,
+		TodoFormat:       services.NewTodoFormat(cfg.Format),
+		DryRun:           syncDryRun,
+		ConfirmDeletions: syncConfirmDeletions && !syncForce,
+		HideCompleted:    !cfg.Format.ShowCompletedInTodoEnabled(),
+
+		BaseDir:               cfg.Paths.BaseDir,
+		AutoArchiveAfterDays:  cfg.Archive.AutoAfterDays,
+		PruneRemovedAfterDays: cfg.Archive.PruneRemovedAfterDays,
+		LinkDuplicates:        cfg.Format.LinkDuplicateTasks,
 	}
 
 	result, err := taskService.SyncTasks(ctx, opts)
@@ -106,15 +164,186 @@ func syncTasks(ctx context.Context, cfg *config.LoadedConfig) error {
 		return err
 	}
 
-	if syncJSON {
-		return outputSyncJSON(result)
+	if len(result.Conflicts) > 0 && syncResolve && !syncJSON && !syncDryRun {
+		result, err = resolveSyncConflicts(ctx, taskService, opts, result, os.Stdin)
+		if err != nil {
+			return err
+		}
+	}
+
+	if syncJSON && syncStats {
+		err = outputSyncStatsJSON(result)
+	} else if syncJSON {
+		err = outputSyncJSON(result)
+	} else if syncStats {
+		err = outputSyncStats(result)
+	} else if syncQuiet {
+		err = outputSyncQuiet(result)
+	} else {
+		err = outputSyncDefault(result, syncVerbose)
+	}
+
+	if err != nil {
+		return err
 	}
 
-	if syncQuiet {
-		return outputSyncQuiet(result)
+	if len(result.Conflicts) > 0 {
+		return utils.NewExitError(utils.ExitCodeConflicts, utils.ErrSyncConflicts)
 	}
 
-	return outputSyncDefault(result, syncVerbose)
+	if syncAlerts && !syncDryRun && !syncJSON {
+		if err := printSyncAlerts(result.StatePath, taskService.Now(), opts.TodoFormat.Tasks, os.Stdout); err != nil {
+			return err
+		}
+	}
+
+	if len(result.PendingDeletions) > 0 && !syncDryRun && !syncJSON {
+		if err := confirmPendingDeletions(ctx, taskService, opts, len(result.PendingDeletions)); err != nil {
+			return err
+		}
+	}
+
+	// The hook runs last so it sees any deletions confirmed above.
+	if !syncDryRun {
+		hooks.RunOrWarn(ctx, os.Stderr, cfg.Hooks.PostSync, cfg.Paths.BaseDir, result.TodoPath, result.StatePath, result.DailyPath)
+	}
+
+	return nil
+}
+
+// syncAlertLimit caps how many tasks each --alerts group lists.
+const syncAlertLimit = 5
+
+// printSyncAlerts lists the tasks in the synced state at statePath that are
+// overdue or due on the day of now, the date that was synced. Nothing is
+// printed when there are none.
+func printSyncAlerts(statePath string, now time.Time, taskOpts tasks.Options, out io.Writer) error {
+	todoState, err := state.Read(statePath)
+	if err != nil {
+		return err
+	}
+
+	var overdue, dueToday []state.TaskState
+	for _, task := range sortedStateTasks(todoState, taskOpts) {
+		switch t := stateTaskToTask(task); {
+		case tasks.DueOn(t, now):
+			dueToday = append(dueToday, task)
+		case tasks.IsOverdueAt(t, now):
+			overdue = append(overdue, task)
+		}
+	}
+
+	if len(overdue) == 0 && len(dueToday) == 0 {
+		return nil
+	}
+
+	fmt.Fprintf(out, "\n⚠ %d overdue, %d due today\n", len(overdue), len(dueToday))
+	printSyncAlertGroup(out, "Overdue", overdue, taskOpts)
+	printSyncAlertGroup(out, "Due today", dueToday, taskOpts)
+
+	return nil
+}
+
+func printSyncAlertGroup(out io.Writer, title string, group []state.TaskState, taskOpts tasks.Options) {
+	if len(group) == 0 {
+		return
+	}
+
+	fmt.Fprintf(out, "  %s:\n", title)
+	for i, task := range group {
+		if i == syncAlertLimit {
+			fmt.Fprintf(out, "    ... and %d more\n", len(group)-syncAlertLimit)
+			break
+		}
+		fmt.Fprintf(out, "    - %s\n", taskOpts.StripTaskID(task.Text))
+	}
+}
+
+// resolveSyncConflicts asks, for each conflict in result, whether to keep the
+// daily note or the todo list version, reading answers from in, and syncs
+// again with those choices. Skipped conflicts still stop the sync, so nothing
+// is written until every conflict is resolved.
+func resolveSyncConflicts(ctx context.Context, taskService *services.TaskService, opts services.SyncOptions, result *services.SyncResult, in io.Reader) (*services.SyncResult, error) {
+	resolutions, err := promptConflictResolutions(result.ConflictsDetail, in)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resolutions) == 0 {
+		return result, nil
+	}
+
+	opts.Resolutions = resolutions
+	return taskService.SyncTasks(ctx, opts)
+}
+
+// promptConflictResolutions shows each conflict and reads a choice of daily,
+// todo or skip for it. The returned map holds the resolved task IDs.
+func promptConflictResolutions(conflicts []state.ConflictDetail, in io.Reader) (map[string]string, error) {
+	reader := bufio.NewReader(in)
+	resolutions := make(map[string]string)
+
+	for i, conflict := range conflicts {
+		fmt.Printf("\nConflict %d of %d: %s\n", i+1, len(conflicts), conflict.Reason)
+		fmt.Printf("  Daily: %s\n", describeConflictSide(conflict.TextDaily, conflict.CompletedDaily))
+		fmt.Printf("  Todo:  %s\n", describeConflictSide(conflict.TextTodo, conflict.CompletedTodo))
+
+		for {
+			fmt.Print("Keep [d]aily, [t]odo or [s]kip? ")
+
+			input, err := reader.ReadString('\n')
+			if err != nil && input == "" {
+				return nil, fmt.Errorf("failed to read choice: %w", err)
+			}
+
+			choice := strings.ToLower(strings.TrimSpace(input))
+			switch choice {
+			case "d", state.ResolveDaily:
+				resolutions[conflict.ID] = state.ResolveDaily
+			case "t", state.ResolveTodo:
+				resolutions[conflict.ID] = state.ResolveTodo
+			case "s", "skip":
+			default:
+				fmt.Println("Please answer d, t or s.")
+				continue
+			}
+			break
+		}
+	}
+
+	return resolutions, nil
+}
+
+// describeConflictSide formats one side of a conflict for the resolver prompt.
+func describeConflictSide(text string, completed bool) string {
+	if completed {
+		return fmt.Sprintf("%q (done)", text)
+	}
+	return fmt.Sprintf("%q", text)
+}
+
+// confirmPendingDeletions asks whether to apply withheld deletions and, if
+// so, syncs again with deletions enabled. Without a terminal to ask on, the
+// deletions stay withheld.
+func confirmPendingDeletions(ctx context.Context, taskService *services.TaskService, opts services.SyncOptions, count int) error {
+	if !isatty.IsTerminal(os.Stdin.Fd()) {
+		fmt.Println("Run with --force to apply the pending deletions.")
+		return nil
+	}
+
+	if !utils.PromptYesNo(fmt.Sprintf("\nDelete %d task(s) missing from both files? [y/N]: ", count)) {
+		fmt.Println("No tasks deleted")
+		return nil
+	}
+
+	opts.ConfirmDeletions = false
+	result, err := taskService.SyncTasks(ctx, opts)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ Deleted %d task(s)\n", result.DeletedTasks)
+	return nil
 }
 
 func outputSyncJSON(result *services.SyncResult) error {
@@ -126,19 +355,99 @@ func outputSyncJSON(result *services.SyncResult) error {
 	return nil
 }
 
+// syncSourceStats counts the changes a sync picked up from one source.
+type syncSourceStats struct {
+	Added   int `json:"added"`
+	Updated int `json:"updated"`
+}
+
+// syncStatsSummary is the --stats breakdown of a sync result.
+type syncStatsSummary struct {
+	FromDaily syncSourceStats `json:"from_daily"`
+	FromTodo  syncSourceStats `json:"from_todo"`
+	Deleted   int             `json:"deleted"`
+	Pending   int             `json:"pending_deletions"`
+	Conflicts int             `json:"conflicts"`
+}
+
+func buildSyncStats(result *services.SyncResult) syncStatsSummary {
+	return syncStatsSummary{
+		FromDaily: syncSourceStats{
+			Added:   len(result.AddedFromDaily),
+			Updated: len(result.UpdatedFromDaily),
+		},
+		FromTodo: syncSourceStats{
+			Added:   len(result.AddedFromTodo),
+			Updated: len(result.UpdatedFromTodo),
+		},
+		Deleted:   len(result.DeletedTasksDetail),
+		Pending:   len(result.PendingDeletions),
+		Conflicts: len(result.Conflicts),
+	}
+}
+
+func outputSyncStatsJSON(result *services.SyncResult) error {
+	data, err := json.MarshalIndent(struct {
+		*services.SyncResult
+		Stats syncStatsSummary `json:"stats"`
+	}{result, buildSyncStats(result)}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal result to JSON: %w", err)
+	}
+	fmt.Println(string(data))
+	return nil
+}
+
+func outputSyncStats(result *services.SyncResult) error {
+	stats := buildSyncStats(result)
+
+	for _, warning := range result.Warnings {
+		fmt.Printf("Warning: %s\n", warning)
+	}
+
+	if syncDryRun {
+		fmt.Println("Sync stats (dry run - no changes made):")
+	} else {
+		fmt.Println("Sync stats:")
+	}
+	fmt.Printf("  Tasks checked: %d\n", result.TasksRead)
+	fmt.Printf("  Added:         %d (%d from daily notes, %d from todo list)\n",
+		stats.FromDaily.Added+stats.FromTodo.Added, stats.FromDaily.Added, stats.FromTodo.Added)
+	fmt.Printf("  Updated:       %d (%d from daily notes, %d from todo list)\n",
+		stats.FromDaily.Updated+stats.FromTodo.Updated, stats.FromDaily.Updated, stats.FromTodo.Updated)
+	fmt.Printf("  Deleted:       %d\n", stats.Deleted)
+	if stats.Pending > 0 {
+		fmt.Printf("  Pending:       %d deletion(s) awaiting confirmation\n", stats.Pending)
+	}
+	fmt.Printf("  Conflicts:     %d\n", stats.Conflicts)
+
+	if stats.Conflicts > 0 {
+		fmt.Println("\nResolve conflicts manually and run sync again.")
+	}
+
+	return nil
+}
+
 func outputSyncQuiet(result *services.SyncResult) error {
+	for _, warning := range result.Warnings {
+		fmt.Printf("Warning: %s\n", warning)
+	}
+
 	if len(result.Conflicts) > 0 {
 		fmt.Printf("Conflicts: %d\n", len(result.Conflicts))
 		return nil
 	}
 
 	totalChanges := result.TasksFromDaily + result.TasksFromTodo
-	if totalChanges == 0 && result.DeletedTasks == 0 {
+	if totalChanges == 0 && result.DeletedTasks == 0 && len(result.PendingDeletions) == 0 {
 		fmt.Println("No changes")
 		return nil
 	}
 
 	fmt.Printf("Daily: %d, Todo: %d, Deleted: %d\n", result.TasksFromDaily, result.TasksFromTodo, result.DeletedTasks)
+	if len(result.PendingDeletions) > 0 {
+		fmt.Printf("Pending deletions: %d\n", len(result.PendingDeletions))
+	}
 	return nil
 }
 
@@ -150,6 +459,13 @@ func outputSyncDefault(result *services.SyncResult, verbose bool) error {
 		fmt.Println()
 	}
 
+	if len(result.Warnings) > 0 {
+		for _, warning := range result.Warnings {
+			fmt.Printf("%s %s\n", formatPrefix("⚠", c), warning)
+		}
+		fmt.Println()
+	}
+
 	if len(result.Conflicts) > 0 {
 		fmt.Printf("%s Conflicts detected:\n", formatPrefix("⚠", c))
 		for _, conflict := range result.ConflictsDetail {
@@ -164,7 +480,7 @@ func outputSyncDefault(result *services.SyncResult, verbose bool) error {
 	}
 
 	totalChanges := result.TasksFromDaily + result.TasksFromTodo
-	if totalChanges == 0 && result.DeletedTasks == 0 {
+	if totalChanges == 0 && result.DeletedTasks == 0 && len(result.PendingDeletions) == 0 && result.AutoArchived == 0 && len(result.PrunedTaskIDs) == 0 && len(result.ReconciledIDs) == 0 {
 		fmt.Printf("%s Everything is in sync\n", formatPrefix("✓", c))
 		return nil
 	}
@@ -199,6 +515,22 @@ func outputSyncDefault(result *services.SyncResult, verbose bool) error {
 		fmt.Println()
 	}
 
+	if len(result.ReconciledIDs) > 0 {
+		fmt.Println("Task IDs changed by hand:")
+		for _, migration := range result.ReconciledIDs {
+			fmt.Printf("  %s %s → %s\n", formatPrefix("~", c), migration.From, migration.To)
+		}
+		fmt.Println()
+	}
+
+	if len(result.PendingDeletions) > 0 {
+		fmt.Println("Pending deletion (missing from both files, not yet deleted):")
+		for _, task := range result.PendingDeletions {
+			fmt.Printf("  %s \"%s\" (id: %s)\n", formatPrefix("⚠", c), task.Text, task.ID)
+		}
+		fmt.Println()
+	}
+
 	fmt.Println("Summary:")
 	fmt.Printf("  %d tasks checked\n", result.TasksRead)
 	if result.TasksFromDaily > 0 {
@@ -210,6 +542,15 @@ func outputSyncDefault(result *services.SyncResult, verbose bool) error {
 	if result.DeletedTasks > 0 {
 		fmt.Printf("  %d task(s) deleted\n", result.DeletedTasks)
 	}
+	if result.AutoArchived > 0 {
+		fmt.Printf("  %d completed task(s) archived\n", result.AutoArchived)
+	}
+	if len(result.PrunedTaskIDs) > 0 {
+		fmt.Printf("  %d removed completed task(s) pruned from the todo file\n", len(result.PrunedTaskIDs))
+	}
+	if len(result.ReconciledIDs) > 0 {
+		fmt.Printf("  %d task ID(s) reconciled\n", len(result.ReconciledIDs))
+	}
 
 	if verbose {
 		if len(result.ChangedTaskIDs) > 0 {
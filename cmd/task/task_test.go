@@ -2,6 +2,10 @@ package cmd
 
 import (
 	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -10,7 +14,10 @@ import (
 
 	"github.com/AnishShah1803/jotr/internal/config"
 	"github.com/AnishShah1803/jotr/internal/notes"
+	"github.com/AnishShah1803/jotr/internal/services"
+	"github.com/AnishShah1803/jotr/internal/state"
 	"github.com/AnishShah1803/jotr/internal/tasks"
+	"github.com/AnishShah1803/jotr/internal/testhelpers"
 	"github.com/AnishShah1803/jotr/internal/utils"
 )
 
@@ -444,3 +451,969 @@ func TestTaskParsingWithPriority(t *testing.T) {
 		t.Errorf("Expected 1 task with no priority marker, got %d: %v", priorityCounts[""], priorityCounts)
 	}
 }
+
+// writeSyncConflictFixture sets up state, todo, and daily note files so that
+// the same task was edited differently on both sides.
+func writeSyncConflictFixture(t *testing.T, fs *testhelpers.TestFS, conflict bool) {
+	t.Helper()
+
+	fs.WriteFile(t, ".todo_state.json", `{
+  "tasks": {
+    "abc12345": {"id": "abc12345", "text": "Original task", "section": "Tasks"}
+  },
+  "version": 1
+}`)
+
+	todoText := "Original task"
+	if conflict {
+		todoText = "Edited in todo"
+	}
+	fs.WriteFile(t, "todo.md", "# To-Do List\n\n## Tasks\n\n- [ ] "+todoText+" <!-- id: abc12345 -->\n")
+
+	notePath := notes.BuildDailyNotePath(filepath.Join(fs.BaseDir, "diary"), time.Now())
+	if err := notes.WriteNote(context.Background(), notePath, "# Today\n\n## Tasks\n\n- [ ] Edited in daily <!-- id: abc12345 -->\n"); err != nil {
+		t.Fatalf("Failed to create daily note: %v", err)
+	}
+}
+
+// TestSyncCmd_ExitCodes tests that sync exits 3 on conflicts and 0 otherwise.
+func TestSyncCmd_ExitCodes(t *testing.T) {
+	tests := []struct {
+		name     string
+		conflict bool
+		wantCode int
+	}{
+		{name: "no conflict", conflict: false, wantCode: utils.ExitCodeSuccess},
+		{name: "conflict", conflict: true, wantCode: utils.ExitCodeConflicts},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := testhelpers.NewTestFS(t)
+			defer fs.Cleanup()
+
+			testhelpers.NewConfigHelper(fs).CreateBasicConfig(t)
+			t.Setenv("JOTR_CONFIG", filepath.Join(fs.BaseDir, ".config", "jotr", "config.json"))
+
+			writeSyncConflictFixture(t, fs, tt.conflict)
+
+			result := testhelpers.ExecuteCommand(SyncCmd, "--quiet")
+			if result.ExitCode != tt.wantCode {
+				t.Errorf("Expected exit code %d, got %d (err: %v)", tt.wantCode, result.ExitCode, result.Error)
+			}
+		})
+	}
+}
+
+// TestSyncTasks_Alerts tests that --alerts lists overdue and due-today tasks
+// from the synced state.
+func TestSyncTasks_Alerts(t *testing.T) {
+	fs := testhelpers.NewTestFS(t)
+	defer fs.Cleanup()
+
+	cfg := createTestTaskConfig(t, fs.BaseDir)
+	cfg.StatePath = filepath.Join(fs.BaseDir, ".todo_state.json")
+
+	now := time.Now()
+	today := now.Format("2006-01-02")
+	past := now.AddDate(0, 0, -3).Format("2006-01-02")
+	future := now.AddDate(0, 0, 3).Format("2006-01-02")
+	content := "# Today\n\n## Tasks\n\n" +
+		"- [ ] Renew passport due:" + past + "\n" +
+		"- [ ] Ship release due:" + today + "\n" +
+		"- [ ] Plan offsite due:" + future + "\n" +
+		"- [x] Pay rent due:" + past + "\n"
+	if err := notes.WriteNote(context.Background(), notes.BuildDailyNotePath(cfg.DiaryPath, now), content); err != nil {
+		t.Fatalf("Failed to create daily note: %v", err)
+	}
+
+	syncAlerts = true
+	defer func() { syncAlerts = false }()
+
+	var syncErr error
+	output := testhelpers.CaptureStdout(func() {
+		syncErr = syncTasks(context.Background(), cfg)
+	})
+	if syncErr != nil {
+		t.Fatalf("syncTasks failed: %v", syncErr)
+	}
+
+	_, alerts, found := strings.Cut(output, "⚠ ")
+	if !found {
+		t.Fatalf("expected an alert summary, got:\n%s", output)
+	}
+
+	want := "1 overdue, 1 due today\n" +
+		"  Overdue:\n    - Renew passport due: " + past + "\n" +
+		"  Due today:\n    - Ship release due: " + today + "\n"
+	if alerts != want {
+		t.Errorf("alert summary = %q, want %q", alerts, want)
+	}
+}
+
+// TestSyncTasks_AlertsUseSyncDate tests that --alerts with --date judges due
+// dates against the synced date rather than the real one.
+func TestSyncTasks_AlertsUseSyncDate(t *testing.T) {
+	fs := testhelpers.NewTestFS(t)
+	defer fs.Cleanup()
+
+	cfg := createTestTaskConfig(t, fs.BaseDir)
+	cfg.StatePath = filepath.Join(fs.BaseDir, ".todo_state.json")
+
+	date := time.Date(2025, 3, 10, 0, 0, 0, 0, time.Local)
+	content := "# Today\n\n## Tasks\n\n" +
+		"- [ ] Renew passport due:2025-03-05\n" +
+		"- [ ] Ship release due:2025-03-10\n" +
+		"- [ ] Plan offsite due:2025-03-20\n"
+	if err := notes.WriteNote(context.Background(), notes.BuildDailyNotePath(cfg.DiaryPath, date), content); err != nil {
+		t.Fatalf("Failed to create daily note: %v", err)
+	}
+
+	syncAlerts = true
+	syncDate = "2025-03-10"
+	defer func() { syncAlerts, syncDate = false, "" }()
+
+	var syncErr error
+	output := testhelpers.CaptureStdout(func() {
+		syncErr = syncTasks(context.Background(), cfg)
+	})
+	if syncErr != nil {
+		t.Fatalf("syncTasks failed: %v", syncErr)
+	}
+
+	_, alerts, found := strings.Cut(output, "⚠ ")
+	if !found {
+		t.Fatalf("expected an alert summary, got:\n%s", output)
+	}
+
+	want := "1 overdue, 1 due today\n" +
+		"  Overdue:\n    - Renew passport due: 2025-03-05\n" +
+		"  Due today:\n    - Ship release due: 2025-03-10\n"
+	if alerts != want {
+		t.Errorf("alert summary = %q, want %q", alerts, want)
+	}
+}
+
+// TestSyncTasks_StatsOutput tests that --stats reports added and conflict counts.
+func TestSyncTasks_StatsOutput(t *testing.T) {
+	fs := testhelpers.NewTestFS(t)
+	defer fs.Cleanup()
+
+	cfg := createTestTaskConfig(t, fs.BaseDir)
+	cfg.StatePath = filepath.Join(fs.BaseDir, ".todo_state.json")
+	notePath := notes.BuildDailyNotePath(cfg.DiaryPath, time.Now())
+	if err := notes.WriteNote(context.Background(), notePath, "# Today\n\n## Tasks\n\n- [ ] First\n- [ ] Second\n"); err != nil {
+		t.Fatalf("Failed to create daily note: %v", err)
+	}
+
+	syncStats = true
+	defer func() { syncStats = false }()
+
+	var syncErr error
+	output := testhelpers.CaptureStdout(func() {
+		syncErr = syncTasks(context.Background(), cfg)
+	})
+	if syncErr != nil {
+		t.Fatalf("syncTasks failed: %v", syncErr)
+	}
+
+	for _, want := range []string{
+		"Added:         2 (2 from daily notes, 0 from todo list)",
+		"Deleted:       0",
+		"Conflicts:     0",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+
+	syncJSON = true
+	defer func() { syncJSON = false }()
+
+	output = testhelpers.CaptureStdout(func() {
+		syncErr = syncTasks(context.Background(), cfg)
+	})
+	if syncErr != nil {
+		t.Fatalf("syncTasks failed: %v", syncErr)
+	}
+
+	var decoded struct {
+		TasksRead int              `json:"tasks_read"`
+		Stats     syncStatsSummary `json:"stats"`
+	}
+	if err := json.Unmarshal([]byte(output), &decoded); err != nil {
+		t.Fatalf("--stats --json output is not JSON: %v\n%s", err, output)
+	}
+	if decoded.TasksRead == 0 || decoded.Stats.FromDaily.Added != 0 || decoded.Stats.Conflicts != 0 {
+		t.Errorf("second sync should read tasks without adding any, got %+v", decoded)
+	}
+}
+
+// mockAssistant returns a canned response and records the prompt it was sent.
+type mockAssistant struct {
+	response string
+	prompt   string
+}
+
+func (m *mockAssistant) Ask(ctx context.Context, prompt string) (string, error) {
+	m.prompt = prompt
+	return m.response, nil
+}
+
+func TestSuggestPriorities_ParsesMockResponse(t *testing.T) {
+	cfg := createTestTaskConfig(t, t.TempDir())
+
+	todoContent := `# To-Do List
+
+## Tasks
+
+- [ ] Fix login bug
+- [ ] [P2] Write docs
+- [x] Already done
+- [ ] Clean desk
+`
+	if err := notes.WriteNote(context.Background(), cfg.TodoPath, todoContent); err != nil {
+		t.Fatalf("Failed to create todo file: %v", err)
+	}
+
+	assistant := &mockAssistant{response: "1: P1\n2. [P2]\nnot a suggestion\n3) P3\n9: P0"}
+
+	suggestions, err := suggestPriorities(context.Background(), cfg, assistant)
+	if err != nil {
+		t.Fatalf("suggestPriorities() error = %v", err)
+	}
+
+	if strings.Contains(assistant.prompt, "Already done") {
+		t.Errorf("Prompt should only include pending tasks:\n%s", assistant.prompt)
+	}
+
+	want := map[string]string{"Fix login bug": "P1", "Clean desk": "P3"}
+	if len(suggestions) != len(want) {
+		t.Fatalf("suggestPriorities() returned %d suggestions; want %d: %+v", len(suggestions), len(want), suggestions)
+	}
+	for _, s := range suggestions {
+		if want[s.Task.Text] != s.Priority {
+			t.Errorf("suggestion for %q = %s; want %s", s.Task.Text, s.Priority, want[s.Task.Text])
+		}
+	}
+}
+
+func TestTriageTasks_AIDisabled(t *testing.T) {
+	cfg := createTestTaskConfig(t, t.TempDir())
+	cfg.AI.Enabled = false
+
+	if err := triageTasks(context.Background(), cfg); err != nil {
+		t.Errorf("triageTasks() with AI disabled should not fail, got %v", err)
+	}
+}
+
+func TestAddTask_InvalidPriority(t *testing.T) {
+	cfg := createTestTaskConfig(t, t.TempDir())
+	cfg.StatePath = filepath.Join(filepath.Dir(cfg.TodoPath), ".todo_state.json")
+
+	addPriority = "P9"
+	defer func() { addPriority = "" }()
+
+	if err := addTask(context.Background(), cfg, "Buy milk"); err == nil {
+		t.Error("addTask() expected error for invalid priority")
+	}
+	if utils.FileExists(cfg.TodoPath) {
+		t.Error("addTask() should not write the todo file on invalid input")
+	}
+}
+
+func TestAddTask_DefaultsToConfiguredSection(t *testing.T) {
+	cfg := createTestTaskConfig(t, t.TempDir())
+	cfg.StatePath = filepath.Join(filepath.Dir(cfg.TodoPath), ".todo_state.json")
+
+	addPriority = "p1"
+	defer func() { addPriority = "" }()
+
+	if err := addTask(context.Background(), cfg, "Call plumber #home"); err != nil {
+		t.Fatalf("addTask() error = %v", err)
+	}
+
+	allTasks, err := tasks.ReadTasks(context.Background(), cfg.TodoPath)
+	if err != nil {
+		t.Fatalf("Failed to read tasks: %v", err)
+	}
+
+	if len(allTasks) != 1 {
+		t.Fatalf("Expected 1 task, got %d", len(allTasks))
+	}
+	got := allTasks[0]
+	if got.Section != "Tasks" || got.Priority != "P1" || len(got.Tags) != 1 || got.Tags[0] != "home" || got.ID == "" {
+		t.Errorf("Added task = %+v; want section Tasks, priority P1, tag home, and an ID", got)
+	}
+}
+
+func TestWriteTasksCSV_RoundTrip(t *testing.T) {
+	todoState := state.NewTodoState()
+	todoState.Tasks["aaaa0001"] = state.TaskState{
+		ID:          "aaaa0001",
+		Text:        `[P1] Buy milk, eggs and "good" bread #shopping #home due:2025-07-01 <!-- id: aaaa0001 -->`,
+		Section:     "Errands",
+		Priority:    "P1",
+		Tags:        []string{"shopping", "home"},
+		CreatedDate: "2025-06-01",
+	}
+	todoState.Tasks["aaaa0002"] = state.TaskState{
+		ID:            "aaaa0002",
+		Text:          "Done task",
+		Section:       "Tasks",
+		Completed:     true,
+		CreatedDate:   "2025-06-02",
+		CompletedDate: "2025-06-03",
+	}
+
+	var buf strings.Builder
+	if err := writeTasksCSV(&buf, todoState, tasks.Options{}); err != nil {
+		t.Fatalf("writeTasksCSV() error = %v", err)
+	}
+
+	records, err := csv.NewReader(strings.NewReader(buf.String())).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to parse CSV: %v\n%s", err, buf.String())
+	}
+
+	if len(records) != 3 {
+		t.Fatalf("Expected header + 2 rows, got %d", len(records))
+	}
+
+	want := [][]string{
+		{"id", "text", "section", "priority", "completed", "created_date", "completed_date", "tags", "due"},
+		{"aaaa0001", `[P1] Buy milk, eggs and "good" bread #shopping #home due:2025-07-01`, "Errands", "P1", "false", "2025-06-01", "", "shopping;home", "2025-07-01"},
+		{"aaaa0002", "Done task", "Tasks", "", "true", "2025-06-02", "2025-06-03", "", ""},
+	}
+	for i := range want {
+		if strings.Join(records[i], "|") != strings.Join(want[i], "|") {
+			t.Errorf("row %d = %q; want %q", i, records[i], want[i])
+		}
+	}
+
+	if tags := strings.Split(records[1][7], ";"); len(tags) != 2 || tags[0] != "shopping" || tags[1] != "home" {
+		t.Errorf("tags did not round-trip: %q", records[1][7])
+	}
+}
+
+// TestWriteTasksCSV_CustomIDTemplate tests that IDs embedded with a
+// configured task_id_format are stripped from the exported text.
+func TestWriteTasksCSV_CustomIDTemplate(t *testing.T) {
+	todoState := state.NewTodoState()
+	todoState.Tasks["aaaa0001"] = state.TaskState{ID: "aaaa0001", Text: "Buy milk ^aaaa0001", Section: "Errands"}
+
+	var buf strings.Builder
+	if err := writeTasksCSV(&buf, todoState, tasks.Options{IDTemplate: "^{id}"}); err != nil {
+		t.Fatalf("writeTasksCSV() error = %v", err)
+	}
+
+	if !strings.Contains(buf.String(), "\naaaa0001,Buy milk,Errands,") {
+		t.Errorf("exported text kept the task ID:\n%s", buf.String())
+	}
+}
+
+func TestListTasks_Porcelain(t *testing.T) {
+	fs := testhelpers.NewTestFS(t)
+	defer fs.Cleanup()
+
+	cfg := createTestTaskConfig(t, fs.BaseDir)
+	cfg.StatePath = filepath.Join(fs.BaseDir, ".todo_state.json")
+
+	todoState := state.NewTodoState()
+	todoState.Tasks["bbbb0002"] = state.TaskState{ID: "bbbb0002", Text: "Write\tdocs <!-- id: bbbb0002 -->"}
+	todoState.Tasks["aaaa0001"] = state.TaskState{ID: "aaaa0001", Text: "[P2] Review PR", Priority: "P2"}
+	todoState.Tasks["cccc0003"] = state.TaskState{ID: "cccc0003", Text: "[P0] Fix outage", Priority: "P0", Completed: true, CompletedDate: "2025-06-03"}
+	todoState.Tasks["dddd0004"] = state.TaskState{ID: "dddd0004", Text: "Old idea", Status: tasks.StatusCancelled}
+	if err := todoState.Write(cfg.StatePath); err != nil {
+		t.Fatalf("Failed to write state: %v", err)
+	}
+
+	tasksPorcelain = true
+	defer func() { tasksPorcelain = false }()
+
+	var buf strings.Builder
+	if err := listTasks(cfg, &buf); err != nil {
+		t.Fatalf("listTasks() error = %v", err)
+	}
+
+	want := "cccc0003\tdone\tP0\t[P0] Fix outage\n" +
+		"aaaa0001\topen\tP2\t[P2] Review PR\n" +
+		"dddd0004\tcancelled\t\tOld idea\n" +
+		"bbbb0002\topen\t\tWrite docs\n"
+	if buf.String() != want {
+		t.Errorf("listTasks() porcelain =\n%q\nwant\n%q", buf.String(), want)
+	}
+}
+
+func TestShowSyncStatus_ReportsTextMismatch(t *testing.T) {
+	fs := testhelpers.NewTestFS(t)
+	defer fs.Cleanup()
+
+	cfg := createTestTaskConfig(t, fs.BaseDir)
+	cfg.StatePath = filepath.Join(fs.BaseDir, ".todo_state.json")
+
+	todoState := state.NewTodoState()
+	todoState.Tasks["aaaa0001"] = state.TaskState{ID: "aaaa0001", Text: "Buy milk", Section: "Inbox"}
+	if err := todoState.Write(cfg.StatePath); err != nil {
+		t.Fatalf("Failed to write state: %v", err)
+	}
+	fs.WriteFile(t, "todo.md", "# Todo\n\n## Inbox\n\n- [ ] Buy oat milk <!-- id: aaaa0001 -->\n")
+
+	stateBefore, _ := os.ReadFile(cfg.StatePath)
+	todoBefore := fs.ReadFile(t, "todo.md")
+
+	statusJSON = true
+	defer func() { statusJSON = false }()
+
+	var err error
+	out := testhelpers.CaptureStdout(func() {
+		err = showSyncStatus(context.Background(), cfg)
+	})
+	if err != nil {
+		t.Fatalf("showSyncStatus() error = %v", err)
+	}
+
+	var result struct {
+		Todo struct {
+			Mismatches []state.TaskChangeDetail `json:"mismatches"`
+		} `json:"todo"`
+	}
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("output is not valid JSON: %v\n%s", err, out)
+	}
+
+	if len(result.Todo.Mismatches) != 1 {
+		t.Fatalf("expected 1 mismatch, got %+v", result.Todo.Mismatches)
+	}
+	mismatch := result.Todo.Mismatches[0]
+	if mismatch.ID != "aaaa0001" || mismatch.From != "Buy milk" || mismatch.To != "Buy oat milk" {
+		t.Errorf("unexpected mismatch %+v", mismatch)
+	}
+
+	stateAfter, _ := os.ReadFile(cfg.StatePath)
+	if string(stateAfter) != string(stateBefore) {
+		t.Error("status modified the state file")
+	}
+	if fs.ReadFile(t, "todo.md") != todoBefore {
+		t.Error("status modified the todo file")
+	}
+}
+
+func TestRepairState_TruncatedJSON(t *testing.T) {
+	fs := testhelpers.NewTestFS(t)
+	defer fs.Cleanup()
+
+	cfg := createTestTaskConfig(t, fs.BaseDir)
+	cfg.StatePath = filepath.Join(fs.BaseDir, ".todo_state.json")
+
+	todoState := state.NewTodoState()
+	todoState.Tasks["aaaa0001"] = state.TaskState{ID: "aaaa0001", Text: "Buy milk"}
+	todoState.Tasks["bbbb0002"] = state.TaskState{ID: "bbbb0002", Text: "Call bank"}
+	todoState.Tasks["cccc0003"] = state.TaskState{ID: "cccc0003", Text: "Write report"}
+	if err := todoState.Write(cfg.StatePath); err != nil {
+		t.Fatalf("Failed to write state: %v", err)
+	}
+
+	// Cut the file off in the middle of the last task entry.
+	data, _ := os.ReadFile(cfg.StatePath)
+	cut := strings.Index(string(data), `"Write report"`)
+	if err := os.WriteFile(cfg.StatePath, data[:cut], 0644); err != nil {
+		t.Fatalf("Failed to truncate state: %v", err)
+	}
+
+	if _, err := state.Read(cfg.StatePath); !errors.Is(err, state.ErrCorrupt) {
+		t.Fatalf("state.Read() error = %v, want ErrCorrupt", err)
+	}
+
+	var err error
+	out := testhelpers.CaptureStdout(func() {
+		err = repairState(cfg)
+	})
+	if err != nil {
+		t.Fatalf("repairState() error = %v", err)
+	}
+	if !strings.Contains(out, "Recovered 2 tasks") || !strings.Contains(out, "dropped: task cccc0003") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+
+	repaired, err := state.Read(cfg.StatePath)
+	if err != nil {
+		t.Fatalf("state.Read() after repair error = %v", err)
+	}
+	if len(repaired.Tasks) != 2 || repaired.Tasks["aaaa0001"].Text != "Buy milk" || repaired.Tasks["bbbb0002"].Text != "Call bank" {
+		t.Errorf("unexpected repaired tasks: %+v", repaired.Tasks)
+	}
+	if !utils.FileExists(cfg.StatePath + state.CorruptSuffix) {
+		t.Error("expected the corrupt file to be kept")
+	}
+}
+
+func TestListAllTasks_DedupesAcrossNotes(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := createTestTaskConfig(t, tmpDir)
+	cfg.StatePath = filepath.Join(tmpDir, ".todo_state.json")
+	ctx := context.Background()
+
+	monday := time.Date(2025, 1, 6, 0, 0, 0, 0, time.Local)
+	mondayPath := notes.BuildDailyNotePath(cfg.DiaryPath, monday)
+	tuesdayPath := notes.BuildDailyNotePath(cfg.DiaryPath, monday.AddDate(0, 0, 1))
+	files := map[string]string{
+		mondayPath:   "# Monday\n\n## Tasks\n\n- [ ] Write report <!-- id: aaaa1111 -->\n",
+		tuesdayPath:  "# Tuesday\n\n## Tasks\n\n- [ ] Call plumber #home <!-- id: bbbb2222 -->\n",
+		cfg.TodoPath: "# To-Do List\n\n## Tasks\n\n- [ ] Write report <!-- id: aaaa1111 -->\n- [ ] Buy milk #home\n",
+	}
+	for path, content := range files {
+		if err := notes.WriteNote(ctx, path, content); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+	}
+
+	todoState := state.NewTodoState()
+	todoState.Tasks["aaaa1111"] = state.TaskState{ID: "aaaa1111", Text: "Write report", Section: "Tasks", Priority: "P1"}
+	if err := todoState.Write(cfg.StatePath); err != nil {
+		t.Fatalf("Failed to write state: %v", err)
+	}
+	stateBefore, _ := os.ReadFile(cfg.StatePath)
+
+	var err error
+	out := testhelpers.CaptureStdout(func() {
+		err = listAllTasks(ctx, cfg)
+	})
+	if err != nil {
+		t.Fatalf("listAllTasks() error = %v", err)
+	}
+
+	if !strings.Contains(out, "3 task(s)") {
+		t.Errorf("expected 3 deduplicated tasks, got:\n%s", out)
+	}
+
+	rel := func(path string) string {
+		r, _ := filepath.Rel(tmpDir, path)
+		return r
+	}
+	wantLines := []string{
+		"P1 Write report  (" + rel(mondayPath) + ", todo.md)",
+		"Call plumber #home  (" + rel(tuesdayPath) + ")",
+		"Buy milk #home  (todo.md)",
+	}
+	for _, want := range wantLines {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+
+	if stateAfter, _ := os.ReadFile(cfg.StatePath); string(stateAfter) != string(stateBefore) {
+		t.Error("listAllTasks() modified the state file")
+	}
+
+	tasksTag = "#home"
+	defer func() { tasksTag = "" }()
+	out = testhelpers.CaptureStdout(func() {
+		err = listAllTasks(ctx, cfg)
+	})
+	if err != nil {
+		t.Fatalf("listAllTasks() with --tag error = %v", err)
+	}
+	if !strings.Contains(out, "2 task(s)") || strings.Contains(out, "Write report") {
+		t.Errorf("--tag home should list only the two #home tasks, got:\n%s", out)
+	}
+}
+
+func TestCompleteTaskByText(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := createTestTaskConfig(t, tmpDir)
+	cfg.StatePath = filepath.Join(tmpDir, ".todo_state.json")
+	ctx := context.Background()
+
+	notePath := notes.BuildDailyNotePath(cfg.DiaryPath, time.Now())
+	content := "# Today\n\n## Tasks\n\n- [ ] Review proposal #work\n- [ ] Review budget\n- [ ] Call plumber\n"
+	if err := notes.WriteNote(ctx, notePath, content); err != nil {
+		t.Fatalf("Failed to write daily note: %v", err)
+	}
+
+	syncOpts := services.SyncOptions{
+		DiaryPath:   cfg.DiaryPath,
+		TodoPath:    cfg.TodoPath,
+		StatePath:   cfg.StatePath,
+		TaskSection: "Tasks",
+	}
+	if _, err := services.NewTaskService().SyncTasks(ctx, syncOpts); err != nil {
+		t.Fatalf("SyncTasks() error = %v", err)
+	}
+
+	readTodo := func() string {
+		data, err := os.ReadFile(cfg.TodoPath)
+		if err != nil {
+			t.Fatalf("Failed to read todo file: %v", err)
+		}
+		return string(data)
+	}
+
+	t.Run("unique match", func(t *testing.T) {
+		var err error
+		out := testhelpers.CaptureStdout(func() {
+			err = completeTaskByText(ctx, cfg, "PLUMBER", strings.NewReader(""))
+		})
+		if err != nil {
+			t.Fatalf("completeTaskByText() error = %v", err)
+		}
+		if !strings.Contains(out, "Completed: Call plumber") {
+			t.Errorf("unexpected output:\n%s", out)
+		}
+		if !strings.Contains(readTodo(), "- [x] Call plumber") {
+			t.Errorf("todo file does not show the task completed:\n%s", readTodo())
+		}
+		note, _ := os.ReadFile(notePath)
+		if !strings.Contains(string(note), "- [x] Call plumber") || !strings.Contains(string(note), "- [ ] Review budget") {
+			t.Errorf("daily note not updated as expected:\n%s", note)
+		}
+	})
+
+	t.Run("ambiguous match prompts", func(t *testing.T) {
+		if err := completeTaskByText(ctx, cfg, "review", strings.NewReader("")); err == nil {
+			t.Error("completeTaskByText() expected an error without a selection")
+		}
+
+		var err error
+		out := testhelpers.CaptureStdout(func() {
+			err = completeTaskByText(ctx, cfg, "review", strings.NewReader("2\n"))
+		})
+		if err != nil {
+			t.Fatalf("completeTaskByText() error = %v", err)
+		}
+		for _, want := range []string{"Multiple tasks found:", "1. Review budget", "2. Review proposal #work", "Completed: Review proposal #work"} {
+			if !strings.Contains(out, want) {
+				t.Errorf("output missing %q:\n%s", want, out)
+			}
+		}
+
+		todo := readTodo()
+		if !strings.Contains(todo, "- [x] Review proposal #work") || !strings.Contains(todo, "- [ ] Review budget") {
+			t.Errorf("wrong task completed:\n%s", todo)
+		}
+	})
+
+	t.Run("survives the next sync", func(t *testing.T) {
+		if _, err := services.NewTaskService().SyncTasks(ctx, syncOpts); err != nil {
+			t.Fatalf("SyncTasks() error = %v", err)
+		}
+		todoState, err := state.Read(cfg.StatePath)
+		if err != nil {
+			t.Fatalf("Failed to read state: %v", err)
+		}
+		for _, ts := range todoState.Tasks {
+			wantCompleted := !strings.Contains(ts.Text, "budget")
+			if ts.Completed != wantCompleted {
+				t.Errorf("task %q completed = %v, want %v", ts.Text, ts.Completed, wantCompleted)
+			}
+		}
+	})
+
+	if err := completeTaskByText(ctx, cfg, "nothing like this", strings.NewReader("")); err == nil {
+		t.Error("completeTaskByText() expected an error for no match")
+	}
+}
+
+func TestFocusTasks_TopByPriorityThenDue(t *testing.T) {
+	fs := testhelpers.NewTestFS(t)
+	defer fs.Cleanup()
+
+	cfg := createTestTaskConfig(t, fs.BaseDir)
+	cfg.StatePath = filepath.Join(fs.BaseDir, ".todo_state.json")
+
+	todoState := state.NewTodoState()
+	seed := []state.TaskState{
+		{ID: "aaaa0001", Text: "[P2] Write report due: 2030-01-05", Priority: "P2"},
+		{ID: "aaaa0002", Text: "[P1] Call bank due: 2030-02-01", Priority: "P1"},
+		{ID: "aaaa0003", Text: "[P1] Pay rent due: 2030-01-01", Priority: "P1"},
+		{ID: "aaaa0004", Text: "[P1] Book flights", Priority: "P1"},
+		{ID: "aaaa0005", Text: "Tidy desk due: 2029-12-01"},
+		{ID: "aaaa0006", Text: "[P3] Read book", Priority: "P3"},
+		{ID: "aaaa0007", Text: "[P0] Fixed outage", Priority: "P0", Completed: true},
+		{ID: "aaaa0008", Text: "[P0] Dropped idea", Priority: "P0", Status: tasks.StatusCancelled},
+		{ID: "aaaa0009", Text: "[P0] Old release", Priority: "P0", Completed: true, ArchivedDate: "2029-01-01"},
+		{ID: "aaaa0010", Text: "[P2] Renew passport due: 2030-01-03", Priority: "P2"},
+	}
+	for _, task := range seed {
+		todoState.Tasks[task.ID] = task
+	}
+	if err := todoState.Write(cfg.StatePath); err != nil {
+		t.Fatalf("Failed to write state: %v", err)
+	}
+
+	focus, err := services.NewTaskService().FocusTasks(cfg.StatePath, 4)
+	if err != nil {
+		t.Fatalf("FocusTasks() error = %v", err)
+	}
+
+	want := []string{"aaaa0003", "aaaa0002", "aaaa0004", "aaaa0010"}
+	var got []string
+	for _, task := range focus {
+		got = append(got, task.ID)
+	}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("FocusTasks() = %v, want %v", got, want)
+	}
+
+	cfg.Format.FocusCount = 2
+	out := testhelpers.CaptureStdout(func() {
+		if err := showFocus(cfg); err != nil {
+			t.Fatalf("showFocus() error = %v", err)
+		}
+	})
+	if !strings.Contains(out, "1. ○  P1 [P1] Pay rent") || !strings.Contains(out, "2. ○  P1 [P1] Call bank") {
+		t.Errorf("showFocus() output missing top tasks:\n%s", out)
+	}
+	if strings.Contains(out, "Book flights") {
+		t.Errorf("showFocus() showed more than focus_count tasks:\n%s", out)
+	}
+}
+
+func TestResolveSyncConflicts(t *testing.T) {
+	fs := testhelpers.NewTestFS(t)
+	defer fs.Cleanup()
+
+	cfg := createTestTaskConfig(t, fs.BaseDir)
+	cfg.StatePath = filepath.Join(fs.BaseDir, ".todo_state.json")
+
+	today := time.Now()
+	notePath := notes.BuildDailyNotePath(cfg.DiaryPath, today)
+	if err := notes.WriteNote(context.Background(), notePath,
+		"# Note\n\n## Tasks\n\n- [ ] Buy oat milk <!-- id: aaaa0001 -->\n- [ ] Call mum <!-- id: bbbb0002 -->\n"); err != nil {
+		t.Fatalf("Failed to create daily note: %v", err)
+	}
+	fs.WriteFile(t, "todo.md", "# To-Do List\n\n## Tasks\n\n- [ ] Buy soy milk <!-- id: aaaa0001 -->\n- [ ] Call mum tonight <!-- id: bbbb0002 -->\n")
+
+	todoState := state.NewTodoState()
+	todoState.Tasks["aaaa0001"] = state.TaskState{ID: "aaaa0001", Text: "Buy milk", Section: "Tasks", Source: notePath}
+	todoState.Tasks["bbbb0002"] = state.TaskState{ID: "bbbb0002", Text: "Phone mum", Section: "Tasks", Source: notePath}
+	if err := todoState.Write(cfg.StatePath); err != nil {
+		t.Fatalf("Failed to write state: %v", err)
+	}
+
+	taskService := services.NewTaskService()
+	opts := services.SyncOptions{
+		DiaryPath:   cfg.DiaryPath,
+		TodoPath:    cfg.TodoPath,
+		StatePath:   cfg.StatePath,
+		TaskSection: "Tasks",
+	}
+
+	result, err := taskService.SyncTasks(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("SyncTasks() error = %v", err)
+	}
+	if len(result.ConflictsDetail) != 2 {
+		t.Fatalf("expected 2 conflicts, got %+v", result.ConflictsDetail)
+	}
+
+	var resolved *services.SyncResult
+	testhelpers.CaptureStdout(func() {
+		resolved, err = resolveSyncConflicts(context.Background(), taskService, opts, result, strings.NewReader("daily\nmaybe\nt\n"))
+	})
+	if err != nil {
+		t.Fatalf("resolveSyncConflicts() error = %v", err)
+	}
+	if len(resolved.Conflicts) != 0 {
+		t.Fatalf("conflicts remain after resolving: %v", resolved.Conflicts)
+	}
+
+	after, err := state.Read(cfg.StatePath)
+	if err != nil {
+		t.Fatalf("state.Read() error = %v", err)
+	}
+	if got := tasks.StripTaskID(after.Tasks["aaaa0001"].Text); got != "Buy oat milk" {
+		t.Errorf("aaaa0001 text = %q, want the daily version", got)
+	}
+	if got := tasks.StripTaskID(after.Tasks["bbbb0002"].Text); got != "Call mum tonight" {
+		t.Errorf("bbbb0002 text = %q, want the todo version", got)
+	}
+
+	todo := fs.ReadFile(t, "todo.md")
+	if !strings.Contains(todo, "Buy oat milk") || strings.Contains(todo, "Buy soy milk") {
+		t.Errorf("todo file does not have the daily version:\n%s", todo)
+	}
+	daily, _ := os.ReadFile(notePath)
+	if !strings.Contains(string(daily), "Call mum tonight") {
+		t.Errorf("daily note does not have the todo version:\n%s", daily)
+	}
+
+	// Both sides now agree, so the next sync finds nothing to do.
+	again, err := taskService.SyncTasks(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("SyncTasks() after resolving error = %v", err)
+	}
+	if len(again.Conflicts) != 0 || again.TasksFromDaily+again.TasksFromTodo != 0 {
+		t.Errorf("sync after resolving = %+v, want no changes", again)
+	}
+}
+
+func TestListTasks_WithAge(t *testing.T) {
+	fs := testhelpers.NewTestFS(t)
+	defer fs.Cleanup()
+
+	cfg := createTestTaskConfig(t, fs.BaseDir)
+	cfg.StatePath = filepath.Join(fs.BaseDir, ".todo_state.json")
+
+	now := time.Now()
+	todoState := state.NewTodoState()
+	todoState.Tasks["aaaa0001"] = state.TaskState{ID: "aaaa0001", Text: "[P1] Recent task", Priority: "P1", CreatedAt: now.Add(-2*24*time.Hour - time.Hour)}
+	todoState.Tasks["bbbb0002"] = state.TaskState{ID: "bbbb0002", Text: "Lingering task", CreatedAt: now.Add(-12*24*time.Hour - time.Hour)}
+	todoState.Tasks["cccc0003"] = state.TaskState{
+		ID: "cccc0003", Text: "Finished task", Completed: true,
+		CreatedAt: now.AddDate(0, 0, -30), CompletedAt: now.AddDate(0, 0, -25),
+	}
+	todoState.Tasks["dddd0004"] = state.TaskState{ID: "dddd0004", Text: "Imported task"}
+	if err := todoState.Write(cfg.StatePath); err != nil {
+		t.Fatalf("Failed to write state: %v", err)
+	}
+
+	tasksWithAge = true
+	tasksSort = "age"
+	defer func() {
+		tasksWithAge = false
+		tasksSort = "priority"
+	}()
+
+	var buf strings.Builder
+	if err := listTasks(cfg, &buf); err != nil {
+		t.Fatalf("listTasks() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	want := []string{
+		"Lingering task (12d)",
+		"Finished task (5d)",
+		"Recent task (2d)",
+		"Imported task",
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("listTasks() printed %d lines, want %d:\n%s", len(lines), len(want), buf.String())
+	}
+	for i, suffix := range want {
+		if !strings.HasSuffix(lines[i], suffix) {
+			t.Errorf("line %d = %q, want it to end with %q", i+1, lines[i], suffix)
+		}
+	}
+}
+
+func TestListTasks_Filters(t *testing.T) {
+	fs := testhelpers.NewTestFS(t)
+	defer fs.Cleanup()
+
+	cfg := createTestTaskConfig(t, fs.BaseDir)
+	cfg.StatePath = filepath.Join(fs.BaseDir, ".todo_state.json")
+
+	todoState := state.NewTodoState()
+	for _, task := range []state.TaskState{
+		{ID: "aaaa0001", Text: "[P1] Ship release #work due: 2020-01-10", Priority: "P1", Section: "Work", Tags: []string{"work"}},
+		{ID: "bbbb0002", Text: "[P1] Plan offsite #work due: 2099-01-01", Priority: "P1", Section: "Work", Tags: []string{"work"}},
+		{ID: "cccc0003", Text: "[P1] Old launch #work due: 2020-01-01", Priority: "P1", Section: "Work", Tags: []string{"work"}, Completed: true},
+		{ID: "dddd0004", Text: "[P2] Renew passport due: 2020-02-01", Priority: "P2", Section: "Home"},
+		{ID: "eeee0005", Text: "Paint fence #home", Section: "Home", Tags: []string{"home"}, Status: tasks.StatusCancelled},
+	} {
+		todoState.Tasks[task.ID] = task
+	}
+	if err := todoState.Write(cfg.StatePath); err != nil {
+		t.Fatalf("Failed to write state: %v", err)
+	}
+
+	reset := func() {
+		tasksStatus, tasksPriority, tasksSection, tasksTag = "", "", "", ""
+		tasksOverdue, tasksJSON, tasksPorcelain = false, false, false
+		tasksSort = "priority"
+	}
+	defer reset()
+
+	listIDs := func() []string {
+		t.Helper()
+		tasksJSON = true
+		var buf strings.Builder
+		if err := listTasks(cfg, &buf); err != nil {
+			t.Fatalf("listTasks() error = %v", err)
+		}
+		var listed []struct {
+			ID string `json:"id"`
+		}
+		if err := json.Unmarshal([]byte(buf.String()), &listed); err != nil {
+			t.Fatalf("output is not valid JSON: %v\n%s", err, buf.String())
+		}
+		var ids []string
+		for _, task := range listed {
+			ids = append(ids, task.ID)
+		}
+		return ids
+	}
+
+	tests := []struct {
+		name  string
+		setup func()
+		want  []string
+	}{
+		{"status and priority", func() { tasksStatus, tasksPriority = "open", "p1" }, []string{"bbbb0002", "aaaa0001"}},
+		{"tag and overdue", func() { tasksTag, tasksOverdue = "#work", true }, []string{"aaaa0001"}},
+		{"section sorted by due", func() { tasksSection, tasksSort = "Home", "due" }, []string{"dddd0004", "eeee0005"}},
+		{"overdue sorted by due", func() { tasksOverdue, tasksSort = true, "due" }, []string{"aaaa0001", "dddd0004"}},
+		{"cancelled", func() { tasksStatus = "cancelled" }, []string{"eeee0005"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reset()
+			tt.setup()
+			if got := listIDs(); strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("listTasks() IDs = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	reset()
+	tasksStatus = "blocked"
+	if err := listTasks(cfg, io.Discard); err == nil {
+		t.Error("listTasks() with an unknown --status should fail")
+	}
+}
+
+// TestShowTaskLocations tests that tasks where reports every file carrying the
+// ID and the source recorded in state.
+func TestShowTaskLocations(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := createTestTaskConfig(t, tmpDir)
+	cfg.StatePath = filepath.Join(tmpDir, ".todo_state.json")
+
+	dailyPath := filepath.Join(cfg.DiaryPath, "2025-03-10-Mon.md")
+	if err := os.MkdirAll(cfg.DiaryPath, 0755); err != nil {
+		t.Fatalf("Failed to create diary dir: %v", err)
+	}
+	if err := os.WriteFile(dailyPath, []byte("# Mon\n\n## Tasks\n\n- [ ] Other <!-- id: bbbb2222 -->\n- [ ] Ship it <!-- id: aaaa1111 -->\n"), 0644); err != nil {
+		t.Fatalf("Failed to write daily note: %v", err)
+	}
+	if err := os.WriteFile(cfg.TodoPath, []byte("# To-Do List\n\n## Tasks\n\n- [ ] Ship it <!-- id: aaaa1111 -->\n"), 0644); err != nil {
+		t.Fatalf("Failed to write todo file: %v", err)
+	}
+
+	todoState := state.NewTodoState()
+	todoState.AddTask(tasks.Task{ID: "aaaa1111", Text: "Ship it", Section: "Tasks"}, dailyPath)
+	if err := todoState.Write(cfg.StatePath); err != nil {
+		t.Fatalf("Failed to write state: %v", err)
+	}
+
+	var out strings.Builder
+	if err := showTaskLocations(context.Background(), cfg, "aaaa1111", &out); err != nil {
+		t.Fatalf("showTaskLocations() error = %v", err)
+	}
+
+	for _, want := range []string{
+		filepath.Join("Diary", "2025-03-10-Mon.md") + ":6",
+		"todo.md:5",
+		"State source: " + filepath.Join("Diary", "2025-03-10-Mon.md"),
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+
+	if err := showTaskLocations(context.Background(), cfg, "cccc3333", &out); err == nil {
+		t.Error("showTaskLocations() should fail for an unknown ID")
+	}
+}
@@ -0,0 +1,605 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/AnishShah1803/jotr/internal/config"
+	"github.com/AnishShah1803/jotr/internal/output"
+	"github.com/AnishShah1803/jotr/internal/services"
+	"github.com/AnishShah1803/jotr/internal/state"
+	"github.com/AnishShah1803/jotr/internal/tasks"
+)
+
+var (
+	tasksDryRun bool
+	tasksYes    bool
+	tasksDays   int
+	tasksFormat string
+
+	tasksPorcelain bool
+
+	tasksPriority string
+	tasksTag      string
+	tasksSection  string
+
+	tasksWithAge bool
+	tasksSort    string
+
+	tasksStatus  string
+	tasksOverdue bool
+	tasksJSON    bool
+)
+
+var TasksCmd = &cobra.Command{
+	Use:   "tasks [action]",
+	Short: "Maintain the todo list",
+	Long: `Maintenance operations on the todo list.
+
+Actions:
+  list              List tasks from state, filtered by --status, --priority,
+                    --section, --tag and --overdue (--json or --porcelain
+                    for scripts)
+  all               List tasks from every note, with the files they appear in
+  count-all         Count total, completed, pending and overdue tasks across
+                    every note (--json for scripts)
+  where <id>        Show every file and line carrying a task ID, and the
+                    source recorded in state
+  dedupe            Remove exact duplicate tasks
+  overdue           List overdue tasks across daily notes and the todo file
+  triage            Suggest task priorities using the configured AI command
+  stale             List active tasks untouched for --days days
+  waiting           List @waiting tasks grouped by the @person they wait on
+  export            Export all tasks from state (--format csv)
+
+Examples:
+  jotr tasks list              # List tasks by priority
+  jotr tasks list --porcelain | fzf
+  jotr tasks list --with-age --sort age  # Oldest tasks first
+  jotr tasks list --status open --priority P1 --tag work
+  jotr tasks list --overdue --sort due --json
+  jotr tasks all --priority P1 # Every P1 task across all notes
+  jotr tasks all --tag work    # Every #work task across all notes
+  jotr tasks count-all         # Task totals across the vault
+  jotr tasks where abcd1234    # Find every copy of a task
+  jotr tasks dedupe            # Remove duplicate tasks
+  jotr tasks dedupe --dry-run  # Show duplicates without removing them
+  jotr tasks overdue           # List everything past its due date
+  jotr tasks triage            # Review AI-suggested priorities
+  jotr tasks triage --yes      # Apply suggestions without asking
+  jotr tasks stale --days 30   # Tasks with no activity for a month
+  jotr tasks waiting           # Who needs a follow-up
+  jotr tasks export --format csv > tasks.csv`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return fmt.Errorf("action required: list, all, count-all, where, dedupe, overdue, triage, stale, waiting, or export")
+		}
+
+		cfg, err := config.LoadWithContext(cmd.Context(), "")
+		if err != nil {
+			return err
+		}
+
+		switch args[0] {
+		case "list", "ls":
+			return listTasks(cfg, os.Stdout)
+		case "all":
+			return listAllTasks(cmd.Context(), cfg)
+		case "count-all":
+			return countAllTasks(cmd.Context(), cfg, os.Stdout)
+		case "where":
+			if len(args) < 2 {
+				return fmt.Errorf("task ID required: jotr tasks where <id>")
+			}
+			return showTaskLocations(cmd.Context(), cfg, args[1], os.Stdout)
+		case "dedupe":
+			return dedupeTasks(cmd.Context(), cfg)
+		case "overdue":
+			return listOverdueTasks(cmd.Context(), cfg)
+		case "triage":
+			return triageTasks(cmd.Context(), cfg)
+		case "stale":
+			return listStaleTasks(cfg, time.Now())
+		case "waiting":
+			return listWaitingTasks(cfg)
+		case "export":
+			return exportTasks(cfg, os.Stdout)
+		default:
+			return fmt.Errorf("unknown action: %s", args[0])
+		}
+	},
+}
+
+func init() {
+	TasksCmd.Flags().BoolVar(&tasksDryRun, "dry-run", false, "Show what would be done without making changes")
+	TasksCmd.Flags().BoolVarP(&tasksYes, "yes", "y", false, "Apply triage suggestions without confirmation")
+	TasksCmd.Flags().IntVar(&tasksDays, "days", 30, "Days without activity before a task is stale")
+	TasksCmd.Flags().StringVar(&tasksFormat, "format", "csv", "Export format (csv)")
+	TasksCmd.Flags().BoolVar(&tasksPorcelain, "porcelain", false, "List tasks as id<TAB>status<TAB>priority<TAB>text lines")
+	TasksCmd.Flags().StringVar(&tasksPriority, "priority", "", "Only show tasks with this priority (list, all)")
+	TasksCmd.Flags().StringVar(&tasksTag, "tag", "", "Only show tasks with this tag (list, all)")
+	TasksCmd.Flags().StringVar(&tasksSection, "section", "", "Only show tasks in this section (list, all)")
+	TasksCmd.Flags().BoolVar(&tasksWithAge, "with-age", false, "Show how many days each task has been open, e.g. (12d) (list)")
+	TasksCmd.Flags().StringVar(&tasksSort, "sort", "priority", "Sort by priority, due (earliest first) or age (oldest first) (list)")
+	TasksCmd.Flags().StringVar(&tasksStatus, "status", "", "Only show tasks with this status: open, done, in-progress or cancelled (list)")
+	TasksCmd.Flags().BoolVar(&tasksOverdue, "overdue", false, "Only show overdue tasks (list)")
+	TasksCmd.Flags().BoolVar(&tasksJSON, "json", false, "Output in JSON format (list, count-all)")
+}
+
+// porcelainStatus returns the status column written by --porcelain.
+func porcelainStatus(task state.TaskState) string {
+	switch {
+	case task.Completed:
+		return "done"
+	case task.Status != "":
+		return task.Status
+	default:
+		return "open"
+	}
+}
+
+// sortedStateTasks returns every task in state ordered by priority (P0
+// first, unprioritized last), then text, then ID.
+func sortedStateTasks(todoState *state.TodoState, taskOpts tasks.Options) []state.TaskState {
+	all := make([]state.TaskState, 0, len(todoState.Tasks))
+	for _, task := range todoState.Tasks {
+		all = append(all, task)
+	}
+
+	sort.Slice(all, func(i, j int) bool {
+		pi, pj := all[i].Priority, all[j].Priority
+		if pi != pj {
+			if pi == "" || pj == "" {
+				return pj == ""
+			}
+			return pi < pj
+		}
+		ti, tj := taskOpts.StripTaskID(all[i].Text), taskOpts.StripTaskID(all[j].Text)
+		if ti != tj {
+			return ti < tj
+		}
+		return all[i].ID < all[j].ID
+	})
+
+	return all
+}
+
+// listTasks lists every task in state. With --porcelain each task is one
+// undecorated id<TAB>status<TAB>priority<TAB>text line; tabs and newlines in
+// the text are replaced with spaces so the layout is stable.
+func listTasks(cfg *config.LoadedConfig, out io.Writer) error {
+	todoState, err := state.Read(cfg.StatePath)
+	if err != nil {
+		return err
+	}
+
+	taskOpts := services.TaskOptions(cfg.Format)
+	all, err := filterStateTasks(sortedStateTasks(todoState, taskOpts))
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	switch tasksSort {
+	case "", "priority":
+	case "due":
+		sortByDue(all)
+	case "age":
+		sortByAge(all, now)
+	default:
+		return fmt.Errorf("unknown sort: %s (use priority, due or age)", tasksSort)
+	}
+
+	if tasksJSON {
+		return writeTasksJSON(out, all, now, taskOpts)
+	}
+
+	if tasksPorcelain {
+		for _, task := range all {
+			text := tasks.StripCompletedTag(taskOpts.StripTaskID(task.Text))
+			text = strings.NewReplacer("\t", " ", "\n", " ", "\r", " ").Replace(text)
+			fmt.Fprintf(out, "%s\t%s\t%s\t%s\n", task.ID, porcelainStatus(task), task.Priority, text)
+		}
+		return nil
+	}
+
+	if len(all) == 0 {
+		fmt.Fprintln(out, "No tasks")
+		return nil
+	}
+
+	colorOn := output.StdoutColorEnabled()
+	for _, task := range all {
+		line := tasks.FormatTask(tasks.Task{
+			Text:      tasks.StripCompletedTag(taskOpts.StripTaskID(task.Text)),
+			Priority:  task.Priority,
+			Completed: task.Completed,
+			Status:    task.Status,
+		})
+		if tasksWithAge {
+			line += ageBadge(task, now)
+		}
+		fmt.Fprintf(out, "  %s\n", output.ColorizeTags(line, cfg.Format.TagColors, colorOn))
+	}
+
+	return nil
+}
+
+// listTaskStatuses are the values accepted by --status.
+var listTaskStatuses = []string{"open", "done", tasks.StatusInProgress, tasks.StatusCancelled}
+
+// filterStateTasks keeps the tasks matching the --status, --priority,
+// --section, --tag and --overdue flags, in order.
+func filterStateTasks(all []state.TaskState) ([]state.TaskState, error) {
+	status := strings.ToLower(tasksStatus)
+	if status != "" && !slices.Contains(listTaskStatuses, status) {
+		return nil, fmt.Errorf("unknown status: %s (use %s)", tasksStatus, strings.Join(listTaskStatuses, ", "))
+	}
+
+	priority := strings.ToUpper(tasksPriority)
+	tag := strings.TrimPrefix(tasksTag, "#")
+
+	var filtered []state.TaskState
+	for _, task := range all {
+		if status != "" && porcelainStatus(task) != status {
+			continue
+		}
+		if priority != "" && task.Priority != priority {
+			continue
+		}
+		if tasksSection != "" && task.Section != tasksSection {
+			continue
+		}
+		if tag != "" && !slices.ContainsFunc(task.Tags, func(t string) bool { return strings.EqualFold(t, tag) }) {
+			continue
+		}
+		if tasksOverdue && !tasks.IsOverdue(stateTaskToTask(task)) {
+			continue
+		}
+		filtered = append(filtered, task)
+	}
+
+	return filtered, nil
+}
+
+// stateTaskToTask converts a state task for the helpers in package tasks.
+func stateTaskToTask(task state.TaskState) tasks.Task {
+	return tasks.Task{
+		Text:      task.Text,
+		Priority:  task.Priority,
+		Section:   task.Section,
+		ID:        task.ID,
+		Tags:      task.Tags,
+		Completed: task.Completed,
+		Status:    task.Status,
+		Meta:      task.Meta,
+	}
+}
+
+// sortByDue orders tasks by due date, earliest first, keeping tasks without
+// a due date last. The sort is stable, so the priority order breaks ties.
+func sortByDue(all []state.TaskState) {
+	sort.SliceStable(all, func(i, j int) bool {
+		di, okI := tasks.DueDate(stateTaskToTask(all[i]))
+		dj, okJ := tasks.DueDate(stateTaskToTask(all[j]))
+		if okI != okJ {
+			return okI
+		}
+		return okI && di.Before(dj)
+	})
+}
+
+// listedTask is one task in the --json output of "tasks list".
+type listedTask struct {
+	ID       string   `json:"id"`
+	Text     string   `json:"text"`
+	Status   string   `json:"status"`
+	Priority string   `json:"priority,omitempty"`
+	Section  string   `json:"section,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+	Due      string   `json:"due,omitempty"`
+	Overdue  bool     `json:"overdue"`
+	AgeDays  *int     `json:"age_days,omitempty"`
+}
+
+func writeTasksJSON(out io.Writer, all []state.TaskState, now time.Time, taskOpts tasks.Options) error {
+	listed := make([]listedTask, 0, len(all))
+	for _, task := range all {
+		item := listedTask{
+			ID:       task.ID,
+			Text:     tasks.StripCompletedTag(taskOpts.StripTaskID(task.Text)),
+			Status:   porcelainStatus(task),
+			Priority: task.Priority,
+			Section:  task.Section,
+			Tags:     task.Tags,
+			Overdue:  tasks.IsOverdue(stateTaskToTask(task)),
+		}
+		if due, ok := tasks.DueDate(stateTaskToTask(task)); ok {
+			item.Due = due.Format("2006-01-02")
+		}
+		if age, ok := task.AgeDays(now); ok {
+			item.AgeDays = &age
+		}
+		listed = append(listed, item)
+	}
+
+	data, err := json.MarshalIndent(listed, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	fmt.Fprintln(out, string(data))
+
+	return nil
+}
+
+// ageBadge returns a " (Nd)" badge with the task's age in days, or "" if
+// its creation time is unknown.
+func ageBadge(task state.TaskState, now time.Time) string {
+	age, ok := task.AgeDays(now)
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf(" (%dd)", age)
+}
+
+// sortByAge orders tasks oldest first, keeping tasks of unknown age last.
+// The sort is stable, so tasks of equal age keep their order.
+func sortByAge(all []state.TaskState, now time.Time) {
+	sort.SliceStable(all, func(i, j int) bool {
+		ai, okI := all[i].AgeDays(now)
+		aj, okJ := all[j].AgeDays(now)
+		if okI != okJ {
+			return okI
+		}
+		return ai > aj
+	})
+}
+
+func dedupeTasks(ctx context.Context, cfg *config.LoadedConfig) error {
+	taskService := services.NewTaskService()
+
+	result, err := taskService.DedupeTasks(ctx, services.DedupeOptions{
+		TodoPath:   cfg.TodoPath,
+		StatePath:  cfg.StatePath,
+		TodoFormat: services.NewTodoFormat(cfg.Format),
+		DryRun:     tasksDryRun,
+	})
+	if err != nil {
+		return err
+	}
+
+	if result.Removed == 0 {
+		fmt.Println("✓ No duplicate tasks found")
+		return nil
+	}
+
+	if tasksDryRun {
+		fmt.Println("⚠️  DRY RUN - No changes made")
+		fmt.Println()
+	}
+
+	for _, group := range result.Groups {
+		fmt.Printf("  \"%s\" (line %d)\n", group.Keep.Text, group.Keep.Line)
+		for _, dup := range group.Duplicates {
+			fmt.Printf("    - duplicate on line %d: \"%s\"\n", dup.Line, dup.Text)
+		}
+	}
+
+	fmt.Println()
+
+	if tasksDryRun {
+		fmt.Printf("Would remove %d duplicate task(s)\n", result.Removed)
+	} else {
+		fmt.Printf("✓ Removed %d duplicate task(s)\n", result.Removed)
+	}
+
+	return nil
+}
+
+func listOverdueTasks(ctx context.Context, cfg *config.LoadedConfig) error {
+	taskService := services.NewTaskService()
+
+	overdue, err := taskService.FindOverdueTasks(ctx, services.OverdueOptions{
+		DiaryPath:   cfg.DiaryPath,
+		TodoPath:    cfg.TodoPath,
+		TaskOptions: services.TaskOptions(cfg.Format),
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(overdue) == 0 {
+		fmt.Println("✓ No overdue tasks")
+		return nil
+	}
+
+	fmt.Printf("⚠️  %d overdue task(s)\n", len(overdue))
+	fmt.Println()
+
+	for _, item := range overdue {
+		source, relErr := filepath.Rel(cfg.Paths.BaseDir, item.Source)
+		if relErr != nil {
+			source = item.Source
+		}
+		fmt.Printf("  %s  %s  (%s:%d)\n", item.Due.Format("2006-01-02"), item.Task.Text, source, item.Task.Line)
+	}
+
+	return nil
+}
+
+// listAllTasks lists the tasks in every note and the todo file, one line
+// per task ID, with the files each one appears in.
+func listAllTasks(ctx context.Context, cfg *config.LoadedConfig) error {
+	taskOpts := services.TaskOptions(cfg.Format)
+	all, err := services.NewTaskService().FindAllTasks(ctx, services.AllTasksOptions{
+		BaseDir:     cfg.Paths.BaseDir,
+		TodoPath:    cfg.TodoPath,
+		StatePath:   cfg.StatePath,
+		Priority:    strings.ToUpper(tasksPriority),
+		Tag:         tasksTag,
+		Section:     tasksSection,
+		TaskOptions: taskOpts,
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(all) == 0 {
+		fmt.Println("✓ No tasks found")
+		return nil
+	}
+
+	fmt.Printf("📋 %d task(s)\n", len(all))
+	fmt.Println()
+
+	colorOn := output.StdoutColorEnabled()
+	for _, item := range all {
+		sources := make([]string, 0, len(item.Sources))
+		for _, source := range item.Sources {
+			if rel, relErr := filepath.Rel(cfg.Paths.BaseDir, source); relErr == nil {
+				source = rel
+			}
+			sources = append(sources, source)
+		}
+
+		task := item.Task
+		task.Text = output.ColorizeTags(taskOpts.StripTaskID(task.Text), cfg.Format.TagColors, colorOn)
+		fmt.Printf("  %s  (%s)\n", tasks.FormatTask(task), strings.Join(sources, ", "))
+	}
+
+	return nil
+}
+
+// countAllTasks prints task totals across every note in the vault.
+func countAllTasks(ctx context.Context, cfg *config.LoadedConfig, out io.Writer) error {
+	tally, noteCount, err := services.NewTaskService().CountAllTasks(ctx, cfg.Paths.BaseDir, services.TaskOptions(cfg.Format))
+	if err != nil {
+		return err
+	}
+
+	if tasksJSON {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(tally)
+	}
+
+	fmt.Fprintf(out, "📊 %d task(s) in %d note(s)\n", tally.Total, noteCount)
+	fmt.Fprintf(out, "  Completed: %d\n", tally.Completed)
+	fmt.Fprintf(out, "  Pending:   %d\n", tally.Pending)
+	fmt.Fprintf(out, "  Overdue:   %d\n", tally.Overdue)
+	return nil
+}
+
+// showTaskLocations prints every file line carrying a task ID, relative to
+// the base directory, and the source state recorded for it.
+func showTaskLocations(ctx context.Context, cfg *config.LoadedConfig, id string, out io.Writer) error {
+	taskOpts := services.TaskOptions(cfg.Format)
+	found, err := services.NewTaskService().FindTaskLocations(ctx, services.AllTasksOptions{
+		BaseDir:     cfg.Paths.BaseDir,
+		TodoPath:    cfg.TodoPath,
+		StatePath:   cfg.StatePath,
+		TaskOptions: taskOpts,
+	}, id)
+	if err != nil {
+		return err
+	}
+
+	if len(found.Locations) == 0 && !found.InState {
+		return fmt.Errorf("task not found: %s", id)
+	}
+
+	relPath := func(path string) string {
+		if rel, err := filepath.Rel(cfg.Paths.BaseDir, path); err == nil && !strings.HasPrefix(rel, "..") {
+			return rel
+		}
+		return path
+	}
+
+	fmt.Fprintf(out, "📍 Task %s\n", id)
+	if found.InState {
+		fmt.Fprintf(out, "  %s\n", taskOpts.StripTaskID(found.StateTask.Text))
+	}
+	fmt.Fprintln(out)
+
+	if len(found.Locations) == 0 {
+		fmt.Fprintln(out, "  Not found in any file")
+	}
+	for _, location := range found.Locations {
+		fmt.Fprintf(out, "  %s:%d\n", relPath(location.Path), location.Line)
+	}
+
+	switch {
+	case !found.InState:
+		fmt.Fprintln(out, "\n  Not tracked in state")
+	case found.StateTask.Source != "":
+		fmt.Fprintf(out, "\n  State source: %s\n", relPath(found.StateTask.Source))
+	default:
+		fmt.Fprintln(out, "\n  State source: (none recorded)")
+	}
+
+	return nil
+}
+
+func listStaleTasks(cfg *config.LoadedConfig, now time.Time) error {
+	if tasksDays < 1 {
+		return fmt.Errorf("--days must be at least 1")
+	}
+
+	stale, err := services.NewTaskService().FindStaleTasks(cfg.StatePath, tasksDays, now)
+	if err != nil {
+		return err
+	}
+
+	if len(stale) == 0 {
+		fmt.Printf("✓ No tasks untouched for more than %d days\n", tasksDays)
+		return nil
+	}
+
+	fmt.Printf("⚠️  %d task(s) untouched for more than %d days\n", len(stale), tasksDays)
+	fmt.Println()
+
+	colorOn := output.StdoutColorEnabled()
+	taskOpts := services.TaskOptions(cfg.Format)
+	for _, task := range stale {
+		age := int(now.Sub(task.LastModified).Hours() / 24)
+		fmt.Printf("  %4dd  %s\n", age, output.ColorizeTags(taskOpts.StripTaskID(task.Text), cfg.Format.TagColors, colorOn))
+	}
+
+	return nil
+}
+
+func listWaitingTasks(cfg *config.LoadedConfig) error {
+	groups, err := services.NewTaskService().FindWaitingTasks(cfg.StatePath)
+	if err != nil {
+		return err
+	}
+
+	if len(groups) == 0 {
+		fmt.Println("✓ No tasks waiting on anyone")
+		return nil
+	}
+
+	colorOn := output.StdoutColorEnabled()
+	taskOpts := services.TaskOptions(cfg.Format)
+	for i, group := range groups {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("%s (%d)\n", group.Person, len(group.Tasks))
+		for _, task := range group.Tasks {
+			fmt.Printf("  - %s\n", output.ColorizeTags(taskOpts.StripTaskID(task.Text), cfg.Format.TagColors, colorOn))
+		}
+	}
+
+	return nil
+}
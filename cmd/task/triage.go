@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/AnishShah1803/jotr/internal/ai"
+	"github.com/AnishShah1803/jotr/internal/config"
+	"github.com/AnishShah1803/jotr/internal/services"
+	"github.com/AnishShah1803/jotr/internal/tasks"
+	"github.com/AnishShah1803/jotr/internal/utils"
+)
+
+// triageLineRegex matches a suggestion line such as "3: P1", "3. [P2]" or "- 3) P0".
+var triageLineRegex = regexp.MustCompile(`^\s*[-*]?\s*(\d+)\s*[:.)-]\s*\[?(P[0-3])\]?`)
+
+// triageSuggestion is a proposed priority for a pending task.
+type triageSuggestion struct {
+	Task     tasks.Task
+	Priority string
+}
+
+func buildTriagePrompt(pending []tasks.Task) string {
+	var sb strings.Builder
+
+	sb.WriteString("Suggest a priority for each task below, from P0 (most urgent) to P3 (least urgent).\n")
+	sb.WriteString("Reply with one line per task in the form \"<number>: P<n>\" and nothing else.\n\n")
+
+	for i, task := range pending {
+		fmt.Fprintf(&sb, "%d. %s\n", i+1, task.Text)
+	}
+
+	return sb.String()
+}
+
+// parseTriageSuggestions maps the numbered lines of an AI response back onto
+// the pending tasks. Unparseable lines and out-of-range numbers are ignored.
+func parseTriageSuggestions(response string, pending []tasks.Task) []triageSuggestion {
+	var suggestions []triageSuggestion
+
+	seen := make(map[int]bool)
+
+	for _, line := range strings.Split(response, "\n") {
+		match := triageLineRegex.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+
+		n, err := strconv.Atoi(match[1])
+		if err != nil || n < 1 || n > len(pending) || seen[n] {
+			continue
+		}
+		seen[n] = true
+
+		suggestions = append(suggestions, triageSuggestion{Task: pending[n-1], Priority: match[2]})
+	}
+
+	return suggestions
+}
+
+func triageTasks(ctx context.Context, cfg *config.LoadedConfig) error {
+	assistant, err := ai.NewAssistant(cfg.AI)
+	if errors.Is(err, ai.ErrDisabled) {
+		fmt.Println("⚠️  Task triage is unavailable: AI is not enabled")
+		fmt.Println("   Set ai.enabled and ai.command in your config to use it")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	suggestions, err := suggestPriorities(ctx, cfg, assistant)
+	if err != nil {
+		return err
+	}
+
+	if len(suggestions) == 0 {
+		fmt.Println("✓ No priority changes suggested")
+		return nil
+	}
+
+	if !tasksYes && !utils.PromptYesNo("\nApply these priorities? [y/N]: ") {
+		fmt.Println("No changes made")
+		return nil
+	}
+
+	format := services.NewTodoFormat(cfg.Format)
+	priorities := make(map[string]string, len(suggestions))
+	for _, s := range suggestions {
+		task := s.Task
+		format.Tasks.EnsureTaskID(&task)
+		priorities[task.ID] = s.Priority
+	}
+
+	updated, err := services.NewTaskService().SetTaskPriorities(ctx, services.PriorityOptions{
+		Priorities: priorities,
+		TodoPath:   cfg.TodoPath,
+		StatePath:  cfg.StatePath,
+		TodoFormat: format,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ Updated priority of %d task(s)\n", updated)
+
+	return nil
+}
+
+// suggestPriorities asks the assistant to prioritize pending tasks and prints
+// the proposed changes. Only suggestions that differ from the current
+// priority are returned.
+func suggestPriorities(ctx context.Context, cfg *config.LoadedConfig, assistant ai.Assistant) ([]triageSuggestion, error) {
+	allTasks, err := services.NewTodoFormat(cfg.Format).ReadTasks(ctx, cfg.TodoPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tasks: %w", err)
+	}
+
+	completed := false
+	pending := tasks.FilterTasks(allTasks, &completed, "", "")
+
+	if len(pending) == 0 {
+		return nil, nil
+	}
+
+	response, err := assistant.Ask(ctx, buildTriagePrompt(pending))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get priority suggestions: %w", err)
+	}
+
+	var changes []triageSuggestion
+
+	for _, s := range parseTriageSuggestions(response, pending) {
+		if s.Task.Priority == s.Priority {
+			continue
+		}
+
+		current := s.Task.Priority
+		if current == "" {
+			current = "none"
+		}
+		fmt.Printf("  [%s] %s  (was %s)\n", s.Priority, s.Task.Text, current)
+
+		changes = append(changes, s)
+	}
+
+	return changes, nil
+}
@@ -144,6 +144,9 @@ func runHealthCheck() error {
 	fmt.Printf("  Capture Section: %s\n", cfg.Format.CaptureSection)
 	fmt.Printf("  Daily Note Sections: %v\n", cfg.Format.DailyNoteSections)
 	fmt.Printf("  Include Weekends: %v\n", cfg.Streaks.IncludeWeekends)
+	if len(cfg.Streaks.WeekendDays) > 0 {
+		fmt.Printf("  Weekend Days: %v\n", cfg.Streaks.WeekendDays)
+	}
 
 	return nil
 }
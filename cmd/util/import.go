@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/AnishShah1803/jotr/internal/config"
+	"github.com/AnishShah1803/jotr/internal/notes"
+	"github.com/AnishShah1803/jotr/internal/utils"
+)
+
+var importDryRun bool
+
+var ImportCmd = &cobra.Command{
+	Use:   "import [source] [path]",
+	Short: "Import notes from other tools",
+	Long: `Import notes from other tools into the vault.
+
+Sources:
+  dir [path]        Convert each .txt file under path into a markdown note
+
+Imported notes keep their relative folder layout under the base directory.
+A "# Title" heading taken from the filename is added when the file doesn't
+start with one. Notes that already exist are skipped.
+
+Examples:
+  jotr import dir ~/old-notes
+  jotr import dir ~/old-notes --dry-run`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return fmt.Errorf("source required: dir")
+		}
+
+		cfg, err := config.LoadWithContext(cmd.Context(), "")
+		if err != nil {
+			return err
+		}
+
+		switch args[0] {
+		case "dir":
+			if len(args) < 2 {
+				return fmt.Errorf("usage: import dir [path]")
+			}
+			return importDir(cmd.Context(), cfg, args[1])
+		default:
+			return fmt.Errorf("unknown source: %s", args[0])
+		}
+	},
+}
+
+func init() {
+	ImportCmd.Flags().BoolVar(&importDryRun, "dry-run", false, "Show what would be imported without writing notes")
+}
+
+// textToMarkdown prepends a "# title" heading to content unless its first
+// non-blank line is already a top-level heading.
+func textToMarkdown(title, content string) string {
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "# ") {
+			return content
+		}
+		break
+	}
+
+	return fmt.Sprintf("# %s\n\n%s", title, strings.TrimLeft(content, "\n"))
+}
+
+func importDir(ctx context.Context, cfg *config.LoadedConfig, srcDir string) error {
+	info, err := os.Stat(srcDir)
+	if err != nil {
+		return fmt.Errorf("cannot read source directory: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("not a directory: %s", srcDir)
+	}
+
+	if importDryRun {
+		fmt.Println("⚠️  DRY RUN - No changes made")
+		fmt.Println()
+	}
+
+	imported, skipped := 0, 0
+
+	err = filepath.WalkDir(srcDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.EqualFold(filepath.Ext(path), ".txt") {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(srcDir, path)
+		if err != nil {
+			return err
+		}
+		relNote := strings.TrimSuffix(relPath, filepath.Ext(relPath)) + ".md"
+		notePath := filepath.Join(cfg.Paths.BaseDir, relNote)
+
+		if utils.FileExists(notePath) {
+			fmt.Printf("  skip    %s (already exists)\n", relNote)
+			skipped++
+			return nil
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+
+		if !importDryRun {
+			title := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+			if err := notes.WriteNote(ctx, notePath, textToMarkdown(title, string(content))); err != nil {
+				return fmt.Errorf("failed to write %s: %w", relNote, err)
+			}
+		}
+
+		fmt.Printf("  import  %s\n", relNote)
+		imported++
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Println()
+
+	if importDryRun {
+		fmt.Printf("Would import %d note(s), %d skipped\n", imported, skipped)
+	} else {
+		fmt.Printf("✓ Imported %d note(s), %d skipped\n", imported, skipped)
+	}
+
+	return nil
+}
@@ -0,0 +1,250 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/AnishShah1803/jotr/internal/config"
+	"github.com/AnishShah1803/jotr/internal/notes"
+	"github.com/AnishShah1803/jotr/internal/services"
+	"github.com/AnishShah1803/jotr/internal/state"
+	"github.com/AnishShah1803/jotr/internal/tasks"
+)
+
+var LintCmd = &cobra.Command{
+	Use:   "lint [files...]",
+	Short: "Check notes for structural problems",
+	Long: `Check notes for common structural problems.
+
+Reports file and line for:
+  - malformed tasks (e.g. "- [ missing the closing bracket")
+  - duplicate task IDs within a file
+  - daily note section headings not in the configured sections
+  - unmatched [[ ]] link brackets
+  - unclosed code fences
+  - blocked-by: task IDs that match no task in the sync state or the
+    checked notes
+
+With no arguments all notes are checked.
+
+Examples:
+  jotr lint                                  # Lint every note
+  jotr lint Diary/2025/01-Jan/2025-01-15-Wed.md`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.LoadWithContext(cmd.Context(), "")
+		if err != nil {
+			return err
+		}
+
+		count, err := lintNotes(cmd.Context(), cfg, args)
+		if err != nil {
+			return err
+		}
+
+		if count > 0 {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("found %d issue(s)", count)
+		}
+
+		return nil
+	},
+}
+
+// lintIssue is a single problem found in a note.
+type lintIssue struct {
+	Message string
+	Line    int
+}
+
+var (
+	// malformedTaskRegex matches a bullet followed by an opening checkbox
+	// bracket that is never closed, e.g. "- [ text" or "* [x".
+	malformedTaskRegex  = regexp.MustCompile(`^(\*|-|\+)\s*\[([ xX]?)(\s|$)`)
+	wellFormedTaskRegex = regexp.MustCompile(`^(\*|-|\+)\s*\[[ xX]\]`)
+)
+
+// lintNote checks note content for problems. sections lists the allowed
+// "## " headings; when empty, headings are not checked.
+func lintNote(content string, sections []string, taskOpts tasks.Options) []lintIssue {
+	var issues []lintIssue
+
+	allowed := make(map[string]bool, len(sections))
+	for _, section := range sections {
+		allowed[section] = true
+	}
+
+	seenIDs := make(map[string]int)
+	fenceLine := 0
+
+	for i, line := range strings.Split(content, "\n") {
+		lineNum := i + 1
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "```") {
+			if fenceLine == 0 {
+				fenceLine = lineNum
+			} else {
+				fenceLine = 0
+			}
+			continue
+		}
+		if fenceLine != 0 {
+			continue
+		}
+
+		if malformedTaskRegex.MatchString(trimmed) && !wellFormedTaskRegex.MatchString(trimmed) {
+			issues = append(issues, lintIssue{Line: lineNum, Message: "malformed task: missing closing ]"})
+		}
+
+		if id := taskOpts.ExtractTaskID(line); id != "" {
+			if first, ok := seenIDs[id]; ok {
+				issues = append(issues, lintIssue{Line: lineNum, Message: fmt.Sprintf("duplicate task ID %s (first on line %d)", id, first)})
+			} else {
+				seenIDs[id] = lineNum
+			}
+		}
+
+		if len(allowed) > 0 && strings.HasPrefix(trimmed, "## ") {
+			heading := strings.TrimSpace(strings.TrimPrefix(trimmed, "## "))
+			if !allowed[heading] {
+				issues = append(issues, lintIssue{Line: lineNum, Message: fmt.Sprintf("unexpected section heading %q", heading)})
+			}
+		}
+
+		if opens, closes := strings.Count(line, "[["), strings.Count(line, "]]"); opens != closes {
+			issues = append(issues, lintIssue{Line: lineNum, Message: "unmatched [[ ]] link brackets"})
+		}
+	}
+
+	if fenceLine != 0 {
+		issues = append(issues, lintIssue{Line: fenceLine, Message: "unclosed code fence"})
+	}
+
+	return issues
+}
+
+// danglingDependencies reports blocked-by references in content to task IDs
+// not in known.
+func danglingDependencies(content string, known map[string]bool, taskOpts tasks.Options) []lintIssue {
+	var issues []lintIssue
+	for _, task := range taskOpts.ParseTasks(content) {
+		for _, id := range tasks.BlockedBy(task) {
+			if !known[id] {
+				issues = append(issues, lintIssue{Line: task.Line, Message: fmt.Sprintf("blocked-by references unknown task %s", id)})
+			}
+		}
+	}
+	return issues
+}
+
+// knownTaskIDs returns the IDs of the tasks in sync state and in contents.
+func knownTaskIDs(cfg *config.LoadedConfig, contents []string) map[string]bool {
+	known := make(map[string]bool)
+
+	if cfg.StatePath != "" {
+		if todoState, err := state.Read(cfg.StatePath); err == nil {
+			for id := range todoState.Tasks {
+				known[id] = true
+			}
+		}
+	}
+
+	taskOpts := services.TaskOptions(cfg.Format)
+	for _, content := range contents {
+		for _, task := range taskOpts.ParseTasks(content) {
+			if task.ID != "" {
+				known[task.ID] = true
+			}
+		}
+	}
+
+	return known
+}
+
+// dailyNoteSections returns the headings allowed in daily notes.
+func dailyNoteSections(cfg *config.LoadedConfig) []string {
+	sections := notes.BuildDailyNoteSections(cfg)
+	if cfg.Format.CaptureSection != "" {
+		sections = append(sections, cfg.Format.CaptureSection)
+	}
+
+	return sections
+}
+
+func isDailyNote(cfg *config.LoadedConfig, notePath string) bool {
+	rel, err := filepath.Rel(cfg.DiaryPath, notePath)
+	return err == nil && !strings.HasPrefix(rel, "..")
+}
+
+// lintNotes lints the given paths (or all notes) and prints each issue.
+// It returns the total number of issues found.
+func lintNotes(ctx context.Context, cfg *config.LoadedConfig, paths []string) (int, error) {
+	if len(paths) == 0 {
+		allNotes, err := notes.FindNotes(ctx, cfg.Paths.BaseDir)
+		if err != nil {
+			return 0, fmt.Errorf("failed to find notes: %w", err)
+		}
+		paths = allNotes
+	}
+
+	sections := dailyNoteSections(cfg)
+	taskOpts := services.TaskOptions(cfg.Format)
+	total := 0
+
+	absPaths := make([]string, len(paths))
+	contents := make([]string, len(paths))
+	for i, notePath := range paths {
+		absPath, err := filepath.Abs(notePath)
+		if err != nil {
+			return total, err
+		}
+
+		content, err := os.ReadFile(absPath)
+		if err != nil {
+			return total, fmt.Errorf("failed to read %s: %w", notePath, err)
+		}
+
+		absPaths[i] = absPath
+		contents[i] = string(content)
+	}
+
+	known := knownTaskIDs(cfg, contents)
+
+	for i, notePath := range paths {
+		absPath := absPaths[i]
+
+		var allowed []string
+		if isDailyNote(cfg, absPath) {
+			allowed = sections
+		}
+
+		relPath, relErr := filepath.Rel(cfg.Paths.BaseDir, absPath)
+		if relErr != nil {
+			relPath = notePath
+		}
+
+		issues := lintNote(contents[i], allowed, taskOpts)
+		issues = append(issues, danglingDependencies(contents[i], known, taskOpts)...)
+		sort.SliceStable(issues, func(a, b int) bool {
+			return issues[a].Line < issues[b].Line
+		})
+
+		for _, issue := range issues {
+			fmt.Printf("%s:%d: %s\n", relPath, issue.Line, issue.Message)
+			total++
+		}
+	}
+
+	if total == 0 {
+		fmt.Printf("✓ No issues found in %d note(s)\n", len(paths))
+	}
+
+	return total, nil
+}
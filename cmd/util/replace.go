@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/AnishShah1803/jotr/internal/config"
+	"github.com/AnishShah1803/jotr/internal/constants"
+	"github.com/AnishShah1803/jotr/internal/notes"
+	"github.com/AnishShah1803/jotr/internal/utils"
+)
+
+var (
+	replaceDryRun             bool
+	replaceRegex              bool
+	replaceIncludeFrontmatter bool
+)
+
+var ReplaceCmd = &cobra.Command{
+	Use:   "replace [old] [new]",
+	Short: "Find and replace text across notes",
+	Long: `Find and replace text across all notes.
+
+By default the search text is matched literally. Use --regex to treat it
+as a regular expression (the replacement may then use $1-style groups).
+Frontmatter blocks are left untouched unless --include-frontmatter is given.
+
+Examples:
+  jotr replace "old phrase" "new phrase"
+  jotr replace "old phrase" "new phrase" --dry-run
+  jotr replace --regex "TODO\((\w+)\)" "TODO[$1]"`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.LoadWithContext(cmd.Context(), "")
+		if err != nil {
+			return err
+		}
+
+		return replaceInNotes(cmd.Context(), cfg, args[0], args[1])
+	},
+}
+
+func init() {
+	ReplaceCmd.Flags().BoolVar(&replaceDryRun, "dry-run", false, "Show what would be replaced without making changes")
+	ReplaceCmd.Flags().BoolVar(&replaceRegex, "regex", false, "Treat the search text as a regular expression")
+	ReplaceCmd.Flags().BoolVar(&replaceIncludeFrontmatter, "include-frontmatter", false, "Also replace inside frontmatter blocks")
+}
+
+// replacer applies a single replacement to a string and reports how many
+// occurrences were replaced.
+type replacer func(s string) (string, int)
+
+func newReplacer(oldText, newText string, useRegex bool) (replacer, error) {
+	if oldText == "" {
+		return nil, fmt.Errorf("search text cannot be empty")
+	}
+
+	if !useRegex {
+		return func(s string) (string, int) {
+			n := strings.Count(s, oldText)
+			if n == 0 {
+				return s, 0
+			}
+			return strings.ReplaceAll(s, oldText, newText), n
+		}, nil
+	}
+
+	re, err := regexp.Compile(oldText)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regex: %w", err)
+	}
+
+	return func(s string) (string, int) {
+		n := len(re.FindAllStringIndex(s, -1))
+		if n == 0 {
+			return s, 0
+		}
+		return re.ReplaceAllString(s, newText), n
+	}, nil
+}
+
+// splitFrontmatter splits content into a leading frontmatter block (including
+// both --- delimiters) and the remaining body. If there is no well-formed
+// frontmatter, the returned frontmatter is empty.
+func splitFrontmatter(content string) (frontmatter, body string) {
+	if !strings.HasPrefix(content, "---\n") {
+		return "", content
+	}
+
+	end := strings.Index(content[4:], "\n---")
+	if end == -1 {
+		return "", content
+	}
+
+	end += 4 + len("\n---")
+	if end < len(content) && content[end] == '\n' {
+		end++
+	}
+
+	return content[:end], content[end:]
+}
+
+func replaceInNotes(ctx context.Context, cfg *config.LoadedConfig, oldText, newText string) error {
+	replace, err := newReplacer(oldText, newText, replaceRegex)
+	if err != nil {
+		return err
+	}
+
+	allNotes, err := notes.FindNotes(ctx, cfg.Paths.BaseDir)
+	if err != nil {
+		return fmt.Errorf("failed to find notes: %w", err)
+	}
+
+	if replaceDryRun {
+		fmt.Println("⚠️  DRY RUN - No changes made")
+		fmt.Println()
+	}
+
+	modifiedCount := 0
+	totalReplacements := 0
+
+	for _, notePath := range allNotes {
+		content, err := os.ReadFile(notePath)
+		if err != nil {
+			continue
+		}
+
+		frontmatter, body := "", string(content)
+		if !replaceIncludeFrontmatter {
+			frontmatter, body = splitFrontmatter(body)
+		}
+
+		newBody, count := replace(body)
+		if count == 0 {
+			continue
+		}
+
+		relPath, _ := filepath.Rel(cfg.Paths.BaseDir, notePath)
+
+		if !replaceDryRun {
+			if err := utils.AtomicWriteFile(notePath, []byte(frontmatter+newBody), constants.FilePerm0644); err != nil {
+				fmt.Printf("⚠️  Failed to update: %s\n", relPath)
+				continue
+			}
+		}
+
+		fmt.Printf("  %s: %d replacement(s)\n", relPath, count)
+
+		modifiedCount++
+		totalReplacements += count
+	}
+
+	if modifiedCount == 0 {
+		fmt.Println("No matches found")
+		return nil
+	}
+
+	if replaceDryRun {
+		fmt.Printf("\nWould replace %d occurrence(s) in %d notes\n", totalReplacements, modifiedCount)
+	} else {
+		fmt.Printf("\n✓ Replaced %d occurrence(s) in %d notes\n", totalReplacements, modifiedCount)
+	}
+
+	return nil
+}
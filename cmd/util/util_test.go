@@ -11,6 +11,9 @@ import (
 	"github.com/AnishShah1803/jotr/internal/config"
 	"github.com/AnishShah1803/jotr/internal/constants"
 	"github.com/AnishShah1803/jotr/internal/notes"
+	"github.com/AnishShah1803/jotr/internal/state"
+	"github.com/AnishShah1803/jotr/internal/tasks"
+	"github.com/AnishShah1803/jotr/internal/testhelpers"
 )
 
 func createTestUtilConfig(t *testing.T, tmpDir string) *config.LoadedConfig {
@@ -91,6 +94,271 @@ func TestBulkTag_Placeholder(t *testing.T) {
 	}
 }
 
+func TestReplaceInNotes_Literal(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := createTestUtilConfig(t, tmpDir)
+
+	notePath := filepath.Join(tmpDir, "Note.md")
+	notes.WriteNote(context.Background(), notePath, "# Note\nold phrase and old phrase.\n")
+
+	defer func() { replaceDryRun, replaceRegex, replaceIncludeFrontmatter = false, false, false }()
+
+	if err := replaceInNotes(context.Background(), cfg, "old phrase", "new phrase"); err != nil {
+		t.Fatalf("replaceInNotes should succeed: %v", err)
+	}
+
+	content, _ := os.ReadFile(notePath)
+	if string(content) != "# Note\nnew phrase and new phrase.\n" {
+		t.Errorf("Unexpected content after replace: %q", string(content))
+	}
+}
+
+func TestReplaceInNotes_Regex(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := createTestUtilConfig(t, tmpDir)
+
+	notePath := filepath.Join(tmpDir, "Note.md")
+	notes.WriteNote(context.Background(), notePath, "TODO(alice) and TODO(bob)\n")
+
+	replaceRegex = true
+	defer func() { replaceDryRun, replaceRegex, replaceIncludeFrontmatter = false, false, false }()
+
+	if err := replaceInNotes(context.Background(), cfg, `TODO\((\w+)\)`, "TODO[$1]"); err != nil {
+		t.Fatalf("replaceInNotes should succeed: %v", err)
+	}
+
+	content, _ := os.ReadFile(notePath)
+	if string(content) != "TODO[alice] and TODO[bob]\n" {
+		t.Errorf("Unexpected content after regex replace: %q", string(content))
+	}
+}
+
+func TestReplaceInNotes_DryRunDoesNotModify(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := createTestUtilConfig(t, tmpDir)
+
+	original := "# Note\nold phrase\n"
+	notePath := filepath.Join(tmpDir, "Note.md")
+	notes.WriteNote(context.Background(), notePath, original)
+
+	replaceDryRun = true
+	defer func() { replaceDryRun, replaceRegex, replaceIncludeFrontmatter = false, false, false }()
+
+	if err := replaceInNotes(context.Background(), cfg, "old phrase", "new phrase"); err != nil {
+		t.Fatalf("replaceInNotes should succeed: %v", err)
+	}
+
+	content, _ := os.ReadFile(notePath)
+	if string(content) != original {
+		t.Errorf("Dry run modified file: %q", string(content))
+	}
+}
+
+func TestReplaceInNotes_SkipsFrontmatter(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := createTestUtilConfig(t, tmpDir)
+
+	notePath := filepath.Join(tmpDir, "Note.md")
+	notes.WriteNote(context.Background(), notePath, "---\nstatus: draft\n---\nThis is a draft.\n")
+
+	defer func() { replaceDryRun, replaceRegex, replaceIncludeFrontmatter = false, false, false }()
+
+	if err := replaceInNotes(context.Background(), cfg, "draft", "final"); err != nil {
+		t.Fatalf("replaceInNotes should succeed: %v", err)
+	}
+
+	content, _ := os.ReadFile(notePath)
+	if string(content) != "---\nstatus: draft\n---\nThis is a final.\n" {
+		t.Errorf("Frontmatter should be preserved, got: %q", string(content))
+	}
+
+	replaceIncludeFrontmatter = true
+
+	if err := replaceInNotes(context.Background(), cfg, "draft", "final"); err != nil {
+		t.Fatalf("replaceInNotes should succeed: %v", err)
+	}
+
+	content, _ = os.ReadFile(notePath)
+	if !strings.Contains(string(content), "status: final") {
+		t.Errorf("Expected frontmatter to be replaced with --include-frontmatter, got: %q", string(content))
+	}
+}
+
+func TestLintNote(t *testing.T) {
+	content := `## Notes
+
+- [ forgot to close this task
+- [ ] Write report <!-- id: abcd1234 -->
+- [ ] Write report again <!-- id: abcd1234 -->
+See [[Project Plan for details
+
+## Random
+
+` + "```" + `
+- [ not a task inside code
+`
+
+	issues := lintNote(content, []string{"Notes", "Tasks"}, tasks.Options{})
+
+	want := map[int]string{
+		3:  "malformed task",
+		5:  "duplicate task ID abcd1234",
+		6:  "unmatched [[",
+		8:  "unexpected section heading",
+		10: "unclosed code fence",
+	}
+
+	if len(issues) != len(want) {
+		t.Fatalf("lintNote() returned %d issues, want %d: %+v", len(issues), len(want), issues)
+	}
+
+	for _, issue := range issues {
+		prefix, ok := want[issue.Line]
+		if !ok || !strings.HasPrefix(issue.Message, prefix) {
+			t.Errorf("unexpected issue on line %d: %q", issue.Line, issue.Message)
+		}
+	}
+}
+
+func TestLintNotes_DailyNoteFlagged(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := createTestUtilConfig(t, tmpDir)
+	cfg.Format.DailyNoteSections = []string{"Notes"}
+
+	notePath := filepath.Join(cfg.DiaryPath, "2025", "01-Jan", "2025-01-15-Wed.md")
+	content := "## Notes\n\n- [x malformed\n\n## Tasks\n\n- [ ] One <!-- id: 12345678 -->\n- [ ] Two <!-- id: 12345678 -->\n"
+	if err := notes.WriteNote(context.Background(), notePath, content); err != nil {
+		t.Fatalf("Failed to write note: %v", err)
+	}
+
+	otherPath := filepath.Join(tmpDir, "project.md")
+	if err := notes.WriteNote(context.Background(), otherPath, "## Anything Goes\n"); err != nil {
+		t.Fatalf("Failed to write note: %v", err)
+	}
+
+	count, err := lintNotes(context.Background(), cfg, nil)
+	if err != nil {
+		t.Fatalf("lintNotes() error = %v", err)
+	}
+
+	if count != 2 {
+		t.Errorf("lintNotes() = %d issues, want 2 (malformed task and duplicate ID)", count)
+	}
+}
+
+func TestLintNotes_DanglingBlockedBy(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := createTestUtilConfig(t, tmpDir)
+	cfg.StatePath = filepath.Join(tmpDir, ".todo_state.json")
+
+	todoState := state.NewTodoState()
+	todoState.Tasks["cafef00d"] = state.TaskState{ID: "cafef00d", Text: "Get approval"}
+	if err := todoState.Write(cfg.StatePath); err != nil {
+		t.Fatalf("Failed to write state: %v", err)
+	}
+
+	notePath := filepath.Join(tmpDir, "project.md")
+	content := "## Tasks\n\n- [ ] Ship release blocked-by: cafef00d\n- [ ] Announce blocked-by: deadbeef\n"
+	if err := notes.WriteNote(context.Background(), notePath, content); err != nil {
+		t.Fatalf("Failed to write note: %v", err)
+	}
+
+	var count int
+	var err error
+	out := testhelpers.CaptureStdout(func() {
+		count, err = lintNotes(context.Background(), cfg, nil)
+	})
+	if err != nil {
+		t.Fatalf("lintNotes() error = %v", err)
+	}
+
+	if count != 1 {
+		t.Fatalf("lintNotes() = %d issues, want 1:\n%s", count, out)
+	}
+	if !strings.Contains(out, "project.md:4: blocked-by references unknown task deadbeef") {
+		t.Errorf("expected the dangling reference to be reported with its source, got:\n%s", out)
+	}
+}
+
+func TestImportDir_CreatesNotesAndSkipsExisting(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := createTestUtilConfig(t, tmpDir)
+
+	srcDir := t.TempDir()
+	files := map[string]string{
+		"groceries.txt":        "milk\neggs\n",
+		"work/meeting.txt":     "# Standup\n\nAll good.\n",
+		"ignored-image.png":    "binary",
+		"work/nested/idea.TXT": "Build a thing",
+	}
+	for name, content := range files {
+		path := filepath.Join(srcDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), constants.FilePerm0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	if err := importDir(context.Background(), cfg, srcDir); err != nil {
+		t.Fatalf("importDir() error = %v", err)
+	}
+
+	want := map[string]string{
+		"groceries.md":        "# groceries\n\nmilk\neggs\n",
+		"work/meeting.md":     "# Standup\n\nAll good.\n",
+		"work/nested/idea.md": "# idea\n\nBuild a thing",
+	}
+	for name, wantContent := range want {
+		got, err := os.ReadFile(filepath.Join(tmpDir, name))
+		if err != nil {
+			t.Errorf("Expected note %s: %v", name, err)
+			continue
+		}
+		if string(got) != wantContent {
+			t.Errorf("%s = %q; want %q", name, got, wantContent)
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(tmpDir, "ignored-image.md")); err == nil {
+		t.Error("Non-txt files should not be imported")
+	}
+
+	// Re-running must not overwrite notes that now exist.
+	edited := filepath.Join(tmpDir, "groceries.md")
+	if err := os.WriteFile(edited, []byte("edited"), constants.FilePerm0644); err != nil {
+		t.Fatalf("Failed to edit note: %v", err)
+	}
+	if err := importDir(context.Background(), cfg, srcDir); err != nil {
+		t.Fatalf("importDir() second run error = %v", err)
+	}
+	if got, _ := os.ReadFile(edited); string(got) != "edited" {
+		t.Errorf("Existing note was overwritten on re-import: %q", got)
+	}
+}
+
+func TestImportDir_DryRun(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := createTestUtilConfig(t, tmpDir)
+
+	srcDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(srcDir, "note.txt"), []byte("text"), constants.FilePerm0644); err != nil {
+		t.Fatalf("Failed to write source: %v", err)
+	}
+
+	importDryRun = true
+	defer func() { importDryRun = false }()
+
+	if err := importDir(context.Background(), cfg, srcDir); err != nil {
+		t.Fatalf("importDir() error = %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(tmpDir, "note.md")); err == nil {
+		t.Error("Dry run should not create notes")
+	}
+}
+
 func TestRunHealthCheck_ValidConfig(t *testing.T) {
 	tmpDir, err := os.MkdirTemp("", "jotr-health-test-")
 	if err != nil {
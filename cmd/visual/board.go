@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/AnishShah1803/jotr/internal/config"
+	"github.com/AnishShah1803/jotr/internal/services"
+	"github.com/AnishShah1803/jotr/internal/state"
+	"github.com/AnishShah1803/jotr/internal/tasks"
+)
+
+var boardJSON bool
+
+// boardColumnWidth is the width of each text column, including padding.
+const boardColumnWidth = 30
+
+// Board columns, in display order.
+const (
+	columnPending    = "Pending"
+	columnInProgress = "In Progress"
+	columnBlocked    = "Blocked"
+	columnDone       = "Done"
+)
+
+var boardColumnNames = []string{columnPending, columnInProgress, columnBlocked, columnDone}
+
+var BoardCmd = &cobra.Command{
+	Use:   "board",
+	Short: "Show tasks as a board by status",
+	Long: `Show tasks from the sync state as side-by-side columns: Pending,
+In Progress ([/] tasks), Blocked (@waiting or blocked-by: tasks) and Done.
+Cancelled and archived tasks are left out. Within a column tasks are grouped
+by section and sorted by priority.
+
+Examples:
+  jotr board                   # Show the board
+  jotr board --json            # Output columns as JSON`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.LoadWithContext(cmd.Context(), "")
+		if err != nil {
+			return err
+		}
+
+		return showBoard(cfg)
+	},
+}
+
+func init() {
+	BoardCmd.Flags().BoolVar(&boardJSON, "json", false, "Output in JSON format")
+}
+
+// boardTask is a task as shown on the board.
+type boardTask struct {
+	ID       string `json:"id"`
+	Text     string `json:"text"`
+	Section  string `json:"section"`
+	Priority string `json:"priority,omitempty"`
+}
+
+// boardColumn is one status column of the board.
+type boardColumn struct {
+	Name  string      `json:"name"`
+	Tasks []boardTask `json:"tasks"`
+}
+
+// boardColumnFor returns the column a task belongs in, or "" to leave it off
+// the board.
+func boardColumnFor(task tasks.Task) string {
+	switch {
+	case tasks.IsCancelled(task):
+		return ""
+	case task.Completed:
+		return columnDone
+	case len(tasks.BlockedBy(task)) > 0 || tasks.IsWaiting(task.Text):
+		return columnBlocked
+	case task.Status == tasks.StatusInProgress:
+		return columnInProgress
+	default:
+		return columnPending
+	}
+}
+
+// buildBoard sorts the unarchived state tasks into board columns.
+func buildBoard(todoState *state.TodoState, taskOpts tasks.Options) []boardColumn {
+	var taskList []tasks.Task
+	for _, ts := range todoState.Tasks {
+		if ts.ArchivedDate != "" {
+			continue
+		}
+		taskList = append(taskList, tasks.Task{
+			Text:      ts.Text,
+			Priority:  ts.Priority,
+			Section:   ts.Section,
+			ID:        ts.ID,
+			Tags:      ts.Tags,
+			Completed: ts.Completed,
+			Status:    ts.Status,
+			Meta:      ts.Meta,
+		})
+	}
+	// Map order is random; fix it before the stable priority sort.
+	sort.Slice(taskList, func(i, j int) bool {
+		return taskList[i].ID < taskList[j].ID
+	})
+
+	byColumn := make(map[string][]tasks.Task)
+	for _, task := range taskList {
+		if column := boardColumnFor(task); column != "" {
+			byColumn[column] = append(byColumn[column], task)
+		}
+	}
+
+	columns := make([]boardColumn, 0, len(boardColumnNames))
+	for _, name := range boardColumnNames {
+		column := boardColumn{Name: name, Tasks: []boardTask{}}
+
+		sections := tasks.GroupBySection(byColumn[name])
+		for _, section := range tasks.SortedSections(sections) {
+			sectionTasks := sections[section]
+			tasks.SortByPriority(sectionTasks)
+			for _, task := range sectionTasks {
+				column.Tasks = append(column.Tasks, boardTask{
+					ID:       task.ID,
+					Text:     taskOpts.StripTaskID(task.Text),
+					Section:  task.Section,
+					Priority: task.Priority,
+				})
+			}
+		}
+
+		columns = append(columns, column)
+	}
+
+	return columns
+}
+
+// truncateCell shortens text to fit a board column.
+func truncateCell(text string, width int) string {
+	runes := []rune(text)
+	if len(runes) <= width {
+		return text
+	}
+	return string(runes[:width-1]) + "…"
+}
+
+// padCell pads text with spaces to width runes.
+func padCell(text string, width int) string {
+	if n := len([]rune(text)); n < width {
+		return text + strings.Repeat(" ", width-n)
+	}
+	return text
+}
+
+func showBoard(cfg *config.LoadedConfig) error {
+	todoState, err := state.Read(cfg.StatePath)
+	if err != nil {
+		return fmt.Errorf("failed to read state file: %w", err)
+	}
+
+	columns := buildBoard(todoState, services.TaskOptions(cfg.Format))
+
+	if boardJSON {
+		data, err := json.MarshalIndent(struct {
+			Columns []boardColumn `json:"columns"`
+		}{columns}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	cellWidth := boardColumnWidth - 2
+	rows := 0
+	var header, rule strings.Builder
+	for _, column := range columns {
+		header.WriteString(padCell(fmt.Sprintf("%s (%d)", column.Name, len(column.Tasks)), boardColumnWidth))
+		rule.WriteString(strings.Repeat("─", cellWidth) + "  ")
+		if len(column.Tasks) > rows {
+			rows = len(column.Tasks)
+		}
+	}
+	fmt.Println(strings.TrimRight(header.String(), " "))
+	fmt.Println(strings.TrimRight(rule.String(), " "))
+
+	for row := 0; row < rows; row++ {
+		var line strings.Builder
+		for _, column := range columns {
+			cell := ""
+			if row < len(column.Tasks) {
+				cell = truncateCell(column.Tasks[row].Text, cellWidth)
+			}
+			line.WriteString(padCell(cell, boardColumnWidth))
+		}
+		fmt.Println(strings.TrimRight(line.String(), " "))
+	}
+
+	return nil
+}
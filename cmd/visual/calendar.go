@@ -8,19 +8,29 @@ import (
 
 	"github.com/AnishShah1803/jotr/internal/config"
 	"github.com/AnishShah1803/jotr/internal/notes"
+	"github.com/AnishShah1803/jotr/internal/state"
 	"github.com/AnishShah1803/jotr/internal/utils"
 )
 
+var (
+	calendarMonthFlag string
+	calendarTasks     bool
+)
+
 var CalendarCmd = &cobra.Command{
 	Use:   "calendar",
 	Short: "Show calendar view",
-	Long: `Show a calendar view of daily notes for the current month.
+	Long: `Show a calendar view of daily notes for a month.
 
-Days with notes are highlighted in green.
+Days with a note are marked with *, and days that are part of a streak of
+two or more notes are highlighted. Weekends are skipped when counting
+streaks unless streaks.include_weekends is set.
 
 Examples:
-  jotr calendar               # Show this month's calendar
-  jotr cal                    # Using alias`,
+  jotr calendar                    # Show this month's calendar
+  jotr calendar --month 2025-01    # Show January 2025
+  jotr calendar --tasks            # Annotate days with completed task counts
+  jotr cal                         # Using alias`,
 	Aliases: []string{"cal"},
 	RunE: func(cmd *cobra.Command, args []string) error {
 		cfg, err := config.LoadWithContext(cmd.Context(), "")
@@ -32,79 +42,183 @@ Examples:
 	},
 }
 
+func init() {
+	CalendarCmd.Flags().StringVar(&calendarMonthFlag, "month", "", "Month to show (YYYY-MM, default: current month)")
+	CalendarCmd.Flags().BoolVar(&calendarTasks, "tasks", false, "Show completed task counts per day")
+}
+
+// calendarDay holds what is known about a single day in the calendar.
+type calendarDay struct {
+	Date      time.Time
+	HasNote   bool
+	InStreak  bool
+	Completed int
+}
+
+// buildCalendarMonth collects note presence, streaks and completed task
+// counts for every day of the month containing first.
+func buildCalendarMonth(cfg *config.LoadedConfig, first time.Time, withTasks bool) []calendarDay {
+	lastDay := first.AddDate(0, 1, -1).Day()
+	days := make([]calendarDay, lastDay)
+
+	for i := range days {
+		date := first.AddDate(0, 0, i)
+		days[i] = calendarDay{
+			Date:    date,
+			HasNote: utils.FileExists(notes.BuildDailyNotePath(cfg.DiaryPath, date)),
+		}
+	}
+
+	markStreaks(days, cfg.Streaks)
+
+	if withTasks {
+		counts := completedCountsByDate(cfg.StatePath)
+		for i := range days {
+			days[i].Completed = counts[days[i].Date.Format("2006-01-02")]
+		}
+	}
+
+	return days
+}
+
+// markStreaks flags days in runs of two or more consecutive notes. When
+// weekends are excluded they neither extend nor break a run.
+func markStreaks(days []calendarDay, streaks config.StreaksConfig) {
+	var run []int
+
+	flush := func() {
+		if len(run) >= 2 {
+			for _, i := range run {
+				days[i].InStreak = true
+			}
+		}
+		run = run[:0]
+	}
+
+	for i, day := range days {
+		if streaks.SkipsDay(day.Date) {
+			continue
+		}
+		if day.HasNote {
+			run = append(run, i)
+		} else {
+			flush()
+		}
+	}
+	flush()
+}
+
+// completedCountsByDate counts completed tasks in state by completion date.
+func completedCountsByDate(statePath string) map[string]int {
+	counts := make(map[string]int)
+
+	if statePath == "" || !utils.FileExists(statePath) {
+		return counts
+	}
+
+	todoState, err := state.Read(statePath)
+	if err != nil {
+		return counts
+	}
+
+	for _, task := range todoState.GetCompletedTasks() {
+		if task.CompletedDate != "" {
+			counts[task.CompletedDate]++
+		}
+	}
+
+	return counts
+}
+
+func parseCalendarMonth(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local), nil
+	}
+
+	first, err := time.ParseInLocation("2006-01", value, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --month %q (use YYYY-MM)", value)
+	}
+
+	return first, nil
+}
+
 func showCalendar(cfg *config.LoadedConfig) error {
 	now := time.Now()
-	year := now.Year()
-	month := now.Month()
 
-	fmt.Printf("📅 %s %d", month.String(), year)
-	fmt.Println("====================")
+	first, err := parseCalendarMonth(calendarMonthFlag, now)
+	if err != nil {
+		return err
+	}
 
-	// Print header
-	fmt.Println("Su Mo Tu We Th Fr Sa")
+	days := buildCalendarMonth(cfg, first, calendarTasks)
+
+	fmt.Printf("📅 %s %d\n", first.Month().String(), first.Year())
+	fmt.Println("============================")
 
-	// Get first day of month
-	firstDay := time.Date(year, month, 1, 0, 0, 0, 0, time.Local)
+	// Print header
+	fmt.Println("Su  Mo  Tu  We  Th  Fr  Sa")
 
 	// Print leading spaces
-	weekday := int(firstDay.Weekday())
+	weekday := int(first.Weekday())
 	for i := 0; i < weekday; i++ {
-		fmt.Print("   ")
+		fmt.Print("    ")
 	}
 
-	// Get last day of month
-	lastDay := firstDay.AddDate(0, 1, -1).Day()
-
-	// Print days
-	for day := 1; day <= lastDay; day++ {
-		date := time.Date(year, month, day, 0, 0, 0, 0, time.Local)
-		notePath := notes.BuildDailyNotePath(cfg.DiaryPath, date)
-
-		exists := utils.FileExists(notePath)
+	notesThisMonth := 0
 
-		// Format day
-		dayStr := fmt.Sprintf("%2d", day)
+	for _, day := range days {
+		dayStr := fmt.Sprintf("%2d", day.Date.Day())
+		mark := " "
+		if day.HasNote {
+			mark = "*"
+			notesThisMonth++
+		}
 
-		if exists {
-			// Highlight days with notes
-			if day == now.Day() && month == now.Month() {
-				fmt.Printf("\033[1;32m%s\033[0m ", dayStr) // Bold green for today
-			} else {
-				fmt.Printf("\033[32m%s\033[0m ", dayStr) // Green for notes
-			}
-		} else {
-			if day == now.Day() && month == now.Month() {
-				fmt.Printf("\033[1m%s\033[0m ", dayStr) // Bold for today
-			} else {
-				fmt.Printf("%s ", dayStr) // Normal
-			}
+		isToday := day.Date.Format("2006-01-02") == now.Format("2006-01-02")
+
+		switch {
+		case isToday && day.HasNote:
+			fmt.Printf("\033[1;32m%s\033[0m%s ", dayStr, mark) // Bold green for today
+		case isToday:
+			fmt.Printf("\033[1m%s\033[0m%s ", dayStr, mark) // Bold for today
+		case day.InStreak:
+			fmt.Printf("\033[33m%s\033[0m%s ", dayStr, mark) // Yellow for streaks
+		case day.HasNote:
+			fmt.Printf("\033[32m%s\033[0m%s ", dayStr, mark) // Green for notes
+		default:
+			fmt.Printf("%s%s ", dayStr, mark) // Normal
 		}
 
 		// New line on Saturday
-		if (weekday+day)%7 == 0 {
+		if day.Date.Weekday() == time.Saturday {
 			fmt.Println()
 		}
 	}
 
 	fmt.Println()
 	fmt.Println("Legend:")
-	fmt.Println("  \033[32m##\033[0m - Day with note")
-	fmt.Println("  \033[1m##\033[0m - Today")
-	fmt.Println("  ## - No note")
+	fmt.Println("  ##* - Day with note")
+	fmt.Println("  \033[33m##\033[0m  - Part of a streak")
+	fmt.Println("  \033[1m##\033[0m  - Today")
 
-	// Show stats for the month
-	notesThisMonth := 0
+	fmt.Printf("\nNotes this month: %d/%d days\n", notesThisMonth, len(days))
 
-	for day := 1; day <= lastDay; day++ {
-		date := time.Date(year, month, day, 0, 0, 0, 0, time.Local)
+	if calendarTasks {
+		fmt.Println("\nCompleted tasks:")
 
-		notePath := notes.BuildDailyNotePath(cfg.DiaryPath, date)
-		if utils.FileExists(notePath) {
-			notesThisMonth++
+		total := 0
+		for _, day := range days {
+			if day.Completed > 0 {
+				fmt.Printf("  %s: %d\n", day.Date.Format("Jan 02"), day.Completed)
+				total += day.Completed
+			}
 		}
-	}
 
-	fmt.Printf("\nNotes this month: %d/%d days\n", notesThisMonth, lastDay)
+		if total == 0 {
+			fmt.Println("  none")
+		}
+	}
 
 	return nil
 }
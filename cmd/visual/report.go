@@ -0,0 +1,325 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/AnishShah1803/jotr/internal/config"
+	"github.com/AnishShah1803/jotr/internal/notes"
+	"github.com/AnishShah1803/jotr/internal/tasks"
+	"github.com/AnishShah1803/jotr/internal/utils"
+)
+
+var (
+	reportSince string
+	reportUntil string
+	reportTop   int
+	reportJSON  bool
+	reportYear  int
+)
+
+var ReportCmd = &cobra.Command{
+	Use:   "report [type]",
+	Short: "Generate reports from daily notes",
+	Long: `Generate reports over a range of daily notes.
+
+Reports:
+  words             Most frequent terms, excluding markdown, stopwords and task IDs
+  activity          Daily notes written per month of a year (--year)
+
+The range defaults to the current month up to today.
+
+Examples:
+  jotr report words                                   # Top terms this month
+  jotr report words --since 2025-01-01 --until 2025-01-31
+  jotr report words --top 50 --json                   # Feed a word cloud
+  jotr report activity --year 2025                    # Notes per month in 2025`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return fmt.Errorf("report type required: words, activity")
+		}
+
+		cfg, err := config.LoadWithContext(cmd.Context(), "")
+		if err != nil {
+			return err
+		}
+
+		since, until, err := parseReportRange(reportSince, reportUntil, time.Now())
+		if err != nil {
+			return err
+		}
+
+		switch args[0] {
+		case "words":
+			return showWordReport(cmd.Context(), cfg, since, until)
+		case "activity":
+			year := reportYear
+			if year == 0 {
+				year = time.Now().Year()
+			}
+			return showActivityReport(cmd.Context(), cfg, year)
+		default:
+			return fmt.Errorf("unknown report: %s", args[0])
+		}
+	},
+}
+
+func init() {
+	ReportCmd.Flags().StringVar(&reportSince, "since", "", "Start date (YYYY-MM-DD, default: first of this month)")
+	ReportCmd.Flags().StringVar(&reportUntil, "until", "", "End date (YYYY-MM-DD, default: today)")
+	ReportCmd.Flags().IntVar(&reportTop, "top", 20, "Number of terms to show")
+	ReportCmd.Flags().BoolVar(&reportJSON, "json", false, "Output in JSON format")
+	ReportCmd.Flags().IntVar(&reportYear, "year", 0, "Year for the activity report (default: this year)")
+}
+
+// termCount is a term and the number of times it occurs.
+type termCount struct {
+	Term  string `json:"term"`
+	Count int    `json:"count"`
+}
+
+var (
+	wordTokenRegex   = regexp.MustCompile(`[a-zA-Z][a-zA-Z']*[a-zA-Z]`)
+	htmlCommentRegex = regexp.MustCompile(`<!--.*?-->`)
+	urlRegex         = regexp.MustCompile(`https?://\S+`)
+	checkboxRegex    = regexp.MustCompile(`^\s*[-*+]\s*\[[ xX]\]`)
+)
+
+// stopwords are common English words excluded from word reports.
+var stopwords = map[string]bool{
+	"a": true, "about": true, "after": true, "all": true, "also": true, "am": true,
+	"an": true, "and": true, "any": true, "are": true, "as": true, "at": true,
+	"be": true, "been": true, "but": true, "by": true, "can": true, "could": true,
+	"did": true, "do": true, "does": true, "for": true, "from": true, "had": true,
+	"has": true, "have": true, "he": true, "her": true, "him": true, "his": true,
+	"how": true, "i": true, "i'm": true, "if": true, "in": true, "into": true,
+	"is": true, "it": true, "it's": true, "its": true, "just": true, "me": true,
+	"more": true, "my": true, "no": true, "not": true, "of": true, "on": true,
+	"one": true, "or": true, "our": true, "out": true, "over": true, "she": true,
+	"so": true, "some": true, "than": true, "that": true, "the": true, "their": true,
+	"them": true, "then": true, "there": true, "these": true, "they": true,
+	"this": true, "to": true, "up": true, "us": true, "was": true, "we": true,
+	"were": true, "what": true, "when": true, "which": true, "who": true,
+	"will": true, "with": true, "would": true, "you": true, "your": true,
+}
+
+// parseReportRange parses --since/--until, defaulting to the first of the
+// current month through today.
+func parseReportRange(sinceStr, untilStr string, now time.Time) (time.Time, time.Time, error) {
+	since := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local)
+	until := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+
+	if sinceStr != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", sinceStr, time.Local)
+		if err != nil {
+			return since, until, fmt.Errorf("invalid --since date (use YYYY-MM-DD): %w", err)
+		}
+		since = parsed
+	}
+
+	if untilStr != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", untilStr, time.Local)
+		if err != nil {
+			return since, until, fmt.Errorf("invalid --until date (use YYYY-MM-DD): %w", err)
+		}
+		until = parsed
+	}
+
+	if until.Before(since) {
+		return since, until, fmt.Errorf("--until must not be before --since")
+	}
+
+	return since, until, nil
+}
+
+// countWords adds the terms in a note body to counts, skipping frontmatter,
+// code blocks, headings, markdown syntax, URLs, task IDs and stopwords.
+func countWords(content string, counts map[string]int) {
+	inFrontmatter := strings.HasPrefix(content, "---\n")
+	inCodeBlock := false
+
+	for i, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		if inFrontmatter {
+			if i > 0 && trimmed == "---" {
+				inFrontmatter = false
+			}
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "```") {
+			inCodeBlock = !inCodeBlock
+			continue
+		}
+		// Headings are mostly the repeated daily note sections, not prose.
+		if inCodeBlock || strings.HasPrefix(trimmed, "#") && strings.Contains(trimmed, "# ") {
+			continue
+		}
+
+		line = checkboxRegex.ReplaceAllString(line, "")
+		line = htmlCommentRegex.ReplaceAllString(tasks.StripCompletedTag(line), "")
+		line = urlRegex.ReplaceAllString(line, "")
+
+		for _, word := range wordTokenRegex.FindAllString(line, -1) {
+			word = strings.ToLower(word)
+			if stopwords[word] {
+				continue
+			}
+			counts[word]++
+		}
+	}
+}
+
+// topTerms returns the n most frequent terms, ties broken alphabetically.
+func topTerms(counts map[string]int, n int) []termCount {
+	terms := make([]termCount, 0, len(counts))
+	for term, count := range counts {
+		terms = append(terms, termCount{Term: term, Count: count})
+	}
+
+	sort.Slice(terms, func(i, j int) bool {
+		if terms[i].Count != terms[j].Count {
+			return terms[i].Count > terms[j].Count
+		}
+		return terms[i].Term < terms[j].Term
+	})
+
+	if n > 0 && len(terms) > n {
+		terms = terms[:n]
+	}
+
+	return terms
+}
+
+// collectWordCounts counts terms across the daily notes from since to until inclusive.
+func collectWordCounts(ctx context.Context, cfg *config.LoadedConfig, since, until time.Time) (map[string]int, int, error) {
+	counts := make(map[string]int)
+	noteCount := 0
+
+	for date := since; !date.After(until); date = date.AddDate(0, 0, 1) {
+		if err := ctx.Err(); err != nil {
+			return nil, 0, err
+		}
+
+		notePath := notes.BuildDailyNotePath(cfg.DiaryPath, date)
+		if !utils.FileExists(notePath) {
+			continue
+		}
+
+		content, err := os.ReadFile(notePath)
+		if err != nil {
+			continue
+		}
+
+		countWords(string(content), counts)
+		noteCount++
+	}
+
+	return counts, noteCount, nil
+}
+
+func showWordReport(ctx context.Context, cfg *config.LoadedConfig, since, until time.Time) error {
+	counts, noteCount, err := collectWordCounts(ctx, cfg, since, until)
+	if err != nil {
+		return err
+	}
+
+	terms := topTerms(counts, reportTop)
+
+	if reportJSON {
+		data, err := json.MarshalIndent(terms, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	fmt.Printf("Top terms: %s to %s (%d notes)\n\n", since.Format("2006-01-02"), until.Format("2006-01-02"), noteCount)
+
+	if len(terms) == 0 {
+		fmt.Println("No words found")
+		return nil
+	}
+
+	for i, term := range terms {
+		fmt.Printf("  %2d. %-20s %d\n", i+1, term.Term, term.Count)
+	}
+
+	return nil
+}
+
+// monthActivity is the number of daily notes written in one month.
+type monthActivity struct {
+	Month string `json:"month"`
+	Notes int    `json:"notes"`
+}
+
+// noteActivity is the number of daily notes written per month of a year.
+type noteActivity struct {
+	Year   int             `json:"year"`
+	Total  int             `json:"total"`
+	Months []monthActivity `json:"months"`
+}
+
+// collectNoteActivity counts the daily notes in the diary dated in year, by
+// the YYYY-MM-DD prefix of their file names.
+func collectNoteActivity(ctx context.Context, cfg *config.LoadedConfig, year int) (noteActivity, error) {
+	activity := noteActivity{Year: year, Months: make([]monthActivity, 12)}
+	for i := range activity.Months {
+		activity.Months[i].Month = fmt.Sprintf("%d-%02d", year, i+1)
+	}
+
+	if !utils.FileExists(cfg.DiaryPath) {
+		return activity, nil
+	}
+
+	allNotes, err := notes.FindNotes(ctx, cfg.DiaryPath)
+	if err != nil {
+		return activity, err
+	}
+
+	for _, notePath := range allNotes {
+		date, ok := notes.DailyNoteDate(notePath)
+		if !ok || date.Year() != year {
+			continue
+		}
+		activity.Months[date.Month()-1].Notes++
+		activity.Total++
+	}
+
+	return activity, nil
+}
+
+func showActivityReport(ctx context.Context, cfg *config.LoadedConfig, year int) error {
+	activity, err := collectNoteActivity(ctx, cfg, year)
+	if err != nil {
+		return err
+	}
+
+	if reportJSON {
+		data, err := json.MarshalIndent(activity, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	fmt.Printf("Daily notes in %d: %d\n\n", year, activity.Total)
+
+	for i, month := range activity.Months {
+		fmt.Printf("  %s  %3d  %s\n", time.Month(i + 1).String()[:3], month.Notes, strings.Repeat("█", month.Notes))
+	}
+
+	return nil
+}
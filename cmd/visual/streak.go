@@ -39,7 +39,11 @@ type streakResult struct {
 
 // calculateStreak computes the current and longest streak for daily notes.
 func calculateStreak(cfg *config.LoadedConfig) streakResult {
-	today := time.Now()
+	return calculateStreakFrom(cfg, time.Now())
+}
+
+// calculateStreakFrom computes the streaks counting back from today.
+func calculateStreakFrom(cfg *config.LoadedConfig, today time.Time) streakResult {
 	result := streakResult{}
 
 	firstValidDay := true
@@ -51,11 +55,8 @@ func calculateStreak(cfg *config.LoadedConfig) streakResult {
 		date := today.AddDate(0, 0, -i)
 
 		// Skip weekends if configured
-		if !cfg.Streaks.IncludeWeekends {
-			weekday := date.Weekday()
-			if weekday == time.Saturday || weekday == time.Sunday {
-				continue
-			}
+		if cfg.Streaks.SkipsDay(date) {
+			continue
 		}
 
 		notePath := notes.BuildDailyNotePath(cfg.DiaryPath, date)
@@ -133,11 +134,8 @@ func displayRecentActivity(cfg *config.LoadedConfig) {
 		date := today.AddDate(0, 0, -i)
 
 		// Skip weekends if configured
-		if !cfg.Streaks.IncludeWeekends {
-			weekday := date.Weekday()
-			if weekday == time.Saturday || weekday == time.Sunday {
-				continue
-			}
+		if cfg.Streaks.SkipsDay(date) {
+			continue
 		}
 
 		notePath := notes.BuildDailyNotePath(cfg.DiaryPath, date)
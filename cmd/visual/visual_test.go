@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"strings"
@@ -10,6 +11,8 @@ import (
 
 	"github.com/AnishShah1803/jotr/internal/config"
 	"github.com/AnishShah1803/jotr/internal/notes"
+	"github.com/AnishShah1803/jotr/internal/state"
+	"github.com/AnishShah1803/jotr/internal/testhelpers"
 )
 
 func createTestVisualConfig(t *testing.T, tmpDir string) *config.LoadedConfig {
@@ -307,3 +310,271 @@ func TestLaunchDashboard_MinimumSize(t *testing.T) {
 		}
 	}
 }
+
+func TestCollectWordCounts_TopTermsExcludeStopwords(t *testing.T) {
+	cfg := createTestVisualConfig(t, t.TempDir())
+	ctx := context.Background()
+
+	day1 := time.Date(2025, 3, 3, 0, 0, 0, 0, time.Local)
+	day2 := day1.AddDate(0, 0, 1)
+	outside := day1.AddDate(0, 0, 10)
+
+	note1 := "# Monday\n\n## Notes\n\nThe deploy went well and the deploy was fast.\n\n- [ ] Review deploy logs <!-- id: abcd1234 -->\n"
+	note2 := "## Notes\n\nRefactor the parser. See https://example.com/parser\n\n```\nignored ignored ignored ignored\n```\n\nDeploy again; parser fixed.\n"
+
+	for date, content := range map[time.Time]string{day1: note1, day2: note2, outside: "deploy deploy deploy deploy"} {
+		if err := notes.WriteNote(ctx, notes.BuildDailyNotePath(cfg.DiaryPath, date), content); err != nil {
+			t.Fatalf("Failed to write note: %v", err)
+		}
+	}
+
+	counts, noteCount, err := collectWordCounts(ctx, cfg, day1, day2)
+	if err != nil {
+		t.Fatalf("collectWordCounts() error = %v", err)
+	}
+
+	if noteCount != 2 {
+		t.Errorf("collectWordCounts() noteCount = %d; want 2", noteCount)
+	}
+
+	terms := topTerms(counts, 2)
+	want := []termCount{{Term: "deploy", Count: 4}, {Term: "parser", Count: 2}}
+	if len(terms) != len(want) {
+		t.Fatalf("topTerms() = %+v; want %+v", terms, want)
+	}
+	for i := range want {
+		if terms[i] != want[i] {
+			t.Errorf("topTerms()[%d] = %+v; want %+v", i, terms[i], want[i])
+		}
+	}
+
+	for _, excluded := range []string{"the", "and", "was", "ignored", "abcd", "https", "id"} {
+		if counts[excluded] > 0 {
+			t.Errorf("Expected %q to be excluded, got count %d", excluded, counts[excluded])
+		}
+	}
+}
+
+func TestShowActivityReport_CountsPerMonth(t *testing.T) {
+	cfg := createTestVisualConfig(t, t.TempDir())
+	ctx := context.Background()
+
+	dates := []time.Time{
+		time.Date(2025, 1, 5, 0, 0, 0, 0, time.Local),
+		time.Date(2025, 1, 6, 0, 0, 0, 0, time.Local),
+		time.Date(2025, 3, 1, 0, 0, 0, 0, time.Local),
+		time.Date(2025, 3, 2, 0, 0, 0, 0, time.Local),
+		time.Date(2025, 3, 31, 0, 0, 0, 0, time.Local),
+		time.Date(2024, 3, 1, 0, 0, 0, 0, time.Local),
+	}
+	for _, date := range dates {
+		if err := notes.WriteNote(ctx, notes.BuildDailyNotePath(cfg.DiaryPath, date), "# Note\n"); err != nil {
+			t.Fatalf("Failed to write note: %v", err)
+		}
+	}
+
+	reportJSON = true
+	defer func() { reportJSON = false }()
+
+	var err error
+	out := testhelpers.CaptureStdout(func() {
+		err = showActivityReport(ctx, cfg, 2025)
+	})
+	if err != nil {
+		t.Fatalf("showActivityReport() error = %v", err)
+	}
+
+	var activity noteActivity
+	if err := json.Unmarshal([]byte(out), &activity); err != nil {
+		t.Fatalf("output is not valid JSON: %v\n%s", err, out)
+	}
+
+	if activity.Total != 5 {
+		t.Errorf("Total = %d; want 5", activity.Total)
+	}
+	if len(activity.Months) != 12 {
+		t.Fatalf("got %d months; want 12", len(activity.Months))
+	}
+	want := map[string]int{"2025-01": 2, "2025-03": 3}
+	for _, month := range activity.Months {
+		if month.Notes != want[month.Month] {
+			t.Errorf("%s: %d notes; want %d", month.Month, month.Notes, want[month.Month])
+		}
+	}
+}
+
+func TestParseReportRange(t *testing.T) {
+	now := time.Date(2025, 3, 15, 12, 0, 0, 0, time.Local)
+
+	since, until, err := parseReportRange("", "", now)
+	if err != nil {
+		t.Fatalf("parseReportRange() error = %v", err)
+	}
+	if since.Format("2006-01-02") != "2025-03-01" || until.Format("2006-01-02") != "2025-03-15" {
+		t.Errorf("default range = %s..%s; want 2025-03-01..2025-03-15", since.Format("2006-01-02"), until.Format("2006-01-02"))
+	}
+
+	if _, _, err := parseReportRange("2025-03-10", "2025-03-01", now); err == nil {
+		t.Error("Expected error when --until is before --since")
+	}
+
+	if _, _, err := parseReportRange("03/01/2025", "", now); err == nil {
+		t.Error("Expected error for invalid date format")
+	}
+}
+
+func TestBuildCalendarMonth_MarksNotesStreaksAndTasks(t *testing.T) {
+	cfg := createTestVisualConfig(t, t.TempDir())
+	cfg.StatePath = filepath.Join(cfg.Paths.BaseDir, ".todo_state.json")
+	ctx := context.Background()
+
+	// January 2025: the 3rd is a Friday, the 6th a Monday.
+	for _, day := range []int{3, 6, 15} {
+		path := notes.BuildDailyNotePath(cfg.DiaryPath, time.Date(2025, 1, day, 0, 0, 0, 0, time.Local))
+		if err := notes.WriteNote(ctx, path, "# Note\n"); err != nil {
+			t.Fatalf("Failed to write note: %v", err)
+		}
+	}
+
+	todoState := state.NewTodoState()
+	todoState.Tasks["aaaa0001"] = state.TaskState{ID: "aaaa0001", Completed: true, CompletedDate: "2025-01-06"}
+	todoState.Tasks["aaaa0002"] = state.TaskState{ID: "aaaa0002", Completed: true, CompletedDate: "2025-01-06"}
+	todoState.Tasks["aaaa0003"] = state.TaskState{ID: "aaaa0003", Completed: false}
+	if err := todoState.Write(cfg.StatePath); err != nil {
+		t.Fatalf("Failed to write state: %v", err)
+	}
+
+	first, err := parseCalendarMonth("2025-01", time.Now())
+	if err != nil {
+		t.Fatalf("parseCalendarMonth() error = %v", err)
+	}
+
+	tests := []struct {
+		name            string
+		includeWeekends bool
+		wantStreak      map[int]bool
+	}{
+		{"weekends excluded bridge Friday to Monday", false, map[int]bool{3: true, 6: true}},
+		{"weekends included break the run", true, map[int]bool{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg.Streaks.IncludeWeekends = tt.includeWeekends
+			days := buildCalendarMonth(cfg, first, true)
+
+			if len(days) != 31 {
+				t.Fatalf("buildCalendarMonth() returned %d days; want 31", len(days))
+			}
+
+			for _, day := range days {
+				d := day.Date.Day()
+				wantNote := d == 3 || d == 6 || d == 15
+				if day.HasNote != wantNote {
+					t.Errorf("Jan %d HasNote = %v; want %v", d, day.HasNote, wantNote)
+				}
+				if day.InStreak != tt.wantStreak[d] {
+					t.Errorf("Jan %d InStreak = %v; want %v", d, day.InStreak, tt.wantStreak[d])
+				}
+			}
+
+			if days[5].Completed != 2 {
+				t.Errorf("Jan 6 Completed = %d; want 2", days[5].Completed)
+			}
+		})
+	}
+}
+
+func TestShowBoard_ColumnsByStatus(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := createTestVisualConfig(t, tmpDir)
+	cfg.StatePath = filepath.Join(tmpDir, ".todo_state.json")
+
+	todoState := state.NewTodoState()
+	for _, ts := range []state.TaskState{
+		{ID: "task-1", Text: "Write report", Section: "Work", Priority: "P2"},
+		{ID: "task-2", Text: "Fix login", Section: "Work", Priority: "P0"},
+		{ID: "task-3", Text: "Refactor sync", Section: "Work", Status: "in-progress"},
+		{ID: "task-4", Text: "Review PR @waiting", Section: "Work"},
+		{ID: "task-5", Text: "Deploy blocked-by: task-2", Section: "Work", Meta: map[string]string{"blocked-by": "task-2"}},
+		{ID: "task-6", Text: "Buy milk", Section: "Home", Completed: true},
+		{ID: "task-7", Text: "Old chore", Section: "Home", Completed: true, ArchivedDate: "2025-01-01"},
+		{ID: "task-8", Text: "Dropped idea", Section: "Home", Status: "cancelled"},
+	} {
+		todoState.Tasks[ts.ID] = ts
+	}
+	if err := todoState.Write(cfg.StatePath); err != nil {
+		t.Fatalf("Failed to write state: %v", err)
+	}
+
+	boardJSON = true
+	defer func() { boardJSON = false }()
+
+	var err error
+	out := testhelpers.CaptureStdout(func() {
+		err = showBoard(cfg)
+	})
+	if err != nil {
+		t.Fatalf("showBoard() error = %v", err)
+	}
+
+	var board struct {
+		Columns []boardColumn `json:"columns"`
+	}
+	if err := json.Unmarshal([]byte(out), &board); err != nil {
+		t.Fatalf("output is not valid JSON: %v\n%s", err, out)
+	}
+
+	want := map[string][]string{
+		"Pending":     {"task-2", "task-1"},
+		"In Progress": {"task-3"},
+		"Blocked":     {"task-4", "task-5"},
+		"Done":        {"task-6"},
+	}
+	if len(board.Columns) != len(want) {
+		t.Fatalf("got %d columns; want %d", len(board.Columns), len(want))
+	}
+	for _, column := range board.Columns {
+		var ids []string
+		for _, task := range column.Tasks {
+			ids = append(ids, task.ID)
+		}
+		if strings.Join(ids, ",") != strings.Join(want[column.Name], ",") {
+			t.Errorf("column %q = %v; want %v", column.Name, ids, want[column.Name])
+		}
+	}
+}
+
+func TestCalculateStreak_WeekendDays(t *testing.T) {
+	cfg := createTestVisualConfig(t, t.TempDir())
+	ctx := context.Background()
+
+	// Sunday Jan 12 2025, with notes Mon 6 through Thu 9 and no notes on
+	// Fri 10 or Sat 11.
+	today := time.Date(2025, 1, 12, 9, 0, 0, 0, time.Local)
+	for _, day := range []int{6, 7, 8, 9, 12} {
+		date := time.Date(2025, 1, day, 0, 0, 0, 0, time.Local)
+		if err := notes.WriteNote(ctx, notes.BuildDailyNotePath(cfg.DiaryPath, date), "# Note\n"); err != nil {
+			t.Fatalf("Failed to write note: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name        string
+		weekendDays []string
+		want        int
+	}{
+		{"default Saturday/Sunday weekend breaks on Friday", nil, 0},
+		{"Friday/Saturday weekend bridges the gap", []string{"Friday", "sat"}, 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg.Streaks.WeekendDays = tt.weekendDays
+			result := calculateStreakFrom(cfg, today)
+			if result.currentStreak != tt.want {
+				t.Errorf("currentStreak = %d; want %d", result.currentStreak, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/AnishShah1803/jotr/internal/config"
+)
+
+// ErrDisabled is returned when an AI feature is requested but AI is not enabled in config.
+var ErrDisabled = errors.New("AI features are disabled - set ai.enabled and ai.command in config")
+
+// summaryPrompt is prepended to text passed to CommandSummarizer.Summarize.
+const summaryPrompt = "Summarize the following notes concisely in markdown:\n\n"
+
+// Summarizer defines the interface for producing a summary of note text.
+// Commands depend on this abstraction so the AI backend can be mocked in tests.
+type Summarizer interface {
+	// Summarize returns a summary of the given text.
+	Summarize(ctx context.Context, text string) (string, error)
+}
+
+// Assistant defines the interface for sending a free-form prompt to the AI
+// backend, for features that need more than a summary.
+type Assistant interface {
+	// Ask returns the AI response to the given prompt.
+	Ask(ctx context.Context, prompt string) (string, error)
+}
+
+// CommandSummarizer implements Summarizer and Assistant by running the configured AI command
+// with the prompt on its standard input and returning the command's output. The prompt is
+// not passed as an argument, so it can't run into argument length limits or show up in
+// the process list.
+type CommandSummarizer struct {
+	Command string
+}
+
+// NewCommandSummarizer creates a new CommandSummarizer for the given command line.
+func NewCommandSummarizer(command string) *CommandSummarizer {
+	return &CommandSummarizer{Command: command}
+}
+
+// New returns the Summarizer configured by cfg, or ErrDisabled if AI is off.
+func New(cfg config.AIConfig) (Summarizer, error) {
+	summarizer, err := newFromConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return summarizer, nil
+}
+
+// NewAssistant returns the Assistant configured by cfg, or ErrDisabled if AI is off.
+func NewAssistant(cfg config.AIConfig) (Assistant, error) {
+	assistant, err := newFromConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return assistant, nil
+}
+
+func newFromConfig(cfg config.AIConfig) (*CommandSummarizer, error) {
+	if !cfg.Enabled {
+		return nil, ErrDisabled
+	}
+
+	if strings.TrimSpace(cfg.Command) == "" {
+		return nil, fmt.Errorf("AI is enabled but no command is configured")
+	}
+
+	return NewCommandSummarizer(cfg.Command), nil
+}
+
+// Summarize implements the Summarizer interface.
+func (s *CommandSummarizer) Summarize(ctx context.Context, text string) (string, error) {
+	return s.Ask(ctx, summaryPrompt+text)
+}
+
+// Ask implements the Assistant interface.
+func (s *CommandSummarizer) Ask(ctx context.Context, prompt string) (string, error) {
+	fields := strings.Fields(s.Command)
+	if len(fields) == 0 {
+		return "", fmt.Errorf("no AI command configured")
+	}
+
+	cmd := exec.CommandContext(ctx, fields[0], fields[1:]...)
+	cmd.Stdin = strings.NewReader(prompt)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("AI command failed: %w: %s", err, msg)
+		}
+		return "", fmt.Errorf("AI command failed: %w", err)
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
@@ -0,0 +1,87 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/AnishShah1803/jotr/internal/config"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     config.AIConfig
+		wantErr error
+		wantNil bool
+	}{
+		{
+			name:    "disabled",
+			cfg:     config.AIConfig{Enabled: false, Command: "echo"},
+			wantErr: ErrDisabled,
+			wantNil: true,
+		},
+		{
+			name:    "enabled without command",
+			cfg:     config.AIConfig{Enabled: true},
+			wantNil: true,
+		},
+		{
+			name: "enabled with command",
+			cfg:  config.AIConfig{Enabled: true, Command: "echo"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summarizer, err := New(tt.cfg)
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("New() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantNil && summarizer != nil {
+				t.Errorf("New() returned summarizer, want nil")
+			}
+			if !tt.wantNil && (err != nil || summarizer == nil) {
+				t.Errorf("New() = %v, %v; want summarizer", summarizer, err)
+			}
+		})
+	}
+}
+
+func TestCommandSummarizer_Summarize(t *testing.T) {
+	summarizer := NewCommandSummarizer("cat")
+
+	got, err := summarizer.Summarize(context.Background(), "note text")
+	if err != nil {
+		t.Fatalf("Summarize() error = %v", err)
+	}
+
+	if !strings.HasPrefix(got, strings.TrimSpace(summaryPrompt)) || !strings.HasSuffix(got, "note text") {
+		t.Errorf("Summarize() = %q, want prompt followed by note text", got)
+	}
+}
+
+func TestCommandSummarizer_AskUsesStdin(t *testing.T) {
+	// As an argument, this prompt would make cat print its version, and a
+	// long one could exceed the argument length limit.
+	assistant := NewCommandSummarizer("cat")
+	prompt := "--version\n" + strings.Repeat("x", 256*1024)
+
+	got, err := assistant.Ask(context.Background(), prompt)
+	if err != nil {
+		t.Fatalf("Ask() error = %v", err)
+	}
+
+	if got != prompt {
+		t.Errorf("Ask() returned %d bytes, want the %d-byte prompt read from stdin", len(got), len(prompt))
+	}
+}
+
+func TestCommandSummarizer_CommandFails(t *testing.T) {
+	summarizer := NewCommandSummarizer("false")
+
+	if _, err := summarizer.Summarize(context.Background(), "note text"); err == nil {
+		t.Error("Summarize() expected error for failing command")
+	}
+}
@@ -8,8 +8,10 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/AnishShah1803/jotr/internal/constants"
+	"github.com/AnishShah1803/jotr/internal/output"
 	"github.com/AnishShah1803/jotr/internal/utils"
 )
 
@@ -49,6 +51,23 @@ func ValidateConfig(cfg *Config) ([]ValidationWarning, error) {
 		return nil, fmt.Errorf("format validation failed: %w", err)
 	}
 
+	for _, name := range cfg.Streaks.WeekendDays {
+		if _, ok := parseWeekday(name); !ok {
+			return nil, fmt.Errorf("streaks.weekend_days: unknown weekday %q", name)
+		}
+	}
+
+	if cfg.Archive.AutoAfterDays < 0 {
+		return nil, fmt.Errorf("archive.auto_after_days must not be negative")
+	}
+	if cfg.Archive.PruneRemovedAfterDays < 0 {
+		return nil, fmt.Errorf("archive.prune_removed_after_days must not be negative")
+	}
+
+	if _, err := utils.ParseLogLevel(cfg.LogLevel); err != nil {
+		return nil, fmt.Errorf("log_level: %w", err)
+	}
+
 	// Validate AI settings if enabled
 	if cfg.AI.Enabled && cfg.AI.Command == "" {
 		return nil, fmt.Errorf("AI is enabled but no command is configured")
@@ -86,12 +105,41 @@ func GetConfigFromContext(ctx context.Context) (interface{}, bool) {
 	return cfg, ok
 }
 
+type baseDirContextKey struct{}
+
+var baseDirKey = &baseDirContextKey{}
+
+// WithBaseDir returns a context that makes LoadWithContext use dir as the
+// base directory instead of the configured paths.base_dir.
+func WithBaseDir(ctx context.Context, dir string) context.Context {
+	return context.WithValue(ctx, baseDirKey, dir)
+}
+
+type loadedConfigContextKey struct{}
+
+var loadedConfigKey = &loadedConfigContextKey{}
+
+// WithLoadedConfig returns a context that makes LoadWithContext return cfg
+// instead of loading the config file again.
+func WithLoadedConfig(ctx context.Context, cfg *LoadedConfig) context.Context {
+	return context.WithValue(ctx, loadedConfigKey, cfg)
+}
+
+// GetBaseDirFromContext returns the base directory override, if any.
+func GetBaseDirFromContext(ctx context.Context) (string, bool) {
+	dir, ok := ctx.Value(baseDirKey).(string)
+	return dir, ok && dir != ""
+}
+
 // PathsConfig holds path-related configuration settings.
 type PathsConfig struct {
 	BaseDir      string `json:"base_dir"`
 	DiaryDir     string `json:"diary_dir"`
 	TodoFilePath string `json:"todo_file_path"`
 	PDPFilePath  string `json:"pdp_file_path"`
+	// InboxFile is the capture file used by "jotr in", relative to base_dir
+	// and without .md. Unset means "inbox".
+	InboxFile string `json:"inbox_file,omitempty"`
 }
 
 // FormatConfig holds formatting-related configuration settings.
@@ -101,6 +149,155 @@ type FormatConfig struct {
 	DailyNotePattern    string   `json:"daily_note_pattern"`
 	DailyNoteDirPattern string   `json:"daily_note_dir_pattern"`
 	DailyNoteSections   []string `json:"daily_note_sections"`
+	TodoTitle           string   `json:"todo_title"`
+	TodoSectionLevel    int      `json:"todo_section_level"`
+	// TodoRelativeDates shows recent completion date sections in the todo
+	// file as "Today", "Yesterday" and "This Week".
+	TodoRelativeDates bool `json:"todo_relative_dates,omitempty"`
+	// CaptureTimestamp is a Go time layout for captured items. Unset means
+	// "15:04"; an empty string disables the timestamp.
+	CaptureTimestamp *string `json:"capture_timestamp,omitempty"`
+	// LogSection is the daily note section "jotr log" appends to. Unset
+	// means "Log".
+	LogSection string `json:"log_section,omitempty"`
+	// LogTimestamp is the Go time layout starting each "jotr log" entry.
+	// Unset means "15:04".
+	LogTimestamp string `json:"log_timestamp,omitempty"`
+	// AutoCreateSections controls whether adding to a missing section creates
+	// its heading. Unset means true.
+	AutoCreateSections *bool `json:"auto_create_sections,omitempty"`
+	// CheckboxMarkers maps extra checkbox markers (e.g. "-") to a task status
+	// ("cancelled" or "in-progress"). Unset uses the built-in markers.
+	CheckboxMarkers map[string]string `json:"checkbox_markers,omitempty"`
+	// TaskIDFormat is the marker embedding a task ID in task text, with {id}
+	// standing for the ID (e.g. "^{id}"). Unset uses "<!-- id: {id} -->".
+	TaskIDFormat string `json:"task_id_format,omitempty"`
+	// TaskIDLength is the number of hex characters in generated task IDs,
+	// from 8 to 16. Unset means 8. Shorter existing IDs keep working.
+	TaskIDLength int `json:"task_id_length,omitempty"`
+	// TagColors maps a tag name (without #) to an ANSI color name used to
+	// highlight it in terminal output, e.g. {"urgent": "red"}.
+	TagColors map[string]string `json:"tag_colors,omitempty"`
+	// ShowCompletedInTodo controls whether sync keeps completed tasks in the
+	// todo file until they are archived. Unset means true.
+	ShowCompletedInTodo *bool `json:"show_completed_in_todo,omitempty"`
+	// SectionPriorities maps a section name to the priority (P0-P3) given to
+	// its tasks that have no [Pn] marker, e.g. {"Urgent": "P1"}.
+	SectionPriorities map[string]string `json:"section_priorities,omitempty"`
+	// OnExistingDaily is what "jotr daily" does when the note already exists:
+	// "open", "append-sections" (add missing configured sections, then open)
+	// or "error". Unset means "open".
+	OnExistingDaily string `json:"on_existing_daily,omitempty"`
+	// FilenameReplacement replaces characters that are unsafe in file names
+	// (such as / : ? or emoji) when a note name becomes its file name. Unset
+	// means "-"; an empty string drops them.
+	FilenameReplacement *string `json:"filename_replacement,omitempty"`
+	// CompletedStyle is how completion dates are written to task lines:
+	// "inline-tag" (@completed(YYYY-MM-DD)), "comment"
+	// (<!-- completed: YYYY-MM-DD -->) or "none". Unset means "inline-tag".
+	CompletedStyle string `json:"completed_style,omitempty"`
+	// LinkDuplicateTasks makes sync treat a daily task without an ID as the
+	// active task with the same text, so a task copied between daily notes
+	// is tracked once.
+	LinkDuplicateTasks bool `json:"link_duplicate_tasks,omitempty"`
+	// PreserveTaskOrder keeps tasks in the order they were arranged in the
+	// todo file when it is rewritten, instead of regrouping them.
+	PreserveTaskOrder bool `json:"preserve_task_order,omitempty"`
+	// FocusCount is the number of tasks "jotr focus" shows. Unset means 3.
+	FocusCount int `json:"focus_count,omitempty"`
+}
+
+// DefaultFocusCount is the number of tasks "jotr focus" shows when
+// format.focus_count is not set.
+const DefaultFocusCount = 3
+
+// FocusCountOrDefault returns the number of tasks "jotr focus" shows.
+func (f FormatConfig) FocusCountOrDefault() int {
+	if f.FocusCount == 0 {
+		return DefaultFocusCount
+	}
+
+	return f.FocusCount
+}
+
+// DefaultFilenameReplacement is used for unsafe file name characters when
+// format.filename_replacement is not set.
+const DefaultFilenameReplacement = "-"
+
+// FilenameReplacementString returns the substitute for unsafe characters in
+// note file names.
+func (f FormatConfig) FilenameReplacementString() string {
+	if f.FilenameReplacement == nil {
+		return DefaultFilenameReplacement
+	}
+
+	return *f.FilenameReplacement
+}
+
+// Values for FormatConfig.OnExistingDaily.
+const (
+	OnExistingDailyOpen           = "open"
+	OnExistingDailyAppendSections = "append-sections"
+	OnExistingDailyError          = "error"
+)
+
+// OnExistingDailyMode returns the configured on_existing_daily behavior,
+// defaulting to OnExistingDailyOpen.
+func (f FormatConfig) OnExistingDailyMode() string {
+	if f.OnExistingDaily == "" {
+		return OnExistingDailyOpen
+	}
+
+	return f.OnExistingDaily
+}
+
+// DefaultCaptureTimestamp is the capture timestamp layout used when none is configured.
+const DefaultCaptureTimestamp = "15:04"
+
+// CaptureTimestampLayout returns the time layout for capture timestamps, or
+// an empty string if timestamps are disabled.
+func (f FormatConfig) CaptureTimestampLayout() string {
+	if f.CaptureTimestamp == nil {
+		return DefaultCaptureTimestamp
+	}
+
+	return *f.CaptureTimestamp
+}
+
+// Defaults for "jotr log" entries.
+const (
+	DefaultLogSection   = "Log"
+	DefaultLogTimestamp = "15:04"
+)
+
+// LogSectionName returns the section "jotr log" appends to.
+func (f FormatConfig) LogSectionName() string {
+	if f.LogSection == "" {
+		return DefaultLogSection
+	}
+
+	return f.LogSection
+}
+
+// LogTimestampLayout returns the time layout starting each log entry.
+func (f FormatConfig) LogTimestampLayout() string {
+	if f.LogTimestamp == "" {
+		return DefaultLogTimestamp
+	}
+
+	return f.LogTimestamp
+}
+
+// AutoCreateSectionsEnabled reports whether writers may create a missing
+// section heading on demand.
+func (f FormatConfig) AutoCreateSectionsEnabled() bool {
+	return f.AutoCreateSections == nil || *f.AutoCreateSections
+}
+
+// ShowCompletedInTodoEnabled reports whether sync writes completed tasks to
+// the todo file.
+func (f FormatConfig) ShowCompletedInTodoEnabled() bool {
+	return f.ShowCompletedInTodo == nil || *f.ShowCompletedInTodo
 }
 
 // AIConfig holds AI-related configuration settings.
@@ -112,6 +309,63 @@ type AIConfig struct {
 // StreaksConfig holds streak-related configuration settings.
 type StreaksConfig struct {
 	IncludeWeekends bool `json:"include_weekends"`
+	// WeekendDays names the weekdays skipped when IncludeWeekends is false,
+	// e.g. ["Friday", "Saturday"]. Empty means Saturday and Sunday.
+	WeekendDays []string `json:"weekend_days,omitempty"`
+}
+
+// defaultWeekendDays is used when no weekend days are configured.
+var defaultWeekendDays = []time.Weekday{time.Saturday, time.Sunday}
+
+// parseWeekday converts a weekday name such as "Friday" or "fri" to a
+// time.Weekday, ignoring case.
+func parseWeekday(name string) (time.Weekday, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if len(name) < 3 {
+		return 0, false
+	}
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.HasPrefix(strings.ToLower(day.String()), name) {
+			return day, true
+		}
+	}
+	return 0, false
+}
+
+// IsWeekend reports whether date falls on a configured weekend day.
+func (s StreaksConfig) IsWeekend(date time.Time) bool {
+	weekday := date.Weekday()
+	if len(s.WeekendDays) == 0 {
+		for _, day := range defaultWeekendDays {
+			if weekday == day {
+				return true
+			}
+		}
+		return false
+	}
+	for _, name := range s.WeekendDays {
+		if day, ok := parseWeekday(name); ok && day == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+// SkipsDay reports whether streaks skip date because it is a weekend day
+// and weekends are not included.
+func (s StreaksConfig) SkipsDay(date time.Time) bool {
+	return !s.IncludeWeekends && s.IsWeekend(date)
+}
+
+// ArchiveConfig holds task archive configuration settings.
+type ArchiveConfig struct {
+	// AutoAfterDays makes sync archive completed tasks finished more than
+	// this many days ago. Zero disables automatic archiving.
+	AutoAfterDays int `json:"auto_after_days,omitempty"`
+	// PruneRemovedAfterDays makes sync archive completed tasks that were
+	// removed from both the daily note and the todo file, once completed
+	// more than this many days ago. Zero keeps them indefinitely.
+	PruneRemovedAfterDays int `json:"prune_removed_after_days,omitempty"`
 }
 
 // DailyNoteTemplateConfig holds daily note template configuration.
@@ -129,6 +383,18 @@ type GitConfig struct {
 	AutoCommit          bool   `json:"auto_commit"`
 }
 
+// HooksConfig holds commands run after jotr operations. Each command is
+// split on whitespace and given the affected paths as arguments and in the
+// JOTR_PATH and JOTR_PATHS environment variables. A failing hook is reported
+// as a warning and never fails the operation.
+type HooksConfig struct {
+	// PostCreateNote runs after a note or daily note is created.
+	PostCreateNote string `json:"post_create_note,omitempty"`
+	// PostSync runs after a task sync writes its changes, with the todo
+	// file, state file and daily note paths.
+	PostSync string `json:"post_sync,omitempty"`
+}
+
 // SummaryConfig holds summary-related configuration settings.
 type SummaryConfig struct {
 	Sources                   []string `json:"sources"`
@@ -143,6 +409,9 @@ type FrontmatterConfig struct {
 
 const ConfigVersion = "1.0.0"
 
+// sectionPriorityRegex matches a valid section_priorities value.
+var sectionPriorityRegex = regexp.MustCompile(`(?i)^P[0-3]$`)
+
 type Config struct {
 	Version           string                 `json:"version"`
 	Frontmatter       FrontmatterConfig      `json:"frontmatter"`
@@ -155,6 +424,15 @@ type Config struct {
 	DailyNoteTemplate DailyNoteTemplateConfig `json:"daily_note_template"`
 	Summary           SummaryConfig           `json:"summary"`
 	Streaks           StreaksConfig           `json:"streaks"`
+	Archive           ArchiveConfig           `json:"archive"`
+	Hooks             HooksConfig             `json:"hooks"`
+	// LogLevel is the default log level: "quiet", "normal" or "verbose".
+	// The --quiet and --verbose flags override it.
+	LogLevel string `json:"log_level,omitempty"`
+	// Defaults holds default flag values per command, keyed by command path
+	// without "jotr" and then flag name, e.g. {"list": {"with_tasks": true},
+	// "template edit": {...}}. Flags passed on the command line override them.
+	Defaults map[string]map[string]any `json:"defaults,omitempty"`
 }
 
 // TemplateSection represents a section in a template.
@@ -203,6 +481,7 @@ type LoadedConfig struct {
 	TodoPath      string
 	StatePath     string
 	PDPPath       string
+	InboxPath     string
 	TemplatesPath string
 }
 
@@ -225,6 +504,11 @@ func LoadWithContext(ctx context.Context, configPathOverride string) (*LoadedCon
 	default:
 	}
 
+	if cfg, ok := ctx.Value(loadedConfigKey).(*LoadedConfig); ok && configPathOverride == "" {
+		utils.VerboseLogWithContext(ctx, "Using config already loaded for this command")
+		return cfg, nil
+	}
+
 	var configPath string
 	if configPathOverride != "" {
 		configPath = configPathOverride
@@ -275,6 +559,15 @@ func LoadWithContext(ctx context.Context, configPathOverride string) (*LoadedCon
 		return nil, fmt.Errorf("config migration failed: %w", err)
 	}
 
+	if baseDir, ok := GetBaseDirFromContext(ctx); ok {
+		absBaseDir, err := filepath.Abs(baseDir)
+		if err != nil {
+			return nil, fmt.Errorf("invalid base directory %s: %w", baseDir, err)
+		}
+		utils.VerboseLogWithContext(ctx, "Overriding base_dir with: %s", absBaseDir)
+		cfg.Paths.BaseDir = absBaseDir
+	}
+
 	// Validate required fields
 	if cfg.Paths.BaseDir == "" {
 		return nil, fmt.Errorf("base_dir is required in config")
@@ -286,18 +579,11 @@ func LoadWithContext(ctx context.Context, configPathOverride string) (*LoadedCon
 	// Diary directory
 	loaded.DiaryPath = filepath.Join(cfg.Paths.BaseDir, cfg.Paths.DiaryDir)
 
-	// Todo file path (without .md in config)
-	todoFilePath := cfg.Paths.TodoFilePath
-	if todoFilePath == "" {
-		todoFilePath = "todo"
-	}
-
-	todoFilePath = strings.TrimSuffix(todoFilePath, ".md")
-	loaded.TodoPath = filepath.Join(cfg.Paths.BaseDir, todoFilePath+".md")
+	loaded.TodoPath = ResolveTodoPath(&cfg)
 
 	// State file (auto-generated from todo file name, in same directory)
 	todoDir := filepath.Dir(loaded.TodoPath)
-	todoBasename := filepath.Base(strings.TrimSuffix(todoFilePath, ".md"))
+	todoBasename := strings.TrimSuffix(filepath.Base(loaded.TodoPath), ".md")
 	stateFile := fmt.Sprintf(".%s_state.json", todoBasename)
 	loaded.StatePath = filepath.Join(todoDir, stateFile)
 
@@ -307,6 +593,13 @@ func LoadWithContext(ctx context.Context, configPathOverride string) (*LoadedCon
 		loaded.PDPPath = filepath.Join(cfg.Paths.BaseDir, pdpFilePath+".md")
 	}
 
+	// Inbox file path
+	inboxFile := strings.TrimSuffix(cfg.Paths.InboxFile, ".md")
+	if inboxFile == "" {
+		inboxFile = "inbox"
+	}
+	loaded.InboxPath = filepath.Join(cfg.Paths.BaseDir, inboxFile+".md")
+
 	loaded.TemplatesPath = filepath.Join(cfg.Paths.BaseDir, "templates")
 
 	// Set default editor if not configured
@@ -316,6 +609,11 @@ func LoadWithContext(ctx context.Context, configPathOverride string) (*LoadedCon
 		return nil, fmt.Errorf("config validation failed: %w", err)
 	}
 
+	if cfg.LogLevel != "" {
+		level, _ := utils.ParseLogLevel(cfg.LogLevel)
+		utils.SetDefaultLogLevel(level)
+	}
+
 	return loaded, nil
 }
 
@@ -380,6 +678,75 @@ func validateFormat(format *FormatConfig, warnings []ValidationWarning) ([]Valid
 		}
 	}
 
+	if format.TodoSectionLevel != 0 && (format.TodoSectionLevel < 1 || format.TodoSectionLevel > 6) {
+		return nil, fmt.Errorf("todo_section_level must be between 1 and 6")
+	}
+
+	if layout := format.CaptureTimestampLayout(); layout != "" {
+		// A layout without any time elements renders as itself.
+		ref := time.Date(2001, 2, 3, 4, 5, 6, 0, time.UTC)
+		if ref.Format(layout) == layout {
+			return nil, fmt.Errorf("capture_timestamp %q is not a valid Go time layout (e.g. \"15:04\")", layout)
+		}
+	}
+
+	if layout := format.LogTimestampLayout(); time.Date(2001, 2, 3, 4, 5, 6, 0, time.UTC).Format(layout) == layout {
+		return nil, fmt.Errorf("log_timestamp %q is not a valid Go time layout (e.g. \"15:04\")", layout)
+	}
+
+	if format.TaskIDFormat != "" && strings.Count(format.TaskIDFormat, "{id}") != 1 {
+		return nil, fmt.Errorf("task_id_format must contain {id} exactly once")
+	}
+
+	if format.TaskIDLength != 0 && (format.TaskIDLength < 8 || format.TaskIDLength > 16) {
+		return nil, fmt.Errorf("task_id_length must be between 8 and 16")
+	}
+
+	for tag, color := range format.TagColors {
+		if _, ok := output.ANSIColors[strings.ToLower(color)]; !ok {
+			warnings = append(warnings, ValidationWarning{
+				Category: "format",
+				Message:  fmt.Sprintf("unknown color '%s' for tag '%s' in tag_colors (valid: black, red, green, yellow, blue, magenta, cyan, white)", color, tag),
+			})
+		}
+	}
+
+	for marker, status := range format.CheckboxMarkers {
+		if len([]rune(marker)) != 1 || marker == " " || marker == "x" || marker == "X" {
+			return nil, fmt.Errorf("checkbox_markers key %q must be a single character other than space, x or X", marker)
+		}
+		if status != "cancelled" && status != "in-progress" {
+			return nil, fmt.Errorf("checkbox_markers status %q must be \"cancelled\" or \"in-progress\"", status)
+		}
+	}
+
+	for section, priority := range format.SectionPriorities {
+		if !sectionPriorityRegex.MatchString(priority) {
+			return nil, fmt.Errorf("section_priorities value %q for section %q must be P0, P1, P2, or P3", priority, section)
+		}
+	}
+
+	if replacement := format.FilenameReplacementString(); strings.ContainsFunc(replacement, utils.IsUnsafeFilenameRune) {
+		return nil, fmt.Errorf("filename_replacement %q must not contain characters that are unsafe in file names", replacement)
+	}
+
+	switch format.CompletedStyle {
+	case "", "inline-tag", "comment", "none":
+	default:
+		return nil, fmt.Errorf("completed_style %q must be \"inline-tag\", \"comment\" or \"none\"", format.CompletedStyle)
+	}
+
+	if format.FocusCount < 0 {
+		return nil, fmt.Errorf("focus_count must not be negative")
+	}
+
+	switch format.OnExistingDailyMode() {
+	case OnExistingDailyOpen, OnExistingDailyAppendSections, OnExistingDailyError:
+	default:
+		return nil, fmt.Errorf("on_existing_daily %q must be %q, %q or %q", format.OnExistingDaily,
+			OnExistingDailyOpen, OnExistingDailyAppendSections, OnExistingDailyError)
+	}
+
 	return warnings, nil
 }
 
@@ -452,6 +819,38 @@ func validatePathAbsolutes(cfg *Config, warnings []ValidationWarning) []Validati
 	return warnings
 }
 
+// ResolveTodoPath returns the todo file path for paths.todo_file_path. The
+// setting may be absolute, start with "~/" for the home directory, or be
+// relative to base_dir; ".md" is added if it is missing. Unset means "todo".
+func ResolveTodoPath(cfg *Config) string {
+	todoFilePath := cfg.Paths.TodoFilePath
+	if todoFilePath == "" {
+		todoFilePath = "todo"
+	}
+
+	todoFilePath = strings.TrimSuffix(expandHome(todoFilePath), ".md") + ".md"
+	if filepath.IsAbs(todoFilePath) {
+		return filepath.Clean(todoFilePath)
+	}
+
+	return filepath.Join(cfg.Paths.BaseDir, todoFilePath)
+}
+
+// expandHome replaces a leading "~" in path with the user's home directory.
+// The path is returned unchanged if the home directory is unknown.
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") && !strings.HasPrefix(path, "~"+string(filepath.Separator)) {
+		return path
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+
+	return filepath.Join(homeDir, path[1:])
+}
+
 // Save saves the configuration to ~/.config/jotr/config.json.
 func Save(cfg *Config) error {
 	if cfg.Version != ConfigVersion {
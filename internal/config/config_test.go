@@ -1,6 +1,7 @@
 package config
 
 import (
+	"context"
 	"encoding/json"
 	"os"
 	"path/filepath"
@@ -77,6 +78,65 @@ func TestLoadConfig_Valid(t *testing.T) {
 	}
 }
 
+func TestLoadWithContext_BaseDirOverride(t *testing.T) {
+	tmpDir := t.TempDir()
+	overrideDir := filepath.Join(tmpDir, "other-notes")
+
+	config := Config{}
+	config.Paths.BaseDir = filepath.Join(tmpDir, "notes")
+	config.Paths.DiaryDir = "Diary"
+	config.Paths.TodoFilePath = "todo.md"
+	config.Format.DailyNotePattern = "{year}-{month}-{day}-{weekday}"
+	config.Format.DailyNoteDirPattern = "{year}/{month}"
+
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		t.Fatalf("Failed to marshal config: %v", err)
+	}
+
+	configFile := filepath.Join(tmpDir, "config.json")
+	if err := os.WriteFile(configFile, data, constants.FilePerm0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	ctx := WithBaseDir(context.Background(), overrideDir)
+
+	loadedConfig, err := LoadWithContext(ctx, configFile)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	if loadedConfig.Paths.BaseDir != overrideDir {
+		t.Errorf("Expected base_dir '%s', got '%s'", overrideDir, loadedConfig.Paths.BaseDir)
+	}
+
+	if want := filepath.Join(overrideDir, "Diary"); loadedConfig.DiaryPath != want {
+		t.Errorf("Expected diary path '%s', got '%s'", want, loadedConfig.DiaryPath)
+	}
+
+	if want := filepath.Join(overrideDir, "todo.md"); loadedConfig.TodoPath != want {
+		t.Errorf("Expected todo path '%s', got '%s'", want, loadedConfig.TodoPath)
+	}
+}
+
+func TestLoadWithContext_LoadedConfig(t *testing.T) {
+	loaded := &LoadedConfig{DiaryPath: "/notes/Diary"}
+	ctx := WithLoadedConfig(context.Background(), loaded)
+
+	got, err := LoadWithContext(ctx, "")
+	if err != nil {
+		t.Fatalf("LoadWithContext() error = %v", err)
+	}
+	if got != loaded {
+		t.Errorf("LoadWithContext() = %p; want the loaded config %p", got, loaded)
+	}
+
+	// An explicit path still loads that file.
+	if _, err := LoadWithContext(ctx, filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("LoadWithContext() with a missing config path error = nil")
+	}
+}
+
 func TestLoadConfig_InvalidJSON(t *testing.T) {
 	tmpDir, err := os.MkdirTemp("", "jotr-config-test-")
 	if err != nil {
@@ -373,6 +433,40 @@ func TestValidateConfig_InvalidPlaceholder(t *testing.T) {
 	}
 }
 
+func TestValidateConfig_CaptureTimestamp(t *testing.T) {
+	tests := []struct {
+		name    string
+		layout  *string
+		wantErr bool
+	}{
+		{"unset uses default", nil, false},
+		{"empty disables timestamp", strPtr(""), false},
+		{"custom layout", strPtr("3:04PM"), false},
+		{"no time elements", strPtr("hh:mm"), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{}
+			cfg.Paths.BaseDir = "/tmp/test-jotr"
+			cfg.Paths.DiaryDir = "Diary"
+			cfg.Paths.TodoFilePath = "todo.md"
+			cfg.Format.DailyNotePattern = "{year}-{month}-{day}-{weekday}"
+			cfg.Format.DailyNoteDirPattern = "{year}/{month}"
+			cfg.Format.CaptureTimestamp = tt.layout
+
+			_, err := ValidateConfig(cfg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
 func TestValidateConfig_InvalidEditor(t *testing.T) {
 	cfg := &Config{}
 	cfg.Paths.BaseDir = "/tmp/test-jotr"
@@ -602,3 +696,38 @@ func TestConfigVersion_Constant(t *testing.T) {
 		t.Errorf("Expected ConfigVersion to be '1.0.0', got: %s", ConfigVersion)
 	}
 }
+
+func TestResolveTodoPath(t *testing.T) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		t.Skipf("no home directory: %v", err)
+	}
+
+	baseDir := filepath.Join(t.TempDir(), "vault")
+	absTodo := filepath.Join(t.TempDir(), "lists", "todo.md")
+
+	tests := []struct {
+		name    string
+		setting string
+		want    string
+	}{
+		{"unset", "", filepath.Join(baseDir, "todo.md")},
+		{"bare name", "tasks", filepath.Join(baseDir, "tasks.md")},
+		{"relative with extension", "lists/work.md", filepath.Join(baseDir, "lists", "work.md")},
+		{"absolute", absTodo, absTodo},
+		{"absolute without extension", strings.TrimSuffix(absTodo, ".md"), absTodo},
+		{"home", "~/notes/todo", filepath.Join(homeDir, "notes", "todo.md")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{}
+			cfg.Paths.BaseDir = baseDir
+			cfg.Paths.TodoFilePath = tt.setting
+
+			if got := ResolveTodoPath(cfg); got != tt.want {
+				t.Errorf("ResolveTodoPath(%q) = %q, want %q", tt.setting, got, tt.want)
+			}
+		})
+	}
+}
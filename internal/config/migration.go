@@ -47,6 +47,14 @@ func setDefaultValues(cfg *Config) {
 		cfg.Format.CaptureSection = "Captured"
 	}
 
+	if cfg.Format.TodoTitle == "" {
+		cfg.Format.TodoTitle = "To-Do List"
+	}
+
+	if cfg.Format.TodoSectionLevel == 0 {
+		cfg.Format.TodoSectionLevel = 2
+	}
+
 	if len(cfg.Format.DailyNoteSections) == 0 {
 		cfg.Format.DailyNoteSections = []string{"Notes", "Meetings"}
 	}
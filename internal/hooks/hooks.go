@@ -0,0 +1,52 @@
+// Package hooks runs user-configured commands after jotr operations.
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Run executes command with paths appended as arguments. The command is split
+// on whitespace and is not run through a shell. The paths are also available
+// to the command as JOTR_PATH (the first path) and JOTR_PATHS (all paths,
+// one per line). dir, if set, is the working directory. An empty command
+// does nothing.
+func Run(ctx context.Context, command, dir string, paths ...string) error {
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return nil
+	}
+
+	args := append(fields[1:], paths...)
+	cmd := exec.CommandContext(ctx, fields[0], args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "JOTR_PATHS="+strings.Join(paths, "\n"))
+	if len(paths) > 0 {
+		cmd.Env = append(cmd.Env, "JOTR_PATH="+paths[0])
+	}
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("hook %q failed: %w: %s", fields[0], err, msg)
+		}
+		return fmt.Errorf("hook %q failed: %w", fields[0], err)
+	}
+
+	return nil
+}
+
+// RunOrWarn runs a hook like Run, writing a warning to w instead of returning
+// an error, so a failing hook never fails the operation that triggered it.
+func RunOrWarn(ctx context.Context, w io.Writer, command, dir string, paths ...string) {
+	if err := Run(ctx, command, dir, paths...); err != nil {
+		fmt.Fprintf(w, "warning: %v\n", err)
+	}
+}
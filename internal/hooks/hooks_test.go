@@ -0,0 +1,45 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun_PassesPaths(t *testing.T) {
+	dir := t.TempDir()
+	hookPath := filepath.Join(dir, "hook.sh")
+	script := "#!/bin/sh\nprintf '%s\\n' \"$@\" \"$JOTR_PATHS\" > out.txt\n"
+	if err := os.WriteFile(hookPath, []byte(script), 0755); err != nil {
+		t.Fatalf("Failed to write hook: %v", err)
+	}
+
+	if err := Run(context.Background(), hookPath+" --flag", dir, "a.md", "b.md"); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	got, err := os.ReadFile(filepath.Join(dir, "out.txt"))
+	if err != nil {
+		t.Fatalf("hook did not run in dir: %v", err)
+	}
+	if want := "--flag\na.md\nb.md\na.md\nb.md\n"; string(got) != want {
+		t.Errorf("hook output = %q, want %q", got, want)
+	}
+}
+
+func TestRunOrWarn(t *testing.T) {
+	var buf bytes.Buffer
+
+	RunOrWarn(context.Background(), &buf, "", "", "a.md")
+	if buf.Len() != 0 {
+		t.Errorf("RunOrWarn() with no command wrote %q", buf.String())
+	}
+
+	RunOrWarn(context.Background(), &buf, "false", "", "a.md")
+	if !strings.HasPrefix(buf.String(), "warning: hook \"false\" failed") {
+		t.Errorf("RunOrWarn() = %q, want a warning", buf.String())
+	}
+}
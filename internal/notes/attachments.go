@@ -0,0 +1,104 @@
+package notes
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/AnishShah1803/jotr/internal/constants"
+	"github.com/AnishShah1803/jotr/internal/utils"
+)
+
+// AttachmentsDir is the directory under the base directory that holds files
+// attached to notes.
+const AttachmentsDir = "attachments"
+
+// embedRegex matches ![[target]] and ![[target|alias]] embeds.
+var embedRegex = regexp.MustCompile(`!\[\[([^\]|]+)(?:\|[^\]]*)?\]\]`)
+
+// AttachmentName returns the content-addressed file name for an attachment:
+// the first 16 hex characters of its SHA-256 hash plus its lowercased
+// extension. Identical files always get the same name.
+func AttachmentName(content []byte, ext string) string {
+	hash := sha256.Sum256(content)
+	return fmt.Sprintf("%x", hash)[:16] + strings.ToLower(ext)
+}
+
+// StoreAttachment copies src into baseDir's attachments directory under its
+// content-addressed name and returns that name. Storing a file that is
+// already present is a no-op.
+func StoreAttachment(baseDir, src string) (string, error) {
+	content, err := os.ReadFile(src)
+	if err != nil {
+		return "", fmt.Errorf("failed to read attachment: %w", err)
+	}
+
+	name := AttachmentName(content, filepath.Ext(src))
+	dest := filepath.Join(baseDir, AttachmentsDir, name)
+
+	if utils.FileExists(dest) {
+		return name, nil
+	}
+
+	if err := EnsureDir(filepath.Dir(dest)); err != nil {
+		return "", fmt.Errorf("failed to create attachments directory: %w", err)
+	}
+
+	if err := utils.AtomicWriteFile(dest, content, constants.FilePerm0644); err != nil {
+		return "", fmt.Errorf("failed to copy attachment: %w", err)
+	}
+
+	return name, nil
+}
+
+// AttachmentEmbed returns the embed line for an attachment name.
+func AttachmentEmbed(name string) string {
+	return "![[" + name + "]]"
+}
+
+// EmbeddedAttachment is a ![[file]] embed of a non-note file.
+type EmbeddedAttachment struct {
+	Name string
+	Line int
+}
+
+// FindAttachmentEmbeds returns the embeds in content that reference a file
+// other than a markdown note, with 1-based line numbers.
+func FindAttachmentEmbeds(content string) []EmbeddedAttachment {
+	var embeds []EmbeddedAttachment
+
+	for i, line := range strings.Split(content, "\n") {
+		for _, match := range embedRegex.FindAllStringSubmatch(line, -1) {
+			name := strings.TrimSpace(match[1])
+			ext := filepath.Ext(name)
+			if ext == "" || strings.EqualFold(ext, ".md") {
+				continue
+			}
+			embeds = append(embeds, EmbeddedAttachment{Name: name, Line: i + 1})
+		}
+	}
+
+	return embeds
+}
+
+// AttachmentExists reports whether an embedded attachment can be found in
+// the attachments directory, relative to the embedding note, or relative to
+// the base directory.
+func AttachmentExists(baseDir, notePath, name string) bool {
+	candidates := []string{
+		filepath.Join(baseDir, AttachmentsDir, name),
+		filepath.Join(filepath.Dir(notePath), name),
+		filepath.Join(baseDir, name),
+	}
+
+	for _, candidate := range candidates {
+		if utils.FileExists(candidate) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -1,11 +1,14 @@
 package notes
 
 import (
+	"bufio"
 	"context"
 	"fmt"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"time"
 
@@ -29,19 +32,104 @@ func OpenInEditor(path string) error {
 }
 
 func OpenInEditorWithContext(ctx context.Context, path string) error {
+	return OpenFilesInEditor(ctx, []string{path})
+}
+
+// multiFileEditors are editors known to open several files given as
+// arguments. Other editors are run once per file.
+var multiFileEditors = map[string]bool{
+	"vi": true, "vim": true, "nvim": true, "gvim": true, "mvim": true,
+	"emacs": true, "emacsclient": true, "nano": true, "micro": true,
+	"hx": true, "kak": true, "code": true, "codium": true, "subl": true,
+	"zed": true, "mate": true, "gedit": true, "kate": true,
+}
+
+// OpenFilesInEditor opens files in the user's preferred editor, passing them
+// all in one invocation if the editor accepts several files and opening them
+// one after another otherwise.
+func OpenFilesInEditor(ctx context.Context, paths []string) error {
+	editor, err := resolveEditor(ctx)
+	if err != nil {
+		return err
+	}
+
+	batches := [][]string{paths}
+	if !multiFileEditors[editorName(editor)] {
+		batches = batches[:0]
+		for _, path := range paths {
+			batches = append(batches, []string{path})
+		}
+	}
+
+	for _, batch := range batches {
+		if err := runEditor(editor, batch); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// OpenInEditorAtLine opens a file in the user's preferred editor with the
+// cursor on the given line, for editors EditorLineArgs knows. Other editors
+// just open the file.
+func OpenInEditorAtLine(ctx context.Context, path string, line int) error {
+	editor, err := resolveEditor(ctx)
+	if err != nil {
+		return err
+	}
+
+	return runEditor(editor, EditorLineArgs(editor, path, line))
+}
+
+// EditorLineArgs returns the arguments that make editor open path at line,
+// such as "+42 path" for vim or "-g path:42" for VS Code. Unknown editors and
+// lines below 1 get just the path.
+func EditorLineArgs(editor, path string, line int) []string {
+	if line < 1 {
+		return []string{path}
+	}
+
+	switch editorName(editor) {
+	case "vi", "vim", "nvim", "gvim", "mvim", "emacs", "emacsclient", "nano", "micro", "kak", "gedit":
+		return []string{fmt.Sprintf("+%d", line), path}
+	case "code", "codium":
+		return []string{"-g", fmt.Sprintf("%s:%d", path, line)}
+	case "subl", "zed", "hx":
+		return []string{fmt.Sprintf("%s:%d", path, line)}
+	case "mate", "kate":
+		return []string{"-l", fmt.Sprint(line), path}
+	default:
+		return []string{path}
+	}
+}
+
+// editorName returns the editor's executable name, e.g. "vim" for
+// /usr/bin/vim or "code" for code.exe.
+func editorName(editor string) string {
+	return strings.TrimSuffix(filepath.Base(editor), ".exe")
+}
+
+// resolveEditor returns the configured editor after checking it can be run.
+func resolveEditor(ctx context.Context) (string, error) {
 	editor := config.GetEditorWithContext(ctx)
 
 	// Check if editor is configured
 	if editor == "" {
-		return fmt.Errorf("no editor configured - set EDITOR environment variable or configure editor.default")
+		return "", fmt.Errorf("no editor configured - set EDITOR environment variable or configure editor.default")
 	}
 
 	// Validate editor before execution
 	if err := utils.ValidateEditor(editor); err != nil {
-		return fmt.Errorf("invalid editor: %w", err)
+		return "", fmt.Errorf("invalid editor: %w", err)
 	}
 
-	cmd := exec.Command(editor, path)
+	return editor, nil
+}
+
+// runEditor runs the editor attached to the terminal and waits for it.
+func runEditor(editor string, args []string) error {
+	cmd := exec.Command(editor, args...)
 	cmd.Stdin = os.Stdin
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
@@ -128,7 +216,88 @@ func WriteNote(ctx context.Context, path string, content string) error {
 	return os.WriteFile(path, []byte(content), constants.FilePerm0644)
 }
 
-// FindNotes finds all markdown files in a directory recursively with context support.
+type maxDepthContextKey struct{}
+
+var maxDepthKey = &maxDepthContextKey{}
+
+// WithMaxDepth returns a context that limits FindNotes to notes at most depth
+// levels below the directory it walks: 1 means only notes directly in it.
+// A depth of 0 or less means unlimited.
+func WithMaxDepth(ctx context.Context, depth int) context.Context {
+	return context.WithValue(ctx, maxDepthKey, depth)
+}
+
+// MaxDepthFromContext returns the FindNotes depth limit, or 0 for unlimited.
+func MaxDepthFromContext(ctx context.Context) int {
+	if depth, ok := ctx.Value(maxDepthKey).(int); ok && depth > 0 {
+		return depth
+	}
+	return 0
+}
+
+type pathGlobContextKey struct{}
+
+var pathGlobKey = &pathGlobContextKey{}
+
+// WithPathGlob returns a context that limits FindNotes to notes whose path,
+// relative to the directory it walks, matches pattern (see MatchPathGlob).
+// An empty pattern matches every note.
+func WithPathGlob(ctx context.Context, pattern string) context.Context {
+	return context.WithValue(ctx, pathGlobKey, pattern)
+}
+
+// PathGlobFromContext returns the FindNotes path glob, or "" for none.
+func PathGlobFromContext(ctx context.Context) string {
+	pattern, _ := ctx.Value(pathGlobKey).(string)
+	return pattern
+}
+
+// ValidatePathGlob reports whether pattern is a well-formed path glob.
+func ValidatePathGlob(pattern string) error {
+	for _, part := range strings.Split(filepath.ToSlash(pattern), "/") {
+		if _, err := path.Match(part, ""); err != nil {
+			return fmt.Errorf("invalid path glob %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// MatchPathGlob reports whether the slash-separated relative path rel matches
+// pattern. Each path segment is matched with path.Match, except that a "**"
+// segment matches any number of segments, including none, so "work/**"
+// matches every note under work/.
+func MatchPathGlob(pattern, rel string) bool {
+	return matchGlobParts(strings.Split(filepath.ToSlash(pattern), "/"), strings.Split(filepath.ToSlash(rel), "/"))
+}
+
+func matchGlobParts(pattern, parts []string) bool {
+	if len(pattern) == 0 {
+		return len(parts) == 0
+	}
+
+	if pattern[0] == "**" {
+		for i := 0; i <= len(parts); i++ {
+			if matchGlobParts(pattern[1:], parts[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(parts) == 0 {
+		return false
+	}
+	if ok, err := path.Match(pattern[0], parts[0]); err != nil || !ok {
+		return false
+	}
+
+	return matchGlobParts(pattern[1:], parts[1:])
+}
+
+// FindNotes finds all markdown files in a directory recursively with context
+// support. Directories deeper than the context's WithMaxDepth limit are not
+// walked, and notes not matching the context's WithPathGlob pattern are left
+// out.
 func FindNotes(ctx context.Context, dir string) ([]string, error) {
 	select {
 	case <-ctx.Done():
@@ -136,6 +305,9 @@ func FindNotes(ctx context.Context, dir string) ([]string, error) {
 	default:
 	}
 
+	maxDepth := MaxDepthFromContext(ctx)
+	pathGlob := PathGlobFromContext(ctx)
+
 	var notes []string
 
 	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
@@ -150,7 +322,19 @@ func FindNotes(ctx context.Context, dir string) ([]string, error) {
 		default:
 		}
 
+		// A directory n levels below dir holds notes at depth n+1.
+		if maxDepth > 0 && info.IsDir() && path != dir {
+			if rel, err := filepath.Rel(dir, path); err == nil && len(strings.Split(rel, string(filepath.Separator))) >= maxDepth {
+				return filepath.SkipDir
+			}
+		}
+
 		if !info.IsDir() && strings.HasSuffix(path, ".md") {
+			if pathGlob != "" {
+				if rel, err := filepath.Rel(dir, path); err != nil || !MatchPathGlob(pathGlob, rel) {
+					return nil
+				}
+			}
 			notes = append(notes, path)
 		}
 
@@ -160,22 +344,54 @@ func FindNotes(ctx context.Context, dir string) ([]string, error) {
 	return notes, err
 }
 
+// SearchResult is a single case-insensitive match of a search query.
+// LineNumber is 1-based; MatchStart and MatchEnd are byte offsets of the
+// match within Line.
+type SearchResult struct {
+	Path       string
+	LineNumber int
+	Line       string
+	MatchStart int
+	MatchEnd   int
+}
+
 // SearchNotes searches for notes containing a query with context support.
+// It returns each matching note path once, in the order found.
 func SearchNotes(ctx context.Context, dir string, query string) ([]string, error) {
+	results, err := SearchNotesDetailed(ctx, dir, query)
+
+	var matches []string
+	for _, result := range results {
+		if len(matches) == 0 || matches[len(matches)-1] != result.Path {
+			matches = append(matches, result.Path)
+		}
+	}
+
+	return matches, err
+}
+
+// SearchNotesDetailed searches notes for a query with context support and
+// returns every match with its line number and offsets. Matching is
+// case-insensitive; an empty query matches nothing.
+func SearchNotesDetailed(ctx context.Context, dir string, query string) ([]SearchResult, error) {
+	if query == "" {
+		return nil, nil
+	}
+
 	allNotes, err := FindNotes(ctx, dir)
 	if err != nil {
 		return nil, err
 	}
 
-	var matches []string
+	queryRe := regexp.MustCompile("(?i)" + regexp.QuoteMeta(query))
 
-	query = strings.ToLower(query)
+	var results []SearchResult
 
 	for _, notePath := range allNotes {
 		// Check context before reading each file
 		select {
 		case <-ctx.Done():
-			return matches, ctx.Err()
+			return results, ctx.Err()
 		default:
 		}
 
@@ -184,7 +400,72 @@ func SearchNotes(ctx context.Context, dir string, query string) ([]string, error
 			continue
 		}
 
-		if strings.Contains(strings.ToLower(string(content)), query) {
+		for i, line := range strings.Split(string(content), "\n") {
+			for _, loc := range queryRe.FindAllStringIndex(line, -1) {
+				results = append(results, SearchResult{
+					Path:       notePath,
+					LineNumber: i + 1,
+					Line:       line,
+					MatchStart: loc[0],
+					MatchEnd:   loc[1],
+				})
+			}
+		}
+	}
+
+	return results, nil
+}
+
+// NoteTitle returns the text of the first "# " heading in a note, reading
+// only up to that line. It returns "" if the note has none.
+func NoteTitle(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); strings.HasPrefix(line, "# ") {
+			return strings.TrimSpace(strings.TrimPrefix(line, "# ")), nil
+		}
+	}
+
+	return "", scanner.Err()
+}
+
+// SearchNoteTitles returns the notes whose title (first "# " heading) or
+// file name contains query, case-insensitively, in the order found.
+func SearchNoteTitles(ctx context.Context, dir string, query string) ([]string, error) {
+	if query == "" {
+		return nil, nil
+	}
+
+	allNotes, err := FindNotes(ctx, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	query = strings.ToLower(query)
+
+	var matches []string
+	for _, notePath := range allNotes {
+		if err := ctx.Err(); err != nil {
+			return matches, err
+		}
+
+		name := strings.TrimSuffix(filepath.Base(notePath), ".md")
+		if strings.Contains(strings.ToLower(name), query) {
+			matches = append(matches, notePath)
+			continue
+		}
+
+		title, err := NoteTitle(notePath)
+		if err != nil {
+			continue
+		}
+		if strings.Contains(strings.ToLower(title), query) {
 			matches = append(matches, notePath)
 		}
 	}
@@ -230,6 +511,40 @@ func CreateDailyNote(ctx context.Context, notePath string, sections []string, da
 	return os.WriteFile(notePath, []byte(content), constants.FilePerm0644)
 }
 
+// AppendMissingSections adds an empty "## section" heading to the end of the
+// note for each of sections it does not already have, and returns the
+// sections that were added.
+func AppendMissingSections(ctx context.Context, notePath string, sections []string) ([]string, error) {
+	data, err := os.ReadFile(notePath)
+	if err != nil {
+		return nil, err
+	}
+
+	content := string(data)
+	lines := strings.Split(content, "\n")
+
+	var added []string
+	for _, section := range sections {
+		if utils.FindSectionEnd(lines, section) == -1 {
+			added = append(added, section)
+		}
+	}
+	if len(added) == 0 {
+		return nil, nil
+	}
+
+	content = strings.TrimRight(content, "\n") + "\n\n"
+	for _, section := range added {
+		content += fmt.Sprintf("## %s\n\n", section)
+	}
+
+	if err := WriteNote(ctx, notePath, content); err != nil {
+		return nil, err
+	}
+
+	return added, nil
+}
+
 // BuildDailyNoteSections prepares the complete sections list for a daily note,
 // including daily_note_sections from config and ensuring a Task section exists.
 func BuildDailyNoteSections(cfg *config.LoadedConfig) []string {
@@ -288,6 +603,49 @@ func GetRecentDailyNotes(ctx context.Context, diaryDir string, count int) ([]str
 	return notes, nil
 }
 
+// DailyNoteDate returns the date of a daily note from the YYYY-MM-DD prefix
+// of its file name.
+func DailyNoteDate(notePath string) (time.Time, bool) {
+	name := filepath.Base(notePath)
+	if len(name) < len("2006-01-02") {
+		return time.Time{}, false
+	}
+
+	date, err := time.ParseInLocation("2006-01-02", name[:len("2006-01-02")], time.Local)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return date, true
+}
+
+// FindDailyNoteOnOrBefore scans diaryDir for the most recent daily note dated
+// on or before date. Notes are dated by the YYYY-MM-DD prefix of their file
+// name. Returns an empty path if no such note exists.
+func FindDailyNoteOnOrBefore(ctx context.Context, diaryDir string, date time.Time) (string, error) {
+	allNotes, err := FindNotes(ctx, diaryDir)
+	if err != nil {
+		return "", err
+	}
+
+	cutoff := date.Format("2006-01-02")
+
+	var best, bestDate string
+	for _, notePath := range allNotes {
+		parsed, ok := DailyNoteDate(notePath)
+		if !ok {
+			continue
+		}
+
+		noteDate := parsed.Format("2006-01-02")
+		if noteDate <= cutoff && noteDate > bestDate {
+			best, bestDate = notePath, noteDate
+		}
+	}
+
+	return best, nil
+}
+
 // UpdateLinks updates wiki-style links in all notes with context support.
 func UpdateLinks(ctx context.Context) error {
 	select {
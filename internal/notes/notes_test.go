@@ -4,6 +4,7 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 	"time"
 
@@ -176,3 +177,127 @@ func findSubstring(s, substr string) int {
 
 	return -1
 }
+
+func TestSearchNotesDetailed(t *testing.T) {
+	fs := testhelpers.NewTestFS(t)
+	defer fs.Cleanup()
+
+	fs.WriteFile(t, "meeting.md", "# Meeting\n\nDiscuss Budget and budget review\n")
+	fs.WriteFile(t, "other.md", "# Other\n\nNothing relevant\n")
+
+	results, err := SearchNotesDetailed(context.Background(), fs.BaseDir, "budget")
+	if err != nil {
+		t.Fatalf("SearchNotesDetailed() error = %v", err)
+	}
+
+	meetingPath := filepath.Join(fs.BaseDir, "meeting.md")
+	line := "Discuss Budget and budget review"
+	want := []SearchResult{
+		{Path: meetingPath, LineNumber: 3, Line: line, MatchStart: 8, MatchEnd: 14},
+		{Path: meetingPath, LineNumber: 3, Line: line, MatchStart: 19, MatchEnd: 25},
+	}
+
+	if !reflect.DeepEqual(results, want) {
+		t.Errorf("SearchNotesDetailed() = %+v; want %+v", results, want)
+	}
+
+	paths, err := SearchNotes(context.Background(), fs.BaseDir, "budget")
+	if err != nil {
+		t.Fatalf("SearchNotes() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(paths, []string{meetingPath}) {
+		t.Errorf("SearchNotes() = %v; want [%s]", paths, meetingPath)
+	}
+}
+
+func TestFindNotes_MaxDepth(t *testing.T) {
+	fs := testhelpers.NewTestFS(t)
+	defer fs.Cleanup()
+
+	for _, path := range []string{
+		"top.md",
+		filepath.Join("projects", "plan.md"),
+		filepath.Join("archive", "2020", "old.md"),
+		filepath.Join("archive", "2020", "q1", "older.md"),
+	} {
+		fs.WriteFile(t, path, "# Note\n")
+	}
+
+	tests := []struct {
+		depth int
+		want  []string
+	}{
+		{0, []string{"archive/2020/old.md", "archive/2020/q1/older.md", "projects/plan.md", "top.md"}},
+		{1, []string{"top.md"}},
+		{2, []string{"projects/plan.md", "top.md"}},
+		{3, []string{"archive/2020/old.md", "projects/plan.md", "top.md"}},
+	}
+
+	for _, tt := range tests {
+		found, err := FindNotes(WithMaxDepth(context.Background(), tt.depth), fs.BaseDir)
+		if err != nil {
+			t.Fatalf("FindNotes(depth %d) error = %v", tt.depth, err)
+		}
+
+		var got []string
+		for _, path := range found {
+			rel, _ := filepath.Rel(fs.BaseDir, path)
+			got = append(got, filepath.ToSlash(rel))
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("FindNotes(depth %d) = %v, want %v", tt.depth, got, tt.want)
+		}
+	}
+}
+
+func TestMatchPathGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		rel     string
+		want    bool
+	}{
+		{"work/**", "work/plan.md", true},
+		{"work/**", "work/q3/plan.md", true},
+		{"work/**", "personal/plan.md", false},
+		{"work/*.md", "work/plan.md", true},
+		{"work/*.md", "work/q3/plan.md", false},
+		{"**/meetings/*.md", "work/meetings/standup.md", true},
+		{"**/meetings/*.md", "meetings/standup.md", true},
+		{"*.md", "top.md", true},
+		{"*.md", "work/plan.md", false},
+	}
+
+	for _, tt := range tests {
+		if got := MatchPathGlob(tt.pattern, tt.rel); got != tt.want {
+			t.Errorf("MatchPathGlob(%q, %q) = %v, want %v", tt.pattern, tt.rel, got, tt.want)
+		}
+	}
+
+	if err := ValidatePathGlob("work/[a"); err == nil {
+		t.Error("ValidatePathGlob() should reject an unterminated character class")
+	}
+}
+
+func TestEditorLineArgs(t *testing.T) {
+	tests := []struct {
+		editor string
+		line   int
+		want   []string
+	}{
+		{"vim", 42, []string{"+42", "note.md"}},
+		{"/usr/local/bin/nvim", 7, []string{"+7", "note.md"}},
+		{"code", 42, []string{"-g", "note.md:42"}},
+		{"/opt/bin/codium", 3, []string{"-g", "note.md:3"}},
+		{"hx", 5, []string{"note.md:5"}},
+		{"kate", 9, []string{"-l", "9", "note.md"}},
+		{"ed", 42, []string{"note.md"}},
+		{"vim", 0, []string{"note.md"}},
+	}
+
+	for _, tt := range tests {
+		if got := EditorLineArgs(tt.editor, "note.md", tt.line); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("EditorLineArgs(%q, %d) = %q, want %q", tt.editor, tt.line, got, tt.want)
+		}
+	}
+}
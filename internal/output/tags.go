@@ -0,0 +1,76 @@
+package output
+
+import (
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+)
+
+// ANSIColors maps the color names accepted in format.tag_colors to ANSI
+// foreground color codes.
+var ANSIColors = map[string]string{
+	"black":   "30",
+	"red":     "31",
+	"green":   "32",
+	"yellow":  "33",
+	"blue":    "34",
+	"magenta": "35",
+	"cyan":    "36",
+	"white":   "37",
+}
+
+func detectStdoutTerminal() bool {
+	fd := os.Stdout.Fd()
+	return isatty.IsTerminal(fd) || isatty.IsCygwinTerminal(fd)
+}
+
+// stdoutIsTerminal reports whether stdout is a terminal. Tests override it
+// through SetStdoutTerminalForTest.
+var stdoutIsTerminal = detectStdoutTerminal
+
+// SetStdoutTerminalForTest makes StdoutColorEnabled treat stdout as a
+// terminal (or not) regardless of where it points.
+func SetStdoutTerminalForTest(terminal bool) {
+	stdoutIsTerminal = func() bool { return terminal }
+}
+
+// ResetStdoutTerminalForTest restores terminal detection after
+// SetStdoutTerminalForTest.
+func ResetStdoutTerminalForTest() {
+	stdoutIsTerminal = detectStdoutTerminal
+}
+
+// StdoutColorEnabled reports whether output written to stdout may be
+// colorized: stdout must be a terminal and NO_COLOR must be unset.
+func StdoutColorEnabled() bool {
+	return os.Getenv("NO_COLOR") == "" && stdoutIsTerminal()
+}
+
+var tagRegex = regexp.MustCompile(`#([a-zA-Z0-9_-]+)`)
+
+// ColorizeTags wraps each #tag in text that has a configured color in ANSI
+// color codes. Tag names are matched case-insensitively; tags without a
+// color, or with an unknown color name, are left as is. Nothing is changed
+// unless enabled is true.
+func ColorizeTags(text string, colors map[string]string, enabled bool) string {
+	if !enabled || len(colors) == 0 {
+		return text
+	}
+
+	codes := make(map[string]string, len(colors))
+	for tag, color := range colors {
+		if code, ok := ANSIColors[strings.ToLower(color)]; ok {
+			codes[strings.ToLower(strings.TrimPrefix(tag, "#"))] = code
+		}
+	}
+
+	return tagRegex.ReplaceAllStringFunc(text, func(match string) string {
+		code, ok := codes[strings.ToLower(match[1:])]
+		if !ok {
+			return match
+		}
+		return "\x1b[" + code + "m" + match + "\x1b[0m"
+	})
+}
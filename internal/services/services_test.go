@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/AnishShah1803/jotr/internal/config"
+	"github.com/AnishShah1803/jotr/internal/notes"
 	"github.com/AnishShah1803/jotr/internal/state"
 	"github.com/AnishShah1803/jotr/internal/tasks"
 	"github.com/AnishShah1803/jotr/internal/testhelpers"
@@ -45,7 +46,7 @@ func TestTaskService_GetAllTasks(t *testing.T) {
 	service := NewTaskService()
 	ctx := context.Background()
 
-	tasks, err := service.GetAllTasks(ctx, todoPath)
+	tasks, err := service.GetAllTasks(ctx, todoPath, TodoFormat{})
 	if err != nil {
 		t.Fatalf("GetAllTasks() error = %v", err)
 	}
@@ -81,7 +82,7 @@ func TestTaskService_GetTaskStats(t *testing.T) {
 	service := NewTaskService()
 	ctx := context.Background()
 
-	stats, err := service.GetTaskStats(ctx, todoPath)
+	stats, err := service.GetTaskStats(ctx, todoPath, TodoFormat{})
 	if err != nil {
 		t.Fatalf("GetTaskStats() error = %v", err)
 	}
@@ -99,6 +100,42 @@ func TestTaskService_GetTaskStats(t *testing.T) {
 	}
 }
 
+func TestTaskService_GetSectionTaskStats(t *testing.T) {
+	fs := testhelpers.NewTestFS(t)
+	defer fs.Cleanup()
+
+	fs.WriteFile(t, "todo.md", `# To-Do List
+
+## Urgent
+
+- [ ] Fix outage due: 2020-01-01
+- [x] Page on-call
+- [x] Roll back deploy
+
+## Someday
+
+- [ ] Learn Rust due: 2020-01-01
+- [ ] Tidy garage
+`)
+
+	service := NewTaskService()
+	stats, err := service.GetSectionTaskStats(context.Background(), filepath.Join(fs.BaseDir, "todo.md"), "Urgent", TodoFormat{})
+	if err != nil {
+		t.Fatalf("GetSectionTaskStats() error = %v", err)
+	}
+
+	if stats.Total != 3 || stats.Completed != 2 || stats.Pending != 1 || stats.Overdue != 1 {
+		t.Errorf("stats = total %d, completed %d, pending %d, overdue %d; want 3, 2, 1, 1",
+			stats.Total, stats.Completed, stats.Pending, stats.Overdue)
+	}
+	if want := float64(2) / float64(3) * 100; stats.CompletionRate != want {
+		t.Errorf("CompletionRate = %.2f; want %.2f", stats.CompletionRate, want)
+	}
+	if _, ok := stats.BySection["Someday"]; ok || len(stats.BySection) != 1 {
+		t.Errorf("BySection should only hold Urgent, got %v", stats.BySection)
+	}
+}
+
 func TestTaskService_SyncTasks_NoTasksToSync(t *testing.T) {
 	fs := testhelpers.NewTestFS(t)
 	defer fs.Cleanup()
@@ -225,6 +262,133 @@ func TestTaskService_ArchiveTasks_WithCompleted(t *testing.T) {
 	fs.AssertFileExists(t, filepath.Join("Archive", expectedArchive))
 }
 
+func TestTaskService_ArchiveTasks_Cancelled(t *testing.T) {
+	fs := testhelpers.NewTestFS(t)
+	defer fs.Cleanup()
+
+	now := time.Date(2025, 3, 10, 9, 0, 0, 0, time.Local)
+	statePath := filepath.Join(fs.BaseDir, ".todo_state.json")
+	todoPath := filepath.Join(fs.BaseDir, "todo.md")
+
+	todoState := state.NewTodoState()
+	todoState.AddTask(tasks.Task{ID: "aaaa1111", Text: "Open task @waiting @sam", Section: "Tasks"}, "todo.md")
+	todoState.AddTask(tasks.Task{ID: "bbbb2222", Text: "Dropped task @waiting @sam", Section: "Tasks", Status: tasks.StatusCancelled}, "todo.md")
+	for id, task := range todoState.Tasks {
+		task.LastModified = now.AddDate(0, 0, -30)
+		todoState.Tasks[id] = task
+	}
+	if err := todoState.Write(statePath); err != nil {
+		t.Fatalf("Failed to write state: %v", err)
+	}
+
+	service := NewTaskServiceWithClock(func() time.Time { return now })
+
+	stale, err := service.FindStaleTasks(statePath, 7, now)
+	if err != nil {
+		t.Fatalf("FindStaleTasks() error = %v", err)
+	}
+	if len(stale) != 1 || stale[0].ID != "aaaa1111" {
+		t.Errorf("FindStaleTasks() = %+v, want only the open task", stale)
+	}
+
+	waiting, err := service.FindWaitingTasks(statePath)
+	if err != nil {
+		t.Fatalf("FindWaitingTasks() error = %v", err)
+	}
+	if len(waiting) != 1 || len(waiting[0].Tasks) != 1 || waiting[0].Tasks[0].ID != "aaaa1111" {
+		t.Errorf("FindWaitingTasks() = %+v, want only the open task", waiting)
+	}
+
+	result, err := service.ArchiveTasks(context.Background(), ArchiveOptions{
+		TodoPath:  todoPath,
+		StatePath: statePath,
+		BaseDir:   fs.BaseDir,
+	})
+	if err != nil {
+		t.Fatalf("ArchiveTasks() error = %v", err)
+	}
+	if result.ArchivedCount != 1 || result.RemainingCount != 1 {
+		t.Errorf("ArchiveTasks() archived %d, remaining %d; want 1 and 1", result.ArchivedCount, result.RemainingCount)
+	}
+
+	archive := fs.ReadFile(t, filepath.Join("Archive", "archive-2025-03.md"))
+	if !strings.Contains(archive, "- [-] Dropped task") {
+		t.Errorf("archive should keep the cancelled marker:\n%s", archive)
+	}
+	if todo := fs.ReadFile(t, "todo.md"); strings.Contains(todo, "Dropped task") || !strings.Contains(todo, "Open task") {
+		t.Errorf("todo file should keep only the open task:\n%s", todo)
+	}
+}
+
+func TestTaskService_ArchiveTasks_FromNote(t *testing.T) {
+	fs := testhelpers.NewTestFS(t)
+	defer fs.Cleanup()
+
+	now := time.Date(2025, 3, 10, 9, 0, 0, 0, time.Local)
+	statePath := filepath.Join(fs.BaseDir, ".todo_state.json")
+	todoPath := filepath.Join(fs.BaseDir, "todo.md")
+	todoContent := "# To-Do List\n\n## Tasks\n\n- [x] Synced task <!-- id: aaaa1111 -->\n"
+	fs.WriteFile(t, "todo.md", todoContent)
+
+	// One completed task is already in state, still open there.
+	todoState := state.NewTodoState()
+	todoState.AddTask(tasks.Task{ID: "aaaa1111", Text: "Synced task", Section: "Tasks"}, "todo.md")
+	if err := todoState.Write(statePath); err != nil {
+		t.Fatalf("Failed to write state: %v", err)
+	}
+
+	fs.WriteFile(t, "daily.md", `# 2025-03-10
+
+## Tasks
+
+- [x] Synced task <!-- id: aaaa1111 -->
+- [ ] Still open <!-- id: bbbb2222 -->
+- [x] Never synced <!-- id: cccc3333 -->
+`)
+	notePath := filepath.Join(fs.BaseDir, "daily.md")
+
+	result, err := NewTaskServiceWithClock(func() time.Time { return now }).ArchiveTasks(context.Background(), ArchiveOptions{
+		TodoPath:  todoPath,
+		StatePath: statePath,
+		BaseDir:   fs.BaseDir,
+		NotePath:  notePath,
+	})
+	if err != nil {
+		t.Fatalf("ArchiveTasks() error = %v", err)
+	}
+	if result.ArchivedCount != 2 || result.RemainingCount != 1 {
+		t.Errorf("ArchiveTasks() archived %d, remaining %d; want 2 and 1", result.ArchivedCount, result.RemainingCount)
+	}
+
+	archive := fs.ReadFile(t, filepath.Join("Archive", "archive-2025-03.md"))
+	for _, want := range []string{"- [x] Synced task", "- [x] Never synced"} {
+		if !strings.Contains(archive, want) {
+			t.Errorf("archive missing %q:\n%s", want, archive)
+		}
+	}
+
+	if got, want := fs.ReadFile(t, "daily.md"), "# 2025-03-10\n\n## Tasks\n\n- [ ] Still open <!-- id: bbbb2222 -->\n"; got != want {
+		t.Errorf("daily note = %q, want %q", got, want)
+	}
+	if got := fs.ReadFile(t, "todo.md"); got != todoContent {
+		t.Errorf("todo file changed:\n%s", got)
+	}
+
+	saved, err := state.Read(statePath)
+	if err != nil {
+		t.Fatalf("state.Read() error = %v", err)
+	}
+	for _, id := range []string{"aaaa1111", "cccc3333"} {
+		task, ok := saved.Tasks[id]
+		if !ok || !task.Completed || task.ArchivedDate != "2025-03-10" {
+			t.Errorf("state task %s = %+v, want completed and archived on 2025-03-10", id, task)
+		}
+	}
+	if _, ok := saved.Tasks["bbbb2222"]; ok {
+		t.Error("open task bbbb2222 should not be added to state")
+	}
+}
+
 func TestTaskService_GetTaskSummary(t *testing.T) {
 	fs := testhelpers.NewTestFS(t)
 	defer fs.Cleanup()
@@ -249,7 +413,7 @@ func TestTaskService_GetTaskSummary(t *testing.T) {
 	service := NewTaskService()
 	ctx := context.Background()
 
-	summary, err := service.GetTaskSummary(ctx, todoPath)
+	summary, err := service.GetTaskSummary(ctx, todoPath, TodoFormat{})
 	if err != nil {
 		t.Fatalf("GetTaskSummary() error = %v", err)
 	}
@@ -474,458 +638,552 @@ func TestTaskService_SyncTasks_Deduplication_MultipleSimilar(t *testing.T) {
 	}
 }
 
-func TestTaskService_LoadConfig(t *testing.T) {
+func TestTaskService_DedupeTasks_ExactVsNearDuplicate(t *testing.T) {
 	fs := testhelpers.NewTestFS(t)
 	defer fs.Cleanup()
 
-	configHelper := testhelpers.NewConfigHelper(fs)
-	configHelper.CreateBasicConfig(t)
+	todoContent := `# To-Do List
 
-	configPath := filepath.Join(fs.BaseDir, ".config", "jotr", "config.json")
-	os.Setenv("JOTR_CONFIG", configPath)
+## Tasks
 
-	config, err := config.Load()
+- [ ] Update
+- [ ] Update config
+- [ ] Update
+- [ ] Other task
+`
+	fs.WriteFile(t, "todo.md", todoContent)
+
+	service := NewTaskService()
+	result, err := service.DedupeTasks(context.Background(), DedupeOptions{
+		TodoPath:  filepath.Join(fs.BaseDir, "todo.md"),
+		StatePath: filepath.Join(fs.BaseDir, ".todo_state.json"),
+	})
 	if err != nil {
-		t.Fatalf("LoadConfig() error = %v", err)
+		t.Fatalf("DedupeTasks() error = %v", err)
 	}
 
-	if config == nil {
-		t.Error("LoadConfig() returned nil")
+	if result.Removed != 1 {
+		t.Errorf("DedupeTasks().Removed = %d; want 1", result.Removed)
 	}
+
+	want := `# To-Do List
+
+## Tasks
+
+- [ ] Update
+- [ ] Update config
+- [ ] Other task
+`
+	fs.AssertFileEquals(t, "todo.md", want)
 }
 
-func TestTaskService_SyncTasks_ConcurrentFileLocking(t *testing.T) {
+// TestTaskService_SavesStateMigrationUnderLock tests that a state file
+// written by an older version is saved at the current version once a command
+// locks it, even if the command changes nothing.
+func TestTaskService_SavesStateMigrationUnderLock(t *testing.T) {
 	fs := testhelpers.NewTestFS(t)
 	defer fs.Cleanup()
 
-	configHelper := testhelpers.NewConfigHelper(fs)
-	configHelper.CreateBasicConfig(t)
+	fs.WriteFile(t, "todo.md", "## Tasks\n\n- [ ] Only task\n")
+	fs.WriteFile(t, ".todo_state.json", `{"tasks": {"abcd1234": {"text": "Old task", "section": "Tasks"}}}`)
 
-	configPath := filepath.Join(fs.BaseDir, ".config", "jotr", "config.json")
-	os.Setenv("JOTR_CONFIG", configPath)
+	service := NewTaskService()
+	if _, err := service.DedupeTasks(context.Background(), DedupeOptions{
+		TodoPath:  filepath.Join(fs.BaseDir, "todo.md"),
+		StatePath: filepath.Join(fs.BaseDir, ".todo_state.json"),
+	}); err != nil {
+		t.Fatalf("DedupeTasks() error = %v", err)
+	}
 
-	now := time.Now()
-	year := now.Format("2006")
-	monthDir := now.Format("01-Jan")
-	dayFile := now.Format("2006-01-02-Mon.md")
+	saved, err := os.ReadFile(filepath.Join(fs.BaseDir, ".todo_state.json"))
+	if err != nil {
+		t.Fatalf("Failed to read state file: %v", err)
+	}
+	if want := fmt.Sprintf(`"version": %d`, state.CurrentVersion); !strings.Contains(string(saved), want) {
+		t.Errorf("state file does not contain %s:\n%s", want, saved)
+	}
+}
 
-	dailyNoteContent := `# Daily Note
+func TestTaskService_FindOverdueTasks_AcrossDailyNotes(t *testing.T) {
+	fs := testhelpers.NewTestFS(t)
+	defer fs.Cleanup()
 
-## Tasks
+	past := time.Now().AddDate(0, 0, -10).Format("2006-01-02")
+	older := time.Now().AddDate(0, 0, -20).Format("2006-01-02")
+	future := time.Now().AddDate(0, 0, 10).Format("2006-01-02")
 
-- [ ] Task one <!-- id: task1 -->
-- [ ] Task two <!-- id: task2 -->
-`
-	fs.WriteFile(t, filepath.Join("diary", year, monthDir, dayFile), dailyNoteContent)
+	fs.WriteFile(t, "Diary/2025/01-Jan/2025-01-01-Wed.md", "## Tasks\n\n- [ ] Pay rent due:"+past+"\n- [x] Done already due:"+older+"\n")
+	fs.WriteFile(t, "Diary/2025/01-Jan/2025-01-02-Thu.md", "## Tasks\n\n- [ ] File taxes due:"+older+"\n- [ ] Plan trip due:"+future+"\n")
+	fs.WriteFile(t, "todo.md", "## Tasks\n\n- [ ] No due date\n")
 
-	statePath := filepath.Join(fs.BaseDir, ".todo_state.json")
-	todoPath := filepath.Join(fs.BaseDir, "todo.md")
+	service := NewTaskService()
+	overdue, err := service.FindOverdueTasks(context.Background(), OverdueOptions{
+		DiaryPath: filepath.Join(fs.BaseDir, "Diary"),
+		TodoPath:  filepath.Join(fs.BaseDir, "todo.md"),
+	})
+	if err != nil {
+		t.Fatalf("FindOverdueTasks() error = %v", err)
+	}
 
-	dailyNotePath := filepath.Join(fs.BaseDir, "diary", year, monthDir, dayFile)
+	if len(overdue) != 2 {
+		t.Fatalf("FindOverdueTasks() returned %d tasks; want 2: %+v", len(overdue), overdue)
+	}
 
-	initialState := state.NewTodoState()
-	initialState.Tasks = map[string]state.TaskState{
-		"task1": {ID: "task1", Text: "Task one", Completed: false, Source: dailyNotePath},
-		"task2": {ID: "task2", Text: "Task two", Completed: false, Source: dailyNotePath},
+	wantOrder := []struct{ text, file string }{
+		{"File taxes due:" + older, "2025-01-02-Thu.md"},
+		{"Pay rent due:" + past, "2025-01-01-Wed.md"},
 	}
-	if err := initialState.Write(statePath); err != nil {
-		t.Fatalf("Failed to write initial state: %v", err)
+	for i, want := range wantOrder {
+		if overdue[i].Task.Text != want.text {
+			t.Errorf("overdue[%d].Task.Text = %q; want %q", i, overdue[i].Task.Text, want.text)
+		}
+		if filepath.Base(overdue[i].Source) != want.file {
+			t.Errorf("overdue[%d].Source = %q; want file %q", i, overdue[i].Source, want.file)
+		}
 	}
+}
 
-	todoContent := `# To-Do List
-
-## Tasks
+func TestTaskService_SetTaskPriorities(t *testing.T) {
+	fs := testhelpers.NewTestFS(t)
+	defer fs.Cleanup()
 
-- [ ] Task one <!-- id: task1 -->
-- [ ] Task two <!-- id: task2 -->
-`
-	fs.WriteFile(t, "todo.md", todoContent)
+	// The IDs are taken before the file changes, as triage does before
+	// asking the assistant; the task added above them shifts every line.
+	priorities := map[string]string{
+		tasks.GenerateTaskID("Fix login bug"): "P1",
+		"abc12345":                            "P0",
+		tasks.GenerateTaskID("Clean desk"):    "P3",
+	}
+	fs.WriteFile(t, "todo.md", "## Tasks\n\n- [ ] Call bank\n- [ ] Fix login bug\n- [ ] [P2] Write docs <!-- id: abc12345 -->\n- [ ] Clean desk\n")
 
 	service := NewTaskService()
-	ctx := context.Background()
-
-	numGoroutines := 5
-	errors := make(chan error, numGoroutines)
-	done := make(chan bool, numGoroutines)
-	start := make(chan struct{})
-
-	for i := 0; i < numGoroutines; i++ {
-		go func(idx int) {
-			defer func() { done <- true }()
-
-			<-start
+	updated, err := service.SetTaskPriorities(context.Background(), PriorityOptions{
+		Priorities: priorities,
+		TodoPath:   filepath.Join(fs.BaseDir, "todo.md"),
+		StatePath:  filepath.Join(fs.BaseDir, ".todo_state.json"),
+	})
+	if err != nil {
+		t.Fatalf("SetTaskPriorities() error = %v", err)
+	}
 
-			_, err := service.SyncTasks(ctx, SyncOptions{
-				DiaryPath:   filepath.Join(fs.BaseDir, "diary"),
-				TodoPath:    todoPath,
-				StatePath:   statePath,
-				TaskSection: "Tasks",
-			})
-			errors <- err
-		}(i)
+	if updated != 3 {
+		t.Errorf("SetTaskPriorities() = %d; want 3", updated)
 	}
 
-	close(start)
+	fs.AssertFileEquals(t, "todo.md", "## Tasks\n\n- [ ] Call bank\n- [ ] [P1] Fix login bug\n- [ ] [P0] Write docs <!-- id: abc12345 -->\n- [ ] [P3] Clean desk\n")
+}
 
-	timeout := time.After(30 * time.Second)
-	completed := 0
+// TestTaskService_GetSectionTaskStats_SectionLevel tests that stats for a
+// section read a todo file written with level-3 section headings.
+func TestTaskService_GetSectionTaskStats_SectionLevel(t *testing.T) {
+	fs := testhelpers.NewTestFS(t)
+	defer fs.Cleanup()
 
-	for completed < numGoroutines {
-		select {
-		case <-done:
-			completed++
-		case err := <-errors:
-			if err != nil {
-				t.Errorf("Error in goroutine: %v", err)
-			}
-		case <-timeout:
-			t.Fatalf("Test timed out after 30 seconds - possible deadlock (%d/%d completed)", completed, numGoroutines)
-		}
-	}
+	fs.WriteFile(t, "todo.md", "# To-Do List\n\n### Work\n\n- [ ] Write report\n- [x] Send invoice\n\n### Home\n\n- [ ] Tidy garage\n")
 
-	finalState, err := state.Read(statePath)
+	service := NewTaskService()
+	stats, err := service.GetSectionTaskStats(context.Background(), filepath.Join(fs.BaseDir, "todo.md"), "Work", TodoFormat{SectionLevel: 3})
 	if err != nil {
-		t.Fatalf("Failed to read final state: %v", err)
+		t.Fatalf("GetSectionTaskStats() error = %v", err)
 	}
 
-	if len(finalState.Tasks) == 0 {
-		t.Error("Final state has no tasks - data loss detected")
+	if stats.Total != 2 || stats.Completed != 1 || stats.Pending != 1 {
+		t.Errorf("stats = total %d, completed %d, pending %d; want 2, 1, 1", stats.Total, stats.Completed, stats.Pending)
 	}
+}
 
-	for id, task := range finalState.Tasks {
-		if task.Text == "" {
-			t.Errorf("Task %s has empty text (possible corruption)", id)
-		}
-		if task.ID == "" {
-			t.Errorf("Task has empty ID (possible corruption): %v", task)
-		}
+func TestWriteTodoFileFromState_CustomTitleAndLevel(t *testing.T) {
+	fs := testhelpers.NewTestFS(t)
+	defer fs.Cleanup()
+
+	todoState := state.NewTodoState()
+	todoState.Tasks["abcd1234"] = state.TaskState{
+		ID:      "abcd1234",
+		Text:    "Write report",
+		Section: "Work",
+	}
+
+	service := NewTaskService()
+	format := TodoFormat{Title: "Team Backlog", SectionLevel: 3}
+	if err := service.writeTodoFileFromState(filepath.Join(fs.BaseDir, "todo.md"), todoState, true, format); err != nil {
+		t.Fatalf("writeTodoFileFromState() error = %v", err)
 	}
+
+	fs.AssertFileEquals(t, "todo.md", "# Team Backlog\n\n### Work\n\n- [ ] Write report <!-- id: abcd1234 -->\n\n")
 }
 
-func TestTaskService_SyncTasks_LockTimeoutError(t *testing.T) {
+func TestTaskService_SyncTasks_PreservesTodoProse(t *testing.T) {
 	fs := testhelpers.NewTestFS(t)
 	defer fs.Cleanup()
 
-	configHelper := testhelpers.NewConfigHelper(fs)
-	configHelper.CreateBasicConfig(t)
+	now := time.Now()
+	dailyNoteContent := "# Daily Note\n\n## Tasks\n\n- [ ] New task from daily\n"
+	fs.WriteFile(t, filepath.Join("diary", now.Format("2006"), now.Format("01-Jan"), now.Format("2006-01-02-Mon.md")), dailyNoteContent)
 
-	configPath := filepath.Join(fs.BaseDir, ".config", "jotr", "config.json")
-	os.Setenv("JOTR_CONFIG", configPath)
+	todoContent := `# To-Do List
 
-	statePath := filepath.Join(fs.BaseDir, ".todo_state.json")
+Remember: anything tagged #urgent goes first.
+Review this list every Friday.
 
-	lockFile, err := utils.TryLockFile(statePath)
+## Tasks
+
+Quarterly goals live in goals.md.
+
+- [ ] Existing task <!-- id: abcd1234 -->
+`
+	fs.WriteFile(t, "todo.md", todoContent)
+
+	service := NewTaskService()
+	result, err := service.SyncTasks(context.Background(), SyncOptions{
+		DiaryPath:   filepath.Join(fs.BaseDir, "diary"),
+		TodoPath:    filepath.Join(fs.BaseDir, "todo.md"),
+		StatePath:   filepath.Join(fs.BaseDir, ".todo_state.json"),
+		TaskSection: "Tasks",
+	})
 	if err != nil {
-		t.Fatalf("Failed to acquire lock: %v", err)
+		t.Fatalf("SyncTasks() error = %v", err)
 	}
-	if lockFile == nil {
-		t.Fatal("TryLockFile returned nil file")
+	if result.TasksFromDaily != 1 {
+		t.Fatalf("SyncTasks().TasksFromDaily = %d; want 1 so the todo file is rewritten", result.TasksFromDaily)
 	}
-	defer utils.UnlockFile(lockFile)
-
-	service := NewTaskService()
 
-	timeoutErr := fmt.Errorf("%w: %s", utils.ErrLockTimeout, statePath)
-	if !service.isLockTimeoutError(timeoutErr) {
-		t.Error("isLockTimeoutError should return true for ErrLockTimeout wrapped error")
+	content, err := os.ReadFile(filepath.Join(fs.BaseDir, "todo.md"))
+	if err != nil {
+		t.Fatalf("Failed to read todo file: %v", err)
 	}
 
-	otherErr := fmt.Errorf("some other error")
-	if service.isLockTimeoutError(otherErr) {
-		t.Error("isLockTimeoutError should return false for non-timeout error")
+	want := "# To-Do List\n\nRemember: anything tagged #urgent goes first.\nReview this list every Friday.\n\n## Tasks\n\nQuarterly goals live in goals.md.\n\n"
+	if !strings.HasPrefix(string(content), want) {
+		t.Errorf("Todo prose not preserved, got:\n%s", content)
 	}
 
-	if service.isLockTimeoutError(nil) {
-		t.Error("isLockTimeoutError should return false for nil error")
+	for _, task := range []string{"Existing task", "New task from daily"} {
+		if !strings.Contains(string(content), task) {
+			t.Errorf("Expected %q in rewritten todo file:\n%s", task, content)
+		}
 	}
 }
 
-func TestTaskService_SyncTasks_UserFriendlyErrorMessage(t *testing.T) {
-	service := NewTaskService()
+func TestTaskService_SyncTasks_WarnsOnMissingTaskSection(t *testing.T) {
+	fs := testhelpers.NewTestFS(t)
+	defer fs.Cleanup()
 
-	timeoutErr := fmt.Errorf("%w: some/path", utils.ErrLockTimeout)
-	if !service.isLockTimeoutError(timeoutErr) {
-		t.Error("isLockTimeoutError should return true for ErrLockTimeout wrapped error")
+	now := time.Now()
+	notePath := filepath.Join("diary", now.Format("2006"), now.Format("01-Jan"), now.Format("2006-01-02-Mon.md"))
+	noteContent := "# Daily Note\n\n## Notes\n\n- [ ] Task outside the task section\n"
+	fs.WriteFile(t, notePath, noteContent)
+	fs.WriteFile(t, "todo.md", "# To-Do List\n\n## Tasks\n")
+
+	service := NewTaskService()
+	result, err := service.SyncTasks(context.Background(), SyncOptions{
+		DiaryPath:   filepath.Join(fs.BaseDir, "diary"),
+		TodoPath:    filepath.Join(fs.BaseDir, "todo.md"),
+		StatePath:   filepath.Join(fs.BaseDir, ".todo_state.json"),
+		TaskSection: "Tasks",
+	})
+	if err != nil {
+		t.Fatalf("SyncTasks() error = %v", err)
 	}
 
-	wrappedErr := fmt.Errorf("failed to acquire lock on state file: %w", timeoutErr)
-	if !service.isLockTimeoutError(wrappedErr) {
-		t.Error("isLockTimeoutError should detect nested ErrLockTimeout")
+	if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], `"## Tasks"`) {
+		t.Errorf("SyncTasks().Warnings = %v; want a warning about the missing task section", result.Warnings)
 	}
+
+	fs.AssertFileEquals(t, notePath, noteContent)
 }
 
-func TestTaskService_WriteTodoFileFromState_CompletedDateSection(t *testing.T) {
+func TestTaskService_UpdateDailyNoteFromState_MissingSection(t *testing.T) {
 	fs := testhelpers.NewTestFS(t)
 	defer fs.Cleanup()
 
-	// Create a state with a task that:
-	// - was created under section "2026-02-01"
-	// - is completed with CompletedDate="2026-02-06"
-	// - should appear under "## 2026-02-06" section in output, not "## 2026-02-01"
+	noteContent := "# Daily Note\n\n## Notes\n\nJust notes today.\n"
+	fs.WriteFile(t, "note.md", noteContent)
+
 	todoState := state.NewTodoState()
-	todoState.Tasks["task123"] = state.TaskState{
-		ID:            "task123",
-		Text:          "Review project proposal",
-		Section:       "2026-02-01",
-		Completed:     true,
-		CompletedDate: "2026-02-06",
-		CreatedDate:   "2026-02-01",
-		Source:        "diary/2026-02-01.md",
-	}
+	todoState.Tasks["abcd1234"] = state.TaskState{ID: "abcd1234", Text: "Write report", Completed: true}
 
-	// Also add an incomplete task under the same section to verify it stays there
-	todoState.Tasks["task456"] = state.TaskState{
-		ID:          "task456",
-		Text:        "Ongoing task",
-		Section:     "2026-02-01",
-		Completed:   false,
-		CreatedDate: "2026-02-01",
-		Source:      "diary/2026-02-01.md",
+	service := NewTaskService()
+	dailyTasks := []tasks.Task{{ID: "abcd1234", Text: "Write report", Section: "Tasks"}}
+
+	_, err := service.renderDailyNoteFromState(filepath.Join(fs.BaseDir, "note.md"), dailyTasks, todoState, "Tasks", tasks.Options{})
+	if !errors.Is(err, errTaskSectionMissing) {
+		t.Errorf("renderDailyNoteFromState() error = %v; want errTaskSectionMissing", err)
 	}
 
-	todoPath := filepath.Join(fs.BaseDir, "todo.md")
+	fs.AssertFileEquals(t, "note.md", noteContent)
+}
 
-	service := NewTaskService()
-	if err := service.writeTodoFileFromState(todoPath, todoState, true); err != nil {
-		t.Fatalf("writeTodoFileFromState() error = %v", err)
+func TestTaskService_AddTask(t *testing.T) {
+	fs := testhelpers.NewTestFS(t)
+	defer fs.Cleanup()
+
+	fs.WriteFile(t, "todo.md", "# To-Do List\n\n## Tasks\n\n- [ ] Existing task <!-- id: abcd1234 -->\n")
+
+	opts := AddTaskOptions{
+		TodoPath:  filepath.Join(fs.BaseDir, "todo.md"),
+		StatePath: filepath.Join(fs.BaseDir, ".todo_state.json"),
+		Text:      "Buy milk #shopping due:2030-01-01",
+		Section:   "Errands",
+		Priority:  "P2",
 	}
 
-	// Read the generated file
-	content, err := os.ReadFile(todoPath)
+	service := NewTaskService()
+	task, err := service.AddTask(context.Background(), opts)
 	if err != nil {
-		t.Fatalf("Failed to read generated todo file: %v", err)
+		t.Fatalf("AddTask() error = %v", err)
 	}
 
-	contentStr := string(content)
+	wantText := "[P2] Buy milk #shopping due: 2030-01-01"
+	if task.Text != wantText || task.Section != "Errands" || task.Priority != "P2" {
+		t.Errorf("AddTask() = %+v; want text %q in Errands with P2", task, wantText)
+	}
+	if len(task.Tags) != 1 || task.Tags[0] != "shopping" {
+		t.Errorf("AddTask().Tags = %v; want [shopping]", task.Tags)
+	}
+	if task.ID != tasks.GenerateTaskID(wantText) {
+		t.Errorf("AddTask().ID = %q; want stable ID %q", task.ID, tasks.GenerateTaskID(wantText))
+	}
 
-	// Verify the completed task appears under "## 2026-02-06" section
-	// Find the section boundaries
-	idx0206 := strings.Index(contentStr, "## 2026-02-06")
-	idx0201 := strings.Index(contentStr, "## 2026-02-01")
+	content, err := os.ReadFile(opts.TodoPath)
+	if err != nil {
+		t.Fatalf("Failed to read todo file: %v", err)
+	}
 
-	if idx0206 == -1 {
-		t.Error("Expected '## 2026-02-06' section header for completed task, not found")
+	parsed := tasks.ParseTasks(string(content))
+	var found bool
+	for _, p := range parsed {
+		if p.ID == task.ID {
+			found = true
+			if p.Section != "Errands" || p.Priority != "P2" {
+				t.Errorf("Task in todo file = %+v; want section Errands, priority P2", p)
+			}
+		}
 	}
-	if idx0201 == -1 {
-		t.Error("Expected '## 2026-02-01' section header for incomplete task, not found")
+	if !found {
+		t.Errorf("Added task not found in todo file:\n%s", content)
+	}
+	if !strings.Contains(string(content), "Existing task") {
+		t.Errorf("Existing task lost when adding:\n%s", content)
 	}
 
-	// Find task positions
-	idxCompleted := strings.Index(contentStr, "Review project proposal")
-	idxIncomplete := strings.Index(contentStr, "Ongoing task")
-
-	if idxCompleted == -1 {
-		t.Error("Completed task 'Review project proposal' not found in output")
+	if _, err := service.AddTask(context.Background(), opts); err == nil {
+		t.Error("AddTask() expected error when adding the same task twice")
 	}
-	if idxIncomplete == -1 {
-		t.Error("Incomplete task 'Ongoing task' not found in output")
+}
+
+func TestTaskService_AddStructuredTask(t *testing.T) {
+	fs := testhelpers.NewTestFS(t)
+	defer fs.Cleanup()
+
+	opts := AddTaskOptions{
+		TodoPath:  filepath.Join(fs.BaseDir, "todo.md"),
+		StatePath: filepath.Join(fs.BaseDir, ".todo_state.json"),
 	}
 
-	// Verify completed task is in the 2026-02-06 section (after its header but before next section)
-	if idx0206 != -1 && idxCompleted != -1 {
-		if idxCompleted < idx0206 {
-			t.Error("Completed task should appear after '## 2026-02-06' header")
-		}
-		// If there's a section after 2026-02-06, task should be before it
-		if idx0201 > idx0206 && idxCompleted > idx0201 {
-			t.Error("Completed task should be in '## 2026-02-06' section, not after '## 2026-02-01'")
-		}
+	service := NewTaskService()
+	id, err := service.AddStructuredTask(context.Background(), opts, tasks.Task{
+		Text:     "Reply to bot",
+		Section:  "Inbox",
+		Priority: "P1",
+		Tags:     []string{"chat"},
+	})
+	if err != nil {
+		t.Fatalf("AddStructuredTask() error = %v", err)
+	}
+	if id == "" {
+		t.Fatal("AddStructuredTask() returned an empty ID")
 	}
 
-	// Verify incomplete task is in the 2026-02-01 section
-	if idx0201 != -1 && idxIncomplete != -1 {
-		if idxIncomplete < idx0201 {
-			t.Error("Incomplete task should appear after '## 2026-02-01' header")
-		}
+	all, err := service.GetAllTasks(context.Background(), opts.TodoPath, TodoFormat{})
+	if err != nil {
+		t.Fatalf("GetAllTasks() error = %v", err)
+	}
+	if len(all) != 1 {
+		t.Fatalf("GetAllTasks() returned %d tasks, want 1", len(all))
 	}
 
-	// Verify completed task has [x] checkbox
-	if !strings.Contains(contentStr, "- [x] Review project proposal") {
-		t.Error("Completed task should have [x] checkbox")
+	got := all[0]
+	if got.ID != id || got.Section != "Inbox" || got.Priority != "P1" || len(got.Tags) != 1 || got.Tags[0] != "chat" {
+		t.Errorf("task read back = %+v; want ID %s in Inbox with P1 and #chat", got, id)
 	}
 
-	// Verify incomplete task has [ ] checkbox
-	if !strings.Contains(contentStr, "- [ ] Ongoing task") {
-		t.Error("Incomplete task should have [ ] checkbox")
+	if _, err := service.AddStructuredTask(context.Background(), opts, tasks.Task{ID: id, Text: "Other"}); err == nil {
+		t.Error("AddStructuredTask() expected error for an existing ID")
 	}
 }
 
-func TestTaskService_FormatTaskLine_CompletedWithDate(t *testing.T) {
-	service := NewTaskService()
-
+func TestTaskService_AddTask_NewSection(t *testing.T) {
 	tests := []struct {
-		name     string
-		task     state.TaskState
-		expected string
+		name                   string
+		requireExistingSection bool
+		section                string
+		wantErr                bool
 	}{
-		{
-			name: "completed task with CompletedDate includes @completed tag",
-			task: state.TaskState{
-				ID:            "abc12345",
-				Text:          "Review project proposal",
-				Completed:     true,
-				CompletedDate: "2026-02-06",
-			},
-			expected: "- [x] Review project proposal <!-- id: abc12345 --> @completed(2026-02-06)",
-		},
-		{
-			name: "completed task without CompletedDate has no tag",
-			task: state.TaskState{
-				ID:        "def45678",
-				Text:      "Another task",
-				Completed: true,
-			},
-			expected: "- [x] Another task <!-- id: def45678 -->",
-		},
-		{
-			name: "incomplete task never has @completed tag",
-			task: state.TaskState{
-				ID:            "ghi90123",
-				Text:          "Pending task",
-				Completed:     false,
-				CompletedDate: "2026-02-06", // Should be ignored
-			},
-			expected: "- [ ] Pending task <!-- id: ghi90123 -->",
-		},
-		{
-			name: "task without ID omits ID comment",
-			task: state.TaskState{
-				Text:          "No ID task",
-				Completed:     true,
-				CompletedDate: "2026-02-10",
-			},
-			expected: "- [x] No ID task @completed(2026-02-10)",
-		},
-		{
-			name: "completed task with ID but no CompletedDate has no tag",
-			task: state.TaskState{
-				ID:        "jkl45678",
-				Text:      "Just completed",
-				Completed: true,
-			},
-			expected: "- [x] Just completed <!-- id: jkl45678 -->",
-		},
+		{name: "auto-create new section", section: "Errands"},
+		{name: "require existing, section missing", requireExistingSection: true, section: "Errands", wantErr: true},
+		{name: "require existing, section present", requireExistingSection: true, section: "Tasks"},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := service.formatTaskLine(tt.task)
-			if result != tt.expected {
-				t.Errorf("formatTaskLine() = %q, want %q", result, tt.expected)
+			fs := testhelpers.NewTestFS(t)
+			defer fs.Cleanup()
+
+			todoContent := "# To-Do List\n\n## Tasks\n\n- [ ] Existing task <!-- id: abcd1234 -->\n"
+			fs.WriteFile(t, "todo.md", todoContent)
+
+			_, err := NewTaskService().AddTask(context.Background(), AddTaskOptions{
+				TodoPath:               filepath.Join(fs.BaseDir, "todo.md"),
+				StatePath:              filepath.Join(fs.BaseDir, ".todo_state.json"),
+				Text:                   "Buy milk",
+				Section:                tt.section,
+				RequireExistingSection: tt.requireExistingSection,
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("AddTask() error = %v; wantErr %v", err, tt.wantErr)
+			}
+
+			content, readErr := os.ReadFile(filepath.Join(fs.BaseDir, "todo.md"))
+			if readErr != nil {
+				t.Fatalf("Failed to read todo file: %v", readErr)
+			}
+
+			if tt.wantErr {
+				if string(content) != todoContent {
+					t.Errorf("Todo file changed despite error:\n%s", content)
+				}
+				return
+			}
+
+			if !strings.Contains(string(content), "## "+tt.section) || !strings.Contains(string(content), "Buy milk") {
+				t.Errorf("Expected task under %q in todo file:\n%s", tt.section, content)
 			}
 		})
 	}
 }
 
-func TestTaskService_WriteTodoFileFromState_RoundTripIDPreservation(t *testing.T) {
+func TestTaskService_FindWaitingTasks(t *testing.T) {
 	fs := testhelpers.NewTestFS(t)
 	defer fs.Cleanup()
 
-	// Create a state with multiple tasks that have IDs
+	statePath := filepath.Join(fs.BaseDir, ".todo_state.json")
+
 	todoState := state.NewTodoState()
-	todoState.Tasks["abc12345"] = state.TaskState{
-		ID:          "abc12345",
-		Text:        "Review project proposal",
-		Section:     "2026-02-01",
-		Completed:   false,
-		CreatedDate: "2026-02-01",
-		Source:      "diary/2026-02-01.md",
-	}
-	todoState.Tasks["def67890"] = state.TaskState{
-		ID:            "def67890",
-		Text:          "Complete documentation",
-		Section:       "2026-02-01",
-		Completed:     true,
-		CompletedDate: "2026-02-06",
-		CreatedDate:   "2026-02-01",
-		Source:        "diary/2026-02-01.md",
+	for id, task := range map[string]struct {
+		text      string
+		completed bool
+	}{
+		"aaaa0001": {"Contract review @waiting @bob", false},
+		"aaaa0002": {"Budget sign-off @waiting @alice", false},
+		"aaaa0003": {"Design feedback @alice @waiting", false},
+		"aaaa0004": {"Vendor quote @waiting", false},
+		"aaaa0005": {"Ask @alice about lunch", false},
+		"aaaa0006": {"Old request @waiting @bob", true},
+	} {
+		todoState.Tasks[id] = state.TaskState{ID: id, Text: task.text, Completed: task.completed}
 	}
-	todoState.Tasks["cab24680"] = state.TaskState{
-		ID:        "cab24680",
-		Text:      "Write unit tests",
-		Section:   "Tasks",
-		Completed: false,
-		Source:    "todo.md",
+	if err := todoState.Write(statePath); err != nil {
+		t.Fatalf("Failed to write state: %v", err)
 	}
 
-	todoPath := filepath.Join(fs.BaseDir, "todo.md")
-
-	service := NewTaskService()
+	groups, err := NewTaskService().FindWaitingTasks(statePath)
+	if err != nil {
+		t.Fatalf("FindWaitingTasks() error = %v", err)
+	}
 
-	// Write the todo file from state
-	if err := service.writeTodoFileFromState(todoPath, todoState, true); err != nil {
-		t.Fatalf("writeTodoFileFromState() error = %v", err)
+	want := map[string][]string{
+		"alice":          {"aaaa0002", "aaaa0003"},
+		"bob":            {"aaaa0001"},
+		UnassignedPerson: {"aaaa0004"},
 	}
+	wantOrder := []string{"alice", "bob", UnassignedPerson}
 
-	// Read the file back using tasks.ParseTasks
-	content, err := os.ReadFile(todoPath)
-	if err != nil {
-		t.Fatalf("Failed to read generated todo file: %v", err)
+	if len(groups) != len(wantOrder) {
+		t.Fatalf("FindWaitingTasks() returned %d groups; want %d: %+v", len(groups), len(wantOrder), groups)
+	}
+	for i, group := range groups {
+		if group.Person != wantOrder[i] {
+			t.Errorf("groups[%d].Person = %q; want %q", i, group.Person, wantOrder[i])
+			continue
+		}
+		var ids []string
+		for _, task := range group.Tasks {
+			ids = append(ids, task.ID)
+		}
+		if strings.Join(ids, ",") != strings.Join(want[group.Person], ",") {
+			t.Errorf("Tasks waiting on %s = %v; want %v", group.Person, ids, want[group.Person])
+		}
 	}
+}
 
-	parsedTasks := tasks.ParseTasks(string(content))
+func TestTaskService_FindStaleTasks(t *testing.T) {
+	fs := testhelpers.NewTestFS(t)
+	defer fs.Cleanup()
 
-	// Verify all 3 tasks were parsed
-	if len(parsedTasks) != 3 {
-		t.Errorf("Expected 3 tasks, got %d", len(parsedTasks))
-	}
+	now := time.Date(2025, 6, 30, 12, 0, 0, 0, time.UTC)
+	statePath := filepath.Join(fs.BaseDir, ".todo_state.json")
 
-	// Create a map for easy lookup by ID
-	parsedByIDs := make(map[string]tasks.Task)
-	for _, task := range parsedTasks {
-		if task.ID != "" {
-			parsedByIDs[task.ID] = task
+	todoState := state.NewTodoState()
+	seed := []struct {
+		id        string
+		age       int
+		completed bool
+	}{
+		{"aaaa0001", 5, false},
+		{"aaaa0002", 45, false},
+		{"aaaa0003", 90, false},
+		{"aaaa0004", 120, true},
+		{"aaaa0005", 30, false},
+	}
+	for _, task := range seed {
+		todoState.Tasks[task.id] = state.TaskState{
+			ID:           task.id,
+			Text:         "Task " + task.id,
+			Completed:    task.completed,
+			LastModified: now.AddDate(0, 0, -task.age),
 		}
 	}
+	if err := todoState.Write(statePath); err != nil {
+		t.Fatalf("Failed to write state: %v", err)
+	}
 
-	// Verify each task's ID was preserved
-	testCases := []struct {
-		expectedID       string
-		expectedText     string
-		expectedComplete bool
-	}{
-		{"abc12345", "Review project proposal", false},
-		{"def67890", "Complete documentation", true},
-		{"cab24680", "Write unit tests", false},
+	stale, err := NewTaskService().FindStaleTasks(statePath, 30, now)
+	if err != nil {
+		t.Fatalf("FindStaleTasks() error = %v", err)
 	}
 
-	for _, tc := range testCases {
-		task, exists := parsedByIDs[tc.expectedID]
-		if !exists {
-			t.Errorf("Task with ID %s not found in parsed output", tc.expectedID)
-			continue
+	want := []string{"aaaa0003", "aaaa0002"}
+	if len(stale) != len(want) {
+		t.Fatalf("FindStaleTasks() returned %d tasks; want %d: %+v", len(stale), len(want), stale)
+	}
+	for i, id := range want {
+		if stale[i].ID != id {
+			t.Errorf("stale[%d].ID = %s; want %s", i, stale[i].ID, id)
 		}
+	}
+}
 
-		if task.Text != tc.expectedText {
-			t.Errorf("Task %s: expected text %q, got %q", tc.expectedID, tc.expectedText, task.Text)
-		}
+func TestTaskService_LoadConfig(t *testing.T) {
+	fs := testhelpers.NewTestFS(t)
+	defer fs.Cleanup()
 
-		if task.Completed != tc.expectedComplete {
-			t.Errorf("Task %s: expected Completed=%v, got %v", tc.expectedID, tc.expectedComplete, task.Completed)
-		}
-	}
+	configHelper := testhelpers.NewConfigHelper(fs)
+	configHelper.CreateBasicConfig(t)
 
-	// Verify the file content actually contains the ID comments
-	contentStr := string(content)
-	expectedIDPatterns := []string{
-		"<!-- id: abc12345 -->",
-		"<!-- id: def67890 -->",
-		"<!-- id: cab24680 -->",
-	}
+	configPath := filepath.Join(fs.BaseDir, ".config", "jotr", "config.json")
+	os.Setenv("JOTR_CONFIG", configPath)
 
-	for _, pattern := range expectedIDPatterns {
-		if !strings.Contains(contentStr, pattern) {
-			t.Errorf("Expected file to contain %q, but it was not found", pattern)
-		}
+	config, err := config.Load()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
 	}
 
-	// Verify completed task has @completed tag
-	if !strings.Contains(contentStr, "@completed(2026-02-06)") {
-		t.Error("Expected completed task to have @completed(2026-02-06) tag")
+	if config == nil {
+		t.Error("LoadConfig() returned nil")
 	}
 }
 
-func TestTaskService_SyncTasks_DeadlockPrevention(t *testing.T) {
+func TestTaskService_SyncTasks_ConcurrentFileLocking(t *testing.T) {
 	fs := testhelpers.NewTestFS(t)
 	defer fs.Cleanup()
 
@@ -944,16 +1202,20 @@ func TestTaskService_SyncTasks_DeadlockPrevention(t *testing.T) {
 
 ## Tasks
 
-- [ ] Task one <!-- id: abc12345 -->
+- [ ] Task one <!-- id: task1 -->
+- [ ] Task two <!-- id: task2 -->
 `
 	fs.WriteFile(t, filepath.Join("diary", year, monthDir, dayFile), dailyNoteContent)
 
 	statePath := filepath.Join(fs.BaseDir, ".todo_state.json")
 	todoPath := filepath.Join(fs.BaseDir, "todo.md")
 
+	dailyNotePath := filepath.Join(fs.BaseDir, "diary", year, monthDir, dayFile)
+
 	initialState := state.NewTodoState()
 	initialState.Tasks = map[string]state.TaskState{
-		"abc12345": {ID: "abc12345", Text: "Task one", Completed: false, Source: "daily.md"},
+		"task1": {ID: "task1", Text: "Task one", Completed: false, Source: dailyNotePath},
+		"task2": {ID: "task2", Text: "Task two", Completed: false, Source: dailyNotePath},
 	}
 	if err := initialState.Write(statePath); err != nil {
 		t.Fatalf("Failed to write initial state: %v", err)
@@ -963,56 +1225,1393 @@ func TestTaskService_SyncTasks_DeadlockPrevention(t *testing.T) {
 
 ## Tasks
 
-- [ ] Task one <!-- id: abc12345 -->
+- [ ] Task one <!-- id: task1 -->
+- [ ] Task two <!-- id: task2 -->
 `
 	fs.WriteFile(t, "todo.md", todoContent)
 
 	service := NewTaskService()
 	ctx := context.Background()
 
-	numOps := 10
-	var wg sync.WaitGroup
-	errChan := make(chan error, numOps)
+	numGoroutines := 5
+	errors := make(chan error, numGoroutines)
+	done := make(chan bool, numGoroutines)
+	start := make(chan struct{})
+
+	for i := 0; i < numGoroutines; i++ {
+		go func(idx int) {
+			defer func() { done <- true }()
+
+			<-start
 
-	for i := 0; i < numOps; i++ {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
 			_, err := service.SyncTasks(ctx, SyncOptions{
 				DiaryPath:   filepath.Join(fs.BaseDir, "diary"),
 				TodoPath:    todoPath,
 				StatePath:   statePath,
 				TaskSection: "Tasks",
 			})
-			errChan <- err
-		}()
+			errors <- err
+		}(i)
 	}
 
-	done := make(chan struct{})
-	go func() {
-		wg.Wait()
-		close(done)
-	}()
+	close(start)
 
-	select {
-	case <-done:
-	case <-time.After(30 * time.Second):
-		t.Fatal("Test timed out - deadlock detected")
-	}
+	timeout := time.After(30 * time.Second)
+	completed := 0
 
-	close(errChan)
-	for err := range errChan {
-		if err != nil && !errors.Is(err, utils.ErrLockTimeout) {
-			t.Errorf("Unexpected error: %v", err)
+	for completed < numGoroutines {
+		select {
+		case <-done:
+			completed++
+		case err := <-errors:
+			if err != nil {
+				t.Errorf("Error in goroutine: %v", err)
+			}
+		case <-timeout:
+			t.Fatalf("Test timed out after 30 seconds - possible deadlock (%d/%d completed)", completed, numGoroutines)
 		}
 	}
 
 	finalState, err := state.Read(statePath)
 	if err != nil {
-		t.Fatalf("Failed to read state: %v", err)
+		t.Fatalf("Failed to read final state: %v", err)
 	}
 
-	if _, exists := finalState.Tasks["abc12345"]; !exists {
-		t.Error("Task abc12345 should still exist after concurrent syncs")
+	if len(finalState.Tasks) == 0 {
+		t.Error("Final state has no tasks - data loss detected")
+	}
+
+	for id, task := range finalState.Tasks {
+		if task.Text == "" {
+			t.Errorf("Task %s has empty text (possible corruption)", id)
+		}
+		if task.ID == "" {
+			t.Errorf("Task has empty ID (possible corruption): %v", task)
+		}
+	}
+}
+
+func TestTaskService_SyncTasks_LockTimeoutError(t *testing.T) {
+	fs := testhelpers.NewTestFS(t)
+	defer fs.Cleanup()
+
+	configHelper := testhelpers.NewConfigHelper(fs)
+	configHelper.CreateBasicConfig(t)
+
+	configPath := filepath.Join(fs.BaseDir, ".config", "jotr", "config.json")
+	os.Setenv("JOTR_CONFIG", configPath)
+
+	statePath := filepath.Join(fs.BaseDir, ".todo_state.json")
+
+	lockFile, err := utils.TryLockFile(statePath)
+	if err != nil {
+		t.Fatalf("Failed to acquire lock: %v", err)
+	}
+	if lockFile == nil {
+		t.Fatal("TryLockFile returned nil file")
+	}
+	defer utils.UnlockFile(lockFile)
+
+	service := NewTaskService()
+
+	timeoutErr := fmt.Errorf("%w: %s", utils.ErrLockTimeout, statePath)
+	if !service.isLockTimeoutError(timeoutErr) {
+		t.Error("isLockTimeoutError should return true for ErrLockTimeout wrapped error")
+	}
+
+	otherErr := fmt.Errorf("some other error")
+	if service.isLockTimeoutError(otherErr) {
+		t.Error("isLockTimeoutError should return false for non-timeout error")
+	}
+
+	if service.isLockTimeoutError(nil) {
+		t.Error("isLockTimeoutError should return false for nil error")
+	}
+}
+
+func TestTaskService_SyncTasks_UserFriendlyErrorMessage(t *testing.T) {
+	service := NewTaskService()
+
+	timeoutErr := fmt.Errorf("%w: some/path", utils.ErrLockTimeout)
+	if !service.isLockTimeoutError(timeoutErr) {
+		t.Error("isLockTimeoutError should return true for ErrLockTimeout wrapped error")
+	}
+
+	wrappedErr := fmt.Errorf("failed to acquire lock on state file: %w", timeoutErr)
+	if !service.isLockTimeoutError(wrappedErr) {
+		t.Error("isLockTimeoutError should detect nested ErrLockTimeout")
+	}
+}
+
+func TestTaskService_WriteTodoFileFromState_CompletedDateSection(t *testing.T) {
+	fs := testhelpers.NewTestFS(t)
+	defer fs.Cleanup()
+
+	// Create a state with a task that:
+	// - was created under section "2026-02-01"
+	// - is completed with CompletedDate="2026-02-06"
+	// - should appear under "## 2026-02-06" section in output, not "## 2026-02-01"
+	todoState := state.NewTodoState()
+	todoState.Tasks["task123"] = state.TaskState{
+		ID:            "task123",
+		Text:          "Review project proposal",
+		Section:       "2026-02-01",
+		Completed:     true,
+		CompletedDate: "2026-02-06",
+		CreatedDate:   "2026-02-01",
+		Source:        "diary/2026-02-01.md",
+	}
+
+	// Also add an incomplete task under the same section to verify it stays there
+	todoState.Tasks["task456"] = state.TaskState{
+		ID:          "task456",
+		Text:        "Ongoing task",
+		Section:     "2026-02-01",
+		Completed:   false,
+		CreatedDate: "2026-02-01",
+		Source:      "diary/2026-02-01.md",
+	}
+
+	todoPath := filepath.Join(fs.BaseDir, "todo.md")
+
+	service := NewTaskService()
+	if err := service.writeTodoFileFromState(todoPath, todoState, true, TodoFormat{}); err != nil {
+		t.Fatalf("writeTodoFileFromState() error = %v", err)
+	}
+
+	// Read the generated file
+	content, err := os.ReadFile(todoPath)
+	if err != nil {
+		t.Fatalf("Failed to read generated todo file: %v", err)
+	}
+
+	contentStr := string(content)
+
+	// Verify the completed task appears under "## 2026-02-06" section
+	// Find the section boundaries
+	idx0206 := strings.Index(contentStr, "## 2026-02-06")
+	idx0201 := strings.Index(contentStr, "## 2026-02-01")
+
+	if idx0206 == -1 {
+		t.Error("Expected '## 2026-02-06' section header for completed task, not found")
+	}
+	if idx0201 == -1 {
+		t.Error("Expected '## 2026-02-01' section header for incomplete task, not found")
+	}
+
+	// Find task positions
+	idxCompleted := strings.Index(contentStr, "Review project proposal")
+	idxIncomplete := strings.Index(contentStr, "Ongoing task")
+
+	if idxCompleted == -1 {
+		t.Error("Completed task 'Review project proposal' not found in output")
+	}
+	if idxIncomplete == -1 {
+		t.Error("Incomplete task 'Ongoing task' not found in output")
+	}
+
+	// Verify completed task is in the 2026-02-06 section (after its header but before next section)
+	if idx0206 != -1 && idxCompleted != -1 {
+		if idxCompleted < idx0206 {
+			t.Error("Completed task should appear after '## 2026-02-06' header")
+		}
+		// If there's a section after 2026-02-06, task should be before it
+		if idx0201 > idx0206 && idxCompleted > idx0201 {
+			t.Error("Completed task should be in '## 2026-02-06' section, not after '## 2026-02-01'")
+		}
+	}
+
+	// Verify incomplete task is in the 2026-02-01 section
+	if idx0201 != -1 && idxIncomplete != -1 {
+		if idxIncomplete < idx0201 {
+			t.Error("Incomplete task should appear after '## 2026-02-01' header")
+		}
+	}
+
+	// Verify completed task has [x] checkbox
+	if !strings.Contains(contentStr, "- [x] Review project proposal") {
+		t.Error("Completed task should have [x] checkbox")
+	}
+
+	// Verify incomplete task has [ ] checkbox
+	if !strings.Contains(contentStr, "- [ ] Ongoing task") {
+		t.Error("Incomplete task should have [ ] checkbox")
+	}
+}
+
+func TestTaskService_FormatTaskLine_CompletedWithDate(t *testing.T) {
+	service := NewTaskService()
+
+	tests := []struct {
+		name     string
+		task     state.TaskState
+		expected string
+	}{
+		{
+			name: "completed task with CompletedDate includes @completed tag",
+			task: state.TaskState{
+				ID:            "abc12345",
+				Text:          "Review project proposal",
+				Completed:     true,
+				CompletedDate: "2026-02-06",
+			},
+			expected: "- [x] Review project proposal <!-- id: abc12345 --> @completed(2026-02-06)",
+		},
+		{
+			name: "completed task without CompletedDate has no tag",
+			task: state.TaskState{
+				ID:        "def45678",
+				Text:      "Another task",
+				Completed: true,
+			},
+			expected: "- [x] Another task <!-- id: def45678 -->",
+		},
+		{
+			name: "incomplete task never has @completed tag",
+			task: state.TaskState{
+				ID:            "ghi90123",
+				Text:          "Pending task",
+				Completed:     false,
+				CompletedDate: "2026-02-06", // Should be ignored
+			},
+			expected: "- [ ] Pending task <!-- id: ghi90123 -->",
+		},
+		{
+			name: "task without ID omits ID comment",
+			task: state.TaskState{
+				Text:          "No ID task",
+				Completed:     true,
+				CompletedDate: "2026-02-10",
+			},
+			expected: "- [x] No ID task @completed(2026-02-10)",
+		},
+		{
+			name: "completed task with ID but no CompletedDate has no tag",
+			task: state.TaskState{
+				ID:        "jkl45678",
+				Text:      "Just completed",
+				Completed: true,
+			},
+			expected: "- [x] Just completed <!-- id: jkl45678 -->",
+		},
+		{
+			name: "cancelled task keeps its marker",
+			task: state.TaskState{
+				ID:     "mno12345",
+				Text:   "Dropped idea",
+				Status: tasks.StatusCancelled,
+			},
+			expected: "- [-] Dropped idea <!-- id: mno12345 -->",
+		},
+		{
+			name: "in-progress task keeps its marker",
+			task: state.TaskState{
+				ID:     "pqr67890",
+				Text:   "Halfway there",
+				Status: tasks.StatusInProgress,
+			},
+			expected: "- [/] Halfway there <!-- id: pqr67890 -->",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := service.formatTaskLine(tt.task, tasks.Options{})
+			if result != tt.expected {
+				t.Errorf("formatTaskLine() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTaskService_WriteTodoFileFromState_RoundTripIDPreservation(t *testing.T) {
+	fs := testhelpers.NewTestFS(t)
+	defer fs.Cleanup()
+
+	// Create a state with multiple tasks that have IDs
+	todoState := state.NewTodoState()
+	todoState.Tasks["abc12345"] = state.TaskState{
+		ID:          "abc12345",
+		Text:        "Review project proposal",
+		Section:     "2026-02-01",
+		Completed:   false,
+		CreatedDate: "2026-02-01",
+		Source:      "diary/2026-02-01.md",
+	}
+	todoState.Tasks["def67890"] = state.TaskState{
+		ID:            "def67890",
+		Text:          "Complete documentation",
+		Section:       "2026-02-01",
+		Completed:     true,
+		CompletedDate: "2026-02-06",
+		CreatedDate:   "2026-02-01",
+		Source:        "diary/2026-02-01.md",
+	}
+	todoState.Tasks["cab24680"] = state.TaskState{
+		ID:        "cab24680",
+		Text:      "Write unit tests",
+		Section:   "Tasks",
+		Completed: false,
+		Source:    "todo.md",
+	}
+
+	todoPath := filepath.Join(fs.BaseDir, "todo.md")
+
+	service := NewTaskService()
+
+	// Write the todo file from state
+	if err := service.writeTodoFileFromState(todoPath, todoState, true, TodoFormat{}); err != nil {
+		t.Fatalf("writeTodoFileFromState() error = %v", err)
+	}
+
+	// Read the file back using tasks.ParseTasks
+	content, err := os.ReadFile(todoPath)
+	if err != nil {
+		t.Fatalf("Failed to read generated todo file: %v", err)
+	}
+
+	parsedTasks := tasks.ParseTasks(string(content))
+
+	// Verify all 3 tasks were parsed
+	if len(parsedTasks) != 3 {
+		t.Errorf("Expected 3 tasks, got %d", len(parsedTasks))
+	}
+
+	// Create a map for easy lookup by ID
+	parsedByIDs := make(map[string]tasks.Task)
+	for _, task := range parsedTasks {
+		if task.ID != "" {
+			parsedByIDs[task.ID] = task
+		}
+	}
+
+	// Verify each task's ID was preserved
+	testCases := []struct {
+		expectedID       string
+		expectedText     string
+		expectedComplete bool
+	}{
+		{"abc12345", "Review project proposal", false},
+		{"def67890", "Complete documentation", true},
+		{"cab24680", "Write unit tests", false},
+	}
+
+	for _, tc := range testCases {
+		task, exists := parsedByIDs[tc.expectedID]
+		if !exists {
+			t.Errorf("Task with ID %s not found in parsed output", tc.expectedID)
+			continue
+		}
+
+		if task.Text != tc.expectedText {
+			t.Errorf("Task %s: expected text %q, got %q", tc.expectedID, tc.expectedText, task.Text)
+		}
+
+		if task.Completed != tc.expectedComplete {
+			t.Errorf("Task %s: expected Completed=%v, got %v", tc.expectedID, tc.expectedComplete, task.Completed)
+		}
+	}
+
+	// Verify the file content actually contains the ID comments
+	contentStr := string(content)
+	expectedIDPatterns := []string{
+		"<!-- id: abc12345 -->",
+		"<!-- id: def67890 -->",
+		"<!-- id: cab24680 -->",
+	}
+
+	for _, pattern := range expectedIDPatterns {
+		if !strings.Contains(contentStr, pattern) {
+			t.Errorf("Expected file to contain %q, but it was not found", pattern)
+		}
+	}
+
+	// Verify completed task has @completed tag
+	if !strings.Contains(contentStr, "@completed(2026-02-06)") {
+		t.Error("Expected completed task to have @completed(2026-02-06) tag")
+	}
+}
+
+func TestTaskService_SyncTasks_DeadlockPrevention(t *testing.T) {
+	fs := testhelpers.NewTestFS(t)
+	defer fs.Cleanup()
+
+	configHelper := testhelpers.NewConfigHelper(fs)
+	configHelper.CreateBasicConfig(t)
+
+	configPath := filepath.Join(fs.BaseDir, ".config", "jotr", "config.json")
+	os.Setenv("JOTR_CONFIG", configPath)
+
+	now := time.Now()
+	year := now.Format("2006")
+	monthDir := now.Format("01-Jan")
+	dayFile := now.Format("2006-01-02-Mon.md")
+
+	dailyNoteContent := `# Daily Note
+
+## Tasks
+
+- [ ] Task one <!-- id: abc12345 -->
+`
+	fs.WriteFile(t, filepath.Join("diary", year, monthDir, dayFile), dailyNoteContent)
+
+	statePath := filepath.Join(fs.BaseDir, ".todo_state.json")
+	todoPath := filepath.Join(fs.BaseDir, "todo.md")
+
+	initialState := state.NewTodoState()
+	initialState.Tasks = map[string]state.TaskState{
+		"abc12345": {ID: "abc12345", Text: "Task one", Completed: false, Source: "daily.md"},
+	}
+	if err := initialState.Write(statePath); err != nil {
+		t.Fatalf("Failed to write initial state: %v", err)
+	}
+
+	todoContent := `# To-Do List
+
+## Tasks
+
+- [ ] Task one <!-- id: abc12345 -->
+`
+	fs.WriteFile(t, "todo.md", todoContent)
+
+	service := NewTaskService()
+	ctx := context.Background()
+
+	numOps := 10
+	var wg sync.WaitGroup
+	errChan := make(chan error, numOps)
+
+	for i := 0; i < numOps; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := service.SyncTasks(ctx, SyncOptions{
+				DiaryPath:   filepath.Join(fs.BaseDir, "diary"),
+				TodoPath:    todoPath,
+				StatePath:   statePath,
+				TaskSection: "Tasks",
+			})
+			errChan <- err
+		}()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(30 * time.Second):
+		t.Fatal("Test timed out - deadlock detected")
+	}
+
+	close(errChan)
+	for err := range errChan {
+		if err != nil && !errors.Is(err, utils.ErrLockTimeout) {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	}
+
+	finalState, err := state.Read(statePath)
+	if err != nil {
+		t.Fatalf("Failed to read state: %v", err)
+	}
+
+	if _, exists := finalState.Tasks["abc12345"]; !exists {
+		t.Error("Task abc12345 should still exist after concurrent syncs")
+	}
+}
+
+func TestTaskService_SyncTasks_ConfirmDeletionsWithholds(t *testing.T) {
+	fs := testhelpers.NewTestFS(t)
+	defer fs.Cleanup()
+
+	fs.WriteFile(t, ".todo_state.json", `{
+  "tasks": {
+    "abc12345": {"id": "abc12345", "text": "Temporarily cut task", "section": "Tasks"},
+    "def45678": {"id": "def45678", "text": "Finished task", "section": "Tasks", "completed": true}
+  },
+  "version": 1
+}`)
+	fs.WriteFile(t, "todo.md", "# To-Do List\n\n## Tasks\n\n")
+
+	notePath := notes.BuildDailyNotePath(filepath.Join(fs.BaseDir, "diary"), time.Now())
+	if err := notes.WriteNote(context.Background(), notePath, "# Today\n\n## Tasks\n\n"); err != nil {
+		t.Fatalf("Failed to create daily note: %v", err)
+	}
+
+	service := NewTaskService()
+	opts := SyncOptions{
+		DiaryPath:        filepath.Join(fs.BaseDir, "diary"),
+		TodoPath:         filepath.Join(fs.BaseDir, "todo.md"),
+		StatePath:        filepath.Join(fs.BaseDir, ".todo_state.json"),
+		TaskSection:      "Tasks",
+		ConfirmDeletions: true,
+	}
+
+	result, err := service.SyncTasks(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("SyncTasks() error = %v", err)
+	}
+
+	if result.DeletedTasks != 0 {
+		t.Errorf("SyncTasks().DeletedTasks = %d; want 0 without confirmation", result.DeletedTasks)
+	}
+	if len(result.PendingDeletions) != 1 || result.PendingDeletions[0].Text != "Temporarily cut task" {
+		t.Fatalf("SyncTasks().PendingDeletions = %+v; want only the pending task", result.PendingDeletions)
+	}
+
+	todoState, err := state.Read(opts.StatePath)
+	if err != nil {
+		t.Fatalf("state.Read() error = %v", err)
+	}
+	if !todoState.HasTask("abc12345") {
+		t.Error("withheld task should remain in state")
+	}
+
+	opts.ConfirmDeletions = false
+	result, err = service.SyncTasks(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("SyncTasks() error = %v", err)
+	}
+	if result.DeletedTasks != 1 || len(result.DeletedTasksDetail) != 1 || result.DeletedTasksDetail[0].Text != "Temporarily cut task" {
+		t.Errorf("confirmed sync should delete the task and report its text, got %+v", result.DeletedTasksDetail)
+	}
+}
+
+// TestTaskService_SyncTasks_ConfirmDeletionsWithTodoRewrite tests that a
+// withheld deletion is not written back to the todo file when the same sync
+// rewrites it, so the confirming sync can delete it.
+func TestTaskService_SyncTasks_ConfirmDeletionsWithTodoRewrite(t *testing.T) {
+	fs := testhelpers.NewTestFS(t)
+	defer fs.Cleanup()
+
+	fs.WriteFile(t, ".todo_state.json", `{
+  "tasks": {
+    "aaaa1111": {"id": "aaaa1111", "text": "Temporarily cut task", "section": "Tasks"}
+  },
+  "version": 1
+}`)
+	fs.WriteFile(t, "todo.md", "# To-Do List\n\n## Tasks\n\n")
+
+	notePath := notes.BuildDailyNotePath(filepath.Join(fs.BaseDir, "diary"), time.Now())
+	if err := notes.WriteNote(context.Background(), notePath, "# Today\n\n## Tasks\n\n- [ ] Brand new task\n"); err != nil {
+		t.Fatalf("Failed to create daily note: %v", err)
+	}
+
+	service := NewTaskService()
+	opts := SyncOptions{
+		DiaryPath:        filepath.Join(fs.BaseDir, "diary"),
+		TodoPath:         filepath.Join(fs.BaseDir, "todo.md"),
+		StatePath:        filepath.Join(fs.BaseDir, ".todo_state.json"),
+		TaskSection:      "Tasks",
+		ConfirmDeletions: true,
+	}
+
+	result, err := service.SyncTasks(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("SyncTasks() error = %v", err)
+	}
+	if len(result.PendingDeletions) != 1 {
+		t.Fatalf("SyncTasks().PendingDeletions = %+v; want the cut task", result.PendingDeletions)
+	}
+
+	todo := fs.ReadFile(t, "todo.md")
+	if !strings.Contains(todo, "Brand new task") {
+		t.Errorf("todo file should gain the new daily task:\n%s", todo)
+	}
+	if strings.Contains(todo, "Temporarily cut task") {
+		t.Errorf("withheld task should not be written back to the todo file:\n%s", todo)
+	}
+
+	opts.ConfirmDeletions = false
+	result, err = service.SyncTasks(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("SyncTasks() error = %v", err)
+	}
+	if result.DeletedTasks != 1 || len(result.DeletedTaskIDs) != 1 || result.DeletedTaskIDs[0] != "aaaa1111" {
+		t.Errorf("confirmed sync should delete aaaa1111, got %+v", result.DeletedTaskIDs)
+	}
+}
+
+func TestSortSectionTasks(t *testing.T) {
+	content := `# Daily Note
+
+## Notes
+
+- [ ] [P3] Not in the task section
+
+## Tasks
+
+- [ ] Unprioritized <!-- id: aaaa0001 -->
+- [x] [P2] Done later due:2025-02-01
+Some prose that stays put
+- [ ] [P0] Urgent
+  - [ ] Subtask of urgent
+- [ ] [P2] Due sooner due:2025-01-01
+
+## Reflection
+
+- [ ] [P0] Also not touched
+`
+
+	got, changed := sortSectionTasks(content, "Tasks", tasks.Options{})
+	if !changed {
+		t.Fatal("sortSectionTasks() reported no change for a jumbled section")
+	}
+
+	want := `# Daily Note
+
+## Notes
+
+- [ ] [P3] Not in the task section
+
+## Tasks
+
+- [ ] [P0] Urgent
+  - [ ] Subtask of urgent
+- [ ] [P2] Due sooner due:2025-01-01
+Some prose that stays put
+- [x] [P2] Done later due:2025-02-01
+- [ ] Unprioritized <!-- id: aaaa0001 -->
+
+## Reflection
+
+- [ ] [P0] Also not touched
+`
+	if got != want {
+		t.Errorf("sortSectionTasks() =\n%s\nwant\n%s", got, want)
+	}
+
+	if _, changed := sortSectionTasks(got, "Tasks", tasks.Options{}); changed {
+		t.Error("sortSectionTasks() should leave an already sorted section unchanged")
+	}
+}
+
+func TestTaskService_SyncTasks_HideCompleted(t *testing.T) {
+	fs := testhelpers.NewTestFS(t)
+	defer fs.Cleanup()
+
+	fs.WriteFile(t, ".todo_state.json", `{
+  "tasks": {
+    "def45678": {"id": "def45678", "text": "Finished task", "section": "Tasks", "completed": true, "completedDate": "2025-06-01"}
+  },
+  "version": 1
+}`)
+	fs.WriteFile(t, "todo.md", "# To-Do List\n\n## 2025-06-01\n\n- [x] Finished task <!-- id: def45678 --> @completed(2025-06-01)\n")
+
+	notePath := notes.BuildDailyNotePath(filepath.Join(fs.BaseDir, "diary"), time.Now())
+	if err := notes.WriteNote(context.Background(), notePath, "# Today\n\n## Tasks\n\n- [ ] New task\n"); err != nil {
+		t.Fatalf("Failed to create daily note: %v", err)
+	}
+
+	opts := SyncOptions{
+		DiaryPath:     filepath.Join(fs.BaseDir, "diary"),
+		TodoPath:      filepath.Join(fs.BaseDir, "todo.md"),
+		StatePath:     filepath.Join(fs.BaseDir, ".todo_state.json"),
+		TaskSection:   "Tasks",
+		HideCompleted: true,
+	}
+
+	if _, err := NewTaskService().SyncTasks(context.Background(), opts); err != nil {
+		t.Fatalf("SyncTasks() error = %v", err)
+	}
+
+	todo := fs.ReadFile(t, "todo.md")
+	if !strings.Contains(todo, "New task") {
+		t.Errorf("todo file should contain the new active task:\n%s", todo)
+	}
+	if strings.Contains(todo, "Finished task") {
+		t.Errorf("todo file should not contain completed tasks:\n%s", todo)
+	}
+
+	todoState, err := state.Read(opts.StatePath)
+	if err != nil {
+		t.Fatalf("state.Read() error = %v", err)
+	}
+	if task, ok := todoState.Tasks["def45678"]; !ok || !task.Completed {
+		t.Error("completed task should remain in state for archiving")
+	}
+}
+
+func TestTaskService_SyncTasks_PersistsMeta(t *testing.T) {
+	fs := testhelpers.NewTestFS(t)
+	defer fs.Cleanup()
+
+	notePath := notes.BuildDailyNotePath(filepath.Join(fs.BaseDir, "diary"), time.Now())
+	if err := notes.WriteNote(context.Background(), notePath, "# Today\n\n## Tasks\n\n- [ ] Plan launch project: Phoenix estimate: 2h\n"); err != nil {
+		t.Fatalf("Failed to create daily note: %v", err)
+	}
+
+	opts := SyncOptions{
+		DiaryPath:   filepath.Join(fs.BaseDir, "diary"),
+		TodoPath:    filepath.Join(fs.BaseDir, "todo.md"),
+		StatePath:   filepath.Join(fs.BaseDir, ".todo_state.json"),
+		TaskSection: "Tasks",
+	}
+
+	service := NewTaskService()
+	if _, err := service.SyncTasks(context.Background(), opts); err != nil {
+		t.Fatalf("SyncTasks() error = %v", err)
+	}
+
+	todoState, err := state.Read(opts.StatePath)
+	if err != nil {
+		t.Fatalf("state.Read() error = %v", err)
+	}
+	if len(todoState.Tasks) != 1 {
+		t.Fatalf("expected 1 task in state, got %d", len(todoState.Tasks))
+	}
+	for _, task := range todoState.Tasks {
+		if task.Meta["project"] != "Phoenix" || task.Meta["estimate"] != "2h" {
+			t.Errorf("state Meta = %v, want project and estimate", task.Meta)
+		}
+
+		task.Text = "Plan launch"
+		line := service.formatTaskLine(task, tasks.Options{})
+		if !strings.Contains(line, "Plan launch estimate: 2h project: Phoenix") {
+			t.Errorf("formatTaskLine() = %q, want meta fields written back", line)
+		}
+	}
+
+	todoTasks, err := service.GetAllTasks(context.Background(), opts.TodoPath, TodoFormat{})
+	if err != nil {
+		t.Fatalf("GetAllTasks() error = %v", err)
+	}
+	if got := tasks.FilterByMeta(todoTasks, "project", "phoenix"); len(got) != 1 {
+		t.Errorf("FilterByMeta() returned %d tasks from the todo file, want 1", len(got))
+	}
+}
+
+func TestTaskService_SyncTasks_AutoArchive(t *testing.T) {
+	fs := testhelpers.NewTestFS(t)
+	defer fs.Cleanup()
+
+	old := time.Now().AddDate(0, 0, -30).Format("2006-01-02")
+	recent := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
+
+	fs.WriteFile(t, ".todo_state.json", fmt.Sprintf(`{
+  "tasks": {
+    "old12345": {"id": "old12345", "text": "Old finished task", "section": "Tasks", "completed": true, "completedDate": %q},
+    "new12345": {"id": "new12345", "text": "Recently finished task", "section": "Tasks", "completed": true, "completedDate": %q}
+  },
+  "version": 1
+}`, old, recent))
+	fs.WriteFile(t, "todo.md", fmt.Sprintf("# To-Do List\n\n## %s\n\n- [x] Recently finished task <!-- id: new12345 --> @completed(%s)\n\n## %s\n\n- [x] Old finished task <!-- id: old12345 --> @completed(%s)\n", recent, recent, old, old))
+
+	notePath := notes.BuildDailyNotePath(filepath.Join(fs.BaseDir, "diary"), time.Now())
+	if err := notes.WriteNote(context.Background(), notePath, "# Today\n\n## Tasks\n\n- [ ] New task\n"); err != nil {
+		t.Fatalf("Failed to create daily note: %v", err)
+	}
+
+	opts := SyncOptions{
+		DiaryPath:            filepath.Join(fs.BaseDir, "diary"),
+		TodoPath:             filepath.Join(fs.BaseDir, "todo.md"),
+		StatePath:            filepath.Join(fs.BaseDir, ".todo_state.json"),
+		BaseDir:              fs.BaseDir,
+		TaskSection:          "Tasks",
+		AutoArchiveAfterDays: 7,
+	}
+
+	service := NewTaskService()
+	result, err := service.SyncTasks(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("SyncTasks() error = %v", err)
+	}
+	if result.AutoArchived != 1 {
+		t.Errorf("AutoArchived = %d, want 1", result.AutoArchived)
+	}
+
+	todo := fs.ReadFile(t, "todo.md")
+	if strings.Contains(todo, "Old finished task") {
+		t.Errorf("old completed task should be archived out of the todo file:\n%s", todo)
+	}
+	if !strings.Contains(todo, "Recently finished task") || !strings.Contains(todo, "New task") {
+		t.Errorf("recent and active tasks should stay in the todo file:\n%s", todo)
+	}
+
+	archive := fs.ReadFile(t, filepath.Join("Archive", fmt.Sprintf("archive-%s.md", time.Now().Format("2006-01"))))
+	if !strings.Contains(archive, "Old finished task") || strings.Contains(archive, "Recently finished task") {
+		t.Errorf("archive should contain only the old task:\n%s", archive)
+	}
+
+	// A second sync must not archive the same task again.
+	result, err = service.SyncTasks(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("second SyncTasks() error = %v", err)
+	}
+	if result.AutoArchived != 0 {
+		t.Errorf("second sync AutoArchived = %d, want 0", result.AutoArchived)
+	}
+}
+
+func TestTaskService_SyncTasks_InjectedClock(t *testing.T) {
+	fs := testhelpers.NewTestFS(t)
+	defer fs.Cleanup()
+
+	fixed := time.Date(2024, 2, 29, 9, 0, 0, 0, time.Local)
+	service := NewTaskServiceWithClock(func() time.Time { return fixed })
+
+	diaryPath := filepath.Join(fs.BaseDir, "diary")
+	notePath := notes.BuildDailyNotePath(diaryPath, fixed)
+	if err := notes.WriteNote(context.Background(), notePath, "# Leap day\n\n## Tasks\n\n- [ ] Backfilled task\n"); err != nil {
+		t.Fatalf("Failed to create daily note: %v", err)
+	}
+
+	opts := SyncOptions{
+		DiaryPath:   diaryPath,
+		TodoPath:    filepath.Join(fs.BaseDir, "todo.md"),
+		StatePath:   filepath.Join(fs.BaseDir, ".todo_state.json"),
+		TaskSection: "Tasks",
+	}
+
+	result, err := service.SyncTasks(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("SyncTasks() error = %v", err)
+	}
+	if result.DailyPath != notePath {
+		t.Errorf("DailyPath = %q, want the note for the injected date %q", result.DailyPath, notePath)
+	}
+
+	// Complete the task in the todo file and sync again.
+	todo := strings.Replace(fs.ReadFile(t, "todo.md"), "- [ ] Backfilled task", "- [x] Backfilled task", 1)
+	fs.WriteFile(t, "todo.md", todo)
+
+	if _, err := service.SyncTasks(context.Background(), opts); err != nil {
+		t.Fatalf("second SyncTasks() error = %v", err)
+	}
+
+	todoState, err := state.Read(opts.StatePath)
+	if err != nil {
+		t.Fatalf("state.Read() error = %v", err)
+	}
+	if len(todoState.Tasks) != 1 {
+		t.Fatalf("expected 1 task in state, got %d", len(todoState.Tasks))
+	}
+	for _, task := range todoState.Tasks {
+		if !task.Completed || task.CompletedDate != "2024-02-29" {
+			t.Errorf("task = %+v; want completed with CompletedDate 2024-02-29", task)
+		}
+	}
+}
+
+func TestTaskService_SyncTasks_NormalizesDueDates(t *testing.T) {
+	fs := testhelpers.NewTestFS(t)
+	defer fs.Cleanup()
+
+	fixed := time.Date(2025, 3, 10, 9, 0, 0, 0, time.Local)
+	service := NewTaskServiceWithClock(func() time.Time { return fixed })
+
+	diaryPath := filepath.Join(fs.BaseDir, "diary")
+	notePath := notes.BuildDailyNotePath(diaryPath, fixed)
+	if err := notes.WriteNote(context.Background(), notePath, "# Note\n\n## Tasks\n\n- [ ] Renew passport due: 2020/01/01\n"); err != nil {
+		t.Fatalf("Failed to create daily note: %v", err)
+	}
+	// Only todo tasks with an ID take part in sync, so the ID-less task is
+	// dropped rather than normalized
+	fs.WriteFile(t, "todo.md", "# To-Do List\n\n## Tasks\n\n- [ ] File taxes due:2020-04-15 <!-- id: abcd1234 -->\n- [ ] Book flights due:2020/05/01\n")
+
+	opts := SyncOptions{
+		DiaryPath:   diaryPath,
+		TodoPath:    filepath.Join(fs.BaseDir, "todo.md"),
+		StatePath:   filepath.Join(fs.BaseDir, ".todo_state.json"),
+		TaskSection: "Tasks",
+	}
+	if _, err := service.SyncTasks(context.Background(), opts); err != nil {
+		t.Fatalf("SyncTasks() error = %v", err)
+	}
+
+	todo := fs.ReadFile(t, "todo.md")
+	for _, want := range []string{"Renew passport due: 2020-01-01", "File taxes due: 2020-04-15"} {
+		if !strings.Contains(todo, want) {
+			t.Errorf("todo file missing %q:\n%s", want, todo)
+		}
+	}
+
+	if strings.Contains(todo, "Book flights") {
+		t.Errorf("todo task without an ID should not be synced:\n%s", todo)
+	}
+
+	parsed := tasks.ParseTasks(todo)
+	if len(parsed) != 2 {
+		t.Fatalf("todo file has %d tasks, want 2:\n%s", len(parsed), todo)
+	}
+	for _, task := range parsed {
+		if !tasks.IsOverdue(task) {
+			t.Errorf("IsOverdue(%q) = false after normalizing its due date", task.Text)
+		}
+	}
+}
+
+func TestTaskService_SyncTasks_RollsBackOnDailyNoteWriteFailure(t *testing.T) {
+	fs := testhelpers.NewTestFS(t)
+	defer fs.Cleanup()
+
+	fixed := time.Date(2025, 3, 10, 9, 0, 0, 0, time.Local)
+	service := NewTaskServiceWithClock(func() time.Time { return fixed })
+
+	diaryPath := filepath.Join(fs.BaseDir, "diary")
+	notePath := notes.BuildDailyNotePath(diaryPath, fixed)
+	if err := notes.WriteNote(context.Background(), notePath, "# Note\n\n## Tasks\n\n- [ ] First task\n"); err != nil {
+		t.Fatalf("Failed to create daily note: %v", err)
+	}
+
+	opts := SyncOptions{
+		DiaryPath:   diaryPath,
+		TodoPath:    filepath.Join(fs.BaseDir, "todo.md"),
+		StatePath:   filepath.Join(fs.BaseDir, ".todo_state.json"),
+		TaskSection: "Tasks",
+	}
+	if _, err := service.SyncTasks(context.Background(), opts); err != nil {
+		t.Fatalf("SyncTasks() error = %v", err)
+	}
+
+	// Complete the task in the todo file and add one to the daily note, so
+	// the next sync rewrites state, todo and the daily note.
+	todo := strings.Replace(fs.ReadFile(t, "todo.md"), "- [ ] First task", "- [x] First task", 1)
+	fs.WriteFile(t, "todo.md", todo)
+	note, err := os.ReadFile(notePath)
+	if err != nil {
+		t.Fatalf("Failed to read daily note: %v", err)
+	}
+	if err := os.WriteFile(notePath, append(note, "- [ ] Second task\n"...), 0644); err != nil {
+		t.Fatalf("Failed to update daily note: %v", err)
+	}
+	note, _ = os.ReadFile(notePath)
+	stateBefore := fs.ReadFile(t, ".todo_state.json")
+
+	service.rename = func(oldpath, newpath string) error {
+		if newpath == notePath {
+			return errors.New("simulated write failure")
+		}
+		return os.Rename(oldpath, newpath)
+	}
+
+	if _, err := service.SyncTasks(context.Background(), opts); err == nil {
+		t.Fatal("SyncTasks() succeeded; want the daily note write failure")
+	}
+
+	fs.AssertFileEquals(t, "todo.md", todo)
+	fs.AssertFileEquals(t, ".todo_state.json", stateBefore)
+	if got, _ := os.ReadFile(notePath); string(got) != string(note) {
+		t.Errorf("daily note changed after failed sync:\n%s", got)
+	}
+
+	matches, _ := filepath.Glob(filepath.Join(filepath.Dir(notePath), ".*.tmp.*"))
+	if len(matches) > 0 {
+		t.Errorf("temp files left behind: %v", matches)
+	}
+}
+
+func TestRenderTodoFile_RelativeDates(t *testing.T) {
+	fs := testhelpers.NewTestFS(t)
+	defer fs.Cleanup()
+
+	// Wednesday, so Monday falls in "This Week".
+	now := time.Date(2025, 3, 12, 9, 0, 0, 0, time.Local)
+	service := NewTaskServiceWithClock(func() time.Time { return now })
+
+	todoState := state.NewTodoState()
+	todoState.Tasks["aaaa1111"] = state.TaskState{ID: "aaaa1111", Text: "Ship release", Section: "Work", Completed: true, CompletedDate: "2025-03-12"}
+	todoState.Tasks["bbbb2222"] = state.TaskState{ID: "bbbb2222", Text: "Review PR", Section: "Work", Completed: true, CompletedDate: "2025-03-11"}
+	todoState.Tasks["cccc3333"] = state.TaskState{ID: "cccc3333", Text: "Plan sprint", Section: "Work", Completed: true, CompletedDate: "2025-03-10"}
+	todoState.Tasks["dddd4444"] = state.TaskState{ID: "dddd4444", Text: "Old chore", Section: "Home", Completed: true, CompletedDate: "2025-03-01"}
+	todoState.Tasks["eeee5555"] = state.TaskState{ID: "eeee5555", Text: "Write docs", Section: "Work"}
+
+	content := service.renderTodoFileFromState(filepath.Join(fs.BaseDir, "todo.md"), todoState, true, TodoFormat{RelativeDates: true})
+
+	var headings []string
+	for _, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(line, "## ") {
+			headings = append(headings, strings.TrimPrefix(line, "## "))
+		}
+	}
+	wantHeadings := []string{"Today", "Yesterday", "This Week", "2025-03-01", "Work"}
+	if strings.Join(headings, "|") != strings.Join(wantHeadings, "|") {
+		t.Errorf("headings = %v; want %v\n%s", headings, wantHeadings, content)
+	}
+
+	parsed := make(map[string]tasks.Task)
+	for _, task := range tasks.ParseTasks(content) {
+		parsed[task.ID] = task
+	}
+	if len(parsed) != 5 {
+		t.Fatalf("ParseTasks() found %d tasks; want 5\n%s", len(parsed), content)
+	}
+
+	today := parsed["aaaa1111"]
+	if !today.Completed || today.Section != "2025-03-12" || today.CompletedDate != "2025-03-12" {
+		t.Errorf("task completed today parsed as %+v; want section and completed date 2025-03-12", today)
+	}
+	if got := parsed["cccc3333"].Section; got != "2025-03-10" {
+		t.Errorf("task under This Week has section %q; want 2025-03-10", got)
+	}
+	if got := parsed["eeee5555"].Section; got != "Work" {
+		t.Errorf("active task has section %q; want Work", got)
+	}
+
+	plain := service.renderTodoFileFromState(filepath.Join(fs.BaseDir, "todo.md"), todoState, true, TodoFormat{})
+	if strings.Contains(plain, "## Today") || !strings.Contains(plain, "## 2025-03-12") {
+		t.Errorf("relative labels used without RelativeDates:\n%s", plain)
+	}
+}
+
+func TestTaskService_FormatTaskLine_DedupesTags(t *testing.T) {
+	task := state.TaskState{ID: "abcd1234", Text: "Plan sprint #work #Work #urgent #work", Tags: []string{"work", "Work", "urgent", "work"}}
+
+	line := NewTaskService().formatTaskLine(task, tasks.Options{})
+	if want := "- [ ] Plan sprint #work #urgent <!-- id: abcd1234 -->"; line != want {
+		t.Errorf("formatTaskLine() = %q, want %q", line, want)
+	}
+}
+
+func TestTaskService_FormatTaskLine_CompletedStyles(t *testing.T) {
+	service := NewTaskService()
+	task := state.TaskState{ID: "abcd1234", Text: "Ship release", Completed: true, CompletedDate: "2025-03-04"}
+
+	tests := []struct {
+		style    string
+		wantLine string
+		wantDate string
+	}{
+		{tasks.CompletedStyleInlineTag, "- [x] Ship release <!-- id: abcd1234 --> @completed(2025-03-04)", "2025-03-04"},
+		{tasks.CompletedStyleComment, "- [x] Ship release <!-- id: abcd1234 --> <!-- completed: 2025-03-04 -->", "2025-03-04"},
+		{tasks.CompletedStyleNone, "- [x] Ship release <!-- id: abcd1234 -->", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.style, func(t *testing.T) {
+			taskOpts := tasks.Options{CompletedStyle: tt.style}
+
+			line := service.formatTaskLine(task, taskOpts)
+			if line != tt.wantLine {
+				t.Errorf("formatTaskLine() = %q, want %q", line, tt.wantLine)
+			}
+
+			parsed := tasks.ParseTasks(line)
+			if len(parsed) != 1 {
+				t.Fatalf("ParseTasks() returned %d tasks, want 1", len(parsed))
+			}
+			if parsed[0].Text != "Ship release" || parsed[0].ID != "abcd1234" || !parsed[0].Completed {
+				t.Errorf("ParseTasks() = %+v, want completed \"Ship release\" with ID", parsed[0])
+			}
+			if parsed[0].CompletedDate != tt.wantDate {
+				t.Errorf("ParseTasks() CompletedDate = %q, want %q", parsed[0].CompletedDate, tt.wantDate)
+			}
+
+			// Rewriting a line in another style replaces the old marker.
+			task.Text = line[len("- [x] "):]
+			if again := service.formatTaskLine(task, taskOpts); again != tt.wantLine {
+				t.Errorf("formatTaskLine() of rendered text = %q, want %q", again, tt.wantLine)
+			}
+		})
+	}
+}
+
+func TestTaskService_SyncTasks_LinkDuplicates(t *testing.T) {
+	for _, link := range []bool{false, true} {
+		t.Run(fmt.Sprintf("link=%v", link), func(t *testing.T) {
+			fs := testhelpers.NewTestFS(t)
+			defer fs.Cleanup()
+
+			diaryPath := filepath.Join(fs.BaseDir, "diary")
+			opts := SyncOptions{
+				DiaryPath:      diaryPath,
+				TodoPath:       filepath.Join(fs.BaseDir, "todo.md"),
+				StatePath:      filepath.Join(fs.BaseDir, ".todo_state.json"),
+				TaskSection:    "Tasks",
+				LinkDuplicates: link,
+			}
+
+			// The task was renamed after its ID was generated, so copying
+			// it without the ID gives it a different generated ID.
+			yesterday := time.Date(2025, 3, 9, 9, 0, 0, 0, time.Local)
+			if err := notes.WriteNote(context.Background(), notes.BuildDailyNotePath(diaryPath, yesterday),
+				"# Note\n\n## Tasks\n\n- [ ] Buy oat milk <!-- id: abcd1234 -->\n"); err != nil {
+				t.Fatalf("Failed to create daily note: %v", err)
+			}
+			if _, err := NewTaskServiceWithClock(func() time.Time { return yesterday }).SyncTasks(context.Background(), opts); err != nil {
+				t.Fatalf("SyncTasks() yesterday error = %v", err)
+			}
+
+			today := yesterday.AddDate(0, 0, 1)
+			if err := notes.WriteNote(context.Background(), notes.BuildDailyNotePath(diaryPath, today),
+				"# Note\n\n## Tasks\n\n- [ ] Buy oat milk\n"); err != nil {
+				t.Fatalf("Failed to create daily note: %v", err)
+			}
+			if _, err := NewTaskServiceWithClock(func() time.Time { return today }).SyncTasks(context.Background(), opts); err != nil {
+				t.Fatalf("SyncTasks() today error = %v", err)
+			}
+
+			todoState, err := state.Read(opts.StatePath)
+			if err != nil {
+				t.Fatalf("state.Read() error = %v", err)
+			}
+
+			want := 2
+			if link {
+				want = 1
+			}
+			if len(todoState.Tasks) != want {
+				t.Fatalf("state has %d tasks, want %d: %+v", len(todoState.Tasks), want, todoState.Tasks)
+			}
+			if !todoState.HasTask("abcd1234") {
+				t.Errorf("state lost the original task abcd1234: %+v", todoState.Tasks)
+			}
+		})
+	}
+}
+
+func TestTaskService_SyncTasks_TaskOptions(t *testing.T) {
+	fs := testhelpers.NewTestFS(t)
+	defer fs.Cleanup()
+
+	diaryPath := filepath.Join(fs.BaseDir, "diary")
+	opts := SyncOptions{
+		DiaryPath:   diaryPath,
+		TodoPath:    filepath.Join(fs.BaseDir, "todo.md"),
+		StatePath:   filepath.Join(fs.BaseDir, ".todo_state.json"),
+		TaskSection: "Tasks",
+		TodoFormat: TodoFormat{
+			Tasks: TaskOptions(config.FormatConfig{TaskIDFormat: "^{id}", CheckboxMarkers: map[string]string{"~": tasks.StatusCancelled}}),
+		},
+	}
+
+	now := time.Date(2025, 3, 10, 9, 0, 0, 0, time.Local)
+	if err := notes.WriteNote(context.Background(), notes.BuildDailyNotePath(diaryPath, now),
+		"# Note\n\n## Tasks\n\n- [ ] Buy oat milk\n- [~] Call plumber\n"); err != nil {
+		t.Fatalf("Failed to create daily note: %v", err)
+	}
+
+	if _, err := NewTaskServiceWithClock(func() time.Time { return now }).SyncTasks(context.Background(), opts); err != nil {
+		t.Fatalf("SyncTasks() error = %v", err)
+	}
+
+	content, err := os.ReadFile(opts.TodoPath)
+	if err != nil {
+		t.Fatalf("Failed to read todo file: %v", err)
+	}
+
+	milkID := opts.TodoFormat.Tasks.GenerateTaskID("Buy oat milk")
+	if want := "- [ ] Buy oat milk ^" + milkID; !strings.Contains(string(content), want) {
+		t.Errorf("todo file missing %q:\n%s", want, content)
+	}
+	if !strings.Contains(string(content), "- [~] Call plumber ^") {
+		t.Errorf("todo file should keep the configured cancelled marker:\n%s", content)
+	}
+
+	// The options apply only to this call; default parsing is unchanged.
+	if got := tasks.ExtractTaskID("Buy oat milk ^" + milkID); got != "" {
+		t.Errorf("tasks.ExtractTaskID() = %q, want the custom template ignored by default", got)
+	}
+}
+
+// TestTaskService_SyncTasks_PruneWritesArchive tests that completed tasks
+// pruned from state are written to the archive file.
+func TestTaskService_SyncTasks_PruneWritesArchive(t *testing.T) {
+	fs := testhelpers.NewTestFS(t)
+	defer fs.Cleanup()
+
+	diaryPath := filepath.Join(fs.BaseDir, "diary")
+	opts := SyncOptions{
+		DiaryPath:             diaryPath,
+		TodoPath:              filepath.Join(fs.BaseDir, "todo.md"),
+		StatePath:             filepath.Join(fs.BaseDir, ".todo_state.json"),
+		TaskSection:           "Tasks",
+		BaseDir:               fs.BaseDir,
+		PruneRemovedAfterDays: 30,
+	}
+
+	todoState := state.NewTodoState()
+	todoState.Tasks["abcd1234"] = state.TaskState{
+		ID:            "abcd1234",
+		Text:          "Send old report",
+		Section:       "Tasks",
+		Completed:     true,
+		CompletedDate: "2025-01-02",
+	}
+	if err := todoState.Write(opts.StatePath); err != nil {
+		t.Fatalf("Failed to write state: %v", err)
+	}
+
+	now := time.Date(2025, 3, 10, 9, 0, 0, 0, time.Local)
+	if err := notes.WriteNote(context.Background(), notes.BuildDailyNotePath(diaryPath, now), "# Note\n\n## Tasks\n\n- [ ] Buy oat milk\n"); err != nil {
+		t.Fatalf("Failed to create daily note: %v", err)
+	}
+
+	result, err := NewTaskServiceWithClock(func() time.Time { return now }).SyncTasks(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("SyncTasks() error = %v", err)
+	}
+	if len(result.PrunedTaskIDs) != 1 {
+		t.Fatalf("PrunedTaskIDs = %v, want abcd1234", result.PrunedTaskIDs)
+	}
+
+	archive, err := os.ReadFile(filepath.Join(fs.BaseDir, "Archive", "archive-2025-03.md"))
+	if err != nil {
+		t.Fatalf("Failed to read archive: %v", err)
+	}
+	if !strings.Contains(string(archive), "## Archived on 2025-03-10\n\n- [x] Send old report\n") {
+		t.Errorf("archive does not contain the pruned task:\n%s", archive)
+	}
+}
+
+func TestTaskService_SyncTasks_PreserveTaskOrder(t *testing.T) {
+	fs := testhelpers.NewTestFS(t)
+	defer fs.Cleanup()
+
+	diaryPath := filepath.Join(fs.BaseDir, "diary")
+	opts := SyncOptions{
+		DiaryPath:   diaryPath,
+		TodoPath:    filepath.Join(fs.BaseDir, "todo.md"),
+		StatePath:   filepath.Join(fs.BaseDir, ".todo_state.json"),
+		TaskSection: "Tasks",
+		TodoFormat:  TodoFormat{PreserveOrder: true},
+	}
+
+	now := time.Date(2025, 3, 10, 9, 0, 0, 0, time.Local)
+	service := NewTaskServiceWithClock(func() time.Time { return now })
+	notePath := notes.BuildDailyNotePath(diaryPath, now)
+	daily := "# Note\n\n## Tasks\n\n" +
+		"- [ ] Alpha <!-- id: aaaa1111 -->\n" +
+		"- [ ] Bravo <!-- id: bbbb2222 -->\n" +
+		"- [ ] Charlie <!-- id: cccc3333 -->\n"
+	if err := notes.WriteNote(context.Background(), notePath, daily); err != nil {
+		t.Fatalf("Failed to create daily note: %v", err)
+	}
+	if _, err := service.SyncTasks(context.Background(), opts); err != nil {
+		t.Fatalf("SyncTasks() error = %v", err)
+	}
+
+	// Rearrange the tasks by hand in the todo file.
+	handOrder := []string{"Charlie", "Alpha", "Bravo"}
+	todo := fs.ReadFile(t, "todo.md")
+	var taskLines []string
+	for _, line := range strings.Split(todo, "\n") {
+		if strings.HasPrefix(line, "- [") {
+			taskLines = append(taskLines, line)
+		}
+	}
+	var reordered []string
+	for _, text := range handOrder {
+		for _, line := range taskLines {
+			if strings.Contains(line, text) {
+				reordered = append(reordered, line)
+			}
+		}
+	}
+	todo = strings.Replace(todo, strings.Join(taskLines, "\n"), strings.Join(reordered, "\n"), 1)
+	if err := os.WriteFile(opts.TodoPath, []byte(todo), 0644); err != nil {
+		t.Fatalf("Failed to write todo file: %v", err)
+	}
+
+	// A new daily task forces the todo file to be rewritten.
+	if err := notes.WriteNote(context.Background(), notePath, daily+"- [ ] Delta <!-- id: dddd4444 -->\n"); err != nil {
+		t.Fatalf("Failed to update daily note: %v", err)
+	}
+	if _, err := service.SyncTasks(context.Background(), opts); err != nil {
+		t.Fatalf("SyncTasks() error = %v", err)
+	}
+
+	todo = fs.ReadFile(t, "todo.md")
+	last := -1
+	for _, text := range append(handOrder, "Delta") {
+		idx := strings.Index(todo, text)
+		if idx < 0 {
+			t.Fatalf("todo file is missing %q:\n%s", text, todo)
+		}
+		if idx < last {
+			t.Errorf("%q moved out of its hand order:\n%s", text, todo)
+		}
+		last = idx
+	}
+}
+
+// writeVaultNotes writes count daily-style notes into year/month directories
+// under dir, each with a mix of pending, completed, cancelled and overdue
+// tasks.
+func writeVaultNotes(tb testing.TB, dir string, count int) {
+	tb.Helper()
+
+	for i := 0; i < count; i++ {
+		content := fmt.Sprintf(`# Daily Note - %d
+
+## Tasks
+
+- [ ] Complete project setup <!-- id: a%07d -->
+- [x] Initial commit @completed(2025-01-02)
+- [-] Dropped idea
+- [ ] Pay invoice due: 2020-01-%02d
+  - [ ] Nested step %d
+
+## Notes
+
+Discussion about the sprint planning and roadmap.
+`, i, i, i%28+1, i)
+		subDir := filepath.Join(dir, fmt.Sprintf("%d/%02d", 2024+i%3, i%12+1))
+		if err := os.MkdirAll(subDir, 0755); err != nil {
+			tb.Fatalf("Failed to create subdir: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(subDir, fmt.Sprintf("note-%d.md", i)), []byte(content), 0644); err != nil {
+			tb.Fatalf("Failed to create note %d: %v", i, err)
+		}
+	}
+}
+
+func TestTaskService_CountAllTasks_MatchesCountTasks(t *testing.T) {
+	dir := t.TempDir()
+	writeVaultNotes(t, dir, 25)
+
+	got, noteCount, err := NewTaskService().CountAllTasks(context.Background(), dir, tasks.Options{})
+	if err != nil {
+		t.Fatalf("CountAllTasks() error = %v", err)
+	}
+
+	allNotes, err := notes.FindNotes(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("FindNotes() error = %v", err)
+	}
+	var want tasks.Tally
+	for _, notePath := range allNotes {
+		noteTasks, err := tasks.ReadTasks(context.Background(), notePath)
+		if err != nil {
+			t.Fatalf("ReadTasks() error = %v", err)
+		}
+		total, completed, pending := tasks.CountTasks(noteTasks)
+		want.Total += total
+		want.Completed += completed
+		want.Pending += pending
+		for _, task := range noteTasks {
+			if tasks.IsOverdue(task) {
+				want.Overdue++
+			}
+		}
+	}
+
+	if got != want {
+		t.Errorf("CountAllTasks() = %+v, want %+v", got, want)
+	}
+	if noteCount != len(allNotes) {
+		t.Errorf("CountAllTasks() read %d notes, want %d", noteCount, len(allNotes))
+	}
+	if want.Overdue == 0 || want.Completed == 0 || want.Total == want.Pending+want.Completed {
+		t.Errorf("fixture should include overdue, completed and cancelled tasks: %+v", want)
+	}
+}
+
+func BenchmarkCountAllTasks_Large(b *testing.B) {
+	dir := b.TempDir()
+	writeVaultNotes(b, dir, 250)
+
+	service := NewTaskService()
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, _, err := service.CountAllTasks(ctx, dir, tasks.Options{}); err != nil {
+			b.Fatal(err)
+		}
 	}
 }
@@ -4,13 +4,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"sort"
 	"strings"
 	"time"
 
+	"github.com/AnishShah1803/jotr/internal/config"
 	"github.com/AnishShah1803/jotr/internal/constants"
 	"github.com/AnishShah1803/jotr/internal/notes"
 	"github.com/AnishShah1803/jotr/internal/state"
@@ -19,21 +22,176 @@ import (
 )
 
 // TaskService provides task management operations.
-type TaskService struct{}
+type TaskService struct {
+	now func() time.Time
+	// rename replaces os.Rename when committing sync writes, so tests can
+	// simulate a failed write.
+	rename func(oldpath, newpath string) error
+}
 
 // NewTaskService creates a new TaskService instance.
 func NewTaskService() *TaskService {
 	return &TaskService{}
 }
 
+// NewTaskServiceWithClock creates a TaskService whose notion of "today",
+// used for daily note targeting and completion dates, comes from now. This
+// lets tests use a fixed date and lets past days be backfilled.
+func NewTaskServiceWithClock(now func() time.Time) *TaskService {
+	return &TaskService{now: now}
+}
+
+// Now returns the service's current time, which is the date being synced
+// for a service created with NewTaskServiceWithClock.
+func (s *TaskService) Now() time.Time {
+	return s.clock()
+}
+
+// clock returns the service's current time.
+func (s *TaskService) clock() time.Time {
+	if s.now != nil {
+		return s.now()
+	}
+	return time.Now()
+}
+
+// readState reads the state file and points its clock at the service's.
+func (s *TaskService) readState(statePath string) (*state.TodoState, error) {
+	todoState, err := state.Read(statePath)
+	if err != nil {
+		return nil, err
+	}
+	todoState.SetClock(s.now)
+	return todoState, nil
+}
+
+// TodoFormat controls how the todo file is rendered. Zero values fall back
+// to the defaults: a "# To-Do List" title and "## " section headings.
+type TodoFormat struct {
+	Title        string
+	SectionLevel int
+	// RelativeDates shows recent completion date sections as "Today",
+	// "Yesterday" and "This Week" instead of YYYY-MM-DD.
+	RelativeDates bool
+	// PreserveOrder writes the tasks of each section in the order they had
+	// in the todo file (state.TaskState.Order), with new tasks last.
+	PreserveOrder bool
+	// Tasks sets how task lines are parsed and written, in the todo file and
+	// in the daily notes synced with it.
+	Tasks tasks.Options
+}
+
+// TaskOptions returns the task parsing and formatting options configured in
+// format.
+func TaskOptions(format config.FormatConfig) tasks.Options {
+	return tasks.Options{
+		StatusMarkers:     format.CheckboxMarkers,
+		SectionPriorities: format.SectionPriorities,
+		IDTemplate:        format.TaskIDFormat,
+		IDLength:          format.TaskIDLength,
+		CompletedStyle:    format.CompletedStyle,
+	}
+}
+
+// NewTodoFormat returns the todo file layout configured in format.
+func NewTodoFormat(format config.FormatConfig) TodoFormat {
+	return TodoFormat{
+		Title:         format.TodoTitle,
+		SectionLevel:  format.TodoSectionLevel,
+		RelativeDates: format.TodoRelativeDates,
+		PreserveOrder: format.PreserveTaskOrder,
+		Tasks:         TaskOptions(format),
+	}
+}
+
+func (f TodoFormat) title() string {
+	if f.Title == "" {
+		return "To-Do List"
+	}
+	return f.Title
+}
+
+func (f TodoFormat) sectionLevel() int {
+	if f.SectionLevel < 1 || f.SectionLevel > 6 {
+		return 2
+	}
+	return f.SectionLevel
+}
+
+// ParseTasks parses tasks from todo file content, with sections at
+// SectionLevel.
+func (f TodoFormat) ParseTasks(content string) []tasks.Task {
+	return f.Tasks.ParseTasksWithSectionLevel(content, f.sectionLevel())
+}
+
+// ReadTasks reads the tasks in the todo file at path, with sections at
+// SectionLevel.
+func (f TodoFormat) ReadTasks(ctx context.Context, path string) ([]tasks.Task, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return f.ParseTasks(string(content)), nil
+}
+
+func (f TodoFormat) sectionPrefix() string {
+	return strings.Repeat("#", f.sectionLevel()) + " "
+}
+
+// readTodoTasks reads tasks from the todo file using its configured section
+// level, with due dates normalized.
+func (s *TaskService) readTodoTasks(ctx context.Context, todoPath string, format TodoFormat) ([]tasks.Task, error) {
+	todoTasks, err := format.ReadTasks(ctx, todoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range todoTasks {
+		todoTasks[i].Text = tasks.NormalizeDueDate(todoTasks[i].Text)
+	}
+
+	return todoTasks, nil
+}
+
 // SyncOptions contains options for syncing tasks.
 type SyncOptions struct {
 	DiaryPath   string
 	TodoPath    string
 	StatePath   string
 	TaskSection string
+	TodoFormat  TodoFormat
 	LockTimeout time.Duration
 	DryRun      bool
+	// ConfirmDeletions withholds tasks detected as deleted from both sources.
+	// They are reported in SyncResult.PendingDeletions and kept in state so
+	// the caller can confirm and sync again without this option.
+	ConfirmDeletions bool
+	// HideCompleted leaves completed tasks out of the regenerated todo file.
+	// They stay in state until archived.
+	HideCompleted bool
+	// BaseDir is the notes directory holding the Archive folder used by
+	// AutoArchiveAfterDays.
+	BaseDir string
+	// AutoArchiveAfterDays archives completed tasks finished more than this
+	// many days ago once a sync succeeds. Zero disables it.
+	AutoArchiveAfterDays int
+	// PruneRemovedAfterDays archives completed tasks that were removed from
+	// both the daily note and the todo file once they were completed more
+	// than this many days ago, writing them to the Archive folder under
+	// BaseDir. Zero keeps them indefinitely.
+	PruneRemovedAfterDays int
+	// LinkDuplicates gives a daily task without an embedded ID the ID of an
+	// active task in state with the same text, instead of tracking it as a
+	// new task. This catches tasks copied from one daily note to another.
+	LinkDuplicates bool
+	// Resolutions settles conflicts found by an earlier sync, mapping a task
+	// ID to state.ResolveDaily or state.ResolveTodo.
+	Resolutions map[string]string
 }
 
 // SyncResult contains the result of a sync operation.
@@ -56,8 +214,26 @@ type SyncResult struct {
 	UpdatedFromTodo    []state.TaskChangeDetail `json:"updated_from_todo,omitempty"`
 	DeletedTasksDetail []state.TaskChangeDetail `json:"deleted_tasks_detail,omitempty"`
 	ConflictsDetail    []state.ConflictDetail   `json:"conflicts_detail,omitempty"`
+	// PendingDeletions lists deletions withheld by SyncOptions.ConfirmDeletions.
+	PendingDeletions []state.TaskChangeDetail `json:"pending_deletions,omitempty"`
+	// AutoArchived counts tasks archived by SyncOptions.AutoArchiveAfterDays.
+	AutoArchived int `json:"auto_archived,omitempty"`
+	// PrunedTaskIDs lists completed tasks archived by
+	// SyncOptions.PruneRemovedAfterDays.
+	PrunedTaskIDs []string `json:"pruned_task_ids,omitempty"`
+	// ReconciledIDs lists state entries moved to the ID embedded in their
+	// task after it was changed by hand.
+	ReconciledIDs []state.IDMigration `json:"reconciled_ids,omitempty"`
+
+	// Warnings lists problems that did not stop the sync but may need attention,
+	// such as a daily note without the configured task section.
+	Warnings []string `json:"warnings,omitempty"`
 }
 
+// errTaskSectionMissing is returned when a daily note has no task section to
+// write synced tasks back into.
+var errTaskSectionMissing = errors.New("task section not found")
+
 // acquireSyncLocks acquires locks on state, todo, and daily note files in the correct order.
 // Returns a slice of file handles that must be released in reverse order.
 // Lock order: state file → todo file → daily note
@@ -78,6 +254,13 @@ func (s *TaskService) acquireSyncLocks(statePath, todoPath, notePath string, tim
 			return nil, fmt.Errorf("failed to acquire lock on state file: %w", err)
 		}
 		locks = append(locks, lockFile)
+
+		// Save a state file written by an older version the first time it
+		// is locked, rather than waiting for a command that changes it.
+		if err := state.SaveMigration(statePath); err != nil {
+			utils.UnlockFile(lockFile)
+			return nil, fmt.Errorf("failed to save migrated state file: %w", err)
+		}
 	}
 
 	// Lock todo file
@@ -120,12 +303,36 @@ func (s *TaskService) isLockTimeoutError(err error) bool {
 
 // SyncTasks performs bidirectional sync between daily notes and todo list.
 func (s *TaskService) SyncTasks(ctx context.Context, opts SyncOptions) (*SyncResult, error) {
+	result, err := s.syncTasks(ctx, opts)
+	if err != nil || len(result.Conflicts) > 0 || opts.DryRun || opts.AutoArchiveAfterDays <= 0 {
+		return result, err
+	}
+
+	// syncTasks has released its locks by now; ArchiveTasks takes them again.
+	archived, err := s.ArchiveTasks(ctx, ArchiveOptions{
+		TodoPath:        opts.TodoPath,
+		StatePath:       opts.StatePath,
+		BaseDir:         opts.BaseDir,
+		TodoFormat:      opts.TodoFormat,
+		LockTimeout:     opts.LockTimeout,
+		CompletedBefore: s.clock().AddDate(0, 0, -opts.AutoArchiveAfterDays).Format("2006-01-02"),
+		HideCompleted:   opts.HideCompleted,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("tasks synced but auto-archive failed: %w", err)
+	}
+	result.AutoArchived = archived.ArchivedCount
+
+	return result, nil
+}
+
+func (s *TaskService) syncTasks(ctx context.Context, opts SyncOptions) (*SyncResult, error) {
 	result := &SyncResult{
 		StatePath: opts.StatePath,
 		TodoPath:  opts.TodoPath,
 	}
 
-	today := time.Now()
+	today := s.clock()
 	notePath := notes.BuildDailyNotePath(opts.DiaryPath, today)
 	result.DailyPath = notePath
 
@@ -157,13 +364,20 @@ func (s *TaskService) SyncTasks(ctx context.Context, opts SyncOptions) (*SyncRes
 	}()
 
 	// Read all data AFTER acquiring locks to prevent race conditions
-	dailyTasks, err := tasks.ReadTasks(ctx, notePath)
+	taskOpts := opts.TodoFormat.Tasks
+	dailyTasks, err := taskOpts.ReadTasks(ctx, notePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read daily note: %w", err)
 	}
 
+	// Tasks without an embedded ID get one generated from their text.
+	unidentified := make(map[string]bool)
 	for i := range dailyTasks {
-		tasks.EnsureTaskID(&dailyTasks[i])
+		dailyTasks[i].Text = tasks.NormalizeDueDate(dailyTasks[i].Text)
+		if dailyTasks[i].ID == "" {
+			taskOpts.EnsureTaskID(&dailyTasks[i])
+			unidentified[dailyTasks[i].ID] = true
+		}
 	}
 
 	taskSection := opts.TaskSection
@@ -171,6 +385,11 @@ func (s *TaskService) SyncTasks(ctx context.Context, opts SyncOptions) (*SyncRes
 		taskSection = "Tasks"
 	}
 
+	if !hasTaskSection(notePath, taskSection) {
+		result.Warnings = append(result.Warnings,
+			fmt.Sprintf("today's note has no \"## %s\" section, so its tasks were not synced: %s", taskSection, notePath))
+	}
+
 	var activeDailyTasks []tasks.Task
 	for _, task := range dailyTasks {
 		if task.Section == taskSection && !task.Completed {
@@ -178,29 +397,48 @@ func (s *TaskService) SyncTasks(ctx context.Context, opts SyncOptions) (*SyncRes
 		}
 	}
 
-	todoState, err := state.Read(opts.StatePath)
+	todoState, err := s.readState(opts.StatePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read state file: %w", err)
 	}
 
 	if todoState.NeedsMigration() && utils.FileExists(opts.TodoPath) {
-		existingTasks, err := tasks.ReadTasks(ctx, opts.TodoPath)
+		existingTasks, err := s.readTodoTasks(ctx, opts.TodoPath, opts.TodoFormat)
 		if err != nil {
 			return nil, fmt.Errorf("failed to read existing tasks during migration: %w", err)
 		}
 		if len(existingTasks) > 0 {
-			todoState.MigrateFromMarkdown(existingTasks, "migration")
+			todoState.MigrateFromMarkdown(existingTasks, "migration", opts.TodoFormat.Tasks)
+		}
+	}
+
+	if opts.LinkDuplicates {
+		linked := linkDuplicateTasks(todoState, activeDailyTasks, unidentified, taskOpts)
+		for _, id := range slices.Sorted(maps.Keys(linked)) {
+			utils.VerboseLogWithContext(ctx, "sync: task %s has the same text as %s, linking it", id, linked[id])
 		}
 	}
 
 	var todoTasks []tasks.Task
 	if utils.FileExists(opts.TodoPath) {
-		todoTasks, _ = tasks.ReadTasks(ctx, opts.TodoPath)
+		todoTasks, _ = s.readTodoTasks(ctx, opts.TodoPath, opts.TodoFormat)
 	}
 
 	result.TasksRead = len(dailyTasks) + len(todoTasks)
 
-	syncResult := todoState.BidirectionalSync(activeDailyTasks, todoTasks, notePath)
+	syncOpts := state.BidirectionalSyncOptions{
+		WithholdDeletions: opts.ConfirmDeletions,
+		Resolutions:       opts.Resolutions,
+		TaskOptions:       taskOpts,
+	}
+	if opts.PruneRemovedAfterDays > 0 {
+		syncOpts.PruneCompletedBefore = today.AddDate(0, 0, -opts.PruneRemovedAfterDays).Format("2006-01-02")
+	}
+	orderChanged := opts.TodoFormat.PreserveOrder && todoState.RecordTaskOrder(todoTasks)
+
+	syncResult := todoState.BidirectionalSyncWithOptions(activeDailyTasks, todoTasks, notePath, syncOpts)
+	syncResult.StateUpdated = syncResult.StateUpdated || orderChanged
+	logSyncDecisions(ctx, syncResult)
 
 	result.Conflicts = syncResult.Conflicts
 	result.ConflictsDetail = syncResult.ConflictsDetail
@@ -209,20 +447,62 @@ func (s *TaskService) SyncTasks(ctx context.Context, opts SyncOptions) (*SyncRes
 	}
 
 	if !opts.DryRun {
+		// Stage every output first and commit them together, so a failure
+		// part way through leaves state, todo and daily notes unchanged.
+		tx := utils.NewWriteTransaction()
+		if s.rename != nil {
+			tx.Rename = s.rename
+		}
+		defer tx.Abort()
+
 		if syncResult.StateUpdated {
 			if opts.StatePath != "" {
-				if err := todoState.Write(opts.StatePath); err != nil {
+				data, err := todoState.Marshal()
+				if err != nil {
+					return nil, fmt.Errorf("failed to write state file: %w", err)
+				}
+				if err := tx.Stage(opts.StatePath, data, constants.FilePerm0644); err != nil {
 					return nil, fmt.Errorf("failed to write state file: %w", err)
 				}
 			}
 		}
 
 		if syncResult.TodoChanged {
-			if err := s.writeTodoFileFromState(opts.TodoPath, todoState, true); err != nil {
+			// Withheld deletions stay in state until confirmed, but they are
+			// missing from the todo file and must not be written back to it,
+			// or the confirming sync would find them there again
+			renderState := todoState
+			if len(syncResult.WithheldDeletions) > 0 {
+				withheld := make([]string, 0, len(syncResult.WithheldDeletions))
+				for _, detail := range syncResult.WithheldDeletions {
+					withheld = append(withheld, detail.ID)
+				}
+				renderState = todoState.WithoutTasks(withheld)
+			}
+			content := s.renderTodoFileFromState(opts.TodoPath, renderState, !opts.HideCompleted, opts.TodoFormat)
+			if err := tx.Stage(opts.TodoPath, []byte(content), constants.FilePerm0644); err != nil {
 				return nil, fmt.Errorf("failed to write todo file: %w", err)
 			}
 		}
 
+		// Pruned tasks go to the archive file like archived ones, so their
+		// history is kept outside state too
+		if len(syncResult.PrunedTaskIDs) > 0 && opts.BaseDir != "" {
+			prunedIDs := slices.Sorted(slices.Values(syncResult.PrunedTaskIDs))
+			lines := make([]string, 0, len(prunedIDs))
+			for _, id := range prunedIDs {
+				task := todoState.Tasks[id]
+				lines = append(lines, archiveLine(task.Text, task.Status, task.Completed, taskOpts))
+			}
+			archiveFile, content, err := buildArchive(opts.BaseDir, today, lines)
+			if err != nil {
+				return nil, err
+			}
+			if err := tx.Stage(archiveFile, []byte(content), constants.FilePerm0644); err != nil {
+				return nil, fmt.Errorf("failed to write archive: %w", err)
+			}
+		}
+
 		if syncResult.DailyChanged {
 			sourceFiles := make(map[string]bool)
 			for _, taskID := range syncResult.ChangedTaskIDs {
@@ -236,16 +516,33 @@ func (s *TaskService) SyncTasks(ctx context.Context, opts SyncOptions) (*SyncRes
 			}
 
 			for sourceFile := range sourceFiles {
-				sourceTasks, err := tasks.ReadTasks(ctx, sourceFile)
+				sourceTasks, err := taskOpts.ReadTasks(ctx, sourceFile)
 				if err != nil {
 					return nil, fmt.Errorf("failed to read source file %s: %w", sourceFile, err)
 				}
 
-				if err := s.updateDailyNoteFromState(sourceFile, sourceTasks, todoState, opts.TaskSection); err != nil {
+				content, err := s.renderDailyNoteFromState(sourceFile, sourceTasks, todoState, opts.TaskSection, taskOpts)
+				if err != nil {
+					if errors.Is(err, errTaskSectionMissing) {
+						if sourceFile == notePath {
+							// Already reported above for today's note.
+							continue
+						}
+						result.Warnings = append(result.Warnings,
+							fmt.Sprintf("%s has no \"## %s\" section, so task changes were not written back to it", sourceFile, taskSection))
+						continue
+					}
+					return nil, fmt.Errorf("failed to update daily note %s: %w", sourceFile, err)
+				}
+				if err := tx.Stage(sourceFile, []byte(content), constants.FilePerm0644); err != nil {
 					return nil, fmt.Errorf("failed to update daily note %s: %w", sourceFile, err)
 				}
 			}
 		}
+
+		if err := tx.Commit(); err != nil {
+			return nil, fmt.Errorf("failed to write sync changes, no files were changed: %w", err)
+		}
 	}
 
 	result.TasksFromDaily = syncResult.AppliedDaily
@@ -259,18 +556,210 @@ func (s *TaskService) SyncTasks(ctx context.Context, opts SyncOptions) (*SyncRes
 	result.AddedFromTodo = syncResult.AddedFromTodo
 	result.UpdatedFromTodo = syncResult.UpdatedFromTodo
 	result.DeletedTasksDetail = syncResult.DeletedTasks
+	result.PendingDeletions = syncResult.WithheldDeletions
+	result.PrunedTaskIDs = syncResult.PrunedTaskIDs
+	result.ReconciledIDs = syncResult.ReconciledIDs
+
+	return result, nil
+}
+
+// linkDuplicateTasks gives each daily task whose ID was generated, and is not
+// yet in state, the ID of the one active state task with the same text. It
+// returns the generated IDs that were replaced, mapped to the linked IDs.
+// Text shared by several state tasks is ambiguous and is left alone.
+func linkDuplicateTasks(todoState *state.TodoState, dailyTasks []tasks.Task, generated map[string]bool, taskOpts tasks.Options) map[string]string {
+	byText := make(map[string][]string)
+	for id, ts := range todoState.Tasks {
+		if ts.Completed || ts.ArchivedDate != "" {
+			continue
+		}
+		text := taskOpts.StripTaskID(ts.Text)
+		byText[text] = append(byText[text], id)
+	}
+
+	linked := make(map[string]string)
+	for i, task := range dailyTasks {
+		if !generated[task.ID] || todoState.HasTask(task.ID) {
+			continue
+		}
+
+		text := taskOpts.StripTaskID(task.Text)
+		if ids := byText[text]; len(ids) == 1 {
+			linked[task.ID] = ids[0]
+			dailyTasks[i].ID = ids[0]
+			dailyTasks[i].Text = text + " " + taskOpts.FormatTaskID(ids[0])
+		}
+	}
+
+	return linked
+}
+
+// logSyncDecisions logs what sync decided for each task when verbose output
+// is enabled.
+func logSyncDecisions(ctx context.Context, syncResult state.SyncResult) {
+	for _, migration := range syncResult.ReconciledIDs {
+		utils.VerboseLogWithContext(ctx, "sync: task %s moved to embedded ID %s", migration.From, migration.To)
+	}
+	logDetails := func(action string, details []state.TaskChangeDetail) {
+		for _, detail := range details {
+			utils.VerboseLogWithContext(ctx, "sync: %s task %s %q", action, detail.ID, detail.Text)
+		}
+	}
+	logDetails("added from daily note", syncResult.AddedFromDaily)
+	logDetails("updated from daily note", syncResult.UpdatedFromDaily)
+	logDetails("added from todo list", syncResult.AddedFromTodo)
+	logDetails("updated from todo list", syncResult.UpdatedFromTodo)
+	logDetails("deleted", syncResult.DeletedTasks)
+	logDetails("withheld deletion of", syncResult.WithheldDeletions)
+	for id, reason := range syncResult.Conflicts {
+		utils.VerboseLogWithContext(ctx, "sync: conflict on task %s: %s", id, reason)
+	}
+}
+
+// SourceStatus compares state with the tasks in one file.
+type SourceStatus struct {
+	Path   string `json:"path"`
+	Exists bool   `json:"exists"`
+	// NotInState lists tasks in the file that state does not know about.
+	NotInState []state.TaskChangeDetail `json:"not_in_state,omitempty"`
+	// MissingFromFile lists state tasks the file would be expected to contain.
+	MissingFromFile []state.TaskChangeDetail `json:"missing_from_file,omitempty"`
+	// Mismatches lists tasks whose fields differ between state and the file.
+	Mismatches []state.TaskChangeDetail `json:"mismatches,omitempty"`
+}
+
+// InSync reports whether the file and state agree.
+func (s SourceStatus) InSync() bool {
+	return len(s.NotInState) == 0 && len(s.MissingFromFile) == 0 && len(s.Mismatches) == 0
+}
+
+// StatusResult is the read-only comparison made by SyncStatus.
+type StatusResult struct {
+	StatePath  string       `json:"state_path"`
+	StateTasks int          `json:"state_tasks"`
+	Todo       SourceStatus `json:"todo"`
+	Daily      SourceStatus `json:"daily"`
+}
+
+// InSync reports whether both files agree with state.
+func (r *StatusResult) InSync() bool {
+	return r.Todo.InSync() && r.Daily.InSync()
+}
+
+// SyncStatus compares state with the todo file and today's daily note the way
+// SyncTasks would, without changing any of them. DryRun and ConfirmDeletions
+// are ignored.
+func (s *TaskService) SyncStatus(ctx context.Context, opts SyncOptions) (*StatusResult, error) {
+	todoState, err := s.readState(opts.StatePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state file: %w", err)
+	}
+
+	notePath := notes.BuildDailyNotePath(opts.DiaryPath, s.clock())
+	result := &StatusResult{
+		StatePath:  opts.StatePath,
+		StateTasks: len(todoState.Tasks),
+		Todo:       SourceStatus{Path: opts.TodoPath, Exists: utils.FileExists(opts.TodoPath)},
+		Daily:      SourceStatus{Path: notePath, Exists: utils.FileExists(notePath)},
+	}
+
+	if result.Todo.Exists {
+		todoTasks, err := s.readTodoTasks(ctx, opts.TodoPath, opts.TodoFormat)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read todo file: %w", err)
+		}
+		for i := range todoTasks {
+			opts.TodoFormat.Tasks.EnsureTaskID(&todoTasks[i])
+		}
+
+		expected := func(task state.TaskState) bool {
+			return task.ArchivedDate == "" && (!task.Completed || !opts.HideCompleted)
+		}
+		result.Todo.NotInState, result.Todo.MissingFromFile = diffTaskIDs(todoState, todoTasks, expected)
+		result.Todo.Mismatches = describeChanges(todoState.CompareWithTodoList(todoTasks))
+	}
+
+	if result.Daily.Exists {
+		dailyTasks, err := opts.TodoFormat.Tasks.ReadTasks(ctx, notePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read daily note: %w", err)
+		}
+
+		taskSection := opts.TaskSection
+		if taskSection == "" {
+			taskSection = "Tasks"
+		}
+
+		var sectionTasks []tasks.Task
+		for _, task := range dailyTasks {
+			if task.Section == taskSection {
+				opts.TodoFormat.Tasks.EnsureTaskID(&task)
+				sectionTasks = append(sectionTasks, task)
+			}
+		}
+
+		expected := func(task state.TaskState) bool {
+			return task.Source == notePath
+		}
+		result.Daily.NotInState, result.Daily.MissingFromFile = diffTaskIDs(todoState, sectionTasks, expected)
+
+		var modified []state.TaskChange
+		for _, change := range todoState.CompareWithDailyNotes(sectionTasks, notePath) {
+			if change.ChangeType == state.Modified {
+				modified = append(modified, change)
+			}
+		}
+		result.Daily.Mismatches = describeChanges(modified)
+	}
 
 	return result, nil
 }
 
-func (s *TaskService) updateDailyNoteFromState(notePath string, dailyTasks []tasks.Task, todoState *state.TodoState, taskSection string) error {
+// diffTaskIDs returns the file tasks missing from state, and the state tasks
+// accepted by expected that are missing from the file, both sorted by ID.
+func diffTaskIDs(todoState *state.TodoState, fileTasks []tasks.Task, expected func(state.TaskState) bool) (notInState, missing []state.TaskChangeDetail) {
+	inFile := make(map[string]bool, len(fileTasks))
+	for _, task := range fileTasks {
+		inFile[task.ID] = true
+		if !todoState.HasTask(task.ID) {
+			notInState = append(notInState, state.TaskChangeDetail{ID: task.ID, Text: task.Text, Change: "added"})
+		}
+	}
+
+	for id, task := range todoState.Tasks {
+		if !inFile[id] && expected(task) {
+			missing = append(missing, state.TaskChangeDetail{ID: id, Text: task.Text, Change: "deleted"})
+		}
+	}
+
+	sortDetails(notInState)
+	sortDetails(missing)
+	return notInState, missing
+}
+
+func describeChanges(changes []state.TaskChange) []state.TaskChangeDetail {
+	var details []state.TaskChangeDetail
+	for _, change := range changes {
+		details = append(details, state.DescribeChange(change))
+	}
+	sortDetails(details)
+	return details
+}
+
+func sortDetails(details []state.TaskChangeDetail) {
+	sort.Slice(details, func(i, j int) bool {
+		return details[i].ID < details[j].ID
+	})
+}
+
+func (s *TaskService) renderDailyNoteFromState(notePath string, dailyTasks []tasks.Task, todoState *state.TodoState, taskSection string, taskOpts tasks.Options) (string, error) {
 	if taskSection == "" {
 		taskSection = "Tasks"
 	}
 
 	noteContent, err := os.ReadFile(notePath)
 	if err != nil {
-		return fmt.Errorf("failed to read daily note: %w", err)
+		return "", fmt.Errorf("failed to read daily note: %w", err)
 	}
 
 	lines := strings.Split(string(noteContent), "\n")
@@ -300,7 +789,7 @@ func (s *TaskService) updateDailyNoteFromState(notePath string, dailyTasks []tas
 					for _, task := range dailyTasks {
 						if task.Section == taskSection {
 							if stateTask, exists := todoState.Tasks[task.ID]; exists {
-								taskLine := s.formatTaskLine(stateTask)
+								taskLine := s.formatTaskLine(stateTask, taskOpts)
 								updatedLines = append(updatedLines, taskLine)
 							}
 						}
@@ -316,7 +805,7 @@ func (s *TaskService) updateDailyNoteFromState(notePath string, dailyTasks []tas
 	}
 
 	if !sectionFound {
-		return nil
+		return "", errTaskSectionMissing
 	}
 
 	content := strings.Join(updatedLines, "\n")
@@ -324,95 +813,433 @@ func (s *TaskService) updateDailyNoteFromState(notePath string, dailyTasks []tas
 		content += "\n"
 	}
 
-	if err := utils.AtomicWriteFile(notePath, []byte(content), constants.FilePerm0644); err != nil {
-		return fmt.Errorf("failed to write daily note: %w", err)
+	return content, nil
+}
+
+// taskBlock is a top-level task line together with the indented lines
+// (subtasks, continuation text) that follow it.
+type taskBlock struct {
+	task  tasks.Task
+	lines []string
+}
+
+// sortSectionTasks reorders the top-level tasks under the "## section"
+// heading with tasks.SortByPriority. Indented lines move with the task above
+// them; other lines in the section and everything outside it stay in place.
+// It reports whether the content changed.
+func sortSectionTasks(content, section string, taskOpts tasks.Options) (string, bool) {
+	lines := strings.Split(content, "\n")
+	heading := "## " + section
+
+	start := -1
+	end := len(lines)
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if start == -1 {
+			if trimmed == heading {
+				start = i + 1
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "# ") || strings.HasPrefix(trimmed, "## ") {
+			end = i
+			break
+		}
+	}
+	if start == -1 {
+		return content, false
+	}
+
+	// Collect the task blocks and remember where each one starts.
+	var blocks []taskBlock
+	var blockStarts []int
+	for i := start; i < end; i++ {
+		line := lines[i]
+		if line != strings.TrimLeft(line, " \t") {
+			continue
+		}
+		parsed := taskOpts.ParseTasks(line)
+		if len(parsed) == 0 {
+			continue
+		}
+
+		block := taskBlock{task: parsed[0], lines: []string{line}}
+		for j := i + 1; j < end && lines[j] != "" && lines[j] != strings.TrimLeft(lines[j], " \t"); j++ {
+			block.lines = append(block.lines, lines[j])
+		}
+		blocks = append(blocks, block)
+		blockStarts = append(blockStarts, i)
+	}
+
+	// Sort copies of the tasks whose Line is the index of their block.
+	sorted := make([]tasks.Task, len(blocks))
+	for i, block := range blocks {
+		sorted[i] = block.task
+		sorted[i].Line = i
 	}
+	tasks.SortByPriority(sorted)
 
-	return nil
+	changed := false
+	for i, task := range sorted {
+		if task.Line != i {
+			changed = true
+			break
+		}
+	}
+	if !changed {
+		return content, false
+	}
+
+	result := append([]string{}, lines[:start]...)
+	next := 0
+	for i := start; i < end; {
+		if next < len(blockStarts) && i == blockStarts[next] {
+			result = append(result, blocks[sorted[next].Line].lines...)
+			i += len(blocks[next].lines)
+			next++
+			continue
+		}
+		result = append(result, lines[i])
+		i++
+	}
+	result = append(result, lines[end:]...)
+
+	return strings.Join(result, "\n"), true
 }
 
-func (s *TaskService) formatTaskLine(stateTask state.TaskState) string {
-	var sb strings.Builder
-	if stateTask.Completed {
-		sb.WriteString("- [x] ")
-	} else {
-		sb.WriteString("- [ ] ")
+// SortDailyTasks reorders the tasks in a daily note's task section by
+// priority and then due date. It reports whether the note was rewritten.
+func (s *TaskService) SortDailyTasks(notePath, taskSection string, taskOpts tasks.Options) (bool, error) {
+	if taskSection == "" {
+		taskSection = "Tasks"
+	}
+
+	content, err := os.ReadFile(notePath)
+	if err != nil {
+		return false, fmt.Errorf("failed to read daily note: %w", err)
+	}
+
+	if !hasTaskSection(notePath, taskSection) {
+		return false, fmt.Errorf("%w: %s", errTaskSectionMissing, taskSection)
+	}
+
+	sorted, changed := sortSectionTasks(string(content), taskSection, taskOpts)
+	if !changed {
+		return false, nil
+	}
+
+	if err := utils.AtomicWriteFile(notePath, []byte(sorted), constants.FilePerm0644); err != nil {
+		return false, fmt.Errorf("failed to write daily note: %w", err)
+	}
+
+	return true, nil
+}
+
+// hasTaskSection reports whether the note at notePath has a "## " heading
+// named taskSection.
+func hasTaskSection(notePath, taskSection string) bool {
+	content, err := os.ReadFile(notePath)
+	if err != nil {
+		return false
+	}
+
+	for _, line := range strings.Split(string(content), "\n") {
+		if strings.TrimSpace(line) == "## "+taskSection {
+			return true
+		}
 	}
 
+	return false
+}
+
+func (s *TaskService) formatTaskLine(stateTask state.TaskState, taskOpts tasks.Options) string {
+	var sb strings.Builder
+	sb.WriteString("- [" + taskOpts.CheckboxMarker(stateTask.Status, stateTask.Completed) + "] ")
+
 	// Strip any existing ID comments and @completed tags from text to avoid duplication
-	text := tasks.StripCompletedTag(tasks.StripTaskID(stateTask.Text))
+	text := tasks.StripDuplicateTags(tasks.StripCompletedTag(taskOpts.StripTaskID(stateTask.Text)))
 	sb.WriteString(text)
 
+	if meta := tasks.FormatMeta(text, stateTask.Meta); meta != "" {
+		sb.WriteString(" " + meta)
+	}
+
 	if stateTask.ID != "" {
-		sb.WriteString(fmt.Sprintf(" <!-- id: %s -->", stateTask.ID))
+		sb.WriteString(" " + taskOpts.FormatTaskID(stateTask.ID))
 	}
 
 	if stateTask.Completed && stateTask.CompletedDate != "" {
-		sb.WriteString(fmt.Sprintf(" @completed(%s)", stateTask.CompletedDate))
+		sb.WriteString(taskOpts.FormatCompletedDate(stateTask.CompletedDate))
 	}
 
 	return sb.String()
 }
 
-// writeTodoFileFromState generates and writes the todo markdown file from state.
-func (s *TaskService) writeTodoFileFromState(todoPath string, todoState *state.TodoState, includeCompleted bool) error {
-	var content strings.Builder
-	content.WriteString("# To-Do List\n\n")
+// todoProse holds the non-task content of an existing todo file.
+type todoProse struct {
+	// Preamble is the content between the title and the first section.
+	Preamble []string
+	// Sections maps a section name to the non-task lines found under it.
+	Sections map[string][]string
+}
 
-	var tasksToWrite []state.TaskState
-	if includeCompleted {
-		for _, ts := range todoState.Tasks {
-			tasksToWrite = append(tasksToWrite, ts)
-		}
-	} else {
-		tasksToWrite = todoState.GetActiveTasks()
+// readTodoProse extracts the non-task content from an existing todo file so
+// it can be preserved when the file is regenerated from state. The title line,
+// section headings and task lines are dropped; everything else is kept with
+// leading and trailing blank lines trimmed. A missing file yields no prose.
+func readTodoProse(todoPath string, format TodoFormat) todoProse {
+	prose := todoProse{Sections: make(map[string][]string)}
+
+	content, err := os.ReadFile(todoPath)
+	if err != nil {
+		return prose
 	}
 
-	sections := make(map[string][]state.TaskState)
-	for _, task := range tasksToWrite {
-		var section string
-		// If task is completed and has a CompletedDate, use that as the section
-		if task.Completed && task.CompletedDate != "" {
-			section = task.CompletedDate
-		} else {
-			section = task.Section
-			if section == "" {
-				section = "Tasks"
-			}
-		}
-		sections[section] = append(sections[section], task)
+	taskLines := make(map[int]bool)
+	for _, task := range format.ParseTasks(string(content)) {
+		taskLines[task.Line] = true
 	}
 
-	var sectionNames []string
-	for name := range sections {
-		sectionNames = append(sectionNames, name)
+	sectionPrefix := format.sectionPrefix()
+	currentSection := ""
+	seenTitle := false
+
+	for i, line := range strings.Split(string(content), "\n") {
+		switch {
+		case !seenTitle && strings.HasPrefix(line, "# ") && len(prose.Preamble) == 0:
+			seenTitle = true
+		case strings.HasPrefix(line, sectionPrefix):
+			currentSection = strings.TrimPrefix(line, sectionPrefix)
+			seenTitle = true
+		case taskLines[i+1]:
+		case currentSection == "":
+			prose.Preamble = append(prose.Preamble, line)
+		default:
+			prose.Sections[currentSection] = append(prose.Sections[currentSection], line)
+		}
 	}
 
-	dateRegex := regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+	prose.Preamble = trimBlankLines(prose.Preamble)
+	for name, lines := range prose.Sections {
+		if lines = trimBlankLines(lines); len(lines) > 0 {
+			prose.Sections[name] = lines
+		} else {
+			delete(prose.Sections, name)
+		}
+	}
 
-	sort.Slice(sectionNames, func(i, j int) bool {
-		dateI := dateRegex.MatchString(sectionNames[i])
-		dateJ := dateRegex.MatchString(sectionNames[j])
+	return prose
+}
 
-		if dateI && dateJ {
-			return sectionNames[i] > sectionNames[j]
+// todoSectionExists reports whether section is a heading in the todo file or
+// already holds tasks in state.
+func todoSectionExists(todoPath string, todoState *state.TodoState, section string, format TodoFormat) bool {
+	for _, task := range todoState.Tasks {
+		if task.Section == section {
+			return true
 		}
-		return dateI && !dateJ
-	})
+	}
+
+	content, err := os.ReadFile(todoPath)
+	if err != nil {
+		return false
+	}
 
-	for _, sectionName := range sectionNames {
-		content.WriteString(fmt.Sprintf("## %s\n\n", sectionName))
-		for _, task := range sections[sectionName] {
-			content.WriteString(s.formatTaskLine(task) + "\n")
+	heading := format.sectionPrefix() + section
+	for _, line := range strings.Split(string(content), "\n") {
+		if strings.TrimSpace(line) == heading {
+			return true
 		}
-		content.WriteString("\n")
 	}
 
-	if err := utils.AtomicWriteFile(todoPath, []byte(content.String()), constants.FilePerm0644); err != nil {
-		return fmt.Errorf("failed to write todo file: %w", err)
+	return false
+}
+
+func trimBlankLines(lines []string) []string {
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
 	}
 
-	return nil
+	return lines
+}
+
+// writeTodoFileFromState generates and writes the todo markdown file from state.
+//
+// Task lines are always regenerated from state, but manual content in the
+// existing file is preserved: prose between the title and the first section
+// is kept as a preamble, and prose under a section is kept at the top of that
+// section. A section containing only prose is kept even if it has no tasks.
+func (s *TaskService) writeTodoFileFromState(todoPath string, todoState *state.TodoState, includeCompleted bool, format TodoFormat) error {
+	content := s.renderTodoFileFromState(todoPath, todoState, includeCompleted, format)
+	if err := utils.AtomicWriteFile(todoPath, []byte(content), constants.FilePerm0644); err != nil {
+		return fmt.Errorf("failed to write todo file: %w", err)
+	}
+
+	return nil
+}
+
+// renderTodoFileFromState builds the todo file contents written by
+// writeTodoFileFromState.
+func (s *TaskService) renderTodoFileFromState(todoPath string, todoState *state.TodoState, includeCompleted bool, format TodoFormat) string {
+	prose := readTodoProse(todoPath, format)
+
+	var content strings.Builder
+	content.WriteString(fmt.Sprintf("# %s\n\n", format.title()))
+
+	if len(prose.Preamble) > 0 {
+		content.WriteString(strings.Join(prose.Preamble, "\n") + "\n\n")
+	}
+
+	var tasksToWrite []state.TaskState
+	if includeCompleted {
+		for _, ts := range todoState.Tasks {
+			if ts.ArchivedDate == "" {
+				tasksToWrite = append(tasksToWrite, ts)
+			}
+		}
+	} else {
+		// Cancelled tasks stay listed until archived, like completed ones
+		// when they are shown, so sync does not see them as deleted
+		for _, ts := range todoState.Tasks {
+			if !ts.Completed && ts.ArchivedDate == "" {
+				tasksToWrite = append(tasksToWrite, ts)
+			}
+		}
+	}
+
+	sections := make(map[string][]state.TaskState)
+	for _, task := range tasksToWrite {
+		var section string
+		// If task is completed and has a CompletedDate, use that as the section
+		if task.Completed && task.CompletedDate != "" {
+			section = task.CompletedDate
+		} else {
+			section = task.Section
+			if section == "" {
+				section = "Tasks"
+			}
+		}
+		sections[section] = append(sections[section], task)
+	}
+
+	for name := range prose.Sections {
+		if _, ok := sections[name]; !ok {
+			sections[name] = nil
+		}
+	}
+
+	var sectionNames []string
+	for name := range sections {
+		sectionNames = append(sectionNames, name)
+	}
+
+	sort.Slice(sectionNames, func(i, j int) bool {
+		dateI := dateSectionRegex.MatchString(sectionNames[i])
+		dateJ := dateSectionRegex.MatchString(sectionNames[j])
+
+		if dateI && dateJ {
+			return sectionNames[i] > sectionNames[j]
+		}
+		return dateI && !dateJ
+	})
+
+	for _, heading := range format.headings(sectionNames, s.clock()) {
+		var headingTasks []state.TaskState
+		for _, sectionName := range heading.sections {
+			headingTasks = append(headingTasks, sections[sectionName]...)
+		}
+
+		content.WriteString(fmt.Sprintf("%s%s\n\n", format.sectionPrefix(), heading.label))
+		if lines := prose.Sections[heading.label]; len(lines) > 0 {
+			content.WriteString(strings.Join(lines, "\n") + "\n\n")
+		}
+		if format.PreserveOrder {
+			sortByTodoOrder(headingTasks)
+		}
+		for _, task := range headingTasks {
+			content.WriteString(s.formatTaskLine(task, format.Tasks) + "\n")
+		}
+		if len(headingTasks) > 0 {
+			content.WriteString("\n")
+		}
+	}
+
+	return content.String()
+}
+
+// sortByTodoOrder orders tasks by their line in the todo file. Tasks that
+// have not been in the todo file yet go last, oldest first.
+func sortByTodoOrder(taskList []state.TaskState) {
+	sort.Slice(taskList, func(i, j int) bool {
+		oi, oj := taskList[i].Order, taskList[j].Order
+		if (oi == 0) != (oj == 0) {
+			return oj == 0
+		}
+		if oi != oj {
+			return oi < oj
+		}
+		if !taskList[i].CreatedAt.Equal(taskList[j].CreatedAt) {
+			return taskList[i].CreatedAt.Before(taskList[j].CreatedAt)
+		}
+		return taskList[i].ID < taskList[j].ID
+	})
+}
+
+// dateSectionRegex matches a YYYY-MM-DD completion date section.
+var dateSectionRegex = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+
+// todoHeading is a heading in the todo file and the sections it holds.
+type todoHeading struct {
+	label    string
+	sections []string
+}
+
+// headings groups sorted section names under the headings they are written
+// with. Without RelativeDates every section is its own heading. With it,
+// recent date sections share a relative label, and a section named like a
+// label is merged into that heading.
+func (f TodoFormat) headings(sectionNames []string, now time.Time) []todoHeading {
+	var headings []todoHeading
+	index := make(map[string]int)
+
+	for _, name := range sectionNames {
+		label := name
+		if f.RelativeDates && dateSectionRegex.MatchString(name) {
+			label = relativeDateLabel(name, now)
+		}
+
+		if i, ok := index[label]; ok {
+			headings[i].sections = append(headings[i].sections, name)
+			continue
+		}
+		index[label] = len(headings)
+		headings = append(headings, todoHeading{label: label, sections: []string{name}})
+	}
+
+	return headings
+}
+
+// relativeDateLabel returns the relative heading for a YYYY-MM-DD date: today,
+// yesterday, or earlier in the current week (starting Monday). Other dates
+// are returned unchanged.
+func relativeDateLabel(date string, now time.Time) string {
+	today := now.Format("2006-01-02")
+	yesterday := now.AddDate(0, 0, -1).Format("2006-01-02")
+	weekStart := now.AddDate(0, 0, -((int(now.Weekday()) + 6) % 7)).Format("2006-01-02")
+
+	switch {
+	case date == today:
+		return tasks.SectionToday
+	case date == yesterday:
+		return tasks.SectionYesterday
+	case date >= weekStart && date < today:
+		return tasks.SectionThisWeek
+	default:
+		return date
+	}
 }
 
 // ArchiveOptions contains options for archiving tasks.
@@ -420,7 +1247,18 @@ type ArchiveOptions struct {
 	TodoPath    string
 	StatePath   string
 	BaseDir     string
+	TodoFormat  TodoFormat
 	LockTimeout time.Duration // Timeout for acquiring file locks (default: 10s)
+	// CompletedBefore, a YYYY-MM-DD date, limits archiving to tasks completed
+	// before it. Empty archives every completed task.
+	CompletedBefore string
+	// HideCompleted leaves completed tasks that were not archived out of the
+	// rewritten todo file.
+	HideCompleted bool
+	// NotePath, when set, archives the completed tasks in this note instead
+	// of those in state. They are removed from the note and marked archived
+	// in state; the todo file is not touched.
+	NotePath string
 }
 
 // ArchiveResult contains the result of an archive operation.
@@ -432,6 +1270,10 @@ type ArchiveResult struct {
 
 // ArchiveTasks moves completed tasks to an archive file using state as source of truth.
 func (s *TaskService) ArchiveTasks(ctx context.Context, opts ArchiveOptions) (*ArchiveResult, error) {
+	if opts.NotePath != "" {
+		return s.archiveNoteTasks(ctx, opts)
+	}
+
 	result := &ArchiveResult{}
 
 	lockTimeout := opts.LockTimeout
@@ -454,22 +1296,36 @@ func (s *TaskService) ArchiveTasks(ctx context.Context, opts ArchiveOptions) (*A
 		}
 	}()
 
-	todoState, err := state.Read(opts.StatePath)
+	todoState, err := s.readState(opts.StatePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read state file: %w", err)
 	}
 
 	if todoState.NeedsMigration() && utils.FileExists(opts.TodoPath) {
-		existingTasks, err := tasks.ReadTasks(ctx, opts.TodoPath)
+		existingTasks, err := s.readTodoTasks(ctx, opts.TodoPath, opts.TodoFormat)
 		if err != nil {
 			return nil, fmt.Errorf("failed to read existing tasks during migration: %w", err)
 		}
 		if len(existingTasks) > 0 {
-			todoState.MigrateFromMarkdown(existingTasks, "migration")
+			todoState.MigrateFromMarkdown(existingTasks, "migration", opts.TodoFormat.Tasks)
 		}
 	}
 
-	completedTasks := todoState.GetCompletedTasks()
+	// Cancelled tasks are finished too, so they are archived with the
+	// completed ones
+	var completedTasks []state.TaskState
+	for _, task := range append(todoState.GetCompletedTasks(), todoState.GetCancelledTasks()...) {
+		if task.ArchivedDate != "" {
+			continue
+		}
+		if opts.CompletedBefore != "" && (task.CompletedDate == "" || task.CompletedDate >= opts.CompletedBefore) {
+			continue
+		}
+		completedTasks = append(completedTasks, task)
+	}
+	sort.Slice(completedTasks, func(i, j int) bool {
+		return completedTasks[i].ID < completedTasks[j].ID
+	})
 	activeTasks := todoState.GetActiveTasks()
 
 	if len(completedTasks) == 0 {
@@ -478,11 +1334,177 @@ func (s *TaskService) ArchiveTasks(ctx context.Context, opts ArchiveOptions) (*A
 		return result, nil
 	}
 
-	now := time.Now()
+	now := s.clock()
+
+	lines := make([]string, 0, len(completedTasks))
+	for _, task := range completedTasks {
+		lines = append(lines, archiveLine(task.Text, task.Status, task.Completed, opts.TodoFormat.Tasks))
+	}
+	archiveFile, err := appendToArchive(opts.BaseDir, now, lines)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, task := range completedTasks {
+		task.ArchivedDate = now.Format("2006-01-02")
+		todoState.Tasks[task.ID] = task
+	}
+
+	if err := s.writeTodoFileFromState(opts.TodoPath, todoState, !opts.HideCompleted, opts.TodoFormat); err != nil {
+		return nil, fmt.Errorf("failed to write todo file: %w", err)
+	}
+
+	todoState.MarkArchived()
+	if opts.StatePath != "" {
+		if err := todoState.Write(opts.StatePath); err != nil {
+			return nil, fmt.Errorf("failed to write state file: %w", err)
+		}
+	}
+
+	result.ArchivedCount = len(completedTasks)
+	result.RemainingCount = len(activeTasks)
+	result.ArchivePath = archiveFile
+
+	return result, nil
+}
+
+// archiveNoteTasks archives the completed and cancelled tasks in
+// opts.NotePath: it appends
+// them to the archive, removes their lines from the note and marks them
+// archived in state, adding tasks that were never synced.
+func (s *TaskService) archiveNoteTasks(ctx context.Context, opts ArchiveOptions) (*ArchiveResult, error) {
+	result := &ArchiveResult{}
+
+	lockTimeout := opts.LockTimeout
+	if lockTimeout <= 0 {
+		lockTimeout = 10 * time.Second
+	}
+	locks, err := s.acquireSyncLocks(opts.StatePath, "", opts.NotePath, lockTimeout)
+	if err != nil {
+		if s.isLockTimeoutError(err) {
+			return nil, fmt.Errorf("another archive operation is in progress. Please try again in a few seconds")
+		}
+		return nil, err
+	}
+	defer func() {
+		for i := len(locks) - 1; i >= 0; i-- {
+			utils.UnlockFile(locks[i])
+		}
+	}()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	content, err := os.ReadFile(opts.NotePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read note: %w", err)
+	}
+
+	var completedTasks []tasks.Task
+	for _, task := range opts.TodoFormat.Tasks.ParseTasks(string(content)) {
+		switch {
+		case !task.Completed && !tasks.IsCancelled(task):
+			result.RemainingCount++
+		case opts.CompletedBefore != "" && (task.CompletedDate == "" || task.CompletedDate >= opts.CompletedBefore):
+		default:
+			completedTasks = append(completedTasks, task)
+		}
+	}
+
+	if len(completedTasks) == 0 {
+		return result, nil
+	}
+
+	now := s.clock()
+	today := now.Format("2006-01-02")
+
+	lines := make([]string, 0, len(completedTasks))
+	removeLines := make(map[int]bool, len(completedTasks))
+	for _, task := range completedTasks {
+		lines = append(lines, archiveLine(task.Text, task.Status, task.Completed, opts.TodoFormat.Tasks))
+		removeLines[task.Line] = true
+	}
+	archiveFile, err := appendToArchive(opts.BaseDir, now, lines)
+	if err != nil {
+		return nil, err
+	}
+
+	var kept []string
+	for i, line := range strings.Split(string(content), "\n") {
+		if !removeLines[i+1] {
+			kept = append(kept, line)
+		}
+	}
+	if err := utils.AtomicWriteFile(opts.NotePath, []byte(strings.Join(kept, "\n")), constants.FilePerm0644); err != nil {
+		return nil, fmt.Errorf("failed to write note: %w", err)
+	}
+
+	if opts.StatePath != "" {
+		todoState, err := s.readState(opts.StatePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read state file: %w", err)
+		}
+
+		for _, task := range completedTasks {
+			if task.ID == "" {
+				continue
+			}
+			stateTask, ok := todoState.Tasks[task.ID]
+			if !ok {
+				todoState.AddTask(task, opts.NotePath)
+				stateTask = todoState.Tasks[task.ID]
+			}
+			if task.Completed && !stateTask.Completed {
+				stateTask.Completed = true
+				stateTask.CompletedAt = now
+				stateTask.CompletedDate = task.CompletedDate
+				if stateTask.CompletedDate == "" {
+					stateTask.CompletedDate = today
+				}
+			}
+			stateTask.ArchivedDate = today
+			todoState.Tasks[task.ID] = stateTask
+		}
+
+		if err := todoState.Write(opts.StatePath); err != nil {
+			return nil, fmt.Errorf("failed to write state file: %w", err)
+		}
+	}
+
+	result.ArchivedCount = len(completedTasks)
+	result.ArchivePath = archiveFile
+
+	return result, nil
+}
+
+// archiveLine formats a finished task as a line of the archive file.
+func archiveLine(text, status string, completed bool, taskOpts tasks.Options) string {
+	return fmt.Sprintf("- [%s] %s", taskOpts.CheckboxMarker(status, completed), text)
+}
+
+// appendToArchive appends task lines to this month's archive file under
+// baseDir in a section for today, and returns the archive file's path.
+func appendToArchive(baseDir string, now time.Time, lines []string) (string, error) {
+	archiveFile, archiveContent, err := buildArchive(baseDir, now, lines)
+	if err != nil {
+		return "", err
+	}
+
+	if err := utils.AtomicWriteFile(archiveFile, []byte(archiveContent), constants.FilePerm0644); err != nil {
+		return "", fmt.Errorf("failed to write archive: %w", err)
+	}
+
+	return archiveFile, nil
+}
 
-	archiveDir := filepath.Join(opts.BaseDir, "Archive")
+// buildArchive returns the path of this month's archive file under baseDir
+// and its content with task lines appended in a section for today, creating
+// the Archive directory if needed.
+func buildArchive(baseDir string, now time.Time, lines []string) (string, string, error) {
+	archiveDir := filepath.Join(baseDir, "Archive")
 	if err := notes.EnsureDir(archiveDir); err != nil {
-		return nil, fmt.Errorf("failed to create archive directory: %w", err)
+		return "", "", fmt.Errorf("failed to create archive directory: %w", err)
 	}
 
 	archiveFile := filepath.Join(archiveDir, fmt.Sprintf("archive-%s.md", now.Format("2006-01")))
@@ -491,7 +1513,7 @@ func (s *TaskService) ArchiveTasks(ctx context.Context, opts ArchiveOptions) (*A
 	if utils.FileExists(archiveFile) {
 		content, err := os.ReadFile(archiveFile)
 		if err != nil {
-			return nil, fmt.Errorf("failed to read archive: %w", err)
+			return "", "", fmt.Errorf("failed to read archive: %w", err)
 		}
 		archiveContent = string(content)
 	} else {
@@ -499,63 +1521,916 @@ func (s *TaskService) ArchiveTasks(ctx context.Context, opts ArchiveOptions) (*A
 	}
 
 	archiveContent += fmt.Sprintf("\n## Archived on %s\n\n", now.Format("2006-01-02"))
-	for _, task := range completedTasks {
-		archiveContent += fmt.Sprintf("- [x] %s\n", task.Text)
+	for _, line := range lines {
+		archiveContent += line + "\n"
 	}
 
-	if err := utils.AtomicWriteFile(archiveFile, []byte(archiveContent), constants.FilePerm0644); err != nil {
-		return nil, fmt.Errorf("failed to write archive: %w", err)
+	return archiveFile, archiveContent, nil
+}
+
+// DedupeOptions contains options for removing duplicate tasks.
+type DedupeOptions struct {
+	TodoPath    string
+	StatePath   string
+	TodoFormat  TodoFormat
+	LockTimeout time.Duration
+	DryRun      bool
+}
+
+// DedupeResult contains the result of a dedupe operation.
+type DedupeResult struct {
+	Groups  []tasks.DuplicateGroup
+	Removed int
+}
+
+// DedupeTasks removes exact duplicate tasks from the todo file, keeping the
+// first occurrence of each. Duplicates whose IDs differ from the kept task
+// are also dropped from state so they are not reintroduced by the next sync.
+func (s *TaskService) DedupeTasks(ctx context.Context, opts DedupeOptions) (*DedupeResult, error) {
+	result := &DedupeResult{}
+
+	lockTimeout := opts.LockTimeout
+	if lockTimeout <= 0 {
+		lockTimeout = 10 * time.Second
+	}
+	locks, err := s.acquireSyncLocks(opts.StatePath, opts.TodoPath, "", lockTimeout)
+	if err != nil {
+		if s.isLockTimeoutError(err) {
+			return nil, fmt.Errorf("another sync operation is in progress. Please try again in a few seconds")
+		}
+		return nil, err
+	}
+	defer func() {
+		for i := len(locks) - 1; i >= 0; i-- {
+			utils.UnlockFile(locks[i])
+		}
+	}()
+
+	content, err := os.ReadFile(opts.TodoPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read todo file: %w", err)
+	}
+
+	result.Groups = tasks.FindDuplicates(opts.TodoFormat.ParseTasks(string(content)))
+	if len(result.Groups) == 0 || opts.DryRun {
+		for _, group := range result.Groups {
+			result.Removed += len(group.Duplicates)
+		}
+		return result, nil
+	}
+
+	removeLines := make(map[int]bool)
+	var staleIDs []string
+
+	for _, group := range result.Groups {
+		for _, dup := range group.Duplicates {
+			removeLines[dup.Line] = true
+			if dup.ID != "" && dup.ID != group.Keep.ID {
+				staleIDs = append(staleIDs, dup.ID)
+			}
+			result.Removed++
+		}
 	}
 
-	if err := s.writeTodoFileFromState(opts.TodoPath, todoState, false); err != nil {
+	lines := strings.Split(string(content), "\n")
+	kept := make([]string, 0, len(lines))
+	for i, line := range lines {
+		if !removeLines[i+1] {
+			kept = append(kept, line)
+		}
+	}
+
+	if err := utils.AtomicWriteFileCtx(ctx, opts.TodoPath, []byte(strings.Join(kept, "\n")), constants.FilePerm0644); err != nil {
 		return nil, fmt.Errorf("failed to write todo file: %w", err)
 	}
 
-	todoState.MarkArchived()
-	if opts.StatePath != "" {
+	if len(staleIDs) > 0 && opts.StatePath != "" && utils.FileExists(opts.StatePath) {
+		todoState, err := s.readState(opts.StatePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read state file: %w", err)
+		}
+		for _, id := range staleIDs {
+			todoState.RemoveTask(id)
+		}
 		if err := todoState.Write(opts.StatePath); err != nil {
 			return nil, fmt.Errorf("failed to write state file: %w", err)
 		}
 	}
 
-	result.ArchivedCount = len(completedTasks)
-	result.RemainingCount = len(activeTasks)
-	result.ArchivePath = archiveFile
-
 	return result, nil
 }
 
-// GetAllTasks reads all tasks from a file.
-func (s *TaskService) GetAllTasks(ctx context.Context, todoPath string) ([]tasks.Task, error) {
-	return tasks.ReadTasks(ctx, todoPath)
+// AddTaskOptions contains options for adding a task directly to the todo list.
+type AddTaskOptions struct {
+	TodoPath    string
+	StatePath   string
+	Text        string
+	Section     string
+	Priority    string
+	TodoFormat  TodoFormat
+	LockTimeout time.Duration
+
+	// RequireExistingSection makes AddTask fail instead of creating Section
+	// when neither the todo file nor state has it yet.
+	RequireExistingSection bool
 }
 
-// GetTaskSummary returns a summary of tasks grouped by priority.
-func (s *TaskService) GetTaskSummary(ctx context.Context, todoPath string) (*tasks.Task, error) {
-	allTasks, err := tasks.ReadTasks(ctx, todoPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read tasks: %w", err)
+// AddTask adds a new task to state and regenerates the todo file. Priority
+// and #tags are embedded in the task text so they survive later syncs, due
+// dates are normalized, and the task ID is derived from that text.
+func (s *TaskService) AddTask(ctx context.Context, opts AddTaskOptions) (*tasks.Task, error) {
+	text := tasks.NormalizeDueDate(strings.TrimSpace(opts.Text))
+	if text == "" {
+		return nil, fmt.Errorf("task text cannot be empty")
+	}
+	if opts.Priority != "" {
+		text = tasks.SetPriority(text, opts.Priority)
 	}
 
-	completed := false
-	pendingTasks := tasks.FilterTasks(allTasks, &completed, "", "")
-
-	_ = tasks.GroupByPriority(pendingTasks)
-
-	if len(pendingTasks) > 0 {
-		return &pendingTasks[0], nil
+	taskOpts := opts.TodoFormat.Tasks
+	parsed := taskOpts.ParseTasks("- [ ] " + text)
+	if len(parsed) != 1 {
+		return nil, fmt.Errorf("invalid task text: %q", text)
+	}
+	task := parsed[0]
+	task.Line = 0
+	task.Section = opts.Section
+	if task.Section == "" {
+		task.Section = "Tasks"
+	}
+	taskOpts.EnsureTaskID(&task)
+	task.Text = taskOpts.StripTaskID(task.Text)
+
+	if err := s.storeNewTask(ctx, opts, task, "cli"); err != nil {
+		return nil, err
+	}
+
+	return &task, nil
+}
+
+// AddStructuredTask adds an already built task to state and regenerates the
+// todo file in one locked step, for callers embedding jotr. It returns the
+// task's ID, generating one from the text if task.ID is empty. Priority and
+// tags missing from the text are embedded in it so they survive later
+// syncs; the Text and Priority options are ignored.
+func (s *TaskService) AddStructuredTask(ctx context.Context, opts AddTaskOptions, task tasks.Task) (string, error) {
+	taskOpts := opts.TodoFormat.Tasks
+	text := tasks.NormalizeDueDate(strings.TrimSpace(taskOpts.StripTaskID(task.Text)))
+	if text == "" {
+		return "", fmt.Errorf("task text cannot be empty")
+	}
+	if task.Priority != "" {
+		text = tasks.SetPriority(text, task.Priority)
+	}
+	existingTags := taskOpts.ParseTasks("- [ ] " + text)[0].Tags
+	for _, tag := range task.Tags {
+		if !slices.Contains(existingTags, tag) {
+			text += " #" + tag
+		}
+	}
+
+	parsed := taskOpts.ParseTasks("- [ ] " + text)
+	if len(parsed) != 1 {
+		return "", fmt.Errorf("invalid task text: %q", text)
+	}
+	built := parsed[0]
+	built.Line = 0
+	built.ID = task.ID
+	built.Completed = task.Completed
+	built.Status = task.Status
+	built.Section = task.Section
+	if built.Section == "" {
+		built.Section = opts.Section
+	}
+	if built.Section == "" {
+		built.Section = "Tasks"
+	}
+	taskOpts.EnsureTaskID(&built)
+	built.Text = taskOpts.StripTaskID(built.Text)
+
+	if err := s.storeNewTask(ctx, opts, built, "api"); err != nil {
+		return "", err
+	}
+
+	return built.ID, nil
+}
+
+// storeNewTask adds task to state under the sync locks and rewrites the todo
+// file. It fails if a task with the same ID already exists.
+func (s *TaskService) storeNewTask(ctx context.Context, opts AddTaskOptions, task tasks.Task, source string) error {
+	lockTimeout := opts.LockTimeout
+	if lockTimeout <= 0 {
+		lockTimeout = 10 * time.Second
+	}
+	locks, err := s.acquireSyncLocks(opts.StatePath, opts.TodoPath, "", lockTimeout)
+	if err != nil {
+		if s.isLockTimeoutError(err) {
+			return fmt.Errorf("another sync operation is in progress. Please try again in a few seconds")
+		}
+		return err
+	}
+	defer func() {
+		for i := len(locks) - 1; i >= 0; i-- {
+			utils.UnlockFile(locks[i])
+		}
+	}()
+
+	todoState, err := s.readState(opts.StatePath)
+	if err != nil {
+		return fmt.Errorf("failed to read state file: %w", err)
+	}
+
+	if todoState.NeedsMigration() && utils.FileExists(opts.TodoPath) {
+		existingTasks, err := s.readTodoTasks(ctx, opts.TodoPath, opts.TodoFormat)
+		if err != nil {
+			return fmt.Errorf("failed to read existing tasks during migration: %w", err)
+		}
+		todoState.MigrateFromMarkdown(existingTasks, "migration", opts.TodoFormat.Tasks)
+	}
+
+	if todoState.HasTask(task.ID) {
+		return fmt.Errorf("task already exists: %s", task.Text)
+	}
+
+	if opts.RequireExistingSection && !todoSectionExists(opts.TodoPath, todoState, task.Section, opts.TodoFormat) {
+		return fmt.Errorf("section %q does not exist in %s", task.Section, opts.TodoPath)
+	}
+
+	todoState.AddTask(task, source)
+
+	if err := todoState.Write(opts.StatePath); err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+
+	if err := s.writeTodoFileFromState(opts.TodoPath, todoState, true, opts.TodoFormat); err != nil {
+		return fmt.Errorf("failed to write todo file: %w", err)
+	}
+
+	return nil
+}
+
+// PriorityOptions contains options for updating task priorities.
+type PriorityOptions struct {
+	// Priorities maps a task ID to its new priority. A task without an
+	// embedded ID is matched by the ID generated from its text, so a task
+	// edited since the IDs were taken is left alone.
+	Priorities  map[string]string
+	TodoPath    string
+	StatePath   string
+	TodoFormat  TodoFormat
+	LockTimeout time.Duration
+}
+
+// SetTaskPriorities rewrites the priority tag of the given tasks in the todo
+// file. Tasks are looked up by ID after the file is locked and read, so lines
+// that moved in the meantime are still found.
+func (s *TaskService) SetTaskPriorities(ctx context.Context, opts PriorityOptions) (int, error) {
+	lockTimeout := opts.LockTimeout
+	if lockTimeout <= 0 {
+		lockTimeout = 10 * time.Second
+	}
+	locks, err := s.acquireSyncLocks(opts.StatePath, opts.TodoPath, "", lockTimeout)
+	if err != nil {
+		if s.isLockTimeoutError(err) {
+			return 0, fmt.Errorf("another sync operation is in progress. Please try again in a few seconds")
+		}
+		return 0, err
+	}
+	defer func() {
+		for i := len(locks) - 1; i >= 0; i-- {
+			utils.UnlockFile(locks[i])
+		}
+	}()
+
+	content, err := os.ReadFile(opts.TodoPath)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read todo file: %w", err)
+	}
+
+	lines := strings.Split(string(content), "\n")
+	updated := 0
+
+	for _, task := range opts.TodoFormat.ParseTasks(string(content)) {
+		opts.TodoFormat.Tasks.EnsureTaskID(&task)
+		priority, ok := opts.Priorities[task.ID]
+		if !ok || task.Priority == priority {
+			continue
+		}
+
+		line := lines[task.Line-1]
+		start := strings.Index(line, "]") + 1
+		body := strings.TrimLeft(line[start:], " ")
+		lines[task.Line-1] = line[:start] + " " + tasks.SetPriority(body, priority)
+		updated++
+	}
+
+	if updated == 0 {
+		return 0, nil
+	}
+
+	if err := utils.AtomicWriteFileCtx(ctx, opts.TodoPath, []byte(strings.Join(lines, "\n")), constants.FilePerm0644); err != nil {
+		return 0, fmt.Errorf("failed to write todo file: %w", err)
+	}
+
+	return updated, nil
+}
+
+// MatchPendingTasks returns the pending tasks in state best matching query.
+// A task whose normalized text equals the query, or whose ID is the query,
+// is returned alone; otherwise every pending task containing all of the
+// query's words is returned, sorted by priority and text. Completed,
+// cancelled and archived tasks never match.
+func (s *TaskService) MatchPendingTasks(statePath, query string) ([]state.TaskState, error) {
+	todoState, err := s.readState(statePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state file: %w", err)
+	}
+
+	normalized := tasks.NormalizeText(query)
+	words := strings.Fields(normalized)
+	if len(words) == 0 {
+		return nil, nil
+	}
+
+	var matches []state.TaskState
+	for _, ts := range todoState.GetActiveTasks() {
+		if ts.ArchivedDate != "" {
+			continue
+		}
+
+		text := tasks.NormalizeText(ts.Text)
+		if ts.ID == strings.TrimSpace(query) || text == normalized {
+			return []state.TaskState{ts}, nil
+		}
+
+		containsAll := true
+		for _, word := range words {
+			if !strings.Contains(text, word) {
+				containsAll = false
+				break
+			}
+		}
+		if containsAll {
+			matches = append(matches, ts)
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		pi, pj := matches[i].Priority, matches[j].Priority
+		if pi != pj {
+			if pi == "" || pj == "" {
+				return pj == ""
+			}
+			return pi < pj
+		}
+		if matches[i].Text != matches[j].Text {
+			return matches[i].Text < matches[j].Text
+		}
+		return matches[i].ID < matches[j].ID
+	})
+
+	return matches, nil
+}
+
+// CompleteTaskOptions contains options for marking a task complete.
+type CompleteTaskOptions struct {
+	ID            string
+	TodoPath      string
+	StatePath     string
+	TodoFormat    TodoFormat
+	HideCompleted bool
+	LockTimeout   time.Duration
+}
+
+// CompleteTask marks a task complete in state and writes the change to the
+// todo file and to the daily note the task came from, so the next sync does
+// not reopen it. All files are written together or not at all.
+func (s *TaskService) CompleteTask(ctx context.Context, opts CompleteTaskOptions) (*state.TaskState, error) {
+	lockTimeout := opts.LockTimeout
+	if lockTimeout <= 0 {
+		lockTimeout = 10 * time.Second
+	}
+	locks, err := s.acquireSyncLocks(opts.StatePath, opts.TodoPath, "", lockTimeout)
+	if err != nil {
+		if s.isLockTimeoutError(err) {
+			return nil, fmt.Errorf("another sync operation is in progress. Please try again in a few seconds")
+		}
+		return nil, err
+	}
+	defer func() {
+		for i := len(locks) - 1; i >= 0; i-- {
+			utils.UnlockFile(locks[i])
+		}
+	}()
+
+	todoState, err := s.readState(opts.StatePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state file: %w", err)
+	}
+
+	task, ok := todoState.Tasks[opts.ID]
+	if !ok {
+		return nil, fmt.Errorf("task not found: %s", opts.ID)
+	}
+	if task.Completed {
+		return nil, fmt.Errorf("task already completed: %s", opts.TodoFormat.Tasks.StripTaskID(task.Text))
+	}
+
+	now := s.clock()
+	task.Completed = true
+	task.Status = ""
+	task.CompletedAt = now
+	task.CompletedDate = now.Format("2006-01-02")
+	task.LastModified = now
+	todoState.Tasks[task.ID] = task
+
+	tx := utils.NewWriteTransaction()
+	if s.rename != nil {
+		tx.Rename = s.rename
+	}
+	defer tx.Abort()
+
+	data, err := todoState.Marshal()
+	if err != nil {
+		return nil, fmt.Errorf("failed to write state file: %w", err)
+	}
+	if err := tx.Stage(opts.StatePath, data, constants.FilePerm0644); err != nil {
+		return nil, fmt.Errorf("failed to write state file: %w", err)
+	}
+
+	content := s.renderTodoFileFromState(opts.TodoPath, todoState, !opts.HideCompleted, opts.TodoFormat)
+	if err := tx.Stage(opts.TodoPath, []byte(content), constants.FilePerm0644); err != nil {
+		return nil, fmt.Errorf("failed to write todo file: %w", err)
+	}
+
+	if task.Source != "" && task.Source != opts.TodoPath && utils.FileExists(task.Source) {
+		content, changed, err := s.completeTaskLine(task.Source, task, opts.TodoFormat.Tasks)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update daily note %s: %w", task.Source, err)
+		}
+		if changed {
+			if err := tx.Stage(task.Source, []byte(content), constants.FilePerm0644); err != nil {
+				return nil, fmt.Errorf("failed to update daily note %s: %w", task.Source, err)
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to complete task, no files were changed: %w", err)
+	}
+
+	return &task, nil
+}
+
+// completeTaskLine rewrites the line holding task in notePath from its state,
+// keeping the line's indentation. Other lines are left untouched. It reports
+// false if the note does not contain the task.
+func (s *TaskService) completeTaskLine(notePath string, task state.TaskState, taskOpts tasks.Options) (string, bool, error) {
+	data, err := os.ReadFile(notePath)
+	if err != nil {
+		return "", false, err
+	}
+
+	content := string(data)
+	lines := strings.Split(content, "\n")
+
+	for _, noteTask := range taskOpts.ParseTasks(content) {
+		taskOpts.EnsureTaskID(&noteTask)
+		if noteTask.ID != task.ID {
+			continue
+		}
+
+		line := lines[noteTask.Line-1]
+		indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		ending := ""
+		if strings.HasSuffix(line, "\r") {
+			ending = "\r"
+		}
+		lines[noteTask.Line-1] = indent + s.formatTaskLine(task, taskOpts) + ending
+		return strings.Join(lines, "\n"), true, nil
+	}
+
+	return "", false, nil
+}
+
+// FindStaleTasks returns active tasks from state whose LastModified is more
+// than the given number of days before now, sorted oldest first.
+func (s *TaskService) FindStaleTasks(statePath string, days int, now time.Time) ([]state.TaskState, error) {
+	todoState, err := s.readState(statePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state file: %w", err)
+	}
+
+	cutoff := now.AddDate(0, 0, -days)
+
+	var stale []state.TaskState
+	for _, task := range todoState.GetActiveTasks() {
+		if task.LastModified.Before(cutoff) {
+			stale = append(stale, task)
+		}
+	}
+
+	sort.SliceStable(stale, func(i, j int) bool {
+		return stale[i].LastModified.Before(stale[j].LastModified)
+	})
+
+	return stale, nil
+}
+
+// FocusTasks returns at most count active tasks from state, most important
+// first: by priority, then by due date, as tasks.SortByPriority orders them.
+// Cancelled and archived tasks are never picked.
+func (s *TaskService) FocusTasks(statePath string, count int) ([]tasks.Task, error) {
+	todoState, err := s.readState(statePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state file: %w", err)
+	}
+
+	var candidates []tasks.Task
+	for _, ts := range todoState.Tasks {
+		if ts.ArchivedDate != "" || ts.Status == tasks.StatusCancelled {
+			continue
+		}
+		candidates = append(candidates, tasks.Task{
+			Text:      ts.Text,
+			Priority:  ts.Priority,
+			Section:   ts.Section,
+			ID:        ts.ID,
+			Tags:      ts.Tags,
+			Completed: ts.Completed,
+			Status:    ts.Status,
+			Meta:      ts.Meta,
+		})
+	}
+
+	// State is a map; order by ID first so ties are broken the same way
+	// on every run.
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].ID < candidates[j].ID
+	})
+
+	completed := false
+	focus := tasks.FilterTasks(candidates, &completed, "", "")
+	tasks.SortByPriority(focus)
+
+	if len(focus) > count {
+		focus = focus[:count]
+	}
+
+	return focus, nil
+}
+
+// UnassignedPerson is the WaitingGroup person for waiting tasks with no @mention.
+const UnassignedPerson = "Unassigned"
+
+// WaitingGroup is the set of active @waiting tasks blocked on one person.
+type WaitingGroup struct {
+	Person string
+	Tasks  []state.TaskState
+}
+
+// FindWaitingTasks groups active @waiting tasks from state by the people they
+// mention. A task mentioning several people appears under each of them;
+// tasks without a mention are grouped under UnassignedPerson, which sorts last.
+func (s *TaskService) FindWaitingTasks(statePath string) ([]WaitingGroup, error) {
+	todoState, err := s.readState(statePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state file: %w", err)
+	}
+
+	byPerson := make(map[string][]state.TaskState)
+	for _, task := range todoState.GetActiveTasks() {
+		if !tasks.IsWaiting(task.Text) {
+			continue
+		}
+
+		people := tasks.Mentions(task.Text)
+		if len(people) == 0 {
+			people = []string{UnassignedPerson}
+		}
+		for _, person := range people {
+			byPerson[person] = append(byPerson[person], task)
+		}
+	}
+
+	groups := make([]WaitingGroup, 0, len(byPerson))
+	for person, personTasks := range byPerson {
+		sort.Slice(personTasks, func(i, j int) bool {
+			return personTasks[i].Text < personTasks[j].Text
+		})
+		groups = append(groups, WaitingGroup{Person: person, Tasks: personTasks})
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		if (groups[i].Person == UnassignedPerson) != (groups[j].Person == UnassignedPerson) {
+			return groups[j].Person == UnassignedPerson
+		}
+		return strings.ToLower(groups[i].Person) < strings.ToLower(groups[j].Person)
+	})
+
+	return groups, nil
+}
+
+// OverdueOptions contains options for collecting overdue tasks.
+type OverdueOptions struct {
+	DiaryPath   string
+	TodoPath    string
+	TaskOptions tasks.Options
+}
+
+// OverdueTask is an overdue task together with the file it was found in.
+type OverdueTask struct {
+	Due    time.Time
+	Source string
+	Task   tasks.Task
+}
+
+// FindOverdueTasks collects overdue tasks from every daily note under the
+// diary directory and from the todo file, sorted by due date (oldest first).
+func (s *TaskService) FindOverdueTasks(ctx context.Context, opts OverdueOptions) ([]OverdueTask, error) {
+	var sources []string
+
+	if opts.DiaryPath != "" && utils.FileExists(opts.DiaryPath) {
+		dailyNotes, err := notes.FindNotes(ctx, opts.DiaryPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find daily notes: %w", err)
+		}
+		sources = append(sources, dailyNotes...)
+	}
+
+	if opts.TodoPath != "" && utils.FileExists(opts.TodoPath) && !slices.Contains(sources, opts.TodoPath) {
+		sources = append(sources, opts.TodoPath)
+	}
+
+	var overdue []OverdueTask
+
+	for _, source := range sources {
+		sourceTasks, err := opts.TaskOptions.ReadTasks(ctx, source)
+		if err != nil {
+			continue
+		}
+
+		for _, task := range sourceTasks {
+			if !tasks.IsOverdue(task) {
+				continue
+			}
+			due, _ := tasks.DueDate(task)
+			overdue = append(overdue, OverdueTask{Due: due, Source: source, Task: task})
+		}
+	}
+
+	sort.SliceStable(overdue, func(i, j int) bool {
+		return overdue[i].Due.Before(overdue[j].Due)
+	})
+
+	return overdue, nil
+}
+
+// AllTasksOptions contains options for collecting tasks across every note.
+type AllTasksOptions struct {
+	BaseDir   string
+	TodoPath  string
+	StatePath string
+	// Priority, Tag and Section, when set, keep only matching tasks. Tag is
+	// matched case-insensitively, with or without a leading "#".
+	Priority string
+	Tag      string
+	Section  string
+	// TaskOptions sets how task lines in the notes are parsed.
+	TaskOptions tasks.Options
+}
+
+// CountAllTasks tallies the tasks in every note under baseDir in one pass,
+// streaming each file rather than collecting its tasks. A task that appears
+// in several notes, such as a daily note and the todo file, is counted in
+// each. Notes that cannot be read are skipped. It also returns the number of
+// notes read.
+func (s *TaskService) CountAllTasks(ctx context.Context, baseDir string, taskOpts tasks.Options) (tasks.Tally, int, error) {
+	var total tasks.Tally
+
+	allNotes, err := notes.FindNotes(ctx, baseDir)
+	if err != nil {
+		return total, 0, fmt.Errorf("failed to find notes: %w", err)
+	}
+
+	read := 0
+	for _, notePath := range allNotes {
+		if err := ctx.Err(); err != nil {
+			return total, read, err
+		}
+
+		file, err := os.Open(notePath)
+		if err != nil {
+			continue
+		}
+		tally, err := taskOpts.TallyTasksReader(file)
+		file.Close()
+		if err != nil {
+			continue
+		}
+
+		total.Merge(tally)
+		read++
+	}
+
+	return total, read, nil
+}
+
+// AggregatedTask is a task found in one or more notes.
+type AggregatedTask struct {
+	Task tasks.Task
+	// Sources lists every file the task appears in, sorted.
+	Sources []string
+	// InState reports whether the task is tracked in the sync state, in
+	// which case Task holds the state's copy.
+	InState bool
+}
+
+// taskSources returns every note under baseDir and the todo file, sorted,
+// skipping either if it does not exist.
+func taskSources(ctx context.Context, baseDir, todoPath string) ([]string, error) {
+	var sources []string
+
+	if baseDir != "" && utils.FileExists(baseDir) {
+		allNotes, err := notes.FindNotes(ctx, baseDir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find notes: %w", err)
+		}
+		sources = append(sources, allNotes...)
+	}
+
+	if todoPath != "" && utils.FileExists(todoPath) && !slices.Contains(sources, todoPath) {
+		sources = append(sources, todoPath)
+	}
+	sort.Strings(sources)
+
+	return sources, nil
+}
+
+// FindAllTasks collects the tasks in every note under BaseDir and in the todo
+// file. Tasks sharing an ID are listed once with all of their sources, using
+// the state's copy when the ID is tracked there. State is only read.
+func (s *TaskService) FindAllTasks(ctx context.Context, opts AllTasksOptions) ([]AggregatedTask, error) {
+	sources, err := taskSources(ctx, opts.BaseDir, opts.TodoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var todoState *state.TodoState
+	if opts.StatePath != "" {
+		if todoState, err = s.readState(opts.StatePath); err != nil {
+			return nil, fmt.Errorf("failed to read state file: %w", err)
+		}
+	}
+
+	var all []AggregatedTask
+	byID := make(map[string]int)
+
+	for _, source := range sources {
+		sourceTasks, err := opts.TaskOptions.ReadTasks(ctx, source)
+		if err != nil {
+			continue
+		}
+
+		for _, task := range sourceTasks {
+			if task.ID != "" {
+				if i, ok := byID[task.ID]; ok {
+					if !slices.Contains(all[i].Sources, source) {
+						all[i].Sources = append(all[i].Sources, source)
+					}
+					continue
+				}
+				byID[task.ID] = len(all)
+			}
+
+			item := AggregatedTask{Task: task, Sources: []string{source}}
+			if todoState != nil && task.ID != "" {
+				if stateTask, ok := todoState.Tasks[task.ID]; ok {
+					item.InState = true
+					item.Task.Text = stateTask.Text
+					item.Task.Priority = stateTask.Priority
+					item.Task.Section = stateTask.Section
+					item.Task.Tags = stateTask.Tags
+					item.Task.Completed = stateTask.Completed
+					item.Task.Status = stateTask.Status
+				}
+			}
+			all = append(all, item)
+		}
+	}
+
+	tag := strings.TrimPrefix(opts.Tag, "#")
+	filtered := all[:0]
+	for _, item := range all {
+		if opts.Priority != "" && item.Task.Priority != opts.Priority {
+			continue
+		}
+		if opts.Section != "" && item.Task.Section != opts.Section {
+			continue
+		}
+		if tag != "" && !slices.ContainsFunc(item.Task.Tags, func(t string) bool { return strings.EqualFold(t, tag) }) {
+			continue
+		}
+		filtered = append(filtered, item)
+	}
+
+	return filtered, nil
+}
+
+// TaskLocation is a line containing a task ID.
+type TaskLocation struct {
+	Path string
+	Line int
+}
+
+// TaskWhereabouts reports where a task ID appears.
+type TaskWhereabouts struct {
+	// Locations lists every line carrying the ID, by path then line.
+	Locations []TaskLocation
+	// StateTask is the state's copy of the task; InState reports whether
+	// there is one.
+	StateTask state.TaskState
+	InState   bool
+}
+
+// FindTaskLocations scans every note under baseDir and the todo file for
+// lines carrying id, and looks the task up in state, to show where a task
+// lives when debugging sync. State is only read.
+func (s *TaskService) FindTaskLocations(ctx context.Context, opts AllTasksOptions, id string) (*TaskWhereabouts, error) {
+	sources, err := taskSources(ctx, opts.BaseDir, opts.TodoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &TaskWhereabouts{}
+	for _, source := range sources {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		content, err := os.ReadFile(source)
+		if err != nil {
+			continue
+		}
+		for i, line := range strings.Split(string(content), "\n") {
+			if opts.TaskOptions.ExtractTaskID(line) == id {
+				result.Locations = append(result.Locations, TaskLocation{Path: source, Line: i + 1})
+			}
+		}
+	}
+
+	if opts.StatePath != "" {
+		todoState, err := s.readState(opts.StatePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read state file: %w", err)
+		}
+		result.StateTask, result.InState = todoState.Tasks[id]
+	}
+
+	return result, nil
+}
+
+// GetAllTasks reads all tasks from the todo file.
+func (s *TaskService) GetAllTasks(ctx context.Context, todoPath string, format TodoFormat) ([]tasks.Task, error) {
+	return format.ReadTasks(ctx, todoPath)
+}
+
+// GetTaskSummary returns a summary of tasks grouped by priority.
+func (s *TaskService) GetTaskSummary(ctx context.Context, todoPath string, format TodoFormat) (*tasks.Task, error) {
+	allTasks, err := format.ReadTasks(ctx, todoPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tasks: %w", err)
+	}
+
+	completed := false
+	pendingTasks := tasks.FilterTasks(allTasks, &completed, "", "")
+
+	_ = tasks.GroupByPriority(pendingTasks)
+
+	if len(pendingTasks) > 0 {
+		return &pendingTasks[0], nil
 	}
 
 	return nil, nil
 }
 
 // GetTaskStats returns statistics about tasks.
-func (s *TaskService) GetTaskStats(ctx context.Context, todoPath string) (*TaskStats, error) {
-	allTasks, err := tasks.ReadTasks(ctx, todoPath)
+func (s *TaskService) GetTaskStats(ctx context.Context, todoPath string, format TodoFormat) (*TaskStats, error) {
+	return s.GetSectionTaskStats(ctx, todoPath, "", format)
+}
+
+// GetSectionTaskStats returns statistics about the tasks in one section. An
+// empty section covers every task, like GetTaskStats.
+func (s *TaskService) GetSectionTaskStats(ctx context.Context, todoPath, section string, format TodoFormat) (*TaskStats, error) {
+	allTasks, err := format.ReadTasks(ctx, todoPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read tasks: %w", err)
 	}
 
+	if section != "" {
+		allTasks = tasks.FilterTasks(allTasks, nil, "", section)
+	}
+
 	stats := &TaskStats{
 		Total:      len(allTasks),
 		ByPriority: tasks.GroupByPriority(allTasks),
@@ -3,6 +3,8 @@ package state
 import (
 	"fmt"
 	"os"
+	"reflect"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -428,6 +430,31 @@ func TestSmartMerge(t *testing.T) {
 	}
 }
 
+func TestSmartMerge_TagsCaseInsensitive(t *testing.T) {
+	state := NewTodoState()
+
+	dailyChange := TaskChange{
+		TaskID:     "abc123",
+		ChangeType: Modified,
+		NewTask:    &TaskState{ID: "abc123", Text: "Task", Tags: []string{"Work"}},
+	}
+	todoChange := TaskChange{
+		TaskID:     "abc123",
+		ChangeType: Modified,
+		NewTask:    &TaskState{ID: "abc123", Text: "Task", Tags: []string{"work", "urgent"}},
+	}
+
+	merged := state.smartMerge(dailyChange, todoChange)
+	if merged == nil {
+		t.Fatal("Expected merge to succeed but got nil")
+	}
+
+	want := []string{"Work", "urgent"}
+	if !reflect.DeepEqual(merged.Tags, want) {
+		t.Errorf("Expected tags %v, got %v", want, merged.Tags)
+	}
+}
+
 func TestIsTaskModified(t *testing.T) {
 	s := NewTodoState()
 
@@ -479,6 +506,12 @@ func TestIsTaskModified(t *testing.T) {
 			sourceTask: tasks.Task{Tags: []string{"tag2", "tag1"}},
 			expectMod:  false,
 		},
+		{
+			name:       "same tags different case",
+			stateTask:  TaskState{Tags: []string{"Work", "urgent"}},
+			sourceTask: tasks.Task{Tags: []string{"work", "URGENT"}},
+			expectMod:  false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -491,6 +524,27 @@ func TestIsTaskModified(t *testing.T) {
 	}
 }
 
+// TestIsTaskModified_ParsedTagCase tests that tasks parsed from lines that
+// differ only in tag case are not reported as modified.
+func TestIsTaskModified_ParsedTagCase(t *testing.T) {
+	s := NewTodoState()
+	stateTasks := tasks.ParseTasks("- [ ] Fix bug #Work <!-- id: abc12345 -->")
+	sourceTasks := tasks.ParseTasks("- [ ] Fix bug #work <!-- id: abc12345 -->")
+	if len(stateTasks) != 1 || len(sourceTasks) != 1 {
+		t.Fatalf("parsed %d and %d tasks, want 1 each", len(stateTasks), len(sourceTasks))
+	}
+
+	s.AddTask(stateTasks[0], "daily.md")
+	if s.isTaskModified(s.Tasks["abc12345"], sourceTasks[0]) {
+		t.Error("changing #Work to #work was reported as a modification")
+	}
+
+	renamed := tasks.ParseTasks("- [ ] Fix crash #work <!-- id: abc12345 -->")
+	if !s.isTaskModified(s.Tasks["abc12345"], renamed[0]) {
+		t.Error("changing the task text was not reported as a modification")
+	}
+}
+
 func TestConcurrentBidirectionalSync(t *testing.T) {
 	tempDir := t.TempDir()
 	statePath := tempDir + "/.todo_state.json"
@@ -1046,6 +1100,80 @@ func TestBidirectionalSyncWithDeletions(t *testing.T) {
 	}
 }
 
+func TestBidirectionalSyncWithOptions_WithholdDeletions(t *testing.T) {
+	s := &TodoState{
+		Tasks: map[string]TaskState{
+			"abc123": {ID: "abc123", Text: "Cut while reorganizing", Source: "test.md"},
+			"def456": {ID: "def456", Text: "Done", Completed: true, Source: "test.md"},
+		},
+	}
+
+	result := s.BidirectionalSyncWithOptions(nil, nil, "test.md", BidirectionalSyncOptions{WithholdDeletions: true})
+
+	if result.Deleted != 0 || result.StateUpdated {
+		t.Errorf("Expected no deletions applied, got Deleted=%d StateUpdated=%v", result.Deleted, result.StateUpdated)
+	}
+	if len(result.WithheldDeletions) != 1 || result.WithheldDeletions[0].Text != "Cut while reorganizing" {
+		t.Errorf("Expected the pending task to be withheld, got %+v", result.WithheldDeletions)
+	}
+	if !s.HasTask("abc123") || !s.HasTask("def456") {
+		t.Error("Expected withheld and completed tasks to remain in state")
+	}
+}
+
+func TestBidirectionalSyncWithOptions_PruneCompletedBefore(t *testing.T) {
+	newState := func() *TodoState {
+		return &TodoState{
+			Tasks: map[string]TaskState{
+				"abc123": {ID: "abc123", Text: "Shipped", Completed: true, CompletedDate: "2025-05-01", Source: "test.md"},
+			},
+		}
+	}
+
+	// Without the option, a completed task removed from both files is kept.
+	s := newState()
+	result := s.BidirectionalSync(nil, nil, "test.md")
+	if len(result.PrunedTaskIDs) != 0 || s.Tasks["abc123"].ArchivedDate != "" {
+		t.Errorf("Expected the task to be retained, got pruned %v", result.PrunedTaskIDs)
+	}
+
+	// Within the grace period it is still kept.
+	s = newState()
+	result = s.BidirectionalSyncWithOptions(nil, nil, "test.md", BidirectionalSyncOptions{PruneCompletedBefore: "2025-05-01"})
+	if len(result.PrunedTaskIDs) != 0 {
+		t.Errorf("Expected no pruning inside the grace period, got %v", result.PrunedTaskIDs)
+	}
+
+	// Past the grace period it is archived but stays in state.
+	s = newState()
+	s.SetClock(func() time.Time { return time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local) })
+	result = s.BidirectionalSyncWithOptions(nil, nil, "test.md", BidirectionalSyncOptions{PruneCompletedBefore: "2025-05-02"})
+	if len(result.PrunedTaskIDs) != 1 || !result.TodoChanged {
+		t.Errorf("Expected the task to be pruned, got %+v", result)
+	}
+	if task, ok := s.Tasks["abc123"]; !ok || task.ArchivedDate != "2025-06-01" {
+		t.Errorf("Expected the task to stay in state archived on 2025-06-01, got %+v", task)
+	}
+}
+
+func TestBidirectionalSync_SectionDefaultKeepsStatePriority(t *testing.T) {
+	s := &TodoState{
+		Tasks: map[string]TaskState{
+			"abc123": {ID: "abc123", Text: "Call bank", Section: "Urgent", Priority: "P2", Source: "test.md"},
+		},
+	}
+
+	daily := []tasks.Task{{ID: "abc123", Text: "Call bank", Section: "Urgent", Priority: "P1", PriorityDefaulted: true}}
+	result := s.BidirectionalSync(daily, nil, "test.md")
+
+	if result.StateUpdated {
+		t.Errorf("Expected a section default not to count as a change, got %+v", result)
+	}
+	if got := s.Tasks["abc123"].Priority; got != "P2" {
+		t.Errorf("Priority = %q, want existing P2 kept", got)
+	}
+}
+
 func TestBuildTaskChangeDetail(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -1108,7 +1236,7 @@ func TestBuildTaskChangeDetail(t *testing.T) {
 			},
 			expected: TaskChangeDetail{
 				ID:      "abc123",
-				Text:    "",
+				Text:    "Deleted task",
 				Change:  "deleted",
 				From:    "Deleted task",
 				Details: "task deleted",
@@ -1241,3 +1369,102 @@ func TestBuildChangeDetails(t *testing.T) {
 		})
 	}
 }
+
+func TestBidirectionalSync_ReconcilesEditedIDs(t *testing.T) {
+	s := NewTodoState()
+	// The ID marker was edited by hand in both files from aaaa1111 to bbbb2222.
+	s.Tasks["aaaa1111"] = TaskState{ID: "aaaa1111", Text: "Write report", Section: "Tasks", Priority: "P1", Source: "daily.md"}
+	// A state entry stored under a key that differs from its recorded ID.
+	s.Tasks["stale999"] = TaskState{ID: "cccc3333", Text: "Call plumber", Section: "Home"}
+
+	todoTasks := []tasks.Task{
+		{ID: "bbbb2222", Text: "Write report", Section: "Tasks", Priority: "P1"},
+		{ID: "cccc3333", Text: "Call plumber", Section: "Home"},
+	}
+
+	result := s.BidirectionalSync(nil, todoTasks, "daily.md")
+
+	want := []IDMigration{{From: "aaaa1111", To: "bbbb2222"}, {From: "stale999", To: "cccc3333"}}
+	if !reflect.DeepEqual(result.ReconciledIDs, want) {
+		t.Errorf("ReconciledIDs = %v; want %v", result.ReconciledIDs, want)
+	}
+	if !result.StateUpdated {
+		t.Error("StateUpdated = false; want true after reconciling IDs")
+	}
+
+	if len(s.Tasks) != 2 {
+		t.Fatalf("state has %d tasks; want 2: %v", len(s.Tasks), s.Tasks)
+	}
+	for _, old := range []string{"aaaa1111", "stale999"} {
+		if s.HasTask(old) {
+			t.Errorf("stale entry %s still in state", old)
+		}
+	}
+
+	migrated := s.Tasks["bbbb2222"]
+	if migrated.ID != "bbbb2222" || migrated.Priority != "P1" || migrated.Source != "daily.md" {
+		t.Errorf("migrated task = %+v; want ID bbbb2222 keeping priority and source", migrated)
+	}
+	if s.Tasks["cccc3333"].ID != "cccc3333" {
+		t.Errorf("task cccc3333 = %+v; want it keyed by its ID", s.Tasks["cccc3333"])
+	}
+	if result.Deleted != 0 || result.AppliedTodo != 0 {
+		t.Errorf("Deleted = %d, AppliedTodo = %d; want no deletions or additions", result.Deleted, result.AppliedTodo)
+	}
+}
+
+func TestBidirectionalSync_ConflictDetails(t *testing.T) {
+	todoState := &TodoState{
+		Tasks: map[string]TaskState{
+			"abc123": {ID: "abc123", Text: "Original", Source: "test.md"},
+			"def456": {ID: "def456", Text: "Ship it", Source: "test.md"},
+		},
+	}
+
+	result := todoState.BidirectionalSync(
+		[]tasks.Task{
+			{ID: "abc123", Text: "Daily version"},
+			{ID: "def456", Text: "Ship it today", Completed: true},
+		},
+		[]tasks.Task{
+			{ID: "abc123", Text: "Todo version"},
+			{ID: "def456", Text: "Ship it now"},
+		},
+		"test.md",
+	)
+
+	if len(result.Conflicts) != 2 {
+		t.Fatalf("Conflicts = %v, want 2 entries", result.Conflicts)
+	}
+
+	want := []ConflictDetail{
+		{
+			ID:        "abc123",
+			TextDaily: "Daily version",
+			TextTodo:  "Todo version",
+			Fields:    []string{ConflictFieldText},
+		},
+		{
+			ID:             "def456",
+			TextDaily:      "Ship it today",
+			TextTodo:       "Ship it now",
+			Fields:         []string{ConflictFieldText, ConflictFieldCompletion},
+			CompletedDaily: true,
+		},
+	}
+	if len(result.ConflictsDetail) != len(want) {
+		t.Fatalf("ConflictsDetail = %+v, want %d entries", result.ConflictsDetail, len(want))
+	}
+
+	for i, got := range result.ConflictsDetail {
+		w := want[i]
+		if got.ID != w.ID || got.TextDaily != w.TextDaily || got.TextTodo != w.TextTodo ||
+			got.CompletedDaily != w.CompletedDaily || got.CompletedTodo != w.CompletedTodo ||
+			strings.Join(got.Fields, ",") != strings.Join(w.Fields, ",") {
+			t.Errorf("ConflictsDetail[%d] = %+v, want %+v", i, got, w)
+		}
+		if got.Reason == "" || got.Reason != result.Conflicts[got.ID] {
+			t.Errorf("ConflictsDetail[%d].Reason = %q, want the string map's %q", i, got.Reason, result.Conflicts[got.ID])
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// CurrentVersion is the state file schema version this build writes.
+const CurrentVersion = 1
+
+// migrations[i] upgrades a state from version i to version i+1. To change the
+// schema, bump CurrentVersion and append the migration for the old version.
+var migrations = []func(s *TodoState){
+	migrateV0toV1,
+}
+
+// migrateV0toV1 fills in fields that state files written before versioning
+// could leave empty.
+func migrateV0toV1(s *TodoState) {
+	if s.Tasks == nil {
+		s.Tasks = make(map[string]TaskState)
+	}
+
+	for id, task := range s.Tasks {
+		if task.ID == "" {
+			task.ID = id
+		}
+		if task.CreatedDate == "" && !task.CreatedAt.IsZero() {
+			task.CreatedDate = task.CreatedAt.Format("2006-01-02")
+		}
+		if task.Completed && task.CompletedDate == "" && !task.CompletedAt.IsZero() {
+			task.CompletedDate = task.CompletedAt.Format("2006-01-02")
+		}
+		s.Tasks[id] = task
+	}
+}
+
+// Migrate upgrades s from its Version to CurrentVersion, running each
+// migration in turn. A state newer than CurrentVersion is an error, since
+// rewriting it could drop fields this build doesn't know about; a negative
+// version wraps ErrCorrupt.
+func Migrate(s *TodoState) error {
+	if s.Version < 0 {
+		return fmt.Errorf("%w: invalid version %d", ErrCorrupt, s.Version)
+	}
+	if s.Version > CurrentVersion {
+		return fmt.Errorf("state file version %d is newer than supported version %d; upgrade jotr", s.Version, CurrentVersion)
+	}
+
+	for s.Version < CurrentVersion {
+		migrations[s.Version](s)
+		s.Version++
+	}
+
+	return nil
+}
+
+// SaveMigration rewrites the state file at statePath at CurrentVersion if it
+// was written at an older version. The caller must hold the state file lock.
+// Missing, corrupt and newer files are left alone for Read to report.
+func SaveMigration(statePath string) error {
+	data, err := os.ReadFile(statePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read state file: %w", err)
+	}
+
+	var header struct {
+		Version int `json:"version"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil || header.Version >= CurrentVersion || header.Version < 0 {
+		return nil
+	}
+
+	s, err := Read(statePath)
+	if err != nil {
+		if errors.Is(err, ErrCorrupt) {
+			return nil
+		}
+		return err
+	}
+
+	return s.Write(statePath)
+}
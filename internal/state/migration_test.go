@@ -0,0 +1,133 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/AnishShah1803/jotr/internal/constants"
+)
+
+func TestRead_MigratesUnversionedState(t *testing.T) {
+	statePath := t.TempDir() + "/.todo_state.json"
+	legacy := `{
+  "lastSync": "2025-03-10T09:00:00Z",
+  "tasks": {
+    "abcd1234": {"text": "Old task", "section": "Tasks", "createdAt": "2025-03-01T09:00:00Z"}
+  }
+}`
+	if err := os.WriteFile(statePath, []byte(legacy), constants.FilePerm0644); err != nil {
+		t.Fatalf("Failed to write state file: %v", err)
+	}
+
+	state, err := Read(statePath)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if state.Version != CurrentVersion {
+		t.Errorf("Version = %d, want %d", state.Version, CurrentVersion)
+	}
+	task := state.Tasks["abcd1234"]
+	if task.ID != "abcd1234" || task.CreatedDate != "2025-03-01" {
+		t.Errorf("migrated task = %+v, want ID and CreatedDate filled in", task)
+	}
+
+	// Read only migrates in memory; saving is left to SaveMigration.
+	saved, err := os.ReadFile(statePath)
+	if err != nil {
+		t.Fatalf("Failed to read state file: %v", err)
+	}
+	if string(saved) != legacy {
+		t.Errorf("Read() rewrote the state file:\n%s", saved)
+	}
+
+	if err := state.Write(statePath); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	saved, err = os.ReadFile(statePath)
+	if err != nil {
+		t.Fatalf("Failed to read state file: %v", err)
+	}
+	if want := fmt.Sprintf(`"version": %d`, CurrentVersion); !strings.Contains(string(saved), want) {
+		t.Errorf("saved state does not contain %s:\n%s", want, saved)
+	}
+}
+
+func TestRead_RejectsNewerVersion(t *testing.T) {
+	statePath := t.TempDir() + "/.todo_state.json"
+	future := fmt.Sprintf(`{"tasks": {}, "version": %d}`, CurrentVersion+1)
+	if err := os.WriteFile(statePath, []byte(future), constants.FilePerm0644); err != nil {
+		t.Fatalf("Failed to write state file: %v", err)
+	}
+
+	if _, err := Read(statePath); err == nil {
+		t.Error("Read() error = nil, want an error for a newer state version")
+	}
+}
+
+func TestRead_NegativeVersionIsCorrupt(t *testing.T) {
+	statePath := t.TempDir() + "/.todo_state.json"
+	data := `{"tasks": {"abcd1234": {"id": "abcd1234", "text": "Old task", "section": "Tasks"}}, "version": -1}`
+	if err := os.WriteFile(statePath, []byte(data), constants.FilePerm0644); err != nil {
+		t.Fatalf("Failed to write state file: %v", err)
+	}
+
+	if _, err := Read(statePath); !errors.Is(err, ErrCorrupt) {
+		t.Fatalf("Read() error = %v, want ErrCorrupt", err)
+	}
+
+	if _, err := Repair(statePath); err != nil {
+		t.Fatalf("Repair() error = %v", err)
+	}
+	state, err := Read(statePath)
+	if err != nil {
+		t.Fatalf("Read() after Repair() error = %v", err)
+	}
+	if state.Version != CurrentVersion || !state.HasTask("abcd1234") {
+		t.Errorf("repaired state = version %d, tasks %+v; want version %d with abcd1234", state.Version, state.Tasks, CurrentVersion)
+	}
+}
+
+func TestSaveMigration(t *testing.T) {
+	statePath := t.TempDir() + "/.todo_state.json"
+	legacy := `{"tasks": {"abcd1234": {"text": "Old task", "section": "Tasks"}}}`
+	if err := os.WriteFile(statePath, []byte(legacy), constants.FilePerm0644); err != nil {
+		t.Fatalf("Failed to write state file: %v", err)
+	}
+
+	if err := SaveMigration(statePath); err != nil {
+		t.Fatalf("SaveMigration() error = %v", err)
+	}
+	saved, err := os.ReadFile(statePath)
+	if err != nil {
+		t.Fatalf("Failed to read state file: %v", err)
+	}
+	if want := fmt.Sprintf(`"version": %d`, CurrentVersion); !strings.Contains(string(saved), want) {
+		t.Errorf("saved state does not contain %s:\n%s", want, saved)
+	}
+
+	// A current file is left as it is.
+	info, err := os.Stat(statePath)
+	if err != nil {
+		t.Fatalf("Failed to stat state file: %v", err)
+	}
+	if err := SaveMigration(statePath); err != nil {
+		t.Fatalf("SaveMigration() error = %v", err)
+	}
+	if after, err := os.Stat(statePath); err != nil || !after.ModTime().Equal(info.ModTime()) {
+		t.Errorf("SaveMigration() rewrote a state file already at version %d", CurrentVersion)
+	}
+
+	// Missing and corrupt files are left for Read and Repair to report.
+	if err := SaveMigration(t.TempDir() + "/missing.json"); err != nil {
+		t.Errorf("SaveMigration() on a missing file error = %v", err)
+	}
+	if err := os.WriteFile(statePath, []byte(`{"version": -1}`), constants.FilePerm0644); err != nil {
+		t.Fatalf("Failed to write state file: %v", err)
+	}
+	if err := SaveMigration(statePath); err != nil {
+		t.Errorf("SaveMigration() on a corrupt file error = %v", err)
+	}
+}
@@ -0,0 +1,164 @@
+package state
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/AnishShah1803/jotr/internal/constants"
+)
+
+// BackupSuffix is appended to the state path for the backup Repair restores
+// from, if one exists.
+const BackupSuffix = ".bak"
+
+// CorruptSuffix is appended to the state path for the copy of a corrupt state
+// file that Repair keeps before rewriting it.
+const CorruptSuffix = ".corrupt"
+
+// Repair sources.
+const (
+	RepairSourceNone    = ""        // the state file was already valid
+	RepairSourceBackup  = "backup"  // restored from the .bak backup
+	RepairSourceSalvage = "salvage" // rebuilt from the readable task entries
+)
+
+// RepairResult describes what Repair recovered.
+type RepairResult struct {
+	Source      string   `json:"source"`
+	Recovered   int      `json:"recovered"`
+	Dropped     []string `json:"dropped,omitempty"`
+	CorruptCopy string   `json:"corrupt_copy,omitempty"`
+}
+
+// Repair rewrites a corrupt state file. It restores the backup at
+// statePath+BackupSuffix when that parses, and otherwise keeps every task
+// entry that can still be decoded, recording what was dropped. The corrupt
+// file is kept at statePath+CorruptSuffix. A valid or missing state file is
+// left untouched.
+func Repair(statePath string) (*RepairResult, error) {
+	if _, err := Read(statePath); !errors.Is(err, ErrCorrupt) {
+		if err != nil {
+			return nil, err
+		}
+		return &RepairResult{Source: RepairSourceNone}, nil
+	}
+
+	data, err := os.ReadFile(statePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state file: %w", err)
+	}
+
+	result := &RepairResult{Source: RepairSourceBackup}
+	repaired, err := readBackup(statePath + BackupSuffix)
+	if err != nil {
+		repaired, result.Dropped = salvage(data)
+		result.Source = RepairSourceSalvage
+	}
+	result.Recovered = len(repaired.Tasks)
+
+	result.CorruptCopy = statePath + CorruptSuffix
+	if err := os.WriteFile(result.CorruptCopy, data, constants.FilePerm0644); err != nil {
+		return nil, fmt.Errorf("failed to keep a copy of the corrupt state file: %w", err)
+	}
+
+	if err := repaired.Write(statePath); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// readBackup reads a state backup, failing if it is missing or corrupt.
+func readBackup(backupPath string) (*TodoState, error) {
+	if _, err := os.Stat(backupPath); err != nil {
+		return nil, err
+	}
+	return Read(backupPath)
+}
+
+// salvage decodes state JSON entry by entry, keeping every task read before
+// the first unreadable one. It returns descriptions of what was dropped.
+func salvage(data []byte) (*TodoState, []string) {
+	s := NewTodoState()
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return s, []string{"state file is not a JSON object; no tasks could be read"}
+	}
+
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return s, []string{truncatedAt(dec)}
+		}
+		key, _ := tok.(string)
+
+		switch key {
+		case "tasks":
+			if dropped := salvageTasks(dec, s); dropped != nil {
+				return s, dropped
+			}
+			continue
+		case "lastSync":
+			err = dec.Decode(&s.LastSync)
+		case "lastArchive":
+			err = dec.Decode(&s.LastArchive)
+		case "version":
+			// A negative version is what made the file corrupt; migrating
+			// from version 0 fills in anything it is missing.
+			if err = dec.Decode(&s.Version); err == nil && s.Version < 0 {
+				s.Version = 0
+			}
+		default:
+			var skip json.RawMessage
+			err = dec.Decode(&skip)
+		}
+		if err != nil {
+			return s, []string{fmt.Sprintf("field %s: %v", key, err)}
+		}
+	}
+
+	if _, err := dec.Token(); err != nil {
+		return s, []string{truncatedAt(dec)}
+	}
+
+	return s, nil
+}
+
+// salvageTasks decodes the entries of the "tasks" object into s until one
+// cannot be read, returning what was dropped (nil if nothing).
+func salvageTasks(dec *json.Decoder, s *TodoState) []string {
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return []string{"tasks is not a JSON object; no tasks could be read"}
+	}
+
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return []string{truncatedAt(dec)}
+		}
+		id, _ := tok.(string)
+
+		var task TaskState
+		if err := dec.Decode(&task); err != nil {
+			return []string{fmt.Sprintf("task %s and anything after it: %v", id, err)}
+		}
+		if task.ID == "" {
+			task.ID = id
+		}
+		s.Tasks[task.ID] = task
+	}
+
+	if _, err := dec.Token(); err != nil {
+		return []string{truncatedAt(dec)}
+	}
+
+	return nil
+}
+
+func truncatedAt(dec *json.Decoder) string {
+	return fmt.Sprintf("content after byte %d could not be read", dec.InputOffset())
+}
@@ -2,8 +2,10 @@ package state
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"slices"
 	"sort"
 	"strings"
 	"time"
@@ -18,6 +20,8 @@ type TodoState struct {
 	LastArchive time.Time            `json:"lastArchive,omitempty"`
 	Tasks       map[string]TaskState `json:"tasks"`
 	Version     int                  `json:"version"`
+
+	now func() time.Time // clock for timestamps; nil uses time.Now
 }
 
 // TaskState represents the state of a single task
@@ -34,18 +38,76 @@ type TaskState struct {
 	Source        string    `json:"source,omitempty"`
 	CreatedDate   string    `json:"createdDate,omitempty"`
 	CompletedDate string    `json:"completedDate,omitempty"`
+	Status        string    `json:"status,omitempty"`
+	// Meta holds custom "key: value" fields parsed from the task text.
+	Meta map[string]string `json:"meta,omitempty"`
+	// ArchivedDate is the date a completed task was archived. Archived tasks
+	// stay in state as history but are no longer written to the todo file.
+	ArchivedDate string `json:"archivedDate,omitempty"`
+	// Order is the task's line in the todo file when it was last synced. It
+	// keeps hand-ordered tasks in place when format.preserve_task_order is
+	// set; zero means the task has not been in the todo file yet.
+	Order int `json:"order,omitempty"`
+}
+
+// AgeDays returns how many whole days the task has been open: from CreatedAt
+// (or CreatedDate) to now, or to when it was completed for completed tasks.
+// It reports false if the task has no creation time.
+func (t TaskState) AgeDays(now time.Time) (int, bool) {
+	created := t.CreatedAt
+	if created.IsZero() && t.CreatedDate != "" {
+		date, err := time.ParseInLocation("2006-01-02", t.CreatedDate, time.Local)
+		if err != nil {
+			return 0, false
+		}
+		created = date
+	}
+	if created.IsZero() {
+		return 0, false
+	}
+
+	end := now
+	if t.Completed && !t.CompletedAt.IsZero() {
+		end = t.CompletedAt
+	}
+
+	if end.Before(created) {
+		return 0, true
+	}
+
+	return int(end.Sub(created).Hours() / 24), true
 }
 
 // NewTodoState creates a new empty TodoState
 func NewTodoState() *TodoState {
 	return &TodoState{
 		Tasks:    make(map[string]TaskState),
-		Version:  1,
+		Version:  CurrentVersion,
 		LastSync: time.Now(),
 	}
 }
 
-// Read reads the state from a file
+// SetClock sets the clock used for timestamps and completion dates, so
+// callers can control the effective "today". A nil clock uses time.Now.
+func (s *TodoState) SetClock(now func() time.Time) {
+	s.now = now
+}
+
+func (s *TodoState) clock() time.Time {
+	if s.now != nil {
+		return s.now()
+	}
+	return time.Now()
+}
+
+// ErrCorrupt is returned by Read when the state file exists but cannot be
+// parsed. Repair recovers what it can from such a file.
+var ErrCorrupt = errors.New("state file is corrupt")
+
+// Read reads the state from a file. A missing file yields an empty state; an
+// unparseable one yields an error wrapping ErrCorrupt. A state written at an
+// older version is migrated to CurrentVersion in memory only; Read never
+// writes. SaveMigration saves it once the state file lock is held.
 func Read(statePath string) (*TodoState, error) {
 	data, err := os.ReadFile(statePath)
 	if err != nil {
@@ -57,21 +119,39 @@ func Read(statePath string) (*TodoState, error) {
 
 	var state TodoState
 	if err := json.Unmarshal(data, &state); err != nil {
-		return nil, fmt.Errorf("failed to parse state file: %w", err)
+		return nil, fmt.Errorf("%w: %s: %v (run 'jotr state repair' to recover it)", ErrCorrupt, statePath, err)
 	}
 
 	if state.Tasks == nil {
 		state.Tasks = make(map[string]TaskState)
 	}
 
+	if state.Version != CurrentVersion {
+		if err := Migrate(&state); err != nil {
+			if errors.Is(err, ErrCorrupt) {
+				return nil, fmt.Errorf("%s: %w (run 'jotr state repair' to recover it)", statePath, err)
+			}
+			return nil, fmt.Errorf("%s: %w", statePath, err)
+		}
+	}
+
 	return &state, nil
 }
 
+// Marshal encodes the state as it is stored in the state file.
+func (s *TodoState) Marshal() ([]byte, error) {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal state: %w", err)
+	}
+	return data, nil
+}
+
 // Write writes the state to a file
 func (s *TodoState) Write(statePath string) error {
-	data, err := json.MarshalIndent(s, "", "  ")
+	data, err := s.Marshal()
 	if err != nil {
-		return fmt.Errorf("failed to marshal state: %w", err)
+		return err
 	}
 
 	if err := os.WriteFile(statePath, data, constants.FilePerm0644); err != nil {
@@ -92,7 +172,7 @@ func isDateSection(s string) bool {
 
 // AddTask adds or updates a task in the state
 func (s *TodoState) AddTask(task tasks.Task, source string) {
-	now := time.Now()
+	now := s.clock()
 	today := now.Format("2006-01-02")
 
 	ts := TaskState{
@@ -102,6 +182,8 @@ func (s *TodoState) AddTask(task tasks.Task, source string) {
 		Tags:         task.Tags,
 		ID:           task.ID,
 		Completed:    task.Completed,
+		Status:       task.Status,
+		Meta:         task.Meta,
 		LastModified: now,
 		Source:       source,
 	}
@@ -111,6 +193,9 @@ func (s *TodoState) AddTask(task tasks.Task, source string) {
 		ts.CompletedAt = existing.CompletedAt
 		ts.CreatedDate = existing.CreatedDate
 		ts.CompletedDate = existing.CompletedDate
+		if task.Completed {
+			ts.ArchivedDate = existing.ArchivedDate
+		}
 	} else {
 		ts.CreatedAt = now
 		if isDateSection(task.Section) {
@@ -129,17 +214,44 @@ func (s *TodoState) AddTask(task tasks.Task, source string) {
 	s.LastSync = now
 }
 
-// GetActiveTasks returns all non-completed tasks
+// RecordTaskOrder sets the Order of each state task found in todoTasks to
+// its line in the todo file. It reports whether any Order changed.
+func (s *TodoState) RecordTaskOrder(todoTasks []tasks.Task) bool {
+	changed := false
+	for _, todoTask := range todoTasks {
+		task, exists := s.Tasks[todoTask.ID]
+		if todoTask.ID == "" || !exists || task.Order == todoTask.Line {
+			continue
+		}
+		task.Order = todoTask.Line
+		s.Tasks[todoTask.ID] = task
+		changed = true
+	}
+	return changed
+}
+
+// GetActiveTasks returns all tasks that are neither completed nor cancelled
 func (s *TodoState) GetActiveTasks() []TaskState {
 	var active []TaskState
 	for _, task := range s.Tasks {
-		if !task.Completed {
+		if !task.Completed && task.Status != tasks.StatusCancelled {
 			active = append(active, task)
 		}
 	}
 	return active
 }
 
+// GetCancelledTasks returns all cancelled tasks
+func (s *TodoState) GetCancelledTasks() []TaskState {
+	var cancelled []TaskState
+	for _, task := range s.Tasks {
+		if !task.Completed && task.Status == tasks.StatusCancelled {
+			cancelled = append(cancelled, task)
+		}
+	}
+	return cancelled
+}
+
 // GetCompletedTasks returns all completed tasks
 func (s *TodoState) GetCompletedTasks() []TaskState {
 	var completed []TaskState
@@ -159,13 +271,28 @@ func (s *TodoState) HasTask(taskID string) bool {
 
 // MarkArchived records that tasks have been archived
 func (s *TodoState) MarkArchived() {
-	s.LastArchive = time.Now()
+	s.LastArchive = s.clock()
 }
 
 // RemoveTask removes a task from state by ID
 func (s *TodoState) RemoveTask(taskID string) {
 	delete(s.Tasks, taskID)
-	s.LastSync = time.Now()
+	s.LastSync = s.clock()
+}
+
+// WithoutTasks returns a copy of the state without the given task IDs. The
+// tasks themselves are shared, so the copy is for reading only.
+func (s *TodoState) WithoutTasks(taskIDs []string) *TodoState {
+	view := *s
+	view.Tasks = make(map[string]TaskState, len(s.Tasks))
+	for id, task := range s.Tasks {
+		view.Tasks[id] = task
+	}
+	for _, id := range taskIDs {
+		delete(view.Tasks, id)
+	}
+
+	return &view
 }
 
 // ToTasks converts state tasks to tasks.Task slice
@@ -179,6 +306,8 @@ func (s *TodoState) ToTasks() []tasks.Task {
 			ID:        ts.ID,
 			Tags:      ts.Tags,
 			Completed: ts.Completed,
+			Status:    ts.Status,
+			Meta:      ts.Meta,
 		})
 	}
 	return result
@@ -189,11 +318,12 @@ func (s *TodoState) NeedsMigration() bool {
 	return len(s.Tasks) == 0
 }
 
-// MigrateFromMarkdown populates state from existing markdown tasks
-func (s *TodoState) MigrateFromMarkdown(tasksList []tasks.Task, source string) int {
+// MigrateFromMarkdown populates state from existing markdown tasks, giving
+// tasks without an ID one generated with taskOpts.
+func (s *TodoState) MigrateFromMarkdown(tasksList []tasks.Task, source string, taskOpts tasks.Options) int {
 	migrated := 0
 	for _, task := range tasksList {
-		tasks.EnsureTaskID(&task)
+		taskOpts.EnsureTaskID(&task)
 		s.AddTask(task, source)
 		migrated++
 	}
@@ -235,8 +365,19 @@ type ConflictDetail struct {
 	TextDaily string `json:"text_daily"`
 	TextTodo  string `json:"text_todo"`
 	Reason    string `json:"reason"`
+	// Fields names what differs between the two sides: ConflictFieldText
+	// and/or ConflictFieldCompletion.
+	Fields         []string `json:"fields"`
+	CompletedDaily bool     `json:"completed_daily"`
+	CompletedTodo  bool     `json:"completed_todo"`
 }
 
+// Values for ConflictDetail.Fields.
+const (
+	ConflictFieldText       = "text"
+	ConflictFieldCompletion = "completion"
+)
+
 // CompareWithDailyNotes compares the state with tasks from daily notes
 // Returns a list of changes detected
 func (s *TodoState) CompareWithDailyNotes(dailyTasks []tasks.Task, source string) []TaskChange {
@@ -264,10 +405,12 @@ func (s *TodoState) CompareWithDailyNotes(dailyTasks []tasks.Task, source string
 				NewTask: &TaskState{
 					Text:      dailyTask.Text,
 					Section:   dailyTask.Section,
-					Priority:  dailyTask.Priority,
+					Priority:  sourcePriority(stateTask, dailyTask),
 					Tags:      dailyTask.Tags,
 					ID:        dailyTask.ID,
 					Completed: dailyTask.Completed,
+					Status:    dailyTask.Status,
+					Meta:      dailyTask.Meta,
 					Source:    source,
 				},
 				Source: source,
@@ -287,6 +430,8 @@ func (s *TodoState) CompareWithDailyNotes(dailyTasks []tasks.Task, source string
 					Tags:      task.Tags,
 					ID:        task.ID,
 					Completed: task.Completed,
+					Status:    task.Status,
+					Meta:      task.Meta,
 					Source:    source,
 				},
 				Source: source,
@@ -324,10 +469,12 @@ func (s *TodoState) CompareWithTodoList(todoTasks []tasks.Task) []TaskChange {
 				NewTask: &TaskState{
 					Text:      todoTask.Text,
 					Section:   todoTask.Section,
-					Priority:  todoTask.Priority,
+					Priority:  sourcePriority(stateTask, todoTask),
 					Tags:      todoTask.Tags,
 					ID:        todoTask.ID,
 					Completed: todoTask.Completed,
+					Status:    todoTask.Status,
+					Meta:      todoTask.Meta,
 				},
 				Source: "todo-list",
 			})
@@ -337,6 +484,85 @@ func (s *TodoState) CompareWithTodoList(todoTasks []tasks.Task) []TaskChange {
 	return changes
 }
 
+// IDMigration records a state entry moved from one task ID to another.
+type IDMigration struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// ReconcileIDs moves state entries whose key no longer matches the task's
+// embedded ID, so they are not left orphaned. An entry is migrated when:
+//
+//   - its key differs from the ID recorded in the entry, or
+//   - it is missing from both sources and exactly one source task with the
+//     same text has an ID that state does not know, as happens when the
+//     "<!-- id -->" marker is edited by hand.
+//
+// If the target ID is already in state, the stale entry is removed. The
+// migrations are returned sorted by their old ID.
+func (s *TodoState) ReconcileIDs(dailyTasks, todoTasks []tasks.Task, taskOpts tasks.Options) []IDMigration {
+	var migrations []IDMigration
+
+	migrate := func(from, to string) {
+		task := s.Tasks[from]
+		delete(s.Tasks, from)
+		if _, exists := s.Tasks[to]; !exists {
+			task.ID = to
+			s.Tasks[to] = task
+		}
+		migrations = append(migrations, IDMigration{From: from, To: to})
+	}
+
+	for _, key := range sortedKeys(s.Tasks) {
+		if task := s.Tasks[key]; task.ID != "" && task.ID != key {
+			migrate(key, task.ID)
+		}
+	}
+
+	inSource := make(map[string]bool)
+	unknownByText := make(map[string][]string)
+	for _, task := range append(append([]tasks.Task{}, dailyTasks...), todoTasks...) {
+		if task.ID == "" || inSource[task.ID] {
+			continue
+		}
+		inSource[task.ID] = true
+		if !s.HasTask(task.ID) {
+			text := taskOpts.StripTaskID(task.Text)
+			unknownByText[text] = append(unknownByText[text], task.ID)
+		}
+	}
+
+	orphansByText := make(map[string][]string)
+	for _, key := range sortedKeys(s.Tasks) {
+		task := s.Tasks[key]
+		if !inSource[key] && task.ArchivedDate == "" {
+			orphansByText[task.Text] = append(orphansByText[task.Text], key)
+		}
+	}
+
+	for text, orphans := range orphansByText {
+		if candidates := unknownByText[text]; len(orphans) == 1 && len(candidates) == 1 {
+			migrate(orphans[0], candidates[0])
+		}
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].From < migrations[j].From
+	})
+
+	return migrations
+}
+
+// sortedKeys returns the task IDs in state in sorted order.
+func sortedKeys(taskMap map[string]TaskState) []string {
+	keys := make([]string, 0, len(taskMap))
+	for key := range taskMap {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 // DetectDeletions finds tasks that exist in state but are missing from sources.
 // Returns TaskChange entries for tasks that should be deleted.
 // A task is considered deleted if it exists in state but is missing from BOTH sources.
@@ -375,25 +601,23 @@ func (s *TodoState) DetectDeletions(dailyTasks, todoTasks []tasks.Task) []TaskCh
 }
 
 func (s *TodoState) isTaskModified(stateTask TaskState, sourceTask tasks.Task) bool {
-	if stateTask.Text != sourceTask.Text {
+	// Tags stay in the text, so a change in tag case alone is not a change.
+	if tasks.FoldTagCase(stateTask.Text) != tasks.FoldTagCase(sourceTask.Text) {
 		return true
 	}
-	if stateTask.Priority != sourceTask.Priority {
+	if stateTask.Priority != sourcePriority(stateTask, sourceTask) {
 		return true
 	}
 	if stateTask.Completed != sourceTask.Completed {
 		return true
 	}
-
-	stateTags := make(map[string]bool)
-	for _, tag := range stateTask.Tags {
-		stateTags[tag] = true
-	}
-	sourceTags := make(map[string]bool)
-	for _, tag := range sourceTask.Tags {
-		sourceTags[tag] = true
+	if stateTask.Status != sourceTask.Status {
+		return true
 	}
 
+	stateTags := tagSet(stateTask.Tags)
+	sourceTags := tagSet(sourceTask.Tags)
+
 	if len(stateTags) != len(sourceTags) {
 		return true
 	}
@@ -406,6 +630,25 @@ func (s *TodoState) isTaskModified(stateTask TaskState, sourceTask tasks.Task) b
 	return false
 }
 
+// sourcePriority returns the priority a source task should have in state. A
+// section default never replaces a priority the task already has.
+func sourcePriority(stateTask TaskState, sourceTask tasks.Task) string {
+	if sourceTask.PriorityDefaulted && stateTask.Priority != "" {
+		return stateTask.Priority
+	}
+	return sourceTask.Priority
+}
+
+// tagSet returns the set of tags keyed case-insensitively, so "#Work" and
+// "#work" count as the same tag.
+func tagSet(tags []string) map[string]bool {
+	set := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		set[strings.ToLower(tag)] = true
+	}
+	return set
+}
+
 // DetectConflicts checks if there are conflicting changes between daily notes and todo list
 // Returns a map of task IDs to conflict descriptions
 func (s *TodoState) DetectConflicts(dailyChanges, todoChanges []TaskChange) map[string]string {
@@ -463,19 +706,99 @@ type SyncResult struct {
 	UpdatedFromTodo  []TaskChangeDetail
 	DeletedTasks     []TaskChangeDetail
 	ConflictsDetail  []ConflictDetail
+
+	// WithheldDeletions lists detected deletions that were not applied
+	// because BidirectionalSyncOptions.WithholdDeletions was set.
+	WithheldDeletions []TaskChangeDetail
+
+	// PrunedTaskIDs lists completed tasks missing from both sources that
+	// were archived because of BidirectionalSyncOptions.PruneCompletedBefore.
+	PrunedTaskIDs []string
+
+	// ReconciledIDs lists state entries moved to a task's embedded ID by
+	// ReconcileIDs before comparing.
+	ReconciledIDs []IDMigration
+}
+
+// BidirectionalSyncOptions controls optional BidirectionalSync behaviour.
+type BidirectionalSyncOptions struct {
+	// WithholdDeletions reports tasks missing from both sources in
+	// SyncResult.WithheldDeletions instead of removing them from state.
+	WithholdDeletions bool
+	// PruneCompletedBefore, a YYYY-MM-DD date, archives completed tasks
+	// missing from both sources that were completed before it, so they
+	// leave the todo file but stay in state as history. Completed tasks are
+	// otherwise never removed by sync.
+	PruneCompletedBefore string
+	// Resolutions picks the side whose change wins for a conflicting task,
+	// keyed by task ID: ResolveDaily or ResolveTodo. Conflicts without a
+	// resolution still stop the sync.
+	Resolutions map[string]string
+	// TaskOptions sets how task IDs are embedded in task text.
+	TaskOptions tasks.Options
+}
+
+// Values for BidirectionalSyncOptions.Resolutions.
+const (
+	ResolveDaily = "daily"
+	ResolveTodo  = "todo"
+)
+
+// applyResolutions removes resolved conflicts, dropping the change from the
+// side that lost so only the chosen change is applied.
+func applyResolutions(conflicts map[string]string, dailyChanges, todoChanges []TaskChange, resolutions map[string]string) ([]TaskChange, []TaskChange) {
+	without := func(changes []TaskChange, id string) []TaskChange {
+		return slices.DeleteFunc(changes, func(change TaskChange) bool { return change.TaskID == id })
+	}
+
+	for id := range conflicts {
+		switch resolutions[id] {
+		case ResolveDaily:
+			todoChanges = without(todoChanges, id)
+		case ResolveTodo:
+			dailyChanges = without(dailyChanges, id)
+			// Todo changes carry no source; keep the daily note the task
+			// came from so it is rewritten with the chosen text.
+			for i, change := range todoChanges {
+				if change.TaskID == id && change.NewTask != nil && change.OldTask != nil && change.NewTask.Source == "" {
+					task := *change.NewTask
+					task.Source = change.OldTask.Source
+					todoChanges[i].NewTask = &task
+				}
+			}
+		default:
+			continue
+		}
+		delete(conflicts, id)
+	}
+
+	return dailyChanges, todoChanges
 }
 
 // BidirectionalSync performs bidirectional sync between daily notes and todo list
 // Compares both sources with state and propagates changes appropriately
 func (s *TodoState) BidirectionalSync(dailyTasks, todoTasks []tasks.Task, dailySourcePath string) SyncResult {
+	return s.BidirectionalSyncWithOptions(dailyTasks, todoTasks, dailySourcePath, BidirectionalSyncOptions{})
+}
+
+// BidirectionalSyncWithOptions is BidirectionalSync with optional behaviour
+// such as withholding detected deletions for confirmation.
+func (s *TodoState) BidirectionalSyncWithOptions(dailyTasks, todoTasks []tasks.Task, dailySourcePath string, opts BidirectionalSyncOptions) SyncResult {
 	result := SyncResult{
 		Conflicts: make(map[string]string),
 	}
 
+	if migrations := s.ReconcileIDs(dailyTasks, todoTasks, opts.TaskOptions); len(migrations) > 0 {
+		result.ReconciledIDs = migrations
+		result.StateUpdated = true
+		result.TodoChanged = true
+	}
+
 	dailyChanges := s.CompareWithDailyNotes(dailyTasks, dailySourcePath)
 	todoChanges := s.CompareWithTodoList(todoTasks)
 
 	conflicts := s.DetectConflicts(dailyChanges, todoChanges)
+	dailyChanges, todoChanges = applyResolutions(conflicts, dailyChanges, todoChanges, opts.Resolutions)
 	if len(conflicts) > 0 {
 		result.Conflicts = conflicts
 		result.ConflictsDetail = s.buildConflictDetails(dailyChanges, todoChanges, conflicts)
@@ -560,6 +883,18 @@ func (s *TodoState) BidirectionalSync(dailyTasks, todoTasks []tasks.Task, dailyS
 	deletions := s.DetectDeletions(dailyTasks, todoTasks)
 	for _, deletion := range deletions {
 		if deletion.OldTask != nil && deletion.OldTask.Completed {
+			if s.shouldPrune(*deletion.OldTask, opts.PruneCompletedBefore) {
+				pruned := *deletion.OldTask
+				pruned.ArchivedDate = s.clock().Format("2006-01-02")
+				s.Tasks[deletion.TaskID] = pruned
+				result.PrunedTaskIDs = append(result.PrunedTaskIDs, deletion.TaskID)
+				result.StateUpdated = true
+				result.TodoChanged = true
+			}
+			continue
+		}
+		if opts.WithholdDeletions {
+			result.WithheldDeletions = append(result.WithheldDeletions, buildTaskChangeDetail(deletion))
 			continue
 		}
 		s.RemoveTask(deletion.TaskID)
@@ -573,12 +908,18 @@ func (s *TodoState) BidirectionalSync(dailyTasks, todoTasks []tasks.Task, dailyS
 	return result
 }
 
+// shouldPrune reports whether a completed task missing from both sources was
+// completed before cutoff and has not been archived yet.
+func (s *TodoState) shouldPrune(task TaskState, cutoff string) bool {
+	return cutoff != "" && task.ArchivedDate == "" && task.CompletedDate != "" && task.CompletedDate < cutoff
+}
+
 func (s *TodoState) applyChange(change TaskChange) {
 	if change.NewTask == nil {
 		return
 	}
 
-	now := time.Now()
+	now := s.clock()
 	task := *change.NewTask
 
 	// Preserve fields from existing task
@@ -587,6 +928,12 @@ func (s *TodoState) applyChange(change TaskChange) {
 		task.CompletedAt = existing.CompletedAt
 		task.CreatedDate = existing.CreatedDate
 		task.CompletedDate = existing.CompletedDate
+		if task.Order == 0 {
+			task.Order = existing.Order
+		}
+		if task.Completed {
+			task.ArchivedDate = existing.ArchivedDate
+		}
 	}
 
 	// Set CompletedDate if task transitioned to complete
@@ -615,6 +962,8 @@ func (s *TodoState) smartMerge(dailyChange, todoChange TaskChange) *TaskState {
 		Priority:  dailyChange.NewTask.Priority,
 		Tags:      dailyChange.NewTask.Tags,
 		Completed: dailyChange.NewTask.Completed,
+		Status:    dailyChange.NewTask.Status,
+		Meta:      dailyChange.NewTask.Meta,
 		Source:    "merged",
 	}
 
@@ -633,7 +982,7 @@ func (s *TodoState) smartMerge(dailyChange, todoChange TaskChange) *TaskState {
 	}
 	merged.CreatedDate = createdDate
 	if merged.Completed && !wasCompleted {
-		merged.CompletedDate = time.Now().Format("2006-01-02")
+		merged.CompletedDate = s.clock().Format("2006-01-02")
 	} else if wasCompleted {
 		// Preserve existing CompletedDate if task was already completed
 		if dailyChange.OldTask != nil {
@@ -643,18 +992,21 @@ func (s *TodoState) smartMerge(dailyChange, todoChange TaskChange) *TaskState {
 		}
 	}
 
-	merged.LastModified = time.Now()
+	merged.LastModified = s.clock()
 
-	// Merge tags from both sources
-	mergedTags := make(map[string]bool)
-	for _, tag := range dailyChange.NewTask.Tags {
-		mergedTags[tag] = true
-	}
-	for _, tag := range todoChange.NewTask.Tags {
-		mergedTags[tag] = true
+	// Merge tags from both sources, ignoring case differences. The first
+	// casing seen wins, with the daily note taking precedence.
+	mergedTags := make(map[string]string)
+	for _, tags := range [][]string{dailyChange.NewTask.Tags, todoChange.NewTask.Tags} {
+		for _, tag := range tags {
+			key := strings.ToLower(tag)
+			if _, exists := mergedTags[key]; !exists {
+				mergedTags[key] = tag
+			}
+		}
 	}
 	merged.Tags = make([]string, 0, len(mergedTags))
-	for tag := range mergedTags {
+	for _, tag := range mergedTags {
 		merged.Tags = append(merged.Tags, tag)
 	}
 	sort.Strings(merged.Tags)
@@ -667,7 +1019,8 @@ func (s *TodoState) smartMerge(dailyChange, todoChange TaskChange) *TaskState {
 
 	// If both modified to the same state, just use that state (no real conflict)
 	if dailyChange.NewTask.Text == todoChange.NewTask.Text &&
-		dailyChange.NewTask.Completed == todoChange.NewTask.Completed {
+		dailyChange.NewTask.Completed == todoChange.NewTask.Completed &&
+		dailyChange.NewTask.Status == todoChange.NewTask.Status {
 		return &merged
 	}
 
@@ -675,6 +1028,8 @@ func (s *TodoState) smartMerge(dailyChange, todoChange TaskChange) *TaskState {
 	return nil
 }
 
+// buildConflictDetails describes each conflict with both sides of the task,
+// sorted by ID.
 func (s *TodoState) buildConflictDetails(dailyChanges, todoChanges []TaskChange, conflicts map[string]string) []ConflictDetail {
 	var details []ConflictDetail
 
@@ -696,18 +1051,38 @@ func (s *TodoState) buildConflictDetails(dailyChanges, todoChanges []TaskChange,
 
 		if dailyChange, exists := dailyChangeMap[id]; exists && dailyChange.NewTask != nil {
 			detail.TextDaily = dailyChange.NewTask.Text
+			detail.CompletedDaily = dailyChange.NewTask.Completed
 		}
 
 		if todoChange, exists := todoChangeMap[id]; exists && todoChange.NewTask != nil {
 			detail.TextTodo = todoChange.NewTask.Text
+			detail.CompletedTodo = todoChange.NewTask.Completed
+		}
+
+		detail.Fields = []string{}
+		if detail.TextDaily != detail.TextTodo {
+			detail.Fields = append(detail.Fields, ConflictFieldText)
+		}
+		if detail.CompletedDaily != detail.CompletedTodo {
+			detail.Fields = append(detail.Fields, ConflictFieldCompletion)
 		}
 
 		details = append(details, detail)
 	}
 
+	sort.Slice(details, func(i, j int) bool {
+		return details[i].ID < details[j].ID
+	})
+
 	return details
 }
 
+// DescribeChange converts a detected change into its reporting form, as used
+// in SyncResult.
+func DescribeChange(change TaskChange) TaskChangeDetail {
+	return buildTaskChangeDetail(change)
+}
+
 func buildTaskChangeDetail(change TaskChange) TaskChangeDetail {
 	detail := TaskChangeDetail{
 		ID:     change.TaskID,
@@ -729,7 +1104,8 @@ func buildTaskChangeDetail(change TaskChange) TaskChangeDetail {
 			// Both old and new present -> describe what changed
 			detail.Details = buildChangeDetails(change.OldTask, change.NewTask)
 		} else if change.ChangeType == Deleted {
-			// Explicitly mark deletions
+			// Explicitly mark deletions, keeping the text for display
+			detail.Text = change.OldTask.Text
 			detail.Details = "task deleted"
 		}
 	} else if change.ChangeType == Added && change.NewTask != nil {
@@ -0,0 +1,204 @@
+package tasks
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Options configures how tasks are parsed from and written to markdown. The
+// zero value uses the defaults, which is what the package-level functions
+// such as ParseTasks and StripTaskID use. Out-of-range values also fall back
+// to the defaults; config validation reports them before they get here.
+type Options struct {
+	// StatusMarkers maps extra checkbox markers to statuses. A nil or empty
+	// map uses DefaultStatusMarkers. Markers for pending and completed tasks
+	// ([ ], [x], [X]) cannot be overridden.
+	StatusMarkers map[string]string
+
+	// SectionPriorities gives tasks that have no [Pn] marker a priority,
+	// keyed by section name.
+	SectionPriorities map[string]string
+
+	// IDTemplate is the marker used to embed task IDs in task text, e.g.
+	// "^{id}" for Obsidian block references. It must contain "{id}" exactly
+	// once; empty uses DefaultIDTemplate. IDs in the default HTML comment are
+	// always recognized.
+	IDTemplate string
+
+	// IDLength is the length of generated task IDs, from DefaultIDLength to
+	// MaxIDLength; zero uses DefaultIDLength. IDs of any length from
+	// DefaultIDLength up to IDLength are recognized, so raising it keeps
+	// existing IDs.
+	IDLength int
+
+	// CompletedStyle sets how completion dates are written to task lines;
+	// empty uses CompletedStyleInlineTag. Every style is still recognized
+	// when parsing, so switching styles keeps existing dates.
+	CompletedStyle string
+}
+
+func (o Options) statusMarkers() map[string]string {
+	if len(o.StatusMarkers) == 0 {
+		return DefaultStatusMarkers
+	}
+	return o.StatusMarkers
+}
+
+// status returns the status of a task whose checkbox holds marker, or "" if
+// marker is not an extra status marker.
+func (o Options) status(marker string) string {
+	if marker == " " || marker == "x" || marker == "X" {
+		return ""
+	}
+	return o.statusMarkers()[marker]
+}
+
+func (o Options) sectionPriority(section string) string {
+	return strings.ToUpper(o.SectionPriorities[section])
+}
+
+func (o Options) idTemplate() string {
+	if strings.Count(o.IDTemplate, idPlaceholder) != 1 {
+		return DefaultIDTemplate
+	}
+	return o.IDTemplate
+}
+
+func (o Options) idLength() int {
+	if o.IDLength < DefaultIDLength || o.IDLength > MaxIDLength {
+		return DefaultIDLength
+	}
+	return o.IDLength
+}
+
+// CheckboxMarker returns the character written between the brackets of a
+// task line with the given status and completion state. If several markers
+// map to the same status, the lowest one is used.
+func (o Options) CheckboxMarker(status string, completed bool) string {
+	if completed {
+		return "x"
+	}
+
+	chosen := ""
+	if status != "" {
+		for marker := range o.statusMarkers() {
+			if o.status(marker) == status && (chosen == "" || marker < chosen) {
+				chosen = marker
+			}
+		}
+	}
+
+	if chosen == "" {
+		return " "
+	}
+
+	return chosen
+}
+
+// GenerateTaskID generates a unique task ID based on content, of length
+// IDLength.
+func (o Options) GenerateTaskID(text string) string {
+	hash := sha256.Sum256([]byte(strings.TrimSpace(text)))
+	return fmt.Sprintf("%x", hash)[:o.idLength()]
+}
+
+// idPattern matches an ID embedded with one template. Extract captures the
+// ID; Strip also consumes the whitespace before the marker.
+type idPattern struct {
+	Extract *regexp.Regexp
+	Strip   *regexp.Regexp
+}
+
+// idPatternKey identifies the patterns built for one template and length.
+type idPatternKey struct {
+	template string
+	length   int
+}
+
+// idPatternCache holds the []idPattern built for each idPatternKey, so the
+// regexps are compiled once per configuration rather than once per call.
+var idPatternCache sync.Map
+
+// idPatterns returns the patterns matching IDs (DefaultIDLength to IDLength
+// hex chars) embedded with IDTemplate. The default HTML comment is always
+// recognized so IDs written before a custom template was configured are
+// still found.
+func (o Options) idPatterns() []idPattern {
+	key := idPatternKey{template: o.idTemplate(), length: o.idLength()}
+	if patterns, ok := idPatternCache.Load(key); ok {
+		return patterns.([]idPattern)
+	}
+
+	templates := []string{key.template}
+	if key.template != DefaultIDTemplate {
+		templates = append(templates, DefaultIDTemplate)
+	}
+
+	patterns := make([]idPattern, 0, len(templates))
+	for _, t := range templates {
+		parts := strings.SplitN(t, idPlaceholder, 2)
+		marker := regexp.QuoteMeta(parts[0]) + fmt.Sprintf(`([a-f0-9]{%d,%d})`, DefaultIDLength, key.length) + regexp.QuoteMeta(parts[1])
+		patterns = append(patterns, idPattern{
+			Extract: regexp.MustCompile(marker),
+			Strip:   regexp.MustCompile(`\s*` + marker),
+		})
+	}
+
+	actual, _ := idPatternCache.LoadOrStore(key, patterns)
+	return actual.([]idPattern)
+}
+
+// FormatTaskID returns the marker embedding id in task text.
+func (o Options) FormatTaskID(id string) string {
+	return strings.Replace(o.idTemplate(), idPlaceholder, id, 1)
+}
+
+// ExtractTaskID extracts task ID from task text.
+func (o Options) ExtractTaskID(text string) string {
+	for _, pattern := range o.idPatterns() {
+		if match := pattern.Extract.FindStringSubmatch(text); len(match) > 1 {
+			return match[1]
+		}
+	}
+
+	return ""
+}
+
+// EnsureTaskID ensures a task has an ID, generating one if needed.
+func (o Options) EnsureTaskID(task *Task) {
+	if task.ID == "" {
+		// Check if ID is embedded in text
+		if id := o.ExtractTaskID(task.Text); id != "" {
+			task.ID = id
+		} else {
+			// Generate new ID and embed in text
+			task.ID = o.GenerateTaskID(task.Text)
+			task.Text = task.Text + " " + o.FormatTaskID(task.ID)
+		}
+	}
+}
+
+// StripTaskID removes task ID from task text for display.
+func (o Options) StripTaskID(text string) string {
+	for _, pattern := range o.idPatterns() {
+		text = pattern.Strip.ReplaceAllString(text, "")
+	}
+
+	return text
+}
+
+// FormatCompletedDate returns the marker recording date on a task line in
+// CompletedStyle, including its leading space, or "" for CompletedStyleNone.
+func (o Options) FormatCompletedDate(date string) string {
+	switch o.CompletedStyle {
+	case CompletedStyleComment:
+		return " <!-- completed: " + date + " -->"
+	case CompletedStyleNone:
+		return ""
+	default:
+		return " @completed(" + date + ")"
+	}
+}
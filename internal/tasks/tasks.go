@@ -1,13 +1,18 @@
 package tasks
 
 import (
+	"bufio"
+	"bytes"
 	"context"
-	"crypto/sha256"
 	"fmt"
+	"io"
 	"os"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
+
+	"github.com/AnishShah1803/jotr/internal/utils"
 )
 
 // Task represents a task item.
@@ -19,95 +24,479 @@ type Task struct {
 	Tags          []string
 	Line          int
 	Completed     bool
-	CompletedDate string // Extracted from @completed(YYYY-MM-DD) tag
+	CompletedDate string // Extracted from @completed(YYYY-MM-DD) or <!-- completed: ... -->
+	Status        string // StatusCancelled or StatusInProgress for extra checkbox markers
+
+	// Meta holds custom "key: value" fields from the task text, such as
+	// "estimate: 2h", keyed by lowercased key. due:, start: and recur: are
+	// not included.
+	Meta map[string]string
+
+	// PriorityDefaulted is set when Priority came from the section's default
+	// (see Options.SectionPriorities) rather than a [Pn] marker in the text.
+	PriorityDefaulted bool
+
+	// HeadingPath lists the headings enclosing the task at every level, from
+	// the outermost to the nearest, e.g. ["Tasks", "Urgent"]. Section is
+	// still taken only from headings of the configured section level.
+	HeadingPath []string
+
+	// Subtask rollup, populated by RollupCompletion. Zero for childless tasks.
+	SubtasksCompleted int
+	SubtasksTotal     int
 }
 
 // taskFormatRegex matches common markdown task list formats:
 // - [ ] and - [x] (dash)
 // * [ ] and * [x] (asterisk)
 // + [ ] and + [x] (plus)
-var taskFormatRegex = regexp.MustCompile(`^(\*|-|\+)\s*\[([ xX])\]\s*(.*)$`)
+// The checkbox marker is validated separately so extra markers can be configured.
+var taskFormatRegex = regexp.MustCompile(`^(\*|-|\+)\s*\[(.)\]\s*(.*)$`)
+
+// Statuses for tasks whose checkbox uses an extra marker instead of [ ] or [x].
+const (
+	StatusCancelled  = "cancelled"
+	StatusInProgress = "in-progress"
+)
+
+// DefaultStatusMarkers maps the extra checkbox markers recognized out of the
+// box to their statuses.
+var DefaultStatusMarkers = map[string]string{
+	"-": StatusCancelled,
+	"/": StatusInProgress,
+}
+
+// CheckboxMarker returns the character written between the brackets of a
+// task line with the given status and completion state, using the default
+// options.
+func CheckboxMarker(status string, completed bool) string {
+	return Options{}.CheckboxMarker(status, completed)
+}
+
+// IsCancelled reports whether a task has been marked cancelled.
+func IsCancelled(task Task) bool {
+	return task.Status == StatusCancelled
+}
+
+// Relative headings the todo file can use in place of completion date
+// sections. Completed tasks under them are parsed with their completion date
+// as the section, so the section key does not depend on the day it was read.
+const (
+	SectionToday     = "Today"
+	SectionYesterday = "Yesterday"
+	SectionThisWeek  = "This Week"
+)
+
+// isRelativeDateSection reports whether section is a relative date heading.
+func isRelativeDateSection(section string) bool {
+	return section == SectionToday || section == SectionYesterday || section == SectionThisWeek
+}
 
 // ParseTasks parses tasks from markdown content.
 func ParseTasks(content string) []Task {
-	var tasks []Task
+	return Options{}.ParseTasks(content)
+}
 
-	lines := strings.Split(content, "\n")
-	currentSection := ""
+// ParseTasks parses tasks from markdown content.
+func (o Options) ParseTasks(content string) []Task {
+	return o.ParseTasksWithSectionLevel(content, 2)
+}
 
-	for i, line := range lines {
-		// Track sections
-		if strings.HasPrefix(line, "## ") {
-			currentSection = strings.TrimPrefix(line, "## ")
-			continue
+// ParseTasksWithSectionLevel parses tasks from markdown content, treating
+// headings of the given level (e.g. 3 for "### ") as section headers.
+func ParseTasksWithSectionLevel(content string, level int) []Task {
+	return Options{}.ParseTasksWithSectionLevel(content, level)
+}
+
+// ParseTasksWithSectionLevel parses tasks from markdown content, treating
+// headings of the given level (e.g. 3 for "### ") as section headers.
+func (o Options) ParseTasksWithSectionLevel(content string, level int) []Task {
+	var tasks []Task
+
+	parser := newTaskParser(level, o)
+	for i, line := range strings.Split(content, "\n") {
+		if task, ok := parser.parseLine(line, i+1); ok {
+			tasks = append(tasks, task)
 		}
+	}
 
-		// Parse task lines - supports all common markdown formats:
-		// - [ ] / - [x] (dash), * [ ] / * [x] (asterisk), + [ ] / + [x] (plus)
-		trimmedLine := strings.TrimSpace(line)
+	return tasks
+}
 
-		// Must match valid task format: bullet + space + [space/x/X] + optional text
-		match := taskFormatRegex.FindStringSubmatch(trimmedLine)
-		if len(match) == 0 {
-			continue
+// maxTaskLineSize is the longest line ParseTasksReader accepts. The buffer
+// only grows this far for notes with very long lines, such as inline images.
+const maxTaskLineSize = 16 * 1024 * 1024
+
+// ParseTasksReader parses tasks from markdown read from r one line at a time,
+// so large files are never held in memory whole. It returns the same tasks
+// as ParseTasks would for the full content.
+func ParseTasksReader(r io.Reader) ([]Task, error) {
+	return Options{}.ParseTasksReader(r)
+}
+
+// ParseTasksReader parses tasks from markdown read from r one line at a time.
+func (o Options) ParseTasksReader(r io.Reader) ([]Task, error) {
+	var tasks []Task
+	if err := o.scanTasks(r, func(task Task) { tasks = append(tasks, task) }); err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+// Tally holds task counts by status. Cancelled tasks count toward Total only.
+type Tally struct {
+	Total     int `json:"total"`
+	Completed int `json:"completed"`
+	Pending   int `json:"pending"`
+	Overdue   int `json:"overdue"`
+}
+
+// Add counts one task.
+func (t *Tally) Add(task Task) {
+	t.Total++
+	switch {
+	case task.Completed:
+		t.Completed++
+	case IsCancelled(task):
+	default:
+		t.Pending++
+	}
+	if IsOverdue(task) {
+		t.Overdue++
+	}
+}
+
+// Merge adds the counts in other to t.
+func (t *Tally) Merge(other Tally) {
+	t.Total += other.Total
+	t.Completed += other.Completed
+	t.Pending += other.Pending
+	t.Overdue += other.Overdue
+}
+
+// TallyTasksReader counts the tasks in markdown read from r without keeping
+// them, so it is cheaper than ParseTasksReader followed by CountTasks.
+func TallyTasksReader(r io.Reader) (Tally, error) {
+	return Options{}.TallyTasksReader(r)
+}
+
+// TallyTasksReader counts the tasks in markdown read from r without keeping
+// them.
+func (o Options) TallyTasksReader(r io.Reader) (Tally, error) {
+	var tally Tally
+	err := o.scanTasks(r, tally.Add)
+	return tally, err
+}
+
+// scanTasks parses markdown read from r one line at a time, calling fn for
+// each task in order.
+func (o Options) scanTasks(r io.Reader, fn func(Task)) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxTaskLineSize)
+	scanner.Split(scanNewlines)
+
+	parser := newTaskParser(2, o)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		if task, ok := parser.parseLine(scanner.Text(), lineNum); ok {
+			fn(task)
 		}
+	}
+
+	return scanner.Err()
+}
 
-		task := Task{
-			Line:    i + 1,
-			Section: currentSection,
+// scanNewlines is a bufio.SplitFunc that splits on "\n" only. Unlike
+// bufio.ScanLines it keeps a trailing "\r", matching strings.Split.
+func scanNewlines(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if i := bytes.IndexByte(data, '\n'); i >= 0 {
+		return i + 1, data[:i], nil
+	}
+	if atEOF && len(data) > 0 {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
+
+var (
+	priorityRegex = regexp.MustCompile(`\[P([0-3])\]`)
+	tagRegex      = regexp.MustCompile(`#([a-zA-Z0-9_-]+)`)
+)
+
+// Frontmatter fields that set defaults for the tasks in a note.
+const (
+	FrontmatterDefaultPriority = "default_priority"
+	FrontmatterDefaultSection  = "default_section"
+)
+
+// taskParser holds the section and heading context carried from line to
+// line while parsing tasks.
+type taskParser struct {
+	opts           Options
+	sectionPrefix  string
+	currentSection string
+	headings       []heading
+
+	// frontmatter collects the note's leading frontmatter until it closes,
+	// after which it is nil and the defaults below are set from it.
+	frontmatter     []string
+	defaultPriority string
+	defaultSection  string
+}
+
+func newTaskParser(level int, opts Options) *taskParser {
+	return &taskParser{opts: opts, sectionPrefix: strings.Repeat("#", level) + " "}
+}
+
+// parseLine parses one line, numbered from 1, returning the task on it if
+// there is one.
+func (p *taskParser) parseLine(line string, lineNum int) (Task, bool) {
+	p.trackFrontmatter(line, lineNum)
+
+	// Track the heading hierarchy at every level
+	if h, ok := parseHeading(line); ok {
+		for len(p.headings) > 0 && p.headings[len(p.headings)-1].level >= h.level {
+			p.headings = p.headings[:len(p.headings)-1]
 		}
+		p.headings = append(p.headings, h)
+	}
 
-		// Parse completed status and text from regex match
-		checkbox := match[2]
-		task.Completed = checkbox == "x" || checkbox == "X"
-		task.Text = strings.TrimSpace(match[3])
+	// Track sections
+	if strings.HasPrefix(line, p.sectionPrefix) {
+		p.currentSection = strings.TrimPrefix(line, p.sectionPrefix)
+		return Task{}, false
+	}
+
+	// Parse task lines - supports all common markdown formats:
+	// - [ ] / - [x] (dash), * [ ] / * [x] (asterisk), + [ ] / + [x] (plus)
+	trimmedLine := strings.TrimSpace(line)
+
+	// Must match valid task format: bullet + space + [marker] + optional text
+	match := taskFormatRegex.FindStringSubmatch(trimmedLine)
+	if len(match) == 0 {
+		return Task{}, false
+	}
 
-		// Extract priority
-		priorityRe := regexp.MustCompile(`\[P([0-3])\]`)
-		if match := priorityRe.FindStringSubmatch(task.Text); len(match) > 1 {
-			task.Priority = "P" + match[1]
+	task := Task{
+		Line:    lineNum,
+		Section: p.currentSection,
+	}
+	if task.Section == "" {
+		task.Section = p.defaultSection
+	}
+	for _, h := range p.headings {
+		task.HeadingPath = append(task.HeadingPath, h.text)
+	}
+
+	// Parse completed status and text from regex match
+	checkbox := match[2]
+	switch {
+	case checkbox == " ":
+	case checkbox == "x" || checkbox == "X":
+		task.Completed = true
+	case p.opts.status(checkbox) != "":
+		task.Status = p.opts.status(checkbox)
+	default:
+		return Task{}, false
+	}
+	task.Text = strings.TrimSpace(match[3])
+
+	// Extract priority
+	if match := priorityRegex.FindStringSubmatch(task.Text); len(match) > 1 {
+		task.Priority = "P" + match[1]
+	} else if priority := p.opts.sectionPriority(task.Section); priority != "" {
+		task.Priority = priority
+		task.PriorityDefaulted = true
+	} else if p.defaultPriority != "" {
+		task.Priority = p.defaultPriority
+		task.PriorityDefaulted = true
+	}
+
+	// Extract tags
+	matches := tagRegex.FindAllStringSubmatch(task.Text, -1)
+	for _, match := range matches {
+		if len(match) > 1 {
+			task.Tags = append(task.Tags, match[1])
 		}
+	}
+	task.Tags = DedupeTags(task.Tags)
 
-		// Extract tags
-		tagRe := regexp.MustCompile(`#([a-zA-Z0-9_-]+)`)
+	// Extract task ID
+	task.ID = p.opts.ExtractTaskID(task.Text)
+	// Strip ID from text for clean display
+	task.Text = p.opts.StripTaskID(task.Text)
 
-		matches := tagRe.FindAllStringSubmatch(task.Text, -1)
-		for _, match := range matches {
-			if len(match) > 1 {
-				task.Tags = append(task.Tags, match[1])
-			}
+	// Extract completed date from @completed(date) tag
+	task.CompletedDate = ExtractCompletedDate(task.Text)
+	// Strip completed tag from text for clean display
+	task.Text = StripCompletedTag(task.Text)
+	if task.Completed && task.CompletedDate != "" && isRelativeDateSection(task.Section) {
+		task.Section = task.CompletedDate
+	}
+
+	task.Meta = ParseMeta(task.Text)
+
+	return task, true
+}
+
+// trackFrontmatter collects the note's frontmatter, starting at line 1, and
+// once it closes sets the parser's task defaults from its default_priority
+// and default_section fields. Lines inside it are still parsed as usual.
+func (p *taskParser) trackFrontmatter(line string, lineNum int) {
+	if lineNum == 1 {
+		if line == "---" {
+			p.frontmatter = []string{line}
 		}
+		return
+	}
+	if p.frontmatter == nil {
+		return
+	}
 
-		// Extract task ID
-		task.ID = ExtractTaskID(task.Text)
-		// Strip ID from text for clean display
-		task.Text = StripTaskID(task.Text)
+	p.frontmatter = append(p.frontmatter, line)
+	if line != "---" {
+		return
+	}
 
-		// Extract completed date from @completed(date) tag
-		task.CompletedDate = ExtractCompletedDate(task.Text)
-		// Strip completed tag from text for clean display
-		task.Text = StripCompletedTag(task.Text)
+	lines, _, _ := utils.FrontmatterLines(p.frontmatter)
+	fields := utils.FrontmatterFields(lines)
+	if priority := strings.ToUpper(fields[FrontmatterDefaultPriority]); priorityValueRegex.MatchString(priority) {
+		p.defaultPriority = priority
+	}
+	p.defaultSection = fields[FrontmatterDefaultSection]
+	p.frontmatter = nil
+}
+
+// priorityValueRegex matches a bare priority such as "P2".
+var priorityValueRegex = regexp.MustCompile(`^P[0-3]$`)
+
+// heading is a markdown ATX heading and its level (1 for "# ").
+type heading struct {
+	level int
+	text  string
+}
 
-		tasks = append(tasks, task)
+// parseHeading parses a "#"-style markdown heading line.
+func parseHeading(line string) (heading, bool) {
+	level := 0
+	for level < len(line) && line[level] == '#' {
+		level++
 	}
 
-	return tasks
+	if level == 0 || level > 6 || level >= len(line) || line[level] != ' ' {
+		return heading{}, false
+	}
+
+	return heading{level: level, text: strings.TrimSpace(line[level+1:])}, true
+}
+
+// HeadingPathString returns a task's heading path joined with " > ", e.g.
+// "Tasks > Urgent".
+func HeadingPathString(task Task) string {
+	return strings.Join(task.HeadingPath, " > ")
 }
 
 // ReadTasks reads tasks from a file with context support.
 func ReadTasks(ctx context.Context, path string) ([]Task, error) {
+	return Options{}.ReadTasks(ctx, path)
+}
+
+// ReadTasks reads tasks from a file with context support.
+func (o Options) ReadTasks(ctx context.Context, path string) ([]Task, error) {
 	select {
 	case <-ctx.Done():
 		return nil, ctx.Err()
 	default:
 	}
 
-	content, err := os.ReadFile(path)
+	file, err := os.Open(path)
 	if err != nil {
 		return nil, err
 	}
+	defer file.Close()
+
+	return o.ParseTasksReader(file)
+}
+
+// metaRegex matches a "key: value" field in task text. The value is a single
+// word; keys must start with a letter so times such as 10:30 are skipped.
+var metaRegex = regexp.MustCompile(`(?:^|\s)([a-zA-Z][a-zA-Z0-9_-]*):\s*([^\s/][^\s]*)`)
+
+// reservedMetaKeys are "key: value" fields with their own meaning, which are
+// not reported as metadata.
+var reservedMetaKeys = map[string]bool{"due": true, "start": true, "recur": true}
+
+// ParseMeta returns the custom "key: value" fields in task text, keyed by
+// lowercased key, or nil if there are none. If a key repeats, the first value
+// wins.
+func ParseMeta(text string) map[string]string {
+	var meta map[string]string
+	for _, match := range metaRegex.FindAllStringSubmatch(text, -1) {
+		key := strings.ToLower(match[1])
+		if reservedMetaKeys[key] {
+			continue
+		}
+		if meta == nil {
+			meta = make(map[string]string)
+		}
+		if _, seen := meta[key]; !seen {
+			meta[key] = match[2]
+		}
+	}
+	return meta
+}
+
+// FormatMeta renders the entries of meta missing from text as "key: value"
+// fields, sorted by key and separated by spaces.
+func FormatMeta(text string, meta map[string]string) string {
+	present := ParseMeta(text)
+
+	keys := make([]string, 0, len(meta))
+	for key := range meta {
+		if _, ok := present[strings.ToLower(key)]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
 
-	return ParseTasks(string(content)), nil
+	fields := make([]string, 0, len(keys))
+	for _, key := range keys {
+		fields = append(fields, key+": "+meta[key])
+	}
+	return strings.Join(fields, " ")
+}
+
+// BlockedByKey is the Meta key listing the IDs of the tasks a task waits on,
+// e.g. "blocked-by: deadbeef" or "blocked-by: deadbeef,cafef00d".
+const BlockedByKey = "blocked-by"
+
+// BlockedBy returns the task IDs in a task's blocked-by field.
+func BlockedBy(task Task) []string {
+	var ids []string
+	for _, id := range strings.Split(task.Meta[BlockedByKey], ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// FilterByMeta returns the tasks with the given metadata key. If value is
+// non-empty the field must also equal it, ignoring case.
+func FilterByMeta(taskList []Task, key, value string) []Task {
+	key = strings.ToLower(key)
+
+	var filtered []Task
+	for _, task := range taskList {
+		got, ok := task.Meta[key]
+		if !ok || (value != "" && !strings.EqualFold(got, value)) {
+			continue
+		}
+		filtered = append(filtered, task)
+	}
+	return filtered
 }
 
 // FilterTasks filters tasks by various criteria.
@@ -136,14 +525,17 @@ func FilterTasks(tasks []Task, completed *bool, priority string, section string)
 	return filtered
 }
 
-// CountTasks counts tasks by status.
+// CountTasks counts tasks by status. Cancelled tasks count toward the total
+// but are neither completed nor pending.
 func CountTasks(tasks []Task) (total, completed, pending int) {
 	total = len(tasks)
 
 	for _, task := range tasks {
-		if task.Completed {
+		switch {
+		case task.Completed:
 			completed++
-		} else {
+		case IsCancelled(task):
+		default:
 			pending++
 		}
 	}
@@ -183,11 +575,52 @@ func GroupBySection(tasks []Task) map[string][]Task {
 	return groups
 }
 
+// SortedPriorities returns the keys of a GroupByPriority result in display
+// order: P0 through P3, then "None".
+func SortedPriorities(groups map[string][]Task) []string {
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if (keys[i] == "None") != (keys[j] == "None") {
+			return keys[j] == "None"
+		}
+		return keys[i] < keys[j]
+	})
+
+	return keys
+}
+
+// SortedSections returns the keys of a GroupBySection result alphabetically,
+// with "Uncategorized" last.
+func SortedSections(groups map[string][]Task) []string {
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if (keys[i] == "Uncategorized") != (keys[j] == "Uncategorized") {
+			return keys[j] == "Uncategorized"
+		}
+		return keys[i] < keys[j]
+	})
+
+	return keys
+}
+
 // FormatTask formats a task for display.
 func FormatTask(task Task) string {
 	checkbox := "○"
-	if task.Completed {
+	switch {
+	case task.Completed:
 		checkbox = "✓"
+	case task.Status == StatusCancelled:
+		checkbox = "✗"
+	case task.Status == StatusInProgress:
+		checkbox = "◐"
 	}
 
 	priority := ""
@@ -195,16 +628,372 @@ func FormatTask(task Task) string {
 		priority = fmt.Sprintf("%s ", task.Priority)
 	}
 
-	return fmt.Sprintf("%s  %s%s", checkbox, priority, task.Text)
+	ratio := ""
+	if task.SubtasksTotal > 0 {
+		ratio = fmt.Sprintf(" [%d/%d]", task.SubtasksCompleted, task.SubtasksTotal)
+	}
+
+	return fmt.Sprintf("%s  %s%s%s", checkbox, priority, StripDuplicateTags(task.Text), ratio)
+}
+
+// DedupeTags returns tags without repeats, compared ignoring case, keeping
+// the first-seen casing of each.
+func DedupeTags(tags []string) []string {
+	if len(tags) < 2 {
+		return tags
+	}
+
+	seen := make(map[string]bool, len(tags))
+	deduped := tags[:0:0]
+	for _, tag := range tags {
+		key := strings.ToLower(tag)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		deduped = append(deduped, tag)
+	}
+
+	return deduped
+}
+
+// FoldTagCase returns text with every tag lowercased, so texts that differ
+// only in the case of their tags compare equal.
+func FoldTagCase(text string) string {
+	return tagRegex.ReplaceAllStringFunc(text, strings.ToLower)
+}
+
+// StripDuplicateTags removes every repeat of a tag from text, compared
+// ignoring case, so "Plan #work #Work" becomes "Plan #work". Only tags that
+// start a word are considered.
+func StripDuplicateTags(text string) string {
+	matches := tagRegex.FindAllStringSubmatchIndex(text, -1)
+	if len(matches) < 2 {
+		return text
+	}
+
+	seen := make(map[string]bool, len(matches))
+	var sb strings.Builder
+	last := 0
+	for _, match := range matches {
+		start, end := match[0], match[1]
+		key := strings.ToLower(text[match[2]:match[3]])
+		if start > 0 && text[start-1] != ' ' && text[start-1] != '\t' {
+			continue
+		}
+		if !seen[key] {
+			seen[key] = true
+			continue
+		}
+
+		// Drop the repeat and the whitespace before it.
+		cut := start
+		for cut > last && (text[cut-1] == ' ' || text[cut-1] == '\t') {
+			cut--
+		}
+		sb.WriteString(text[last:cut])
+		last = end
+	}
+	sb.WriteString(text[last:])
+
+	return sb.String()
+}
+
+// RollupCompletion returns a copy of parent with its subtask completion counts
+// computed from children. A parent whose children are all complete is itself
+// considered complete. A childless parent is returned unchanged.
+func RollupCompletion(parent Task, children []Task) Task {
+	if len(children) == 0 {
+		return parent
+	}
+
+	total, completed, _ := CountTasks(children)
+	parent.SubtasksTotal = total
+	parent.SubtasksCompleted = completed
+
+	if completed == total {
+		parent.Completed = true
+	}
+
+	return parent
+}
+
+// CompletionPercent returns the percentage of completed subtasks for a task.
+// Returns 0 for tasks without subtasks.
+func CompletionPercent(task Task) float64 {
+	if task.SubtasksTotal == 0 {
+		return 0
+	}
+
+	return float64(task.SubtasksCompleted) / float64(task.SubtasksTotal) * 100
+}
+
+// dueDateRegex matches a "due:YYYY-MM-DD" marker in task text.
+var dueDateRegex = regexp.MustCompile(`due:\s*(\d{4}-\d{2}-\d{2})`)
+
+// DueDate returns the due date from a task's "due:" marker, if present.
+func DueDate(task Task) (time.Time, bool) {
+	match := dueDateRegex.FindStringSubmatch(task.Text)
+	if len(match) < 2 {
+		return time.Time{}, false
+	}
+
+	dueDate, err := time.Parse("2006-01-02", match[1])
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return dueDate, true
+}
+
+// dueDateVariantRegex matches "due:" markers written with or without a space
+// and with -, / or . between the date parts, e.g. "due:2020/1/5".
+var dueDateVariantRegex = regexp.MustCompile(`(?i)\bdue:\s*(\d{4})[-/.](\d{1,2})[-/.](\d{1,2})\b`)
+
+// NormalizeDueDate rewrites recognized due-date markers in task text into the
+// canonical "due: YYYY-MM-DD" form. Markers that are not a valid date are
+// left alone.
+func NormalizeDueDate(text string) string {
+	return dueDateVariantRegex.ReplaceAllStringFunc(text, func(marker string) string {
+		match := dueDateVariantRegex.FindStringSubmatch(marker)
+		date, err := time.Parse("2006-1-2", match[1]+"-"+match[2]+"-"+match[3])
+		if err != nil {
+			return marker
+		}
+		return "due: " + date.Format("2006-01-02")
+	})
+}
+
+// SortByPriority sorts tasks in place by priority (P0 first, unprioritized
+// last) and then by due date (earliest first, no due date last). The sort is
+// stable, so otherwise equal tasks keep their order.
+func SortByPriority(taskList []Task) {
+	sort.SliceStable(taskList, func(i, j int) bool {
+		pi, pj := taskList[i].Priority, taskList[j].Priority
+		if pi != pj {
+			if pi == "" || pj == "" {
+				return pj == ""
+			}
+			return pi < pj
+		}
+
+		di, okI := DueDate(taskList[i])
+		dj, okJ := DueDate(taskList[j])
+		if okI != okJ {
+			return okI
+		}
+
+		return okI && di.Before(dj)
+	})
 }
 
 // IsOverdue checks if a task is overdue based on due date in text.
 func IsOverdue(task Task) bool {
-	// Simple check for "due:" pattern
-	dueRe := regexp.MustCompile(`due:\s*(\d{4}-\d{2}-\d{2})`)
-	if match := dueRe.FindStringSubmatch(task.Text); len(match) > 1 {
-		dueDate, err := time.Parse("2006-01-02", match[1])
-		if err == nil && dueDate.Before(time.Now()) && !task.Completed {
+	return IsOverdueAt(task, time.Now())
+}
+
+// IsOverdueAt reports whether a pending task's due date is before now.
+func IsOverdueAt(task Task, now time.Time) bool {
+	dueDate, ok := DueDate(task)
+	return ok && dueDate.Before(now) && !task.Completed && !IsCancelled(task)
+}
+
+// DueToday reports whether a pending task's due date is today.
+func DueToday(task Task) bool {
+	return DueOn(task, time.Now())
+}
+
+// DueOn reports whether a pending task's due date is the day of day.
+func DueOn(task Task, day time.Time) bool {
+	dueDate, ok := DueDate(task)
+	return ok && dueDate.Format("2006-01-02") == day.Format("2006-01-02") && !task.Completed && !IsCancelled(task)
+}
+
+// priorityTagRegex matches a [P0]-[P3] priority tag in task text.
+var priorityTagRegex = regexp.MustCompile(`\[P[0-3]\]\s*`)
+
+// SetPriority returns task text with its priority tag replaced by the given
+// priority (e.g. "P1"), or prefixed if the text has no priority yet.
+func SetPriority(text, priority string) string {
+	tag := "[" + priority + "] "
+	if loc := priorityTagRegex.FindStringIndex(text); loc != nil {
+		return strings.TrimRight(text[:loc[0]]+tag+text[loc[1]:], " ")
+	}
+
+	return tag + text
+}
+
+// WaitingStatus is the @-tag marking a task as blocked on someone else.
+const WaitingStatus = "waiting"
+
+// mentionRegex matches an @name mention at the start of text or after
+// whitespace, so email addresses are not picked up.
+var mentionRegex = regexp.MustCompile(`(?:^|\s)@([A-Za-z][A-Za-z0-9_-]*)`)
+
+// statusTags are @-tags with special meaning that are not person mentions.
+var statusTags = map[string]bool{
+	WaitingStatus: true,
+	"completed":   true,
+}
+
+// Mentions returns the people mentioned in task text with @name, in order of
+// first appearance. Status tags such as @waiting are not mentions.
+func Mentions(text string) []string {
+	var mentions []string
+
+	seen := make(map[string]bool)
+	for _, match := range mentionRegex.FindAllStringSubmatch(text, -1) {
+		name := match[1]
+		if statusTags[strings.ToLower(name)] || seen[name] {
+			continue
+		}
+		seen[name] = true
+		mentions = append(mentions, name)
+	}
+
+	return mentions
+}
+
+// IsWaiting reports whether task text carries the @waiting status.
+func IsWaiting(text string) bool {
+	for _, match := range mentionRegex.FindAllStringSubmatch(text, -1) {
+		if strings.EqualFold(match[1], WaitingStatus) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NormalizeText returns a comparison key for task text: ID comments and
+// @completed tags removed, lowercased, and whitespace collapsed.
+func NormalizeText(text string) string {
+	text = StripCompletedTag(StripTaskID(text))
+	return strings.Join(strings.Fields(strings.ToLower(text)), " ")
+}
+
+// DuplicateGroup is a set of tasks that represent the same item.
+// Keep is the first occurrence; Duplicates are the later ones.
+type DuplicateGroup struct {
+	Keep       Task
+	Duplicates []Task
+}
+
+// FindDuplicates groups tasks that share an ID or have identical normalized
+// text. Only exact matches are grouped: "Update" and "Update config" are
+// distinct tasks. Groups are returned in order of first occurrence and only
+// groups with at least one duplicate are included.
+func FindDuplicates(taskList []Task) []DuplicateGroup {
+	var groups []DuplicateGroup
+
+	byID := make(map[string]int)
+	byText := make(map[string]int)
+
+	for _, task := range taskList {
+		key := NormalizeText(task.Text)
+
+		idx, found := -1, false
+		if task.ID != "" {
+			idx, found = byID[task.ID]
+		}
+		if !found {
+			idx, found = byText[key]
+		}
+
+		if found {
+			groups[idx].Duplicates = append(groups[idx].Duplicates, task)
+		} else {
+			groups = append(groups, DuplicateGroup{Keep: task})
+			idx = len(groups) - 1
+		}
+
+		if task.ID != "" {
+			byID[task.ID] = idx
+		}
+		byText[key] = idx
+	}
+
+	var result []DuplicateGroup
+	for _, group := range groups {
+		if len(group.Duplicates) > 0 {
+			result = append(result, group)
+		}
+	}
+
+	return result
+}
+
+// Diff compares two sets of tasks, such as a note before and after an edit.
+// Tasks are matched by ID, falling back to normalized text when either task
+// has no ID. added and changed hold tasks from newTasks, removed holds tasks
+// from oldTasks; a matched task is changed if its text, priority, completion,
+// status or tags differ. Each result keeps the order of its input.
+func Diff(oldTasks, newTasks []Task) (added, removed, changed []Task) {
+	byID := make(map[string]int)
+	byText := make(map[string][]int)
+	for i, task := range oldTasks {
+		if task.ID != "" {
+			byID[task.ID] = i
+		}
+		key := NormalizeText(task.Text)
+		byText[key] = append(byText[key], i)
+	}
+
+	matched := make([]bool, len(oldTasks))
+	match := func(task Task) (int, bool) {
+		if i, ok := byID[task.ID]; ok && task.ID != "" && !matched[i] {
+			return i, true
+		}
+		for _, i := range byText[NormalizeText(task.Text)] {
+			if !matched[i] && (task.ID == "" || oldTasks[i].ID == "") {
+				return i, true
+			}
+		}
+		return 0, false
+	}
+
+	for _, task := range newTasks {
+		i, ok := match(task)
+		if !ok {
+			added = append(added, task)
+			continue
+		}
+
+		matched[i] = true
+		if taskDiffers(oldTasks[i], task) {
+			changed = append(changed, task)
+		}
+	}
+
+	for i, task := range oldTasks {
+		if !matched[i] {
+			removed = append(removed, task)
+		}
+	}
+
+	return added, removed, changed
+}
+
+// taskDiffers reports whether two matched tasks differ in text, priority,
+// completion, status or tags. Tags are compared ignoring case and order.
+func taskDiffers(a, b Task) bool {
+	if a.Text != b.Text || a.Priority != b.Priority || a.Completed != b.Completed || a.Status != b.Status {
+		return true
+	}
+
+	tags := make(map[string]bool, len(a.Tags))
+	for _, tag := range a.Tags {
+		tags[strings.ToLower(tag)] = true
+	}
+	other := make(map[string]bool, len(b.Tags))
+	for _, tag := range b.Tags {
+		other[strings.ToLower(tag)] = true
+	}
+	if len(tags) != len(other) {
+		return true
+	}
+	for tag := range tags {
+		if !other[tag] {
 			return true
 		}
 	}
@@ -212,52 +1001,76 @@ func IsOverdue(task Task) bool {
 	return false
 }
 
-// GenerateTaskID generates a unique task ID based on content.
+// GenerateTaskID generates a unique task ID based on content, of the
+// default length.
 func GenerateTaskID(text string) string {
-	hash := sha256.Sum256([]byte(strings.TrimSpace(text)))
-	return fmt.Sprintf("%x", hash)[:8]
+	return Options{}.GenerateTaskID(text)
+}
+
+// Task ID lengths in hex characters. Longer IDs make collisions between
+// generated IDs less likely.
+const (
+	DefaultIDLength = 8
+	MaxIDLength     = 16
+)
+
+// DefaultIDTemplate is the marker used to embed a task ID in task text.
+const DefaultIDTemplate = "<!-- id: {id} -->"
+
+// idPlaceholder is replaced by the task ID in an ID template.
+const idPlaceholder = "{id}"
+
+// FormatTaskID returns the marker embedding id in task text in the default
+// template.
+func FormatTaskID(id string) string {
+	return Options{}.FormatTaskID(id)
 }
 
 // ExtractTaskID extracts task ID from task text.
 func ExtractTaskID(text string) string {
-	// Look for <!-- id: abc12345 --> pattern (8 hex chars)
-	idRe := regexp.MustCompile(`<!-- id: ([a-f0-9]{8}) -->`)
-	if match := idRe.FindStringSubmatch(text); len(match) > 1 {
-		return match[1]
-	}
-
-	return ""
+	return Options{}.ExtractTaskID(text)
 }
 
 // EnsureTaskID ensures a task has an ID, generating one if needed.
 func EnsureTaskID(task *Task) {
-	if task.ID == "" {
-		// Check if ID is embedded in text
-		if id := ExtractTaskID(task.Text); id != "" {
-			task.ID = id
-		} else {
-			// Generate new ID and embed in text
-			task.ID = GenerateTaskID(task.Text)
-			task.Text = task.Text + fmt.Sprintf(" <!-- id: %s -->", task.ID)
-		}
-	}
+	Options{}.EnsureTaskID(task)
 }
 
 // StripTaskID removes task ID from task text for display.
 func StripTaskID(text string) string {
-	idRe := regexp.MustCompile(`\s*<!-- id: [a-f0-9]{8} -->`)
-	return idRe.ReplaceAllString(text, "")
+	return Options{}.StripTaskID(text)
 }
 
-// StripCompletedTag removes @completed(YYYY-MM-DD) tag from task text for clean display.
+// Completion date styles, selecting how FormatCompletedDate writes the date
+// a task was completed.
+const (
+	// CompletedStyleInlineTag writes "@completed(YYYY-MM-DD)".
+	CompletedStyleInlineTag = "inline-tag"
+	// CompletedStyleComment writes "<!-- completed: YYYY-MM-DD -->".
+	CompletedStyleComment = "comment"
+	// CompletedStyleNone leaves the date out of the file.
+	CompletedStyleNone = "none"
+)
+
+// FormatCompletedDate returns the marker recording date on a task line as an
+// inline tag, including its leading space.
+func FormatCompletedDate(date string) string {
+	return Options{}.FormatCompletedDate(date)
+}
+
+var (
+	completedTagRegex  = regexp.MustCompile(`\s*(?:@completed\(\d{4}-\d{2}-\d{2}\)|<!--\s*completed:\s*\d{4}-\d{2}-\d{2}\s*-->)`)
+	completedDateRegex = regexp.MustCompile(`(?:@completed\(|<!--\s*completed:\s*)(\d{4}-\d{2}-\d{2})`)
+)
+
+// StripCompletedTag removes the completion date, as @completed(YYYY-MM-DD) or
+// <!-- completed: YYYY-MM-DD -->, from task text for clean display.
 func StripCompletedTag(text string) string {
-	completedRe := regexp.MustCompile(`\s*@completed\(\d{4}-\d{2}-\d{2}\)`)
-	return completedRe.ReplaceAllString(text, "")
+	return completedTagRegex.ReplaceAllString(text, "")
 }
 
 func ExtractCompletedDate(text string) string {
-	completedRe := regexp.MustCompile(`@completed\((\d{4}-\d{2}-\d{2})\)`)
-	if match := completedRe.FindStringSubmatch(text); len(match) > 1 {
+	if match := completedDateRegex.FindStringSubmatch(text); len(match) > 1 {
 		if _, err := time.Parse("2006-01-02", match[1]); err == nil {
 			return match[1]
 		}
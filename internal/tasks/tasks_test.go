@@ -2,7 +2,9 @@ package tasks
 
 import (
 	"context"
+	"fmt"
 	"path/filepath"
+	"reflect"
 	"regexp"
 	"strings"
 	"testing"
@@ -114,6 +116,51 @@ Another line here
 }
 
 // TestGenerateTaskID tests that GenerateTaskID produces deterministic and unique IDs.
+func TestReadTasks_FrontmatterDefaults(t *testing.T) {
+	fs := testhelpers.NewTestFS(t)
+	defer fs.Cleanup()
+
+	content := `---
+title: Project
+default_priority: p2
+default_section: Inbox
+---
+
+- [ ] Unmarked task
+- [ ] Marked task [P0]
+
+## Later
+
+- [ ] Sectioned task
+`
+	fs.WriteFile(t, "project.md", content)
+
+	tasks, err := ReadTasks(context.Background(), filepath.Join(fs.BaseDir, "project.md"))
+	if err != nil {
+		t.Fatalf("ReadTasks() error = %v", err)
+	}
+	if len(tasks) != 3 {
+		t.Fatalf("ReadTasks() returned %d tasks, want 3", len(tasks))
+	}
+
+	want := []struct {
+		priority  string
+		defaulted bool
+		section   string
+	}{
+		{priority: "P2", defaulted: true, section: "Inbox"},
+		{priority: "P0", section: "Inbox"},
+		{priority: "P2", defaulted: true, section: "Later"},
+	}
+	for i, w := range want {
+		got := tasks[i]
+		if got.Priority != w.priority || got.PriorityDefaulted != w.defaulted || got.Section != w.section {
+			t.Errorf("%q: priority=%q defaulted=%v section=%q, want %q %v %q",
+				got.Text, got.Priority, got.PriorityDefaulted, got.Section, w.priority, w.defaulted, w.section)
+		}
+	}
+}
+
 func TestGenerateTaskID(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -757,6 +804,29 @@ func TestIsOverdue(t *testing.T) {
 }
 
 // TestIsOverdue_PastDateVariants tests IsOverdue with various past date formats.
+func TestIsOverdueAt_DueOn(t *testing.T) {
+	now := time.Date(2025, 3, 10, 0, 0, 0, 0, time.Local)
+	tests := []struct {
+		text        string
+		wantOverdue bool
+		wantDue     bool
+	}{
+		{"Task due: 2025-03-05", true, false},
+		{"Task due: 2025-03-10", false, true},
+		{"Task due: 2025-03-20", false, false},
+	}
+
+	for _, tt := range tests {
+		task := Task{Text: tt.text}
+		if got := IsOverdueAt(task, now); got != tt.wantOverdue {
+			t.Errorf("IsOverdueAt(%q) = %v, want %v", tt.text, got, tt.wantOverdue)
+		}
+		if got := DueOn(task, now); got != tt.wantDue {
+			t.Errorf("DueOn(%q) = %v, want %v", tt.text, got, tt.wantDue)
+		}
+	}
+}
+
 func TestIsOverdue_PastDateVariants(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -1361,3 +1431,594 @@ func TestGroupBySection_IntegrationWithParseTasks(t *testing.T) {
 		t.Errorf("Total tasks in all groups = %d, want 5", totalCount)
 	}
 }
+
+// TestRollupCompletion tests subtask rollup ratios and parent completion.
+func TestRollupCompletion(t *testing.T) {
+	tests := []struct {
+		name          string
+		children      []Task
+		wantCompleted int
+		wantTotal     int
+		wantParent    bool
+		wantFormat    string
+	}{
+		{
+			name:       "childless task",
+			children:   nil,
+			wantParent: false,
+			wantFormat: "○  Parent",
+		},
+		{
+			name: "0 of 3",
+			children: []Task{
+				{Text: "a"}, {Text: "b"}, {Text: "c"},
+			},
+			wantTotal:  3,
+			wantParent: false,
+			wantFormat: "○  Parent [0/3]",
+		},
+		{
+			name: "2 of 3",
+			children: []Task{
+				{Text: "a", Completed: true}, {Text: "b", Completed: true}, {Text: "c"},
+			},
+			wantCompleted: 2,
+			wantTotal:     3,
+			wantParent:    false,
+			wantFormat:    "○  Parent [2/3]",
+		},
+		{
+			name: "3 of 3",
+			children: []Task{
+				{Text: "a", Completed: true}, {Text: "b", Completed: true}, {Text: "c", Completed: true},
+			},
+			wantCompleted: 3,
+			wantTotal:     3,
+			wantParent:    true,
+			wantFormat:    "✓  Parent [3/3]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RollupCompletion(Task{Text: "Parent"}, tt.children)
+
+			if got.SubtasksCompleted != tt.wantCompleted || got.SubtasksTotal != tt.wantTotal {
+				t.Errorf("RollupCompletion() = %d/%d, want %d/%d",
+					got.SubtasksCompleted, got.SubtasksTotal, tt.wantCompleted, tt.wantTotal)
+			}
+			if got.Completed != tt.wantParent {
+				t.Errorf("RollupCompletion() parent completed = %v, want %v", got.Completed, tt.wantParent)
+			}
+			if formatted := FormatTask(got); formatted != tt.wantFormat {
+				t.Errorf("FormatTask() = %q, want %q", formatted, tt.wantFormat)
+			}
+		})
+	}
+}
+
+func TestCompletionPercent(t *testing.T) {
+	task := RollupCompletion(Task{Text: "Parent"}, []Task{{Completed: true}, {}, {}, {Completed: true}})
+	if got := CompletionPercent(task); got != 50 {
+		t.Errorf("CompletionPercent() = %v, want 50", got)
+	}
+
+	if got := CompletionPercent(Task{Text: "Leaf"}); got != 0 {
+		t.Errorf("CompletionPercent() for childless task = %v, want 0", got)
+	}
+}
+
+// TestFindDuplicates tests that exact duplicates are grouped and near-duplicates are not.
+func TestDiff(t *testing.T) {
+	oldTasks := ParseTasks(`## Tasks
+- [ ] Write report <!-- id: aaaa1111 -->
+- [ ] Call Sam <!-- id: bbbb2222 -->
+- [ ] Book flights <!-- id: cccc3333 -->
+- [ ] Water plants
+- [ ] Buy milk
+- [ ] Unchanged <!-- id: dddd4444 -->
+`)
+	newTasks := ParseTasks(`## Tasks
+- [ ] Write the quarterly report <!-- id: aaaa1111 -->
+- [x] Call Sam <!-- id: bbbb2222 -->
+- [x] Water plants
+- [ ] Buy milk
+- [ ] Unchanged <!-- id: dddd4444 -->
+- [ ] Renew passport
+`)
+
+	added, removed, changed := Diff(oldTasks, newTasks)
+
+	texts := func(list []Task) []string {
+		var out []string
+		for _, task := range list {
+			out = append(out, task.Text)
+		}
+		return out
+	}
+
+	if got, want := texts(added), []string{"Renew passport"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("added = %q, want %q", got, want)
+	}
+	if got, want := texts(removed), []string{"Book flights"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("removed = %q, want %q", got, want)
+	}
+	// A new text under the same ID and a completion are changes; the
+	// ID-less "Water plants" is matched by its text.
+	if got, want := texts(changed), []string{"Write the quarterly report", "Call Sam", "Water plants"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("changed = %q, want %q", got, want)
+	}
+}
+
+func TestParseTasks_DedupesTags(t *testing.T) {
+	parsed := ParseTasks("- [ ] Plan sprint #work #urgent #work #Work #URGENT")
+	if len(parsed) != 1 {
+		t.Fatalf("ParseTasks() returned %d tasks, want 1", len(parsed))
+	}
+
+	if want := []string{"work", "urgent"}; !reflect.DeepEqual(parsed[0].Tags, want) {
+		t.Errorf("Tags = %q, want %q", parsed[0].Tags, want)
+	}
+	if got, want := FormatTask(parsed[0]), "○  Plan sprint #work #urgent"; got != want {
+		t.Errorf("FormatTask() = %q, want %q", got, want)
+	}
+}
+
+func TestFoldTagCase(t *testing.T) {
+	if got, want := FoldTagCase("Fix Bug #Work #URGENT-fix"), "Fix Bug #work #urgent-fix"; got != want {
+		t.Errorf("FoldTagCase() = %q, want %q", got, want)
+	}
+}
+
+func TestStripDuplicateTags(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"Plan #work #Work", "Plan #work"},
+		{"#a first #b then #A and #B done", "#a first #b then and done"},
+		{"No tags here", "No tags here"},
+		{"Email C#team about #team", "Email C#team about #team"},
+	}
+
+	for _, tt := range tests {
+		if got := StripDuplicateTags(tt.text); got != tt.want {
+			t.Errorf("StripDuplicateTags(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestFindDuplicates(t *testing.T) {
+	content := `## Tasks
+
+- [ ] Update
+- [ ] Update config
+- [ ]   update
+- [ ] Review PR <!-- id: abc12345 -->
+- [ ] Review the PR again <!-- id: abc12345 -->
+`
+	groups := FindDuplicates(ParseTasks(content))
+
+	if len(groups) != 2 {
+		t.Fatalf("FindDuplicates() returned %d groups, want 2: %+v", len(groups), groups)
+	}
+
+	if groups[0].Keep.Line != 3 || len(groups[0].Duplicates) != 1 || groups[0].Duplicates[0].Line != 5 {
+		t.Errorf("Expected text duplicate on line 5 of line 3, got %+v", groups[0])
+	}
+
+	if groups[1].Keep.ID != "abc12345" || len(groups[1].Duplicates) != 1 || groups[1].Duplicates[0].Line != 7 {
+		t.Errorf("Expected ID duplicate on line 7, got %+v", groups[1])
+	}
+
+	for _, group := range groups {
+		for _, dup := range group.Duplicates {
+			if dup.Text == "Update config" {
+				t.Error("\"Update config\" should not be treated as a duplicate of \"Update\"")
+			}
+		}
+	}
+}
+
+func TestSetPriority(t *testing.T) {
+	tests := []struct {
+		text     string
+		priority string
+		want     string
+	}{
+		{"Write docs", "P1", "[P1] Write docs"},
+		{"[P2] Write docs", "P0", "[P0] Write docs"},
+		{"Write docs [P3]", "P1", "Write docs [P1]"},
+	}
+
+	for _, tt := range tests {
+		if got := SetPriority(tt.text, tt.priority); got != tt.want {
+			t.Errorf("SetPriority(%q, %q) = %q; want %q", tt.text, tt.priority, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeDueDate(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"Pay rent due: 2020/01/01", "Pay rent due: 2020-01-01"},
+		{"Pay rent due:2020-01-01 #home", "Pay rent due: 2020-01-01 #home"},
+		{"Pay rent due:2020/1/5", "Pay rent due: 2020-01-05"},
+		{"Pay rent due: 2020-01-01", "Pay rent due: 2020-01-01"},
+		{"Pay rent due: 2020/13/45", "Pay rent due: 2020/13/45"},
+		{"Pay rent due: friday", "Pay rent due: friday"},
+	}
+
+	for _, tt := range tests {
+		got := NormalizeDueDate(tt.text)
+		if got != tt.want {
+			t.Errorf("NormalizeDueDate(%q) = %q; want %q", tt.text, got, tt.want)
+		}
+	}
+
+	if !IsOverdue(Task{Text: NormalizeDueDate("Pay rent due: 2020/01/01")}) {
+		t.Error("IsOverdue() = false for a normalized slash due date in the past")
+	}
+}
+
+func TestMentions(t *testing.T) {
+	tests := []struct {
+		text        string
+		wantMention []string
+		wantWaiting bool
+	}{
+		{"Review PR @waiting @alice", []string{"alice"}, true},
+		{"@bob sync with @carol and @bob", []string{"bob", "carol"}, false},
+		{"Email me@example.com @Waiting", nil, true},
+		{"Plain task", nil, false},
+	}
+
+	for _, tt := range tests {
+		if got := Mentions(tt.text); strings.Join(got, ",") != strings.Join(tt.wantMention, ",") {
+			t.Errorf("Mentions(%q) = %v; want %v", tt.text, got, tt.wantMention)
+		}
+		if got := IsWaiting(tt.text); got != tt.wantWaiting {
+			t.Errorf("IsWaiting(%q) = %v; want %v", tt.text, got, tt.wantWaiting)
+		}
+	}
+}
+
+func TestSortedPrioritiesAndSections(t *testing.T) {
+	taskList := []Task{
+		{Text: "a", Priority: "P3", Section: "Work"},
+		{Text: "b", Section: ""},
+		{Text: "c", Priority: "P0", Section: "Home"},
+		{Text: "d", Priority: "P1", Section: "Errands"},
+		{Text: "e", Priority: "P2", Section: "Work"},
+	}
+
+	wantPriorities := "P0,P1,P2,P3,None"
+	wantSections := "Errands,Home,Work,Uncategorized"
+
+	// Map iteration order is random, so repeat to catch unstable ordering.
+	for i := 0; i < 20; i++ {
+		if got := strings.Join(SortedPriorities(GroupByPriority(taskList)), ","); got != wantPriorities {
+			t.Fatalf("SortedPriorities() = %s; want %s", got, wantPriorities)
+		}
+		if got := strings.Join(SortedSections(GroupBySection(taskList)), ","); got != wantSections {
+			t.Fatalf("SortedSections() = %s; want %s", got, wantSections)
+		}
+	}
+}
+
+func TestParseTasks_StatusMarkers(t *testing.T) {
+	content := `## Tasks
+- [-] Cancelled task due:2000-01-01
+- [/] In progress task due:2000-01-01
+- [?] Unknown marker
+- [ ] Pending task due:2000-01-01`
+
+	got := ParseTasks(content)
+	if len(got) != 3 {
+		t.Fatalf("ParseTasks() returned %d tasks, want 3", len(got))
+	}
+
+	if got[0].Status != StatusCancelled || got[0].Completed {
+		t.Errorf("[-] task: Status = %q, Completed = %v; want cancelled, not completed", got[0].Status, got[0].Completed)
+	}
+	if got[1].Status != StatusInProgress || got[1].Completed {
+		t.Errorf("[/] task: Status = %q, Completed = %v; want in-progress, not completed", got[1].Status, got[1].Completed)
+	}
+	if got[2].Status != "" {
+		t.Errorf("[ ] task: Status = %q; want empty", got[2].Status)
+	}
+
+	if IsOverdue(got[0]) {
+		t.Error("cancelled task should not be overdue")
+	}
+	if !IsOverdue(got[1]) || !IsOverdue(got[2]) {
+		t.Error("in-progress and pending tasks past their due date should be overdue")
+	}
+
+	total, completed, pending := CountTasks(got)
+	if total != 3 || completed != 0 || pending != 2 {
+		t.Errorf("CountTasks() = %d, %d, %d; want 3, 0, 2", total, completed, pending)
+	}
+
+	for _, task := range got {
+		if marker := CheckboxMarker(task.Status, task.Completed); !strings.Contains(content, "- ["+marker+"] "+task.Text) {
+			t.Errorf("CheckboxMarker(%q) = %q does not round-trip %q", task.Status, marker, task.Text)
+		}
+	}
+}
+
+func TestOptions_StatusMarkers(t *testing.T) {
+	opts := Options{StatusMarkers: map[string]string{"~": StatusCancelled, "x": StatusInProgress}}
+
+	got := opts.ParseTasks("- [~] Custom cancelled\n- [-] No longer a marker\n- [x] Still completed")
+	if len(got) != 2 {
+		t.Fatalf("ParseTasks() returned %d tasks, want 2", len(got))
+	}
+	if got[0].Status != StatusCancelled {
+		t.Errorf("[~] Status = %q; want %q", got[0].Status, StatusCancelled)
+	}
+	if !got[1].Completed || got[1].Status != "" {
+		t.Errorf("[x] should stay completed with no status, got Completed = %v, Status = %q", got[1].Completed, got[1].Status)
+	}
+}
+
+func TestParseTasks_Meta(t *testing.T) {
+	got := ParseTasks("- [ ] Draft spec Estimate: 2h project: Phoenix due: 2025-06-01 at 10:30 <!-- id: abc12345 -->")
+	if len(got) != 1 {
+		t.Fatalf("ParseTasks() returned %d tasks, want 1", len(got))
+	}
+
+	want := map[string]string{"estimate": "2h", "project": "Phoenix"}
+	if !reflect.DeepEqual(got[0].Meta, want) {
+		t.Errorf("Meta = %v, want %v", got[0].Meta, want)
+	}
+
+	if plain := ParseTasks("- [ ] No fields here"); plain[0].Meta != nil {
+		t.Errorf("Meta = %v, want nil", plain[0].Meta)
+	}
+}
+
+func TestFilterByMeta(t *testing.T) {
+	taskList := []Task{
+		{Text: "a", Meta: map[string]string{"project": "Phoenix"}},
+		{Text: "b", Meta: map[string]string{"project": "Atlas"}},
+		{Text: "c"},
+	}
+
+	if got := FilterByMeta(taskList, "Project", ""); len(got) != 2 {
+		t.Errorf("FilterByMeta(key only) returned %d tasks, want 2", len(got))
+	}
+	if got := FilterByMeta(taskList, "project", "phoenix"); len(got) != 1 || got[0].Text != "a" {
+		t.Errorf("FilterByMeta(project=phoenix) = %+v, want task a", got)
+	}
+}
+
+func TestOptions_SectionPriorities(t *testing.T) {
+	opts := Options{SectionPriorities: map[string]string{"Urgent": "p1"}}
+
+	got := opts.ParseTasks("## Urgent\n- [ ] Call bank\n- [ ] [P0] Fix outage\n## Later\n- [ ] Read book")
+	if len(got) != 3 {
+		t.Fatalf("ParseTasks() returned %d tasks, want 3", len(got))
+	}
+
+	if got[0].Priority != "P1" || !got[0].PriorityDefaulted {
+		t.Errorf("unmarked Urgent task: Priority = %q, PriorityDefaulted = %v; want P1, true", got[0].Priority, got[0].PriorityDefaulted)
+	}
+	if got[1].Priority != "P0" || got[1].PriorityDefaulted {
+		t.Errorf("marked Urgent task: Priority = %q, PriorityDefaulted = %v; want P0, false", got[1].Priority, got[1].PriorityDefaulted)
+	}
+	if got[2].Priority != "" {
+		t.Errorf("task outside Urgent: Priority = %q; want none", got[2].Priority)
+	}
+}
+
+func TestOptions_IDTemplate_ObsidianBlockRef(t *testing.T) {
+	opts := Options{IDTemplate: "^{id}"}
+
+	task := Task{Text: "Write report"}
+	opts.EnsureTaskID(&task)
+
+	if want := "Write report ^" + task.ID; task.Text != want {
+		t.Errorf("EnsureTaskID() text = %q, want %q", task.Text, want)
+	}
+	if got := opts.ExtractTaskID(task.Text); got != task.ID {
+		t.Errorf("ExtractTaskID(%q) = %q, want %q", task.Text, got, task.ID)
+	}
+	if got := opts.StripTaskID(task.Text); got != "Write report" {
+		t.Errorf("StripTaskID(%q) = %q, want %q", task.Text, got, "Write report")
+	}
+	if got := ExtractTaskID(task.Text); got != "" {
+		t.Errorf("ExtractTaskID(%q) with default options = %q, want none", task.Text, got)
+	}
+
+	parsed := opts.ParseTasks("- [ ] " + task.Text)
+	if len(parsed) != 1 || parsed[0].ID != task.ID || parsed[0].Text != "Write report" {
+		t.Errorf("ParseTasks() = %+v, want ID %q and text %q", parsed, task.ID, "Write report")
+	}
+
+	// IDs written in the default format are still recognized.
+	legacy := "Old task <!-- id: abc12345 -->"
+	if got := opts.ExtractTaskID(legacy); got != "abc12345" {
+		t.Errorf("ExtractTaskID(%q) = %q, want abc12345", legacy, got)
+	}
+	if got := opts.StripTaskID(legacy); got != "Old task" {
+		t.Errorf("StripTaskID(%q) = %q, want %q", legacy, got, "Old task")
+	}
+}
+
+func TestOptions_IDTemplate_Invalid(t *testing.T) {
+	for _, template := range []string{"id", "{id} {id}"} {
+		if got := (Options{IDTemplate: template}).FormatTaskID("abc12345"); got != "<!-- id: abc12345 -->" {
+			t.Errorf("FormatTaskID() with template %q = %q, want the default HTML comment", template, got)
+		}
+	}
+}
+
+func TestOptions_IDLength_Twelve(t *testing.T) {
+	opts := Options{IDLength: 12}
+
+	id := opts.GenerateTaskID("Write report")
+	if len(id) != 12 {
+		t.Fatalf("GenerateTaskID() = %q, want 12 characters", id)
+	}
+
+	text := "Write report " + opts.FormatTaskID(id)
+	if got := opts.ExtractTaskID(text); got != id {
+		t.Errorf("ExtractTaskID(%q) = %q, want %q", text, got, id)
+	}
+	if got := opts.StripTaskID(text); got != "Write report" {
+		t.Errorf("StripTaskID(%q) = %q, want %q", text, got, "Write report")
+	}
+
+	// IDs generated at the default length are still recognized.
+	legacy := "Old task <!-- id: abc12345 -->"
+	if got := opts.ExtractTaskID(legacy); got != "abc12345" {
+		t.Errorf("ExtractTaskID(%q) = %q, want abc12345", legacy, got)
+	}
+	if got := opts.StripTaskID(legacy); got != "Old task" {
+		t.Errorf("StripTaskID(%q) = %q, want %q", legacy, got, "Old task")
+	}
+}
+
+func TestOptions_IDLength_OutOfRange(t *testing.T) {
+	for _, length := range []int{-1, 7, 17} {
+		if got := (Options{IDLength: length}).GenerateTaskID("Write report"); len(got) != DefaultIDLength {
+			t.Errorf("GenerateTaskID() with length %d = %q, want the default length", length, got)
+		}
+	}
+}
+
+func TestParseTasks_HeadingPath(t *testing.T) {
+	content := `# Project
+- [ ] Top-level task
+## Tasks
+- [ ] Plain task
+### Urgent
+- [ ] Urgent task
+#### Today
+- [ ] Deep task
+### Later
+- [ ] Later task
+## Notes
+- [ ] Note task`
+
+	got := ParseTasks(content)
+	if len(got) != 6 {
+		t.Fatalf("ParseTasks() returned %d tasks, want 6", len(got))
+	}
+
+	want := []struct {
+		section string
+		path    string
+	}{
+		{"", "Project"},
+		{"Tasks", "Project > Tasks"},
+		{"Tasks", "Project > Tasks > Urgent"},
+		{"Tasks", "Project > Tasks > Urgent > Today"},
+		{"Tasks", "Project > Tasks > Later"},
+		{"Notes", "Project > Notes"},
+	}
+
+	for i, w := range want {
+		if got[i].Section != w.section {
+			t.Errorf("task %q Section = %q, want %q", got[i].Text, got[i].Section, w.section)
+		}
+		if path := HeadingPathString(got[i]); path != w.path {
+			t.Errorf("task %q heading path = %q, want %q", got[i].Text, path, w.path)
+		}
+	}
+}
+
+func TestSortByPriority(t *testing.T) {
+	taskList := []Task{
+		{Text: "none"},
+		{Text: "p1 late due:2025-03-01", Priority: "P1"},
+		{Text: "p1 undated", Priority: "P1"},
+		{Text: "p0", Priority: "P0"},
+		{Text: "p1 early due:2025-01-01", Priority: "P1"},
+	}
+
+	SortByPriority(taskList)
+
+	var got []string
+	for _, task := range taskList {
+		got = append(got, task.Text)
+	}
+
+	want := "p0|p1 early due:2025-01-01|p1 late due:2025-03-01|p1 undated|none"
+	if strings.Join(got, "|") != want {
+		t.Errorf("SortByPriority() order = %s; want %s", strings.Join(got, "|"), want)
+	}
+}
+
+func TestParseTasksReader_MatchesParseTasks(t *testing.T) {
+	opts := Options{SectionPriorities: map[string]string{"Urgent": "P0"}}
+
+	content := "# Daily Note\r\n" +
+		"\n" +
+		"## Tasks\n" +
+		"\n" +
+		"- [ ] Write report [P1] #work <!-- id: abcd1234 -->\n" +
+		"  - [x] Draft outline @completed(2025-01-02)\n" +
+		"* [/] Refactor sync owner: sam\n" +
+		"+ [-] Dropped idea\n" +
+		"- [?] Not a task\n" +
+		"\n" +
+		"### Later\n" +
+		"- [ ] Nested heading task\n" +
+		"\n" +
+		"## Urgent\r\n" +
+		"- [ ] Fix login #bug\n" +
+		"- [X] No trailing newline"
+
+	want := opts.ParseTasks(content)
+	got, err := opts.ParseTasksReader(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("ParseTasksReader() error = %v", err)
+	}
+
+	if len(want) == 0 {
+		t.Fatal("ParseTasks() found no tasks")
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseTasksReader() = %+v\nwant %+v", got, want)
+	}
+}
+
+func BenchmarkParseTasksReader(b *testing.B) {
+	var content strings.Builder
+	for i := 0; content.Len() < 8*1024*1024; i++ {
+		if i%50 == 0 {
+			fmt.Fprintf(&content, "## Section %d\n\n", i/50)
+		}
+		fmt.Fprintf(&content, "- [ ] Task number %d [P%d] #tag%d\n", i, i%4, i%10)
+		content.WriteString("Some notes about the task that are not a task line.\n")
+	}
+	data := content.String()
+
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := ParseTasksReader(strings.NewReader(data)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestOptions_CompletedStyle(t *testing.T) {
+	tests := map[string]string{
+		"":                      " @completed(2025-01-02)",
+		CompletedStyleInlineTag: " @completed(2025-01-02)",
+		CompletedStyleComment:   " <!-- completed: 2025-01-02 -->",
+		CompletedStyleNone:      "",
+		"footnote":              " @completed(2025-01-02)",
+	}
+
+	for style, want := range tests {
+		if got := (Options{CompletedStyle: style}).FormatCompletedDate("2025-01-02"); got != want {
+			t.Errorf("FormatCompletedDate() with style %q = %q, want %q", style, got, want)
+		}
+	}
+}
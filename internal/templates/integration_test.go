@@ -77,12 +77,7 @@ func TestCompleteTemplateWorkflow(t *testing.T) {
 	}
 
 	cfg := &config.Config{
-		Paths: struct {
-			BaseDir      string `json:"base_dir"`
-			DiaryDir     string `json:"diary_dir"`
-			TodoFilePath string `json:"todo_file_path"`
-			PDPFilePath  string `json:"pdp_file_path"`
-		}{
+		Paths: config.PathsConfig{
 			BaseDir: baseDir,
 		},
 	}
@@ -10,6 +10,8 @@ import (
 	"testing"
 
 	"github.com/spf13/cobra"
+
+	"github.com/AnishShah1803/jotr/internal/utils"
 )
 
 // CLIResult represents the result of executing a CLI command.
@@ -34,13 +36,10 @@ func ExecuteCommand(rootCmd *cobra.Command, args ...string) *CLIResult {
 	err := rootCmd.Execute()
 
 	// Determine exit code
-	exitCode := 0
-	if err != nil {
-		exitCode = 1
-		// Check if it's an exec.ExitError for proper exit codes
-		if exitErr, ok := err.(*exec.ExitError); ok {
-			exitCode = exitErr.ExitCode()
-		}
+	exitCode := utils.ExitCode(err)
+	// Check if it's an exec.ExitError for proper exit codes
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		exitCode = exitErr.ExitCode()
 	}
 
 	return &CLIResult{
@@ -62,12 +61,9 @@ func ExecuteCommandWithInput(rootCmd *cobra.Command, input string, args ...strin
 
 	err := rootCmd.Execute()
 
-	exitCode := 0
-	if err != nil {
-		exitCode = 1
-		if exitErr, ok := err.(*exec.ExitError); ok {
-			exitCode = exitErr.ExitCode()
-		}
+	exitCode := utils.ExitCode(err)
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		exitCode = exitErr.ExitCode()
 	}
 
 	return &CLIResult{
@@ -17,6 +17,7 @@ import (
 	"github.com/AnishShah1803/jotr/internal/constants"
 	"github.com/AnishShah1803/jotr/internal/notes"
 	"github.com/AnishShah1803/jotr/internal/output"
+	"github.com/AnishShah1803/jotr/internal/services"
 	"github.com/AnishShah1803/jotr/internal/tasks"
 	"github.com/AnishShah1803/jotr/internal/utils"
 )
@@ -316,7 +317,7 @@ func (m Model) loadData() tea.Cmd {
 			return newErrorMsg(ctx.Err(), true)
 		default:
 		}
-		allTasks, err := tasks.ReadTasks(ctx, m.config.TodoPath)
+		allTasks, err := services.TaskOptions(m.config.Format).ReadTasks(ctx, m.config.TodoPath)
 		if err != nil {
 			// This is retryable - todo file might not exist yet
 			return newErrorMsg(fmt.Errorf("failed to load tasks: %w", err), true)
@@ -379,11 +380,8 @@ func calculateStreak(cfg *config.LoadedConfig) int {
 		date := today.AddDate(0, 0, -i)
 
 		// Skip weekends if configured
-		if !cfg.Streaks.IncludeWeekends {
-			weekday := date.Weekday()
-			if weekday == time.Saturday || weekday == time.Sunday {
-				continue
-			}
+		if cfg.Streaks.SkipsDay(date) {
+			continue
 		}
 
 		notePath := notes.BuildDailyNotePath(cfg.DiaryPath, date)
@@ -1,6 +1,9 @@
 package utils
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 // ---- Common Error Variables ----
 // These error variables provide consistent, identifiable error types across the codebase.
@@ -85,6 +88,61 @@ var (
 	ErrInvalidSelection = errors.New("invalid selection")
 )
 
+// ---- Exit Codes ----
+// Exit codes returned by the jotr binary so scripts can tell results apart.
+
+const (
+	ExitCodeSuccess   = 0 // Command completed successfully
+	ExitCodeError     = 1 // Command failed with an error
+	ExitCodeNoMatches = 2 // Search completed but found no matches
+	ExitCodeConflicts = 3 // Sync completed but detected conflicts
+)
+
+// Result Errors
+var (
+	ErrNoMatches     = errors.New("no matches found")
+	ErrSyncConflicts = errors.New("sync conflicts detected")
+)
+
+// ExitError carries a specific process exit code. It is used for outcomes
+// that are not failures but should still be visible to scripts, such as a
+// search with no matches.
+type ExitError struct {
+	Err  error
+	Code int
+}
+
+// NewExitError wraps err with the given exit code.
+func NewExitError(code int, err error) *ExitError {
+	return &ExitError{Code: code, Err: err}
+}
+
+func (e *ExitError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("exit status %d", e.Code)
+	}
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// ExitCode returns the process exit code for err: ExitCodeSuccess for nil,
+// the carried code for an *ExitError, and ExitCodeError otherwise.
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitCodeSuccess
+	}
+
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.Code
+	}
+
+	return ExitCodeError
+}
+
 // ---- Helper Functions ----
 
 // WrapConfigError wraps a config-related error with additional context.
@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"strings"
 	"time"
+	"unicode"
 
 	"github.com/AnishShah1803/jotr/internal/constants"
 	"github.com/AnishShah1803/jotr/internal/utils/platform"
@@ -294,6 +295,36 @@ func FindSectionIndex(lines []string, sectionName string) int {
 	return -1
 }
 
+// FindSectionEnd finds where to append a line to the end of a markdown section.
+// The section header must match "## " + sectionName exactly (ignoring
+// surrounding whitespace). Returns the index just after the last non-empty
+// line of the section, which is the header index + 1 for an empty section,
+// or -1 if the section is not found.
+func FindSectionEnd(lines []string, sectionName string) int {
+	header := "## " + sectionName
+
+	for i, line := range lines {
+		if strings.TrimSpace(line) != header {
+			continue
+		}
+
+		lastContent := i
+		for j := i + 1; j < len(lines); j++ {
+			trimmed := strings.TrimSpace(lines[j])
+			if strings.HasPrefix(trimmed, "# ") || strings.HasPrefix(trimmed, "## ") {
+				break
+			}
+			if trimmed != "" {
+				lastContent = j
+			}
+		}
+
+		return lastContent + 1
+	}
+
+	return -1
+}
+
 // WrapFileError wraps file operation errors with operation and path context.
 // This provides consistent error messages across the codebase for file operations.
 // Returns nil if the input error is nil.
@@ -354,3 +385,47 @@ func ValidateEditor(editorCmd string) error {
 
 	return nil
 }
+
+// IsUnsafeFilenameRune reports whether r should not appear in a file name:
+// path separators, characters Windows reserves (< > : " | ? *), control
+// characters, and symbols such as emoji, which some filesystems reject.
+func IsUnsafeFilenameRune(r rune) bool {
+	switch {
+	case strings.ContainsRune(`/\<>:"|?*`, r):
+		return true
+	case unicode.IsControl(r), unicode.Is(unicode.So, r), unicode.Is(unicode.Cs, r):
+		return true
+	case r == '\u200d' || r == '\ufe0f':
+		// Zero-width joiners and variation selectors left from emoji sequences.
+		return true
+	default:
+		return false
+	}
+}
+
+// SanitizeFilename turns name into a safe file name: whitespace is trimmed
+// and each run of unsafe characters inside the name becomes replacement,
+// while unsafe characters at either end are dropped. It returns an error if
+// nothing usable is left.
+func SanitizeFilename(name, replacement string) (string, error) {
+	var sb strings.Builder
+	pending := false
+	for _, r := range strings.TrimSpace(name) {
+		if IsUnsafeFilenameRune(r) {
+			pending = sb.Len() > 0
+			continue
+		}
+		if pending {
+			sb.WriteString(replacement)
+			pending = false
+		}
+		sb.WriteRune(r)
+	}
+
+	sanitized := strings.TrimSpace(sb.String())
+	if sanitized == "" || strings.Trim(sanitized, ".") == "" {
+		return "", fmt.Errorf("name %q has no characters usable in a file name", name)
+	}
+
+	return sanitized, nil
+}
@@ -596,6 +596,55 @@ func TestFindSectionIndex(t *testing.T) {
 	}
 }
 
+func TestFindSectionEnd(t *testing.T) {
+	tests := []struct {
+		name        string
+		lines       []string
+		sectionName string
+		wantIndex   int
+	}{
+		{
+			name:        "section with entries",
+			lines:       []string{"## Captured", "", "- one", "- two", "", "## Tasks"},
+			sectionName: "Captured",
+			wantIndex:   4,
+		},
+		{
+			name:        "empty section",
+			lines:       []string{"## Captured", "", "## Tasks"},
+			sectionName: "Captured",
+			wantIndex:   1,
+		},
+		{
+			name:        "section at end of file",
+			lines:       []string{"## Notes", "text", "## Captured", "- one", ""},
+			sectionName: "Captured",
+			wantIndex:   4,
+		},
+		{
+			name:        "prefix heading does not match",
+			lines:       []string{"## Captured Ideas", "- idea"},
+			sectionName: "Captured",
+			wantIndex:   -1,
+		},
+		{
+			name:        "section does not exist",
+			lines:       []string{"## Notes"},
+			sectionName: "Captured",
+			wantIndex:   -1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotIndex := FindSectionEnd(tt.lines, tt.sectionName)
+			if gotIndex != tt.wantIndex {
+				t.Errorf("FindSectionEnd(%q) = %d, want %d", tt.sectionName, gotIndex, tt.wantIndex)
+			}
+		})
+	}
+}
+
 func TestFileExists(t *testing.T) {
 	tmpDir, err := os.MkdirTemp("", "jotr-test-")
 	if err != nil {
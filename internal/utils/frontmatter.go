@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrUnterminatedFrontmatter is returned by CheckFrontmatter when a note opens
+// frontmatter without closing it.
+var ErrUnterminatedFrontmatter = errors.New("unterminated frontmatter: missing closing ---")
+
+// FrontmatterLines returns the lines between the opening and closing "---"
+// markers of a note's frontmatter. found is false if the note does not start
+// with frontmatter, and closed is false if the closing marker is missing.
+func FrontmatterLines(lines []string) (frontmatter []string, found, closed bool) {
+	if len(lines) < 3 || lines[0] != "---" {
+		return nil, false, false
+	}
+
+	for i := 1; i < len(lines); i++ {
+		if lines[i] == "---" {
+			return lines[1:i], true, true
+		}
+	}
+
+	return nil, true, false
+}
+
+// FrontmatterFields returns the top-level "key: value" fields in frontmatter
+// lines. Surrounding quotes are removed from values; nested and list lines
+// are skipped.
+func FrontmatterFields(frontmatter []string) map[string]string {
+	fields := make(map[string]string)
+	for _, line := range frontmatter {
+		if line == "" || line[0] == ' ' || line[0] == '\t' || line[0] == '-' || line[0] == '#' {
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		fields[strings.TrimSpace(key)] = value
+	}
+	return fields
+}
+
+// CheckFrontmatter parses the frontmatter of note content as YAML. Content
+// without frontmatter is valid.
+func CheckFrontmatter(content string) error {
+	frontmatter, found, closed := FrontmatterLines(strings.Split(content, "\n"))
+	switch {
+	case !found:
+		return nil
+	case !closed:
+		return ErrUnterminatedFrontmatter
+	}
+
+	var fields map[string]any
+	if err := yaml.Unmarshal([]byte(strings.Join(frontmatter, "\n")), &fields); err != nil {
+		return fmt.Errorf("invalid frontmatter: %w", err)
+	}
+
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
 	"sync"
 	"time"
 )
@@ -12,24 +13,27 @@ import (
 
 // logContext maintains global state for legacy API.
 type logContext struct {
-	verbose bool
-	mu      sync.RWMutex
+	level Level
+	// explicit is set once the level comes from a command-line flag, so a
+	// config default does not override it.
+	explicit bool
+	mu       sync.RWMutex
 }
 
-var globalLogContext = &logContext{verbose: false}
+var globalLogContext = &logContext{level: LevelInfo}
 
 // SetVerbose enables or disables verbose output (legacy API).
 func SetVerbose(verbose bool) {
-	WithWLock(&globalLogContext.mu, func() {
-		globalLogContext.verbose = verbose
-	})
+	if verbose {
+		SetLogLevel(LevelDebug)
+	} else {
+		SetLogLevel(LevelInfo)
+	}
 }
 
 // SetVerboseWithContext sets verbose output with context (legacy API).
 func SetVerboseWithContext(ctx context.Context, verbose bool) {
-	WithWLock(&globalLogContext.mu, func() {
-		globalLogContext.verbose = verbose
-	})
+	SetVerbose(verbose)
 
 	select {
 	case <-ctx.Done():
@@ -38,6 +42,68 @@ func SetVerboseWithContext(ctx context.Context, verbose bool) {
 	}
 }
 
+// SetLogLevel sets the log level for the verbose logger and the structured
+// logger. LevelDebug turns on verbose output; LevelError silences
+// everything but errors. It takes precedence over SetDefaultLogLevel.
+func SetLogLevel(level Level) {
+	WithWLock(&globalLogContext.mu, func() {
+		globalLogContext.level = level
+		globalLogContext.explicit = true
+	})
+	SetGlobalLevel(level)
+}
+
+// SetDefaultLogLevel sets the log level unless SetLogLevel has already been
+// called, so a configured default does not override command-line flags.
+func SetDefaultLogLevel(level Level) {
+	applied := false
+	WithWLock(&globalLogContext.mu, func() {
+		if !globalLogContext.explicit {
+			globalLogContext.level = level
+			applied = true
+		}
+	})
+	if applied {
+		SetGlobalLevel(level)
+	}
+}
+
+// ResetLogLevel restores the default level and forgets any level set by
+// SetLogLevel.
+func ResetLogLevel() {
+	WithWLock(&globalLogContext.mu, func() {
+		globalLogContext.level = LevelInfo
+		globalLogContext.explicit = false
+	})
+	SetGlobalLevel(LevelInfo)
+}
+
+// GetLogLevel returns the current log level.
+func GetLogLevel() Level {
+	var level Level
+	WithRLock(&globalLogContext.mu, func() {
+		level = globalLogContext.level
+	})
+	return level
+}
+
+// ParseLogLevel parses a log level name: "quiet", "normal" or "verbose", or
+// one of "debug", "info", "warn" and "error".
+func ParseLogLevel(name string) (Level, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "verbose", "debug":
+		return LevelDebug, nil
+	case "normal", "info", "":
+		return LevelInfo, nil
+	case "warn", "warning":
+		return LevelWarn, nil
+	case "quiet", "error":
+		return LevelError, nil
+	default:
+		return LevelInfo, fmt.Errorf("unknown log level %q (expected quiet, normal or verbose)", name)
+	}
+}
+
 // VerboseLog prints debug information when verbose mode is enabled (legacy API).
 func VerboseLog(format string, args ...interface{}) {
 	VerboseLogWithContext(context.Background(), format, args...)
@@ -52,7 +118,7 @@ func VerboseLogWithContext(ctx context.Context, format string, args ...interface
 	}
 
 	WithRLock(&globalLogContext.mu, func() {
-		if globalLogContext.verbose {
+		if globalLogContext.level <= LevelDebug {
 			timestamp := time.Now().Format("15:04:05")
 			fmt.Fprintf(os.Stderr, "[%s] DEBUG: %s\n", timestamp, fmt.Sprintf(format, args...))
 		}
@@ -73,7 +139,7 @@ func VerboseLogErrorWithContext(ctx context.Context, operation string, err error
 	}
 
 	WithRLock(&globalLogContext.mu, func() {
-		if globalLogContext.verbose {
+		if globalLogContext.level <= LevelDebug {
 			timestamp := time.Now().Format("15:04:05")
 			fmt.Fprintf(os.Stderr, "[%s] ERROR in %s: %v\n", timestamp, operation, err)
 		}
@@ -0,0 +1,138 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// WriteTransaction writes several files together. Stage writes each file's
+// new contents to a temp file next to it; Commit then moves them all into
+// place, and if any move fails it restores the files already replaced, so
+// either every write lands or none does.
+type WriteTransaction struct {
+	writes []*stagedWrite
+
+	// Rename moves a staged temp file over its target. It defaults to
+	// os.Rename and can be replaced to simulate failures.
+	Rename func(oldpath, newpath string) error
+}
+
+// stagedWrite is one pending write in a WriteTransaction.
+type stagedWrite struct {
+	path     string
+	tmpPath  string
+	perm     os.FileMode
+	original []byte
+	existed  bool
+}
+
+// NewWriteTransaction creates an empty WriteTransaction.
+func NewWriteTransaction() *WriteTransaction {
+	return &WriteTransaction{Rename: os.Rename}
+}
+
+// Stage records data to be written to filename on Commit. Staging the same
+// file again replaces its pending contents.
+func (t *WriteTransaction) Stage(filename string, data []byte, perm os.FileMode) error {
+	if err := CheckWritePermission(filepath.Dir(filename)); err != nil {
+		return fmt.Errorf("permission check failed: %w", err)
+	}
+
+	write := &stagedWrite{path: filename, perm: perm}
+	for i, existing := range t.writes {
+		if existing.path == filename {
+			os.Remove(existing.tmpPath)
+			t.writes = append(t.writes[:i], t.writes[i+1:]...)
+			break
+		}
+	}
+
+	original, err := os.ReadFile(filename)
+	switch {
+	case err == nil:
+		write.original = original
+		write.existed = true
+	case !os.IsNotExist(err):
+		return fmt.Errorf("failed to read %s: %w", filename, err)
+	}
+
+	tmpFile, err := os.CreateTemp(filepath.Dir(filename), "."+filepath.Base(filename)+".tmp.*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	write.tmpPath = tmpFile.Name()
+
+	if _, err := tmpFile.Write(data); err != nil {
+		tmpFile.Close()
+		os.Remove(write.tmpPath)
+		return fmt.Errorf("failed to write to temp file: %w", err)
+	}
+	if err := tmpFile.Sync(); err != nil {
+		tmpFile.Close()
+		os.Remove(write.tmpPath)
+		return fmt.Errorf("failed to sync temp file: %w", err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		os.Remove(write.tmpPath)
+		return fmt.Errorf("failed to close temp file: %w", err)
+	}
+	if err := os.Chmod(write.tmpPath, perm); err != nil {
+		os.Remove(write.tmpPath)
+		return fmt.Errorf("failed to set permissions on temp file: %w", err)
+	}
+
+	t.writes = append(t.writes, write)
+	return nil
+}
+
+// Commit moves every staged file into place in the order they were staged.
+// If one fails, the files already replaced are restored to their original
+// contents (or removed if they did not exist) and the error is returned.
+func (t *WriteTransaction) Commit() error {
+	rename := t.Rename
+	if rename == nil {
+		rename = os.Rename
+	}
+
+	for i, write := range t.writes {
+		if err := rename(write.tmpPath, write.path); err != nil {
+			commitErr := fmt.Errorf("failed to write %s: %w", write.path, err)
+			if rollbackErr := t.rollback(t.writes[:i]); rollbackErr != nil {
+				commitErr = errors.Join(commitErr, rollbackErr)
+			}
+			t.Abort()
+			return commitErr
+		}
+	}
+
+	t.writes = nil
+	return nil
+}
+
+// Abort discards any staged writes that have not been committed.
+func (t *WriteTransaction) Abort() {
+	for _, write := range t.writes {
+		os.Remove(write.tmpPath)
+	}
+	t.writes = nil
+}
+
+// rollback restores committed files in reverse order.
+func (t *WriteTransaction) rollback(committed []*stagedWrite) error {
+	var errs []error
+	for i := len(committed) - 1; i >= 0; i-- {
+		write := committed[i]
+		if !write.existed {
+			if err := os.Remove(write.path); err != nil && !os.IsNotExist(err) {
+				errs = append(errs, fmt.Errorf("failed to remove %s: %w", write.path, err))
+			}
+			continue
+		}
+		if err := AtomicWriteFile(write.path, write.original, write.perm); err != nil {
+			errs = append(errs, fmt.Errorf("failed to restore %s: %w", write.path, err))
+		}
+	}
+	return errors.Join(errs...)
+}
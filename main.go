@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"os"
 
 	"github.com/AnishShah1803/jotr/cmd"
@@ -9,7 +10,12 @@ import (
 
 func main() {
 	if err := cmd.Execute(); err != nil {
-		utils.PrintError("%v", err)
-		os.Exit(1)
+		// Result exit codes (no matches, conflicts) have already been reported
+		// by the command itself; only print genuine errors.
+		var exitErr *utils.ExitError
+		if !errors.As(err, &exitErr) {
+			utils.PrintError("%v", err)
+		}
+		os.Exit(utils.ExitCode(err))
 	}
 }